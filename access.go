@@ -0,0 +1,162 @@
+package geode_go_client
+
+import (
+	"fmt"
+)
+
+// An Operation identifies a kind of Client operation for the purposes of access control.
+type Operation string
+
+const (
+	OpGet         Operation = "Get"
+	OpGetAll      Operation = "GetAll"
+	OpPut         Operation = "Put"
+	OpPutIfAbsent Operation = "PutIfAbsent"
+	OpPutAll      Operation = "PutAll"
+	OpRemove      Operation = "Remove"
+	OpRemoveAll   Operation = "RemoveAll"
+	OpSize        Operation = "Size"
+	OpKeys        Operation = "Keys"
+	OpClear       Operation = "Clear"
+	OpRegions     Operation = "Regions"
+	OpFunction    Operation = "Function"
+	OpQuery       Operation = "Query"
+)
+
+// Operations which modify a region and are therefore rejected by a read-only Client
+var writeOperations = map[Operation]bool{
+	OpPut:         true,
+	OpPutIfAbsent: true,
+	OpPutAll:      true,
+	OpRemove:      true,
+	OpRemoveAll:   true,
+	OpClear:       true,
+}
+
+// An AccessDeniedError is returned when an operation is not permitted by the allow and deny
+// lists of a Client. Region is empty for operations which do not target a region.
+type AccessDeniedError struct {
+	Operation Operation
+	Region    string
+}
+
+func (this *AccessDeniedError) Error() string {
+	if this.Region == "" {
+		return fmt.Sprintf("operation %s is not permitted", this.Operation)
+	}
+	return fmt.Sprintf("operation %s is not permitted on region %s", this.Operation, this.Region)
+}
+
+type accessList struct {
+	allow map[string]bool
+	deny  map[string]bool
+}
+
+func (this *accessList) add(allow bool, names []string) {
+	list := &this.deny
+	if allow {
+		list = &this.allow
+	}
+	if *list == nil {
+		*list = make(map[string]bool)
+	}
+	for _, name := range names {
+		(*list)[name] = true
+	}
+}
+
+func (this *accessList) permits(name string) bool {
+	if this.deny[name] {
+		return false
+	}
+	return len(this.allow) == 0 || this.allow[name]
+}
+
+// AllowOperations restricts the Client to the given operations. Once any operation has been
+// allowed, all others are rejected with an AccessDeniedError before anything is sent to the
+// cluster.
+func (this *Client) AllowOperations(ops ...Operation) {
+	this.accessLock.Lock()
+	defer this.accessLock.Unlock()
+
+	this.operationAccess.add(true, operationNames(ops))
+}
+
+// DenyOperations rejects the given operations with an AccessDeniedError. Denying an
+// operation takes precedence over allowing it.
+func (this *Client) DenyOperations(ops ...Operation) {
+	this.accessLock.Lock()
+	defer this.accessLock.Unlock()
+
+	this.operationAccess.add(false, operationNames(ops))
+}
+
+// AllowRegions restricts the Client to the given regions. Once any region has been allowed,
+// operations on all other regions are rejected with an AccessDeniedError. Queries and
+// function executions on members or groups do not name a region, so they could reach any
+// region and are also rejected unless permitted with AllowRegionlessOperations.
+func (this *Client) AllowRegions(regions ...string) {
+	this.accessLock.Lock()
+	defer this.accessLock.Unlock()
+
+	this.regionAccess.add(true, regions)
+}
+
+// AllowRegionlessOperations permits queries (OpQuery) or function executions on members
+// or groups (OpFunction) when the Client has been restricted with AllowRegions. The client
+// cannot tell which regions such operations use, so this should only be allowed where the
+// cluster enforces its own limits.
+func (this *Client) AllowRegionlessOperations(ops ...Operation) {
+	this.accessLock.Lock()
+	defer this.accessLock.Unlock()
+
+	if this.regionless == nil {
+		this.regionless = make(map[Operation]bool)
+	}
+	for _, op := range ops {
+		this.regionless[op] = true
+	}
+}
+
+// DenyRegions rejects all operations on the given regions with an AccessDeniedError.
+// Denying a region takes precedence over allowing it.
+func (this *Client) DenyRegions(regions ...string) {
+	this.accessLock.Lock()
+	defer this.accessLock.Unlock()
+
+	this.regionAccess.add(false, regions)
+}
+
+// Check that an operation on a region is permitted by the read-only setting and the allow
+// and deny lists. region is empty for operations which do not target a region.
+func (this *Client) authorize(op Operation, region string) error {
+	this.accessLock.RLock()
+	defer this.accessLock.RUnlock()
+
+	if this.readOnly && writeOperations[op] {
+		return ErrReadOnly
+	}
+
+	if !this.operationAccess.permits(string(op)) {
+		return &AccessDeniedError{Operation: op}
+	}
+
+	if region != "" && !this.regionAccess.permits(region) {
+		return &AccessDeniedError{Operation: op, Region: region}
+	}
+
+	// Listing regions only returns the permitted ones, so it is not restricted by region
+	if region == "" && len(this.regionAccess.allow) > 0 && !this.regionless[op] && op != OpRegions {
+		return &AccessDeniedError{Operation: op}
+	}
+
+	return nil
+}
+
+func operationNames(ops []Operation) []string {
+	names := make([]string, len(ops))
+	for i, op := range ops {
+		names[i] = string(op)
+	}
+	return names
+}
@@ -0,0 +1,87 @@
+package geode_go_client_test
+
+import (
+	geode "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/query"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Access lists", func() {
+
+	var cluster *fakeCluster
+	var client *geode.Client
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		client = geode.NewGeodeClient(cluster.connector())
+	})
+
+	It("rejects denied operations before contacting the cluster", func() {
+		client.DenyOperations(geode.OpPutAll, geode.OpQuery)
+
+		_, err := client.PutAll("foo", map[string]int{"A": 1})
+		Expect(err).To(Equal(&geode.AccessDeniedError{Operation: geode.OpPutAll, Region: ""}))
+		_, err = client.QueryForListResult(query.NewQuery("select * from /foo"))
+		Expect(err).To(Equal(&geode.AccessDeniedError{Operation: geode.OpQuery}))
+		Expect(cluster.requests).To(BeEmpty())
+
+		Expect(client.Put("foo", "A", 1)).To(BeNil())
+	})
+
+	It("only permits allowed operations", func() {
+		client.AllowOperations(geode.OpGet, geode.OpPut)
+
+		Expect(client.Put("foo", "A", 1)).To(BeNil())
+		_, err := client.Get("foo", "A")
+		Expect(err).To(BeNil())
+
+		err = client.Remove("foo", "A")
+		Expect(err).To(BeAssignableToTypeOf(&geode.AccessDeniedError{}))
+	})
+
+	It("only permits allowed regions", func() {
+		client.AllowRegions("foo", "bar")
+
+		Expect(client.Put("foo", "A", 1)).To(BeNil())
+		Expect(client.Put("bar", "A", 1)).To(BeNil())
+
+		err := client.Put("baz", "A", 1)
+		Expect(err).To(Equal(&geode.AccessDeniedError{Operation: geode.OpPut, Region: "baz"}))
+		Expect(err.Error()).To(Equal("operation Put is not permitted on region baz"))
+	})
+
+	It("gives deny precedence over allow", func() {
+		client.AllowRegions("foo", "bar")
+		client.DenyRegions("bar")
+
+		Expect(client.Put("foo", "A", 1)).To(BeNil())
+		_, err := client.Size("bar")
+		Expect(err).To(Equal(&geode.AccessDeniedError{Operation: geode.OpSize, Region: "bar"}))
+	})
+
+	It("checks the region of function executions", func() {
+		client.DenyRegions("foo")
+
+		_, err := client.ExecuteOnRegion("fn", "foo", nil, nil)
+		Expect(err).To(Equal(&geode.AccessDeniedError{Operation: geode.OpFunction, Region: "foo"}))
+	})
+
+	It("rejects operations without a region once regions are allowed", func() {
+		client.AllowRegions("public")
+
+		_, err := client.QueryForListResult(query.NewQuery("select * from /secret"))
+		Expect(err).To(Equal(&geode.AccessDeniedError{Operation: geode.OpQuery}))
+		_, err = client.ExecuteOnMembers("fn", []string{"server1"}, nil)
+		Expect(err).To(Equal(&geode.AccessDeniedError{Operation: geode.OpFunction}))
+		_, err = client.ExecuteOnGroups("fn", []string{"group1"}, nil)
+		Expect(err).To(Equal(&geode.AccessDeniedError{Operation: geode.OpFunction}))
+		Expect(cluster.requests).To(BeEmpty())
+
+		client.AllowRegionlessOperations(geode.OpQuery)
+		_, err = client.QueryForListResult(query.NewQuery("select * from /public"))
+		Expect(err).ToNot(BeAssignableToTypeOf(&geode.AccessDeniedError{}))
+		_, err = client.ExecuteOnMembers("fn", []string{"server1"}, nil)
+		Expect(err).To(Equal(&geode.AccessDeniedError{Operation: geode.OpFunction}))
+	})
+})
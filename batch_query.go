@@ -0,0 +1,104 @@
+package geode_go_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	. "github.com/gemfire/geode-go-client/query"
+)
+
+// A BatchQuery is one of the queries run by QueryBatch.
+type BatchQuery struct {
+	Query *Query
+	// Return the results as a table, as QueryForTableResult, rather than as a list
+	Table bool
+}
+
+// A BatchQueryResult holds the results of one query of a batch, in List or Table as the query
+// asked, or the error which stopped it.
+type BatchQueryResult struct {
+	List  []interface{}
+	Table map[string][]interface{}
+	Err   error
+}
+
+// A BatchQueryError is returned by QueryBatch when one or more of its queries fail. The
+// results of the other queries are still returned alongside it.
+type BatchQueryError struct {
+	// Total number of queries in the batch
+	Total int
+	// Errors of the failed queries, by their index in the batch
+	Failures map[int]error
+}
+
+func (this *BatchQueryError) Error() string {
+	return fmt.Sprintf("%d of %d batched queries failed", len(this.Failures), this.Total)
+}
+
+// Unwrap returns the errors of the failed queries, in the order of the batch.
+func (this *BatchQueryError) Unwrap() []error {
+	indices := make([]int, 0, len(this.Failures))
+	for i := range this.Failures {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	errs := make([]error, len(indices))
+	for i, index := range indices {
+		errs[i] = this.Failures[index]
+	}
+	return errs
+}
+
+// QueryBatch runs queries concurrently, each over its own connection from the pool, and
+// returns their results in the order of the queries, so that a page built from many queries
+// waits for the slowest rather than for them all in turn. The queries share ctx, so its
+// deadline bounds the batch as a whole; queries still running when ctx is done fail with
+// ctx.Err(). A connector's SetQueryConcurrency limit applies to the queries of a batch as to
+// any others.
+//
+// If any query fails, a *BatchQueryError is returned along with every result, each holding
+// the error of its query, if any. A nil BatchQuery, or one without a Query, fails without
+// being run.
+func (this *Client) QueryBatch(ctx context.Context, queries []*BatchQuery) ([]*BatchQueryResult, error) {
+	conn := this.connector.BindContext(ctx)
+	results := make([]*BatchQueryResult, len(queries))
+
+	var wg sync.WaitGroup
+	for i, query := range queries {
+		results[i] = &BatchQueryResult{}
+		if query == nil || query.Query == nil {
+			results[i].Err = errors.New(fmt.Sprintf("batched query %d has no query to run", i))
+			continue
+		}
+		wg.Add(1)
+		go func(result *BatchQueryResult, query *BatchQuery) {
+			defer wg.Done()
+			if query.Table {
+				result.Table, result.Err = this.queryForTableResult(conn, query.Query)
+			} else {
+				result.List, result.Err = this.queryForListResult(conn, query.Query)
+			}
+		}(results[i], query)
+	}
+	wg.Wait()
+
+	var batchErr *BatchQueryError
+	for i, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		if batchErr == nil {
+			batchErr = &BatchQueryError{Total: len(queries), Failures: make(map[int]error)}
+		}
+		batchErr.Failures[i] = result.Err
+	}
+
+	if batchErr != nil {
+		return results, batchErr
+	}
+	return results, nil
+}
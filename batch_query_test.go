@@ -0,0 +1,86 @@
+package geode_go_client_test
+
+import (
+	"context"
+	"errors"
+
+	geode "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/connector"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/gemfire/geode-go-client/query"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QueryBatch", func() {
+
+	var cluster *fakeCluster
+	var client *geode.Client
+
+	batch := func(statements ...string) []*geode.BatchQuery {
+		queries := make([]*geode.BatchQuery, len(statements))
+		for i, statement := range statements {
+			queries[i] = &geode.BatchQuery{Query: query.NewQuery(statement)}
+		}
+		return queries
+	}
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		client = geode.NewGeodeClient(cluster.connector())
+	})
+
+	It("returns the results of every query in order", func() {
+		list, err := connector.EncodeValueList([]interface{}{"Joe", "Ann"})
+		Expect(err).To(BeNil())
+		cluster.queryResult = &v1.OQLQueryResponse{Result: &v1.OQLQueryResponse_ListResult{ListResult: list}}
+
+		results, err := client.QueryBatch(context.Background(), batch("select name from /A", "select name from /B"))
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		for _, result := range results {
+			Expect(result.Err).To(BeNil())
+			Expect(result.List).To(Equal([]interface{}{"Joe", "Ann"}))
+		}
+		Expect(cluster.requests).To(HaveLen(2))
+	})
+
+	It("reports each failed query", func() {
+		results, err := client.QueryBatch(context.Background(), batch("select * from /A", "select * from /B"))
+
+		var batchErr *geode.BatchQueryError
+		Expect(errors.As(err, &batchErr)).To(BeTrue())
+		Expect(batchErr.Total).To(Equal(2))
+		Expect(batchErr.Failures).To(HaveLen(2))
+		Expect(batchErr.Failures[1]).To(Equal(results[1].Err))
+		Expect(connector.IsInvalidRequest(err)).To(BeTrue())
+	})
+
+	It("fails the entries without a query rather than running them", func() {
+		list, err := connector.EncodeValueList([]interface{}{"Joe"})
+		Expect(err).To(BeNil())
+		cluster.queryResult = &v1.OQLQueryResponse{Result: &v1.OQLQueryResponse_ListResult{ListResult: list}}
+
+		queries := append(batch("select name from /A"), nil, &geode.BatchQuery{Table: true})
+		results, err := client.QueryBatch(context.Background(), queries)
+
+		var batchErr *geode.BatchQueryError
+		Expect(errors.As(err, &batchErr)).To(BeTrue())
+		Expect(batchErr.Failures).To(HaveLen(2))
+		Expect(results[0].Err).To(BeNil())
+		Expect(results[0].List).To(Equal([]interface{}{"Joe"}))
+		Expect(results[1].Err).To(MatchError("batched query 1 has no query to run"))
+		Expect(results[2].Err).To(MatchError("batched query 2 has no query to run"))
+		Expect(cluster.requests).To(HaveLen(1))
+	})
+
+	It("stops the queries once the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results, err := client.QueryBatch(ctx, batch("select * from /A"))
+		Expect(err).To(BeAssignableToTypeOf(&geode.BatchQueryError{}))
+		Expect(results[0].Err).To(Equal(context.Canceled))
+		Expect(cluster.requests).To(BeEmpty())
+	})
+})
@@ -1,7 +1,13 @@
 package geode_go_client
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
 	"github.com/gemfire/geode-go-client/connector"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
 	. "github.com/gemfire/geode-go-client/query"
 )
 
@@ -26,23 +32,89 @@ import (
 //     geode.feature-protobuf-protocol=true
 //
 type Client struct {
-	connector *connector.Protobuf
+	connector     connector.Operations
+	transformLock sync.RWMutex
+	transforms    map[string][]*Transform
+
+	accessLock        sync.RWMutex
+	readOnly          bool
+	readOnlyFunctions map[string]bool
+	operationAccess   accessList
+	regionAccess      accessList
+	regionless        map[Operation]bool
+
+	configLock   sync.Mutex
+	configSource connector.ConfigSource
+
+	transactionLock sync.RWMutex
+	transactions    *TransactionFunctions
 }
 
-func NewGeodeClient(c *connector.Protobuf) *Client {
+// NewGeodeClient returns a client backed by a connector, usually a *connector.Protobuf.
+// Reloading configuration and transactions need a *connector.Protobuf and fail with other
+// implementations of connector.Operations.
+func NewGeodeClient(c connector.Operations) *Client {
 	return &Client{
 		connector: c,
 	}
 }
 
+// Return the protobuf connector backing this client, failing if the client is backed by
+// another implementation of connector.Operations
+func (this *Client) protobuf(feature string) (*connector.Protobuf, error) {
+	conn, ok := this.connector.(*connector.Protobuf)
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("a protobuf connector is required for %s", feature))
+	}
+	return conn, nil
+}
+
 // Put data into a region. key and value must be a supported type.
 func (this *Client) Put(region string, key, value interface{}) error {
-	return this.connector.Put(region, key, value)
+	return this.put(this.connector, region, key, value)
+}
+
+func (this *Client) put(conn connector.Operations, region string, key, value interface{}) error {
+	if err := this.authorize(OpPut, region); err != nil {
+		return err
+	}
+
+	physicalKey, value, err := this.transformEntry(region, key, value)
+	if err != nil {
+		return err
+	}
+	return conn.Put(region, physicalKey, value)
 }
 
 // Put data into a region if the key is not present. key and value must be a supported type.
 func (this *Client) PutIfAbsent(region string, key, value interface{}) error {
-	return this.connector.PutIfAbsent(region, key, value)
+	return this.putIfAbsent(this.connector, region, key, value)
+}
+
+func (this *Client) putIfAbsent(conn connector.Operations, region string, key, value interface{}) error {
+	if err := this.authorize(OpPutIfAbsent, region); err != nil {
+		return err
+	}
+
+	physicalKey, value, err := this.transformEntry(region, key, value)
+	if err != nil {
+		return err
+	}
+	return conn.PutIfAbsent(region, physicalKey, value)
+}
+
+func (this *Client) transformEntry(region string, key, value interface{}) (interface{}, interface{}, error) {
+	physicalKey, err := this.transformKey(region, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	value, err = this.transformWrite(region, key, value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return physicalKey, value, nil
 }
 
 // Get an entry from a region using the specified key. It is the callers' responsibility
@@ -50,74 +122,609 @@ func (this *Client) PutIfAbsent(region string, key, value interface{}) error {
 // passed, the data retrieved from the region will be attempted to be unmarshalled as JSON
 // into the supplied value.
 func (this *Client) Get(region string, key interface{}, value ...interface{}) (interface{}, error) {
+	return this.get(this.connector, region, key, value...)
+}
+
+func (this *Client) get(conn connector.Operations, region string, key interface{}, value ...interface{}) (interface{}, error) {
+	if err := this.authorize(OpGet, region); err != nil {
+		return nil, err
+	}
+
+	physicalKey, err := this.transformKey(region, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var ref interface{}
 	if len(value) > 0 {
-		return this.connector.Get(region, key, value[0])
+		ref = value[0]
 	}
-	return this.connector.Get(region, key, nil)
+
+	v, err := conn.Get(region, physicalKey, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return this.transformRead(region, key, v)
+}
+
+// GetOptional gets an entry as Get, but also reports whether the region has an entry for the
+// key, so that a null value can be told apart from a missing entry. See
+// connector.Protobuf.GetOptional.
+func (this *Client) GetOptional(region string, key interface{}, value ...interface{}) (connector.Optional, error) {
+	return this.getOptional(this.connector, region, key, value...)
+}
+
+func (this *Client) getOptional(conn connector.Operations, region string, key interface{}, value ...interface{}) (connector.Optional, error) {
+	if err := this.authorize(OpGet, region); err != nil {
+		return connector.Optional{}, err
+	}
+
+	physicalKey, err := this.transformKey(region, key)
+	if err != nil {
+		return connector.Optional{}, err
+	}
+
+	var ref interface{}
+	if len(value) > 0 {
+		ref = value[0]
+	}
+
+	result, err := conn.GetOptional(region, physicalKey, ref)
+	if err != nil || !result.Present {
+		return connector.Optional{}, err
+	}
+
+	if result.Value, err = this.transformRead(region, key, result.Value); err != nil {
+		return connector.Optional{}, err
+	}
+	return result, nil
+}
+
+// GetIfExists gets an entry as Get, but also returns whether the region has an entry for the
+// key, so that a null value can be told apart from a missing entry, as GetOptional does.
+func (this *Client) GetIfExists(region string, key interface{}, value ...interface{}) (interface{}, bool, error) {
+	result, err := this.getOptional(this.connector, region, key, value...)
+	return result.Value, result.Present, err
+}
+
+// PutRaw stores an already encoded key and value without applying any encoding. Values may
+// be obtained from GetRaw or created with connector.EncodeValue.
+func (this *Client) PutRaw(region string, key, value *v1.EncodedValue) error {
+	return this.putRaw(this.connector, region, key, value)
+}
+
+func (this *Client) putRaw(conn connector.Operations, region string, key, value *v1.EncodedValue) error {
+	if err := this.authorize(OpPut, region); err != nil {
+		return err
+	}
+
+	return conn.PutRaw(region, key, value)
+}
+
+// GetRaw retrieves the encoded value for an encoded key without decoding it. This is useful
+// for proxies and tools which need to move data without understanding it.
+func (this *Client) GetRaw(region string, key *v1.EncodedValue) (*v1.EncodedValue, error) {
+	return this.getRaw(this.connector, region, key)
+}
+
+func (this *Client) getRaw(conn connector.Operations, region string, key *v1.EncodedValue) (*v1.EncodedValue, error) {
+	if err := this.authorize(OpGet, region); err != nil {
+		return nil, err
+	}
+
+	return conn.GetRaw(region, key)
 }
 
 // PutAll adds multiple key/value pairs to a single region. Entries must be in the form of
 // a map. The returned values are either a map of individual keys and the associated error
 // when attempting to add that key, or a single error which typically would be as a result
-// of a key or value encoding error.
+// of a key or value encoding error. Keys which fail individually never produce the single
+// error. If the connector splits bulk operations into chunks (see
+// connector.SetBulkChunkSize) and some chunks fail as a whole, their keys are included in
+// the map and a *connector.MultiError is also returned.
 func (this *Client) PutAll(region string, entries interface{}) (map[interface{}]error, error) {
-	return this.connector.PutAll(region, entries)
+	return this.putAll(this.connector, region, entries)
+}
+
+func (this *Client) putAll(conn connector.Operations, region string, entries interface{}) (map[interface{}]error, error) {
+	if err := this.authorize(OpPutAll, region); err != nil {
+		return nil, err
+	}
+
+	entriesMap := reflect.ValueOf(entries)
+	if len(this.transformsFor(region)) == 0 || entriesMap.Kind() != reflect.Map {
+		return conn.PutAll(region, entries)
+	}
+
+	transformed := make(map[interface{}]interface{}, entriesMap.Len())
+	originals := make(map[interface{}]interface{}, entriesMap.Len())
+	for _, k := range entriesMap.MapKeys() {
+		physicalKey, value, err := this.transformEntry(region, k.Interface(), entriesMap.MapIndex(k).Interface())
+		if err != nil {
+			return nil, err
+		}
+		if physicalKey == nil || !reflect.TypeOf(physicalKey).Comparable() {
+			return nil, errors.New(fmt.Sprintf("key transform for region %s produced a key of type %T, which cannot be used with PutAll", region, physicalKey))
+		}
+		if _, ok := transformed[physicalKey]; ok {
+			return nil, errors.New(fmt.Sprintf("key transform for region %s produced the key %v more than once", region, physicalKey))
+		}
+		transformed[physicalKey] = value
+		originals[physicalKey] = k.Interface()
+	}
+
+	failures, err := conn.PutAll(region, transformed)
+	if _, partial := err.(*connector.MultiError); failures == nil || (err != nil && !partial) {
+		return failures, err
+	}
+
+	result := make(map[interface{}]error, len(failures))
+	for k, failure := range failures {
+		result[originalKey(originals, k)] = failure
+	}
+
+	return result, err
 }
 
 // GetAll returns the values of multiple keys. Keys must be passed as an array or slice.
 // The returned values are a map of keys and values for those keys which were
 // successfully retrieved, a map of keys and the relevant error for those keys which produced
 // an error on retrieval and, finally, a single error which typically would be as a result of
-// a key or value encoding error.
+// a key or value encoding error. Keys which fail individually never produce the single
+// error. If the connector splits bulk operations into chunks and some chunks fail as a
+// whole, their keys are included in the map of errors and a *connector.MultiError is also
+// returned alongside the results.
 func (this *Client) GetAll(region string, keys interface{}) (map[interface{}]interface{}, map[interface{}]error, error) {
-	return this.connector.GetAll(region, keys)
+	return this.getAll(this.connector, region, keys)
+}
+
+func (this *Client) getAll(conn connector.Operations, region string, keys interface{}) (map[interface{}]interface{}, map[interface{}]error, error) {
+	if err := this.authorize(OpGetAll, region); err != nil {
+		return nil, nil, err
+	}
+
+	physicalKeys, originals, err := this.transformKeys(region, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, failures, err := conn.GetAll(region, physicalKeys)
+	if _, partial := err.(*connector.MultiError); (err != nil && !partial) || originals == nil {
+		return entries, failures, err
+	}
+
+	result := make(map[interface{}]interface{}, len(entries))
+	for k, v := range entries {
+		key := originalKey(originals, k)
+		value, err := this.transformRead(region, key, v)
+		if err != nil {
+			if failures == nil {
+				failures = make(map[interface{}]error)
+			}
+			failures[key] = err
+			continue
+		}
+		result[key] = value
+	}
+
+	var resultFailures map[interface{}]error
+	for k, failure := range failures {
+		if resultFailures == nil {
+			resultFailures = make(map[interface{}]error, len(failures))
+		}
+		resultFailures[originalKey(originals, k)] = failure
+	}
+
+	return result, resultFailures, err
+}
+
+// GetAllFunc gets many entries as GetAll, passing each to fn as it is decoded rather than
+// returning them in maps, which saves memory for large numbers of entries. Keys which could
+// not be read are passed with their error. See connector.Protobuf.GetAllFunc.
+func (this *Client) GetAllFunc(region string, keys interface{}, fn func(key, value interface{}, err error) bool) error {
+	return this.getAllInto(this.connector, region, keys, nil, fn)
+}
+
+// GetAllInto is GetAllFunc, but decodes each JSON value into a new reference returned by
+// newRef, such as a pointer to a new struct.
+func (this *Client) GetAllInto(region string, keys interface{}, newRef func() interface{}, fn func(key, value interface{}, err error) bool) error {
+	return this.getAllInto(this.connector, region, keys, newRef, fn)
+}
+
+func (this *Client) getAllInto(conn connector.Operations, region string, keys interface{}, newRef func() interface{}, fn func(key, value interface{}, err error) bool) error {
+	if err := this.authorize(OpGetAll, region); err != nil {
+		return err
+	}
+
+	physicalKeys, originals, err := this.transformKeys(region, keys)
+	if err != nil {
+		return err
+	}
+
+	if originals == nil {
+		return conn.GetAllInto(region, physicalKeys, newRef, fn)
+	}
+
+	return conn.GetAllInto(region, physicalKeys, newRef, func(k, v interface{}, err error) bool {
+		key := originalKey(originals, k)
+		if err == nil {
+			if v, err = this.transformRead(region, key, v); err != nil {
+				v = nil
+			}
+		}
+		return fn(key, v, err)
+	})
+}
+
+// GetAllLazy gets many entries as GetAll, but returns their values undecoded, so that only
+// those which are used need be decoded. Read transforms are applied as each is decoded.
+func (this *Client) GetAllLazy(region string, keys interface{}) (map[interface{}]*connector.LazyValue, map[interface{}]error, error) {
+	return this.getAllLazy(this.connector, region, keys)
+}
+
+func (this *Client) getAllLazy(conn connector.Operations, region string, keys interface{}) (map[interface{}]*connector.LazyValue, map[interface{}]error, error) {
+	if err := this.authorize(OpGetAll, region); err != nil {
+		return nil, nil, err
+	}
+
+	physicalKeys, originals, err := this.transformKeys(region, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, failures, err := conn.GetAllLazy(region, physicalKeys)
+	if _, partial := err.(*connector.MultiError); (err != nil && !partial) || originals == nil {
+		return entries, failures, err
+	}
+
+	result := make(map[interface{}]*connector.LazyValue, len(entries))
+	for k, v := range entries {
+		key := originalKey(originals, k)
+		result[key] = v.Transform(func(value interface{}) (interface{}, error) {
+			return this.transformRead(region, key, value)
+		})
+	}
+
+	var resultFailures map[interface{}]error
+	for k, failure := range failures {
+		if resultFailures == nil {
+			resultFailures = make(map[interface{}]error, len(failures))
+		}
+		resultFailures[originalKey(originals, k)] = failure
+	}
+
+	return result, resultFailures, err
+}
+
+// Clear removes every entry of a region. See connector.Protobuf.Clear.
+func (this *Client) Clear(region string) error {
+	return this.clear(this.connector, region)
+}
+
+func (this *Client) clear(conn connector.Operations, region string) error {
+	if err := this.authorize(OpClear, region); err != nil {
+		return err
+	}
+
+	return conn.Clear(region)
+}
+
+// Keys returns the keys of a region, as they are stored; key transforms are not reversed.
+func (this *Client) Keys(region string) ([]interface{}, error) {
+	return this.keys(this.connector, region)
+}
+
+func (this *Client) keys(conn connector.Operations, region string) ([]interface{}, error) {
+	if err := this.authorize(OpKeys, region); err != nil {
+		return nil, err
+	}
+
+	return conn.Keys(region)
+}
+
+// KeysFunc passes each key of a region to fn until it returns false, holding less memory than
+// Keys for large regions. See connector.Protobuf.KeysFunc.
+func (this *Client) KeysFunc(region string, fn func(key interface{}) bool) error {
+	return this.keysFunc(this.connector, region, fn)
+}
+
+func (this *Client) keysFunc(conn connector.Operations, region string, fn func(key interface{}) bool) error {
+	if err := this.authorize(OpKeys, region); err != nil {
+		return err
+	}
+
+	return conn.KeysFunc(region, fn)
 }
 
 // Remove an entry for a region.
 func (this *Client) Remove(region string, key interface{}) error {
-	return this.connector.Remove(region, key)
+	return this.remove(this.connector, region, key)
+}
+
+func (this *Client) remove(conn connector.Operations, region string, key interface{}) error {
+	if err := this.authorize(OpRemove, region); err != nil {
+		return err
+	}
+
+	physicalKey, err := this.transformKey(region, key)
+	if err != nil {
+		return err
+	}
+	return conn.Remove(region, physicalKey)
+}
+
+// PutNull stores a null value for a key, keeping the entry, whereas Remove deletes it. Value
+// transforms are not applied.
+func (this *Client) PutNull(region string, key interface{}) error {
+	return this.putNull(this.connector, region, key)
+}
+
+func (this *Client) putNull(conn connector.Operations, region string, key interface{}) error {
+	if err := this.authorize(OpPut, region); err != nil {
+		return err
+	}
+
+	physicalKey, err := this.transformKey(region, key)
+	if err != nil {
+		return err
+	}
+	return conn.PutNull(region, physicalKey)
+}
+
+// RemoveAll removes many entries from a region. The keys must be passed as an array or slice.
+// The returned values are a map of keys which could not be removed and the associated error,
+// and a single error when the keys cannot be transformed or encoded, in which case nothing is
+// removed. Since the protocol has no bulk remove, each key is removed by its own request.
+func (this *Client) RemoveAll(region string, keys interface{}) (map[interface{}]error, error) {
+	return this.removeAll(this.connector, region, keys)
 }
 
-// Remove many entries from a region. The keys must be passed as an array or slice.
-// Currently still being implemented in Geode.
-//func (this *Client) RemoveAll(region string, keys interface{}) error {
-//	return this.connector.RemoveAll(region, keys)
-//}
+func (this *Client) removeAll(conn connector.Operations, region string, keys interface{}) (map[interface{}]error, error) {
+	if err := this.authorize(OpRemoveAll, region); err != nil {
+		return nil, err
+	}
+
+	keySlice := reflect.ValueOf(keys)
+	if len(this.transformsFor(region)) == 0 || (keySlice.Kind() != reflect.Slice && keySlice.Kind() != reflect.Array) {
+		return conn.RemoveAll(region, keys)
+	}
+
+	transformed := make([]interface{}, keySlice.Len())
+	originals := make(map[interface{}]interface{}, keySlice.Len())
+	for i := 0; i < keySlice.Len(); i++ {
+		physicalKey, err := this.transformKey(region, keySlice.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		if physicalKey == nil || !reflect.TypeOf(physicalKey).Comparable() {
+			return nil, errors.New(fmt.Sprintf("key transform for region %s produced a key of type %T, which cannot be used with RemoveAll", region, physicalKey))
+		}
+		transformed[i] = physicalKey
+		originals[physicalKey] = keySlice.Index(i).Interface()
+	}
+
+	failures, err := conn.RemoveAll(region, transformed)
+	if failures == nil || err != nil {
+		return failures, err
+	}
+
+	result := make(map[interface{}]error, len(failures))
+	for k, failure := range failures {
+		result[originalKey(originals, k)] = failure
+	}
+
+	return result, nil
+}
 
 // Size returns the number of entries in a region
 func (this *Client) Size(region string) (int32, error) {
-	return this.connector.Size(region)
+	return this.size(this.connector, region)
+}
+
+func (this *Client) size(conn connector.Operations, region string) (int32, error) {
+	if err := this.authorize(OpSize, region); err != nil {
+		return 0, err
+	}
+
+	return conn.Size(region)
 }
 
 // Execute a function on a region. This will execute on all members hosting the region and return a slice
-// of results; one entry for each member.
+// of results; one entry for each member. If keyFilter is given, the function executes only on
+// the members holding those keys, which may be codec.RoutedKeys to select entries by their
+// routing.
 func (this *Client) ExecuteOnRegion(functionId, region string, functionArgs interface{}, keyFilter []interface{}) ([]interface{}, error) {
-	return this.connector.ExecuteOnRegion(functionId, region, functionArgs, keyFilter)
+	return this.executeOnRegion(this.connector, functionId, region, functionArgs, keyFilter)
+}
+
+func (this *Client) executeOnRegion(conn connector.Operations, functionId, region string, functionArgs interface{}, keyFilter []interface{}) ([]interface{}, error) {
+	results := make([]interface{}, 0)
+	err := this.executeOnRegionFunc(conn, functionId, region, functionArgs, keyFilter, collectResults(&results))
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ExecuteOnRegionFunc executes a function as ExecuteOnRegion, passing each result to fn as it
+// is decoded, until fn returns false, rather than returning them all in a slice.
+func (this *Client) ExecuteOnRegionFunc(functionId, region string, functionArgs interface{}, keyFilter []interface{}, fn func(result interface{}) bool) error {
+	return this.executeOnRegionFunc(this.connector, functionId, region, functionArgs, keyFilter, fn)
+}
+
+func (this *Client) executeOnRegionFunc(conn connector.Operations, functionId, region string, functionArgs interface{}, keyFilter []interface{}, fn func(result interface{}) bool) error {
+	if err := this.authorize(OpFunction, region); err != nil {
+		return err
+	}
+	if err := this.checkFunction(functionId); err != nil {
+		return err
+	}
+
+	if len(keyFilter) > 0 && len(this.transformsFor(region)) > 0 {
+		physicalFilter := make([]interface{}, len(keyFilter))
+		for i, key := range keyFilter {
+			physicalKey, err := this.transformKey(region, key)
+			if err != nil {
+				return err
+			}
+			physicalFilter[i] = physicalKey
+		}
+		keyFilter = physicalFilter
+	}
+
+	return conn.ExecuteOnRegionFunc(functionId, region, functionArgs, keyFilter, fn)
 }
 
 // Execute a function on a list of members, returning a slice of results, one entry for each member.
 func (this *Client) ExecuteOnMembers(functionId string, members []string, functionArgs interface{}) ([]interface{}, error) {
-	return this.connector.ExecuteOnMembers(functionId, members, functionArgs)
+	return this.executeOnMembers(this.connector, functionId, members, functionArgs)
+}
+
+func (this *Client) executeOnMembers(conn connector.Operations, functionId string, members []string, functionArgs interface{}) ([]interface{}, error) {
+	results := make([]interface{}, 0)
+	err := this.executeOnMembersFunc(conn, functionId, members, functionArgs, collectResults(&results))
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ExecuteOnMembersFunc executes a function as ExecuteOnMembers, passing each result to fn as
+// ExecuteOnRegionFunc does.
+func (this *Client) ExecuteOnMembersFunc(functionId string, members []string, functionArgs interface{}, fn func(result interface{}) bool) error {
+	return this.executeOnMembersFunc(this.connector, functionId, members, functionArgs, fn)
+}
+
+func (this *Client) executeOnMembersFunc(conn connector.Operations, functionId string, members []string, functionArgs interface{}, fn func(result interface{}) bool) error {
+	if err := this.authorize(OpFunction, ""); err != nil {
+		return err
+	}
+	if err := this.checkFunction(functionId); err != nil {
+		return err
+	}
+
+	return conn.ExecuteOnMembersFunc(functionId, members, functionArgs, fn)
 }
 
 // Execute a function on a list of group. This will execute on each member associated with the groups;
 // returning a slice of results, one entry for each member.
 func (this *Client) ExecuteOnGroups(functionId string, groups []string, functionArgs interface{}) ([]interface{}, error) {
-	return this.connector.ExecuteOnGroups(functionId, groups, functionArgs)
+	return this.executeOnGroups(this.connector, functionId, groups, functionArgs)
+}
+
+func (this *Client) executeOnGroups(conn connector.Operations, functionId string, groups []string, functionArgs interface{}) ([]interface{}, error) {
+	results := make([]interface{}, 0)
+	err := this.executeOnGroupsFunc(conn, functionId, groups, functionArgs, collectResults(&results))
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ExecuteOnGroupsFunc executes a function as ExecuteOnGroups, passing each result to fn as
+// ExecuteOnRegionFunc does.
+func (this *Client) ExecuteOnGroupsFunc(functionId string, groups []string, functionArgs interface{}, fn func(result interface{}) bool) error {
+	return this.executeOnGroupsFunc(this.connector, functionId, groups, functionArgs, fn)
+}
+
+func (this *Client) executeOnGroupsFunc(conn connector.Operations, functionId string, groups []string, functionArgs interface{}, fn func(result interface{}) bool) error {
+	if err := this.authorize(OpFunction, ""); err != nil {
+		return err
+	}
+	if err := this.checkFunction(functionId); err != nil {
+		return err
+	}
+
+	return conn.ExecuteOnGroupsFunc(functionId, groups, functionArgs, fn)
+}
+
+func collectResults(results *[]interface{}) func(result interface{}) bool {
+	return func(result interface{}) bool {
+		*results = append(*results, result)
+		return true
+	}
 }
 
 // Execute a query, returning a single result value.
-func (this *Client) QueryForSingleResult(query *Query) (interface{}, error){
-	return this.connector.QuerySingleResult(query)
+func (this *Client) QueryForSingleResult(query *Query) (interface{}, error) {
+	return this.queryForSingleResult(this.connector, query)
+}
+
+func (this *Client) queryForSingleResult(conn connector.Operations, query *Query) (interface{}, error) {
+	if err := this.authorize(OpQuery, ""); err != nil {
+		return nil, err
+	}
+
+	return conn.QuerySingleResult(query)
 }
 
 // Execute a query, returning a list of results.
-func (this *Client) QueryForListResult(query *Query) ([]interface{}, error){
-	return this.connector.QueryListResult(query)
+func (this *Client) QueryForListResult(query *Query) ([]interface{}, error) {
+	return this.queryForListResult(this.connector, query)
+}
+
+func (this *Client) queryForListResult(conn connector.Operations, query *Query) ([]interface{}, error) {
+	if err := this.authorize(OpQuery, ""); err != nil {
+		return nil, err
+	}
+
+	return conn.QueryListResult(query)
 }
 
 // Execute a query, returning a map of column (or field) names and the associated values for each column.
-func (this *Client) QueryForTableResult(query *Query) (map[string][]interface{}, error){
-	return this.connector.QueryTableResult(query)
+func (this *Client) QueryForTableResult(query *Query) (map[string][]interface{}, error) {
+	return this.queryForTableResult(this.connector, query)
+}
+
+func (this *Client) queryForTableResult(conn connector.Operations, query *Query) (map[string][]interface{}, error) {
+	if err := this.authorize(OpQuery, ""); err != nil {
+		return nil, err
+	}
+
+	return conn.QueryTableResult(query)
+}
+
+// Execute a query, returning a list of results which are decoded only when used.
+func (this *Client) QueryForListResultLazy(query *Query) ([]*connector.LazyValue, error) {
+	return this.queryForListResultLazy(this.connector, query)
+}
+
+func (this *Client) queryForListResultLazy(conn connector.Operations, query *Query) ([]*connector.LazyValue, error) {
+	if err := this.authorize(OpQuery, ""); err != nil {
+		return nil, err
+	}
+
+	return conn.QueryListResultLazy(query)
+}
+
+// Execute a query, returning a map of column names and their values, which are decoded only
+// when used.
+func (this *Client) QueryForTableResultLazy(query *Query) (map[string][]*connector.LazyValue, error) {
+	return this.queryForTableResultLazy(this.connector, query)
+}
+
+func (this *Client) queryForTableResultLazy(conn connector.Operations, query *Query) (map[string][]*connector.LazyValue, error) {
+	if err := this.authorize(OpQuery, ""); err != nil {
+		return nil, err
+	}
+
+	return conn.QueryTableResultLazy(query)
+}
+
+// Execute a query, returning an iterator over its results which decodes one row at a time.
+func (this *Client) QueryRows(query *Query) (*connector.Rows, error) {
+	return this.queryRows(this.connector, query)
+}
+
+func (this *Client) queryRows(conn connector.Operations, query *Query) (*connector.Rows, error) {
+	if err := this.authorize(OpQuery, ""); err != nil {
+		return nil, err
+	}
+
+	return conn.QueryRows(query)
 }
 
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	geode "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+// Outcomes of a check
+const (
+	statusPass = "PASS"
+	statusWarn = "WARN"
+	statusFail = "FAIL"
+	statusSkip = "SKIP"
+)
+
+// The outcome of one check against a server, or against the cluster
+type result struct {
+	status string
+	check  string
+	target string
+	detail string
+}
+
+// A doctor checks, step by step, that a client configured by config can use the cluster: that
+// each server's name resolves, that it accepts TCP connections, that its TLS certificate is
+// valid, that it accepts the protocol handshake and the credentials and, if a region is
+// given, that the region exists and an entry can be written, read back and removed. Each step
+// is only checked once those it depends on have passed, so the first failure reported for a
+// server is the one to fix.
+type doctor struct {
+	config    *connector.Config
+	tlsConfig *tls.Config
+	region    string
+	timeout   time.Duration
+	// Certificates expiring within this period are reported as warnings
+	expiryWarning time.Duration
+
+	lookupHost func(host string) ([]string, error)
+	now        func() time.Time
+	// Connects to the servers which passed their checks, for the cluster's checks
+	connect func(servers []string) (connector.Operations, error)
+
+	results []result
+}
+
+func newDoctor(config *connector.Config, tlsConfig *tls.Config, region string, timeout time.Duration) *doctor {
+	this := &doctor{
+		config:        config,
+		tlsConfig:     tlsConfig,
+		region:        region,
+		timeout:       timeout,
+		expiryWarning: 30 * 24 * time.Hour,
+		lookupHost:    net.LookupHost,
+		now:           time.Now,
+	}
+	this.connect = func(servers []string) (connector.Operations, error) {
+		pool, err := this.pool(servers)
+		if err != nil {
+			return nil, err
+		}
+		return connector.NewConnector(pool), nil
+	}
+	return this
+}
+
+// Run every check, returning whether none failed
+func (this *doctor) run() bool {
+	healthy := make([]string, 0, len(this.config.Servers))
+	for _, server := range this.config.Servers {
+		if this.checkServer(server) {
+			healthy = append(healthy, server)
+		}
+	}
+
+	if this.region == "" {
+		this.report(statusSkip, "region", "", "no region given")
+		this.report(statusSkip, "canary", "", "no region given")
+	} else if len(healthy) == 0 {
+		this.report(statusSkip, "region", this.region, "no server passed its checks")
+		this.report(statusSkip, "canary", this.region, "no server passed its checks")
+	} else {
+		this.checkRegion(healthy)
+	}
+
+	for _, r := range this.results {
+		if r.status == statusFail {
+			return false
+		}
+	}
+	return true
+}
+
+func (this *doctor) report(status, check, target, detail string) {
+	this.results = append(this.results, result{status: status, check: check, target: target, detail: detail})
+}
+
+// Report the checks a server did not reach because an earlier one failed
+func (this *doctor) skip(server string, checks ...string) {
+	for _, check := range checks {
+		this.report(statusSkip, check, server, "an earlier check failed")
+	}
+}
+
+// Check one server, returning whether it can be used
+func (this *doctor) checkServer(server string) bool {
+	host, _, err := net.SplitHostPort(server)
+	if err != nil {
+		this.report(statusFail, "dns", server, err.Error())
+		this.skip(server, "tcp", "tls", "handshake", "auth")
+		return false
+	}
+
+	addresses, err := this.lookupHost(host)
+	if err != nil {
+		this.report(statusFail, "dns", server, err.Error())
+		this.skip(server, "tcp", "tls", "handshake", "auth")
+		return false
+	}
+	this.report(statusPass, "dns", server, fmt.Sprintf("resolves to %v", addresses))
+
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", server, this.timeout)
+	if err != nil {
+		this.report(statusFail, "tcp", server, err.Error())
+		this.skip(server, "tls", "handshake", "auth")
+		return false
+	}
+	conn.Close()
+	this.report(statusPass, "tcp", server, fmt.Sprintf("connected in %s", time.Since(start).Round(time.Millisecond)))
+
+	if !this.checkTLS(server, host) {
+		this.skip(server, "handshake", "auth")
+		return false
+	}
+
+	return this.checkHandshake(server)
+}
+
+// Check the certificate a server presents, if the client uses TLS
+func (this *doctor) checkTLS(server, host string) bool {
+	if this.tlsConfig == nil {
+		this.report(statusSkip, "tls", server, "TLS is not configured")
+		return true
+	}
+
+	config := this.tlsConfig.Clone()
+	if config.ServerName == "" {
+		config.ServerName = host
+	}
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: this.timeout}, "tcp", server, config)
+	if err != nil {
+		this.report(statusFail, "tls", server, err.Error())
+		return false
+	}
+	defer conn.Close()
+
+	certificates := conn.ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		this.report(statusFail, "tls", server, "the server presented no certificate")
+		return false
+	}
+
+	certificate := certificates[0]
+	remaining := certificate.NotAfter.Sub(this.now())
+	detail := fmt.Sprintf("%s, issued by %s, expires %s", certificate.Subject.CommonName,
+		certificate.Issuer.CommonName, certificate.NotAfter.Format(time.RFC3339))
+	if remaining < this.expiryWarning {
+		this.report(statusWarn, "tls", server, fmt.Sprintf("%s, in %d days", detail, int(remaining.Hours()/24)))
+	} else {
+		this.report(statusPass, "tls", server, detail)
+	}
+	return true
+}
+
+// Check that a server accepts the handshake and the credentials, telling a rejected
+// handshake from rejected credentials
+func (this *doctor) checkHandshake(server string) bool {
+	pool, err := this.pool([]string{server})
+	if err != nil {
+		this.report(statusFail, "handshake", server, err.Error())
+		this.skip(server, "auth")
+		return false
+	}
+
+	gConn, err := pool.GetConnection()
+	if err == nil {
+		pool.DiscardConnection(gConn)
+	}
+
+	switch {
+	case err == nil:
+		this.report(statusPass, "handshake", server, "protocol version accepted")
+	case connector.IsAuthenticationFailed(err):
+		this.report(statusPass, "handshake", server, "protocol version accepted")
+		this.report(statusFail, "auth", server, err.Error())
+		return false
+	default:
+		this.report(statusFail, "handshake", server, err.Error())
+		this.skip(server, "auth")
+		return false
+	}
+
+	if this.config.Username == nil || *this.config.Username == "" {
+		this.report(statusSkip, "auth", server, "no credentials configured")
+	} else {
+		this.report(statusPass, "auth", server, fmt.Sprintf("authenticated as %s", *this.config.Username))
+	}
+	return true
+}
+
+// Check that the region exists, and that an entry can be written, read back and removed
+func (this *doctor) checkRegion(servers []string) {
+	ops, err := this.connect(servers)
+	if err != nil {
+		this.report(statusFail, "region", this.region, err.Error())
+		this.report(statusSkip, "canary", this.region, "an earlier check failed")
+		return
+	}
+	client := geode.NewGeodeClient(ops)
+
+	info, err := client.GetRegion(this.region)
+	if err != nil {
+		if connector.IsRegionNotFound(err) {
+			this.report(statusFail, "region", this.region, "the region does not exist")
+		} else {
+			this.report(statusFail, "region", this.region, err.Error())
+		}
+		this.report(statusSkip, "canary", this.region, "an earlier check failed")
+		return
+	}
+	this.report(statusPass, "region", this.region, fmt.Sprintf("%d entries", info.Size))
+
+	hostname, _ := os.Hostname()
+	key := "geode-doctor:" + hostname + ":" + strconv.Itoa(os.Getpid())
+	value := strconv.FormatInt(this.now().UnixNano(), 10)
+
+	start := time.Now()
+	if err := client.Put(this.region, key, value); err != nil {
+		this.report(statusFail, "canary", this.region, "put failed: "+err.Error())
+		return
+	}
+	read, err := client.Get(this.region, key)
+	client.Remove(this.region, key)
+	if err != nil {
+		this.report(statusFail, "canary", this.region, "get failed: "+err.Error())
+		return
+	}
+	if !reflect.DeepEqual(read, value) {
+		this.report(statusFail, "canary", this.region, fmt.Sprintf("read %v back, having written %v", read, value))
+		return
+	}
+	this.report(statusPass, "canary", this.region, fmt.Sprintf("put, get and remove of %s took %s", key, time.Since(start).Round(time.Millisecond)))
+}
+
+// Create a pool connecting to servers with the doctor's credentials and TLS configuration
+func (this *doctor) pool(servers []string) (*connector.Pool, error) {
+	config := *this.config
+	config.Servers = servers
+	timeout := connector.Duration(this.timeout)
+	if config.ConnectTimeout == nil {
+		config.ConnectTimeout = &timeout
+	}
+	if config.ReadTimeout == nil {
+		config.ReadTimeout = &timeout
+	}
+	if config.WriteTimeout == nil {
+		config.WriteTimeout = &timeout
+	}
+
+	pool := connector.NewPool()
+	pool.SetTLSConfig(this.tlsConfig)
+	if err := pool.Configure(&config); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// Write the results as a table, followed by a count of each outcome
+func (this *doctor) print(w io.Writer) {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, r := range this.results {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", r.status, r.check, r.target, r.detail)
+	}
+	table.Flush()
+
+	counts := make(map[string]int)
+	for _, r := range this.results {
+		counts[r.status]++
+	}
+	fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed, %d skipped\n",
+		counts[statusPass], counts[statusWarn], counts[statusFail], counts[statusSkip])
+}
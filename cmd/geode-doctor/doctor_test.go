@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// A region held in memory, implementing only the operations the doctor uses
+type memoryRegion struct {
+	connector.Operations
+	entries map[interface{}]interface{}
+	missing bool
+}
+
+func (this *memoryRegion) Size(region string) (int32, error) {
+	if this.missing {
+		return 0, &connector.ServerError{Code: v1.ErrorCode_SERVER_ERROR, Message: fmt.Sprintf("Region \"%s\" not found", region)}
+	}
+	return int32(len(this.entries)), nil
+}
+
+func (this *memoryRegion) Put(region string, k, v interface{}) error {
+	this.entries[k] = v
+	return nil
+}
+
+func (this *memoryRegion) Get(region string, k interface{}, value interface{}) (interface{}, error) {
+	return this.entries[k], nil
+}
+
+func (this *memoryRegion) Remove(region string, k interface{}) error {
+	delete(this.entries, k)
+	return nil
+}
+
+var _ = Describe("Doctor", func() {
+
+	var d *doctor
+
+	resolved := func(host string) ([]string, error) {
+		return []string{"127.0.0.1"}, nil
+	}
+
+	statuses := func() map[string]string {
+		checks := make(map[string]string)
+		for _, r := range d.results {
+			checks[r.check] = r.status
+		}
+		return checks
+	}
+
+	newServerDoctor := func(server string, region string) *doctor {
+		return newDoctor(&connector.Config{Servers: []string{server}}, nil, region, time.Second)
+	}
+
+	It("stops at a server whose name does not resolve", func() {
+		d = newServerDoctor("nowhere.invalid:40404", "")
+		d.lookupHost = func(host string) ([]string, error) {
+			return nil, errors.New("no such host")
+		}
+
+		Expect(d.run()).To(BeFalse())
+		Expect(statuses()).To(Equal(map[string]string{
+			"dns": statusFail, "tcp": statusSkip, "tls": statusSkip, "handshake": statusSkip,
+			"auth": statusSkip, "region": statusSkip, "canary": statusSkip,
+		}))
+	})
+
+	It("reports a server which refuses connections", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		server := listener.Addr().String()
+		listener.Close()
+
+		d = newServerDoctor(server, "")
+		d.lookupHost = resolved
+
+		Expect(d.run()).To(BeFalse())
+		Expect(statuses()).To(HaveKeyWithValue("dns", statusPass))
+		Expect(statuses()).To(HaveKeyWithValue("tcp", statusFail))
+		Expect(statuses()).To(HaveKeyWithValue("handshake", statusSkip))
+	})
+
+	It("reports a server which does not accept the handshake", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		defer listener.Close()
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				conn.Close()
+			}
+		}()
+
+		d = newServerDoctor(listener.Addr().String(), "Canary")
+		d.lookupHost = resolved
+
+		Expect(d.run()).To(BeFalse())
+		Expect(statuses()).To(HaveKeyWithValue("tcp", statusPass))
+		Expect(statuses()).To(HaveKeyWithValue("tls", statusSkip))
+		Expect(statuses()).To(HaveKeyWithValue("handshake", statusFail))
+		Expect(statuses()).To(HaveKeyWithValue("canary", statusSkip))
+	})
+
+	It("checks the server's certificate and warns before it expires", func() {
+		server := httptest.NewTLSServer(http.NotFoundHandler())
+		defer server.Close()
+		roots := x509.NewCertPool()
+		roots.AddCert(server.Certificate())
+		address := server.Listener.Addr().String()
+
+		d = newDoctor(&connector.Config{Servers: []string{address}}, &tls.Config{RootCAs: roots}, "", time.Second)
+		Expect(d.checkTLS(address, "127.0.0.1")).To(BeTrue())
+		Expect(d.results[0].status).To(Equal(statusPass))
+
+		d.now = func() time.Time { return server.Certificate().NotAfter.Add(-24 * time.Hour) }
+		Expect(d.checkTLS(address, "127.0.0.1")).To(BeTrue())
+		Expect(d.results[1].status).To(Equal(statusWarn))
+
+		d.tlsConfig = &tls.Config{}
+		Expect(d.checkTLS(address, "127.0.0.1")).To(BeFalse())
+		Expect(d.results[2].status).To(Equal(statusFail))
+	})
+
+	It("writes, reads back and removes a canary entry", func() {
+		region := &memoryRegion{entries: map[interface{}]interface{}{"A": 1}}
+		d = newServerDoctor("localhost:40404", "Canary")
+		d.connect = func(servers []string) (connector.Operations, error) {
+			return region, nil
+		}
+
+		d.checkRegion([]string{"localhost:40404"})
+		Expect(statuses()).To(Equal(map[string]string{"region": statusPass, "canary": statusPass}))
+		Expect(d.results[0].detail).To(Equal("1 entries"))
+		Expect(region.entries).To(HaveLen(1))
+	})
+
+	It("reports a missing region", func() {
+		d = newServerDoctor("localhost:40404", "Canary")
+		d.connect = func(servers []string) (connector.Operations, error) {
+			return &memoryRegion{missing: true}, nil
+		}
+
+		d.checkRegion([]string{"localhost:40404"})
+		Expect(d.results[0]).To(Equal(result{statusFail, "region", "Canary", "the region does not exist"}))
+		Expect(statuses()).To(HaveKeyWithValue("canary", statusSkip))
+	})
+
+	It("prints a table of the results and a summary", func() {
+		d = newServerDoctor("localhost:40404", "")
+		d.report(statusPass, "dns", "localhost:40404", "resolves to [127.0.0.1]")
+		d.report(statusFail, "tcp", "localhost:40404", "connection refused")
+
+		out := &bytes.Buffer{}
+		d.print(out)
+		Expect(out.String()).To(Equal("PASS  dns  localhost:40404  resolves to [127.0.0.1]\n" +
+			"FAIL  tcp  localhost:40404  connection refused\n" +
+			"\n1 passed, 0 warnings, 1 failed, 0 skipped\n"))
+	})
+})
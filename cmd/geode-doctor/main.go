@@ -0,0 +1,72 @@
+// Command geode-doctor checks that a client can use a Geode cluster, to take the guesswork out
+// of setting up a new environment. For each server it checks that the name resolves, that
+// the server accepts TCP connections, that its TLS certificate is valid and not about to
+// expire, and that it accepts the protocol handshake and the credentials. Given a region, it
+// then checks that the region exists and that an entry can be put, read back and removed.
+//
+// Usage:
+//
+//	geode-doctor [-config geode.json] [-servers localhost:40404] [-username jbloggs]
+//		[-region Canary] [-tls-ca ca.pem [-tls-cert client.pem -tls-key client-key.pem]]
+//
+// The configuration file is the JSON read by connector.FileConfigSource; flags override it.
+// The password, if any, is read from the GEODE_PASSWORD environment variable so that it does
+// not appear in the process list. The command exits with status 1 if any check fails.
+package main
+
+import (
+	"crypto/tls"
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+func main() {
+	configFile := flag.String("config", "", "JSON configuration file, as read by a client")
+	servers := flag.String("servers", "", "comma separated Geode servers, as host:port")
+	username := flag.String("username", "", "user to authenticate as, with the password in GEODE_PASSWORD")
+	region := flag.String("region", "", "region to check and to write a canary entry to")
+	caFile := flag.String("tls-ca", "", "PEM certificates to verify servers with, enabling TLS")
+	certFile := flag.String("tls-cert", "", "PEM client certificate")
+	keyFile := flag.String("tls-key", "", "PEM client key")
+	timeout := flag.Duration("timeout", 5*time.Second, "time allowed for each check")
+	flag.Parse()
+
+	config := &connector.Config{}
+	if *configFile != "" {
+		var err error
+		if config, err = connector.FileConfigSource(*configFile).Load(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *servers != "" {
+		config.Servers = strings.Split(*servers, ",")
+	}
+	if len(config.Servers) == 0 {
+		config.Servers = []string{"localhost:40404"}
+	}
+	if *username != "" {
+		password := os.Getenv("GEODE_PASSWORD")
+		config.Username = username
+		config.Password = &password
+	}
+
+	var tlsConfig *tls.Config
+	if *caFile != "" || *certFile != "" {
+		var err error
+		if tlsConfig, err = connector.LoadTLSConfig(*caFile, *certFile, *keyFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	d := newDoctor(config, tlsConfig, *region, *timeout)
+	healthy := d.run()
+	d.print(os.Stdout)
+	if !healthy {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,59 @@
+// Command geode-memcached serves a Geode region over the memcached text protocol, so that
+// applications written against memcached can read and write Geode unchanged.
+//
+// Usage:
+//
+//	geode-memcached -servers localhost:40404 -region Cache [-listen :11211]
+//
+// The password, if any, is read from the GEODE_PASSWORD environment variable so that it does
+// not appear in the process list.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"strings"
+
+	geode "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+func main() {
+	listen := flag.String("listen", ":11211", "address to accept memcached clients on")
+	servers := flag.String("servers", "localhost:40404", "comma separated Geode servers, as host:port")
+	region := flag.String("region", "", "region holding the entries")
+	username := flag.String("username", "", "user to authenticate as, with the password in GEODE_PASSWORD")
+	maxItemSize := flag.Int("max-item-size", defaultMaxItemSize, "largest value accepted, in bytes")
+	flag.Parse()
+
+	if *region == "" {
+		log.Fatal("a region is required")
+	}
+
+	config := &connector.Config{Servers: strings.Split(*servers, ",")}
+	if *username != "" {
+		password := os.Getenv("GEODE_PASSWORD")
+		config.Username = username
+		config.Password = &password
+	}
+
+	pool := connector.NewPool()
+	if err := pool.Configure(config); err != nil {
+		log.Fatal(err)
+	}
+
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("serving region %s on %s", *region, listener.Addr())
+
+	s := &server{
+		client:      geode.NewGeodeClient(connector.NewConnector(pool)),
+		region:      *region,
+		maxItemSize: *maxItemSize,
+	}
+	log.Fatal(s.serve(listener))
+}
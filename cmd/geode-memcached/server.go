@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+
+	geode "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+// The memcached default
+const defaultMaxItemSize = 1024 * 1024
+
+// Longest key memcached accepts
+const maxKeyLength = 250
+
+// A server answers memcached text protocol commands from the entries of a region, whose
+// values are stored as bytes. Flags are not stored, so only 0 is accepted, and expiration
+// times are ignored in favour of the region's own expiration. Geode cannot compare and swap,
+// so gets reports a CAS value of 0 and cas is not supported.
+type server struct {
+	client      *geode.Client
+	region      string
+	maxItemSize int
+}
+
+func (this *server) serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			defer conn.Close()
+			if err := this.serveConn(conn); err != nil && err != io.EOF {
+				log.Printf("%s: %s", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// Answer commands until the client quits or the connection fails
+func (this *server) serveConn(conn io.ReadWriter) error {
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+
+	for {
+		line, err := reader.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			writer.WriteString("CLIENT_ERROR line too long\r\n")
+			return writer.Flush()
+		}
+		if err != nil {
+			return err
+		}
+
+		fields := strings.Fields(string(line))
+		if len(fields) > 0 && fields[0] == "quit" {
+			return writer.Flush()
+		}
+
+		if len(fields) == 0 {
+			writer.WriteString("ERROR\r\n")
+		} else if err := this.command(fields[0], fields[1:], reader, writer); err != nil {
+			return err
+		}
+
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// Run a command, returning an error only if the connection can no longer be used
+func (this *server) command(name string, args []string, reader *bufio.Reader, writer *bufio.Writer) error {
+	switch name {
+	case "get", "gets":
+		this.get(args, name == "gets", writer)
+	case "set", "add":
+		return this.store(name, args, reader, writer)
+	case "delete":
+		this.delete(args, writer)
+	case "version":
+		fmt.Fprintf(writer, "VERSION geode-memcached-%s\r\n", connector.LibraryVersion)
+	default:
+		writer.WriteString("ERROR\r\n")
+	}
+
+	return nil
+}
+
+func (this *server) get(keys []string, cas bool, writer *bufio.Writer) {
+	if len(keys) == 0 {
+		writer.WriteString("ERROR\r\n")
+		return
+	}
+
+	// Buffer the values so that a failure part way is reported alone
+	var values bytes.Buffer
+	for _, key := range keys {
+		result, err := this.client.GetOptional(this.region, key)
+		if err != nil {
+			serverError(writer, err)
+			return
+		}
+		if !result.Present {
+			continue
+		}
+
+		data, err := valueBytes(result.Value)
+		if err != nil {
+			serverError(writer, err)
+			return
+		}
+
+		fmt.Fprintf(&values, "VALUE %s 0 %d", key, len(data))
+		if cas {
+			values.WriteString(" 0")
+		}
+		values.WriteString("\r\n")
+		values.Write(data)
+		values.WriteString("\r\n")
+	}
+
+	values.WriteTo(writer)
+	writer.WriteString("END\r\n")
+}
+
+// Handle set and add, whose arguments are <key> <flags> <exptime> <bytes> [noreply]
+func (this *server) store(name string, args []string, reader *bufio.Reader, writer *bufio.Writer) error {
+	if len(args) < 4 || len(args) > 5 {
+		writer.WriteString("ERROR\r\n")
+		return nil
+	}
+
+	size, err := strconv.Atoi(args[3])
+	if err != nil || size < 0 {
+		writer.WriteString("CLIENT_ERROR bad data chunk\r\n")
+		return nil
+	}
+	if size > this.maxItemSize {
+		// The data cannot be skipped safely, so give up on the connection
+		writer.WriteString("SERVER_ERROR object too large for cache\r\n")
+		writer.Flush()
+		return errors.New(fmt.Sprintf("value of %d bytes exceeds the limit of %d", size, this.maxItemSize))
+	}
+
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(data, []byte("\r\n")) {
+		writer.WriteString("CLIENT_ERROR bad data chunk\r\n")
+		return nil
+	}
+	data = data[:size]
+
+	key := args[0]
+	noreply := len(args) == 5 && args[4] == "noreply"
+	reply := func(response string) {
+		if !noreply {
+			writer.WriteString(response)
+		}
+	}
+
+	if len(key) > maxKeyLength {
+		reply("CLIENT_ERROR key too long\r\n")
+		return nil
+	}
+	if flags, err := strconv.ParseUint(args[1], 10, 32); err != nil || flags != 0 {
+		reply("CLIENT_ERROR flags are not supported\r\n")
+		return nil
+	}
+	if _, err := strconv.ParseInt(args[2], 10, 64); err != nil {
+		reply("CLIENT_ERROR bad command line format\r\n")
+		return nil
+	}
+
+	if name == "set" {
+		err = this.client.Put(this.region, key, data)
+		if err != nil {
+			reply(serverErrorLine(err))
+		} else {
+			reply("STORED\r\n")
+		}
+		return nil
+	}
+
+	stored, err := this.add(key, data)
+	if err != nil {
+		reply(serverErrorLine(err))
+	} else if stored {
+		reply("STORED\r\n")
+	} else {
+		reply("NOT_STORED\r\n")
+	}
+	return nil
+}
+
+// Put a value if the key has no entry. The protocol does not report whether PutIfAbsent
+// stored the value, so it is read back; a value equal to the existing one counts as stored.
+func (this *server) add(key string, data []byte) (bool, error) {
+	if err := this.client.PutIfAbsent(this.region, key, data); err != nil {
+		return false, err
+	}
+
+	result, err := this.client.GetOptional(this.region, key)
+	if err != nil {
+		return false, err
+	}
+	if !result.Present {
+		// Removed since
+		return true, nil
+	}
+
+	existing, err := valueBytes(result.Value)
+	if err != nil {
+		return false, nil
+	}
+
+	return bytes.Equal(existing, data), nil
+}
+
+// Handle delete, whose arguments are <key> [noreply]. The protocol does not report whether
+// Remove found an entry, so the key is looked up first.
+func (this *server) delete(args []string, writer *bufio.Writer) {
+	if len(args) < 1 || len(args) > 2 {
+		writer.WriteString("ERROR\r\n")
+		return
+	}
+
+	noreply := len(args) == 2 && args[1] == "noreply"
+	reply := func(response string) {
+		if !noreply {
+			writer.WriteString(response)
+		}
+	}
+
+	result, err := this.client.GetOptional(this.region, args[0])
+	if err != nil {
+		reply(serverErrorLine(err))
+		return
+	}
+	if !result.Present {
+		reply("NOT_FOUND\r\n")
+		return
+	}
+
+	if err := this.client.Remove(this.region, args[0]); err != nil {
+		reply(serverErrorLine(err))
+		return
+	}
+	reply("DELETED\r\n")
+}
+
+// Return the bytes of a value, which other clients may have written as a string
+func valueBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, errors.New(fmt.Sprintf("value of type %T cannot be served", value))
+	}
+}
+
+func serverError(writer *bufio.Writer, err error) {
+	writer.WriteString(serverErrorLine(err))
+}
+
+// memcached responses are single lines
+func serverErrorLine(err error) string {
+	message := strings.Replace(err.Error(), "\r", " ", -1)
+	message = strings.Replace(message, "\n", " ", -1)
+	return "SERVER_ERROR " + message + "\r\n"
+}
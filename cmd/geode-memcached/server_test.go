@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	geode "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// A memoryRegion stores entries in memory, implementing only the operations the server uses.
+type memoryRegion struct {
+	connector.Operations
+	sync.Mutex
+	entries map[string]interface{}
+	fail    error
+}
+
+func (this *memoryRegion) Put(region string, k, v interface{}) error {
+	this.Lock()
+	defer this.Unlock()
+
+	if this.fail != nil {
+		return this.fail
+	}
+	this.entries[fmt.Sprint(k)] = v
+	return nil
+}
+
+func (this *memoryRegion) PutIfAbsent(region string, k, v interface{}) error {
+	this.Lock()
+	defer this.Unlock()
+
+	if _, ok := this.entries[fmt.Sprint(k)]; !ok {
+		this.entries[fmt.Sprint(k)] = v
+	}
+	return nil
+}
+
+func (this *memoryRegion) GetOptional(region string, k interface{}, value interface{}) (connector.Optional, error) {
+	this.Lock()
+	defer this.Unlock()
+
+	if this.fail != nil {
+		return connector.Optional{}, this.fail
+	}
+	v, ok := this.entries[fmt.Sprint(k)]
+	return connector.Optional{Value: v, Present: ok}, nil
+}
+
+func (this *memoryRegion) Remove(region string, k interface{}) error {
+	this.Lock()
+	defer this.Unlock()
+
+	delete(this.entries, fmt.Sprint(k))
+	return nil
+}
+
+// Return a stored value
+func (this *memoryRegion) value(k string) interface{} {
+	this.Lock()
+	defer this.Unlock()
+
+	return this.entries[k]
+}
+
+func (this *memoryRegion) failWith(err error) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.fail = err
+}
+
+func (this *memoryRegion) BindContext(ctx context.Context) connector.Operations {
+	return this
+}
+
+func (this *memoryRegion) BindTimeout(timeout time.Duration) connector.Operations {
+	return this
+}
+
+func (this *memoryRegion) BindResultRef(newRef func() interface{}) connector.Operations {
+	return this
+}
+
+var _ = Describe("Server", func() {
+
+	var region *memoryRegion
+	var conn net.Conn
+	var reader *bufio.Reader
+	var done chan error
+
+	BeforeEach(func() {
+		region = &memoryRegion{entries: make(map[string]interface{})}
+		s := &server{
+			client:      geode.NewGeodeClient(region),
+			region:      "Cache",
+			maxItemSize: 16,
+		}
+
+		var serverConn net.Conn
+		conn, serverConn = net.Pipe()
+		reader = bufio.NewReader(conn)
+		finished := make(chan error, 1)
+		done = finished
+		go func() {
+			finished <- s.serveConn(serverConn)
+			serverConn.Close()
+		}()
+	})
+
+	AfterEach(func() {
+		conn.Close()
+	})
+
+	// Send a command and read the given number of response lines
+	send := func(command string, lines int) []string {
+		_, err := conn.Write([]byte(command))
+		Expect(err).ToNot(HaveOccurred())
+
+		response := make([]string, 0, lines)
+		for i := 0; i < lines; i++ {
+			line, err := reader.ReadString('\n')
+			Expect(err).ToNot(HaveOccurred())
+			response = append(response, line)
+		}
+		return response
+	}
+
+	It("sets and gets values", func() {
+		Expect(send("set A 0 0 5\r\napple\r\n", 1)).To(Equal([]string{"STORED\r\n"}))
+
+		Expect(send("get A B\r\n", 3)).To(Equal([]string{"VALUE A 0 5\r\n", "apple\r\n", "END\r\n"}))
+		Expect(region.value("A")).To(Equal([]byte("apple")))
+	})
+
+	It("gets values with a CAS value of 0", func() {
+		send("set A 0 0 5\r\napple\r\n", 1)
+
+		Expect(send("gets A\r\n", 3)).To(Equal([]string{"VALUE A 0 5 0\r\n", "apple\r\n", "END\r\n"}))
+	})
+
+	It("serves string values written by other clients", func() {
+		region.entries["A"] = "apple"
+
+		Expect(send("get A\r\n", 3)).To(Equal([]string{"VALUE A 0 5\r\n", "apple\r\n", "END\r\n"}))
+	})
+
+	It("only adds absent values", func() {
+		Expect(send("add A 0 0 5\r\napple\r\n", 1)).To(Equal([]string{"STORED\r\n"}))
+		Expect(send("add A 0 0 7\r\navocado\r\n", 1)).To(Equal([]string{"NOT_STORED\r\n"}))
+
+		Expect(region.value("A")).To(Equal([]byte("apple")))
+	})
+
+	It("deletes values", func() {
+		send("set A 0 0 5\r\napple\r\n", 1)
+
+		Expect(send("delete A\r\n", 1)).To(Equal([]string{"DELETED\r\n"}))
+		Expect(send("delete A\r\n", 1)).To(Equal([]string{"NOT_FOUND\r\n"}))
+	})
+
+	It("does not reply to noreply commands", func() {
+		send("set A 0 0 5 noreply\r\napple\r\n", 0)
+
+		Expect(send("get A\r\n", 3)).To(Equal([]string{"VALUE A 0 5\r\n", "apple\r\n", "END\r\n"}))
+	})
+
+	It("rejects flags, which are not stored", func() {
+		Expect(send("set A 1 0 5\r\napple\r\n", 1)).To(Equal([]string{"CLIENT_ERROR flags are not supported\r\n"}))
+		Expect(region.value("A")).To(BeNil())
+	})
+
+	It("rejects bad data chunks", func() {
+		Expect(send("set A 0 0 3\r\napple\r\n", 1)).To(Equal([]string{"CLIENT_ERROR bad data chunk\r\n"}))
+	})
+
+	It("closes the connection for values which are too large", func() {
+		Expect(send("set A 0 0 17\r\n", 1)).To(Equal([]string{"SERVER_ERROR object too large for cache\r\n"}))
+		Eventually(done).Should(Receive(HaveOccurred()))
+	})
+
+	It("reports server errors", func() {
+		region.failWith(errors.New("region not found"))
+
+		Expect(send("get A\r\n", 1)).To(Equal([]string{"SERVER_ERROR region not found\r\n"}))
+	})
+
+	It("reports unknown commands", func() {
+		Expect(send("incr A 1\r\n", 1)).To(Equal([]string{"ERROR\r\n"}))
+	})
+
+	It("reports its version", func() {
+		Expect(send("version\r\n", 1)).To(Equal([]string{"VERSION geode-memcached-" + connector.LibraryVersion + "\r\n"}))
+	})
+
+	It("stops when the client quits", func() {
+		send("quit\r\n", 0)
+
+		Eventually(done).Should(Receive(BeNil()))
+	})
+})
@@ -0,0 +1,257 @@
+// Package codec encodes and decodes the values and messages of Geode's protobuf client
+// protocol. It depends only on the generated protocol types, not on the connector or its
+// networking, so that programs which only process Geode payloads, for example stream
+// processors consuming change data captured from a cluster, can decode them without
+// importing the client.
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// EncodeValue encodes a primitive value as the matching protocol type, after converting it
+// with the encoder registered for its type, if any (see RegisterValueCodec). Any other value,
+// such as a struct, is encoded as JSON. A field tagged geode:"name" is sent to the server
+// as name, whatever its JSON name, and DecodeValue reads it back from name. The options
+// omitempty and identity are described by TagOmitEmpty and TagIdentity, and geode:"-"
+// leaves a field out. A time.Time is encoded as a long of its milliseconds since the epoch,
+// which is how Geode stores a java.util.Date, so that it can be compared with dates on the
+// server.
+func EncodeValue(val interface{}) (*v1.EncodedValue, error) {
+	if ev, ok, err := encodeCustomValue(val); ok {
+		return ev, err
+	}
+
+	ev := &v1.EncodedValue{}
+
+	switch k := val.(type) {
+	case int:
+		ev.Value = &v1.EncodedValue_IntResult{IntResult: int32(k)}
+	case int16:
+		ev.Value = &v1.EncodedValue_ShortResult{ShortResult: int32(k)}
+	case int32:
+		ev.Value = &v1.EncodedValue_IntResult{IntResult: k}
+	case int64:
+		ev.Value = &v1.EncodedValue_LongResult{LongResult: k}
+	case byte:
+		ev.Value = &v1.EncodedValue_ByteResult{ByteResult: int32(k)}
+	case bool:
+		ev.Value = &v1.EncodedValue_BooleanResult{BooleanResult: k}
+	case float64:
+		ev.Value = &v1.EncodedValue_DoubleResult{DoubleResult: k}
+	case float32:
+		ev.Value = &v1.EncodedValue_FloatResult{FloatResult: k}
+	case []byte:
+		ev.Value = &v1.EncodedValue_BinaryResult{BinaryResult: k}
+	case string:
+		ev.Value = &v1.EncodedValue_StringResult{StringResult: k}
+	case time.Time:
+		ev.Value = &v1.EncodedValue_LongResult{LongResult: k.UnixMilli()}
+	case RoutedKey:
+		if err := k.validate(); err != nil {
+			return nil, err
+		}
+		ev.Value = &v1.EncodedValue_StringResult{StringResult: k.String()}
+	default:
+		// <nil> is not a type
+		if k == nil {
+			ev.Value = &v1.EncodedValue_NullResult{}
+		} else {
+			// Assume we have some struct and want to turn it into JSON
+			j, err := json.Marshal(k)
+			if err != nil {
+				return nil, err
+			}
+			if j, err = rewriteJSON(reflect.TypeOf(k), j, true); err != nil {
+				return nil, err
+			}
+			ev.Value = &v1.EncodedValue_JsonObjectResult{JsonObjectResult: string(j)}
+		}
+	}
+
+	return ev, nil
+}
+
+// EncodeList encodes each element of a slice or array.
+func EncodeList(list interface{}) ([]*v1.EncodedValue, error) {
+	listSlice := reflect.ValueOf(list)
+	if listSlice.Kind() != reflect.Slice && listSlice.Kind() != reflect.Array {
+		return nil, errors.New("argument must be a slice or array")
+	}
+
+	encodedEntries := make([]*v1.EncodedValue, 0, listSlice.Len())
+	for i := 0; i < listSlice.Len(); i++ {
+		key, err := EncodeValue(listSlice.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		encodedEntries = append(encodedEntries, key)
+	}
+
+	return encodedEntries, nil
+}
+
+// EncodeValueList encodes a slice or array as an EncodedValueList.
+func EncodeValueList(list interface{}) (*v1.EncodedValueList, error) {
+	encodedList, err := EncodeList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.EncodedValueList{Element: encodedList}, nil
+}
+
+// EncodeTable encodes columns of values, keyed by column name, as a Table. The columns are
+// in no particular order unless deterministic encoding is enabled, when they are sorted by
+// name.
+func EncodeTable(table map[string][]interface{}) (*v1.Table, error) {
+	columnNames := make([]string, 0, len(table))
+	for k := range table {
+		columnNames = append(columnNames, k)
+	}
+	if Deterministic() {
+		sort.Strings(columnNames)
+	}
+
+	columns := make([]*v1.EncodedValueList, len(table))
+	for idx, k := range columnNames {
+		list, err := EncodeValueList(table[k])
+		if err != nil {
+			return nil, err
+		}
+		columns[idx] = list
+	}
+
+	result := &v1.Table{
+		FieldName: columnNames,
+		Row:       columns,
+	}
+
+	return result, nil
+}
+
+// DecodeTable decodes a Table into columns of values keyed by column name; the inverse of
+// EncodeTable. JSON values are unmarshalled into new instances of ref's type, one for each
+// value. As with DecodeValue, integers are decoded as int32 or int64 and bytes as uint8.
+func DecodeTable(table *v1.Table, ref interface{}) (map[string][]interface{}, error) {
+	return DecodeTableWith(table, func(value *v1.EncodedValue) (interface{}, error) {
+		return DecodeValue(value, newRef(ref))
+	})
+}
+
+// DecodeTableWith decodes a Table as DecodeTable does, decoding each value with decode.
+func DecodeTableWith(table *v1.Table, decode func(value *v1.EncodedValue) (interface{}, error)) (map[string][]interface{}, error) {
+	columnNames := table.GetFieldName()
+	columns := table.GetRow()
+	if len(columns) != len(columnNames) {
+		return nil, errors.New(fmt.Sprintf("%d columns named but %d received", len(columnNames), len(columns)))
+	}
+
+	result := make(map[string][]interface{}, len(columns))
+	for i, name := range columnNames {
+		if _, ok := result[name]; ok {
+			return nil, errors.New(fmt.Sprintf("column %s is named twice", name))
+		}
+
+		values := make([]interface{}, len(columns[i].GetElement()))
+		for j, value := range columns[i].GetElement() {
+			decoded, err := decode(value)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("column %s: %s", name, err.Error()))
+			}
+			values[j] = decoded
+		}
+		result[name] = values
+	}
+
+	return result, nil
+}
+
+// Return a pointer to a new, empty instance of the type ref points to, or nil
+func newRef(ref interface{}) interface{} {
+	if ref == nil {
+		return nil
+	}
+	return reflect.New(reflect.Indirect(reflect.ValueOf(ref)).Type()).Interface()
+}
+
+// DecodeValue decodes a value. JSON values are unmarshalled into ref, which is returned, as
+// are values converted by the decoder registered for the type ref points to (see
+// RegisterValueCodec). A long decoded into a *time.Time is read as milliseconds since the
+// epoch, the inverse of EncodeValue; without a reference it is returned as an int64.
+func DecodeValue(value *v1.EncodedValue, ref interface{}) (interface{}, error) {
+	if decoded, ok, err := decodeCustomValue(value, ref); ok {
+		return decoded, err
+	}
+
+	if t, ok := ref.(*time.Time); ok && t != nil {
+		if v, ok := value.GetValue().(*v1.EncodedValue_LongResult); ok {
+			*t = time.UnixMilli(v.LongResult)
+			return ref, nil
+		}
+	}
+
+	var decodedValue interface{}
+
+	switch v := value.GetValue().(type) {
+	case *v1.EncodedValue_IntResult:
+		decodedValue = v.IntResult
+	case *v1.EncodedValue_ShortResult:
+		decodedValue = v.ShortResult
+	case *v1.EncodedValue_LongResult:
+		decodedValue = v.LongResult
+	case *v1.EncodedValue_ByteResult:
+		// Protobuf seems to transmit bytes as int32
+		decodedValue = uint8(v.ByteResult)
+	case *v1.EncodedValue_BooleanResult:
+		decodedValue = v.BooleanResult
+	case *v1.EncodedValue_DoubleResult:
+		decodedValue = v.DoubleResult
+	case *v1.EncodedValue_FloatResult:
+		decodedValue = v.FloatResult
+	case *v1.EncodedValue_BinaryResult:
+		decodedValue = v.BinaryResult
+	case *v1.EncodedValue_StringResult:
+		decodedValue = v.StringResult
+	case *v1.EncodedValue_JsonObjectResult:
+		document, err := rewriteJSON(reflect.TypeOf(ref), json.RawMessage(v.JsonObjectResult), false)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(document, ref)
+		if err != nil {
+			return nil, err
+		}
+		decodedValue = ref
+	case *v1.EncodedValue_NullResult, nil:
+		decodedValue = nil
+	default:
+		return nil, errors.New(fmt.Sprintf("unable to decode type: %T", v))
+	}
+
+	return decodedValue, nil
+}
+
+// DecodeValueList decodes each element of a list, unmarshalling JSON elements into ref.
+func DecodeValueList(list *v1.EncodedValueList, ref interface{}) ([]interface{}, error) {
+	decodedValueList := make([]interface{}, len(list.GetElement()))
+
+	for i, v := range list.GetElement() {
+		val, err := DecodeValue(v, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		decodedValueList[i] = val
+	}
+
+	return decodedValueList, nil
+}
@@ -0,0 +1,305 @@
+package codec_test
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"reflect"
+	"strconv"
+	"testing/iotest"
+	"testing/quick"
+	"time"
+
+	"github.com/gemfire/geode-go-client/codec"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type Person struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+var _ = Describe("Codec", func() {
+
+	Context("values", func() {
+		It("round trips primitive values", func() {
+			for _, v := range []interface{}{int32(1), int64(2), "three", true, 4.5, []byte("six")} {
+				ev, err := codec.EncodeValue(v)
+				Expect(err).To(BeNil())
+
+				decoded, err := codec.DecodeValue(ev, nil)
+				Expect(err).To(BeNil())
+				Expect(decoded).To(Equal(v))
+			}
+		})
+
+		It("round trips nil", func() {
+			ev, err := codec.EncodeValue(nil)
+			Expect(err).To(BeNil())
+
+			decoded, err := codec.DecodeValue(ev, nil)
+			Expect(err).To(BeNil())
+			Expect(decoded).To(BeNil())
+		})
+
+		It("round trips structs as JSON", func() {
+			ev, err := codec.EncodeValue(&Person{Name: "Joe", Age: 42})
+			Expect(err).To(BeNil())
+			Expect(ev.GetJsonObjectResult()).To(Equal(`{"name":"Joe","age":42}`))
+
+			decoded, err := codec.DecodeValue(ev, &Person{})
+			Expect(err).To(BeNil())
+			Expect(decoded).To(Equal(&Person{Name: "Joe", Age: 42}))
+		})
+
+		It("encodes times as dates", func() {
+			t := time.Date(2017, time.July, 14, 2, 40, 0, 123456789, time.UTC)
+			ev, err := codec.EncodeValue(t)
+			Expect(err).To(BeNil())
+			Expect(ev.GetLongResult()).To(Equal(int64(1500000000123)))
+
+			var decoded time.Time
+			result, err := codec.DecodeValue(ev, &decoded)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(&decoded))
+			Expect(decoded.Equal(t.Truncate(time.Millisecond))).To(BeTrue())
+
+			result, err = codec.DecodeValue(ev, nil)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(int64(1500000000123)))
+		})
+
+		It("decodes times within JSON as encoding/json does", func() {
+			type Event struct {
+				At time.Time `json:"at"`
+			}
+
+			ev, err := codec.EncodeValue(&Event{At: time.Date(2017, time.July, 14, 2, 40, 0, 0, time.UTC)})
+			Expect(err).To(BeNil())
+			Expect(ev.GetJsonObjectResult()).To(Equal(`{"at":"2017-07-14T02:40:00Z"}`))
+
+			decoded, err := codec.DecodeValue(ev, &Event{})
+			Expect(err).To(BeNil())
+			Expect(decoded.(*Event).At.Equal(time.Date(2017, time.July, 14, 2, 40, 0, 0, time.UTC))).To(BeTrue())
+		})
+
+		It("decodes lists", func() {
+			list, err := codec.EncodeValueList([]interface{}{1, "two"})
+			Expect(err).To(BeNil())
+
+			decoded, err := codec.DecodeValueList(list, nil)
+			Expect(err).To(BeNil())
+			Expect(decoded).To(Equal([]interface{}{int32(1), "two"}))
+		})
+
+		It("sorts table columns when encoding is deterministic", func() {
+			codec.SetDeterministic(true)
+			defer codec.SetDeterministic(false)
+
+			table, err := codec.EncodeTable(map[string][]interface{}{
+				"name": {"Joe"}, "age": {42}, "city": {"Leeds"},
+			})
+			Expect(err).To(BeNil())
+			Expect(table.GetFieldName()).To(Equal([]string{"age", "city", "name"}))
+			Expect(table.GetRow()[0].GetElement()[0].GetIntResult()).To(Equal(int32(42)))
+		})
+	})
+
+	Context("tables", func() {
+		// Encode and decode a table, checking that it comes back unchanged
+		roundTrips := func(table map[string][]interface{}) bool {
+			encoded, err := codec.EncodeTable(table)
+			if err != nil {
+				return false
+			}
+			decoded, err := codec.DecodeTable(encoded, nil)
+			if err != nil || len(decoded) != len(table) {
+				return false
+			}
+			for name, column := range table {
+				if !reflect.DeepEqual(decoded[name], column) {
+					return false
+				}
+			}
+			return true
+		}
+
+		It("round trips columns of primitive values", func() {
+			property := func(ints map[string][]int32, longs []int64, strings []string, flags []bool, doubles []float64) bool {
+				table := make(map[string][]interface{})
+				for name, column := range ints {
+					table[name] = make([]interface{}, len(column))
+					for i, v := range column {
+						table[name][i] = v
+					}
+				}
+				for _, column := range [][]interface{}{toInterfaces(longs), toInterfaces(strings), toInterfaces(flags), toInterfaces(doubles)} {
+					table[strconv.Itoa(len(table))+"_"] = column
+				}
+				return roundTrips(table)
+			}
+
+			Expect(quick.Check(property, nil)).To(Succeed())
+		})
+
+		It("round trips an empty table", func() {
+			Expect(roundTrips(map[string][]interface{}{})).To(BeTrue())
+		})
+
+		It("decodes JSON values into separate instances", func() {
+			encoded, err := codec.EncodeTable(map[string][]interface{}{
+				"person": {&Person{Name: "Joe", Age: 42}, &Person{Name: "Ann", Age: 37}},
+			})
+			Expect(err).To(BeNil())
+
+			decoded, err := codec.DecodeTable(encoded, &Person{})
+			Expect(err).To(BeNil())
+			Expect(decoded["person"]).To(Equal([]interface{}{&Person{Name: "Joe", Age: 42}, &Person{Name: "Ann", Age: 37}}))
+		})
+
+		It("rejects a table whose columns do not match their names", func() {
+			_, err := codec.DecodeTable(&v1.Table{FieldName: []string{"a", "b"}, Row: []*v1.EncodedValueList{{}}}, nil)
+			Expect(err).To(MatchError("2 columns named but 1 received"))
+		})
+	})
+
+	Context("messages", func() {
+		var message *v1.Message
+
+		BeforeEach(func() {
+			value, err := codec.EncodeValue("x")
+			Expect(err).To(BeNil())
+			message = &v1.Message{
+				MessageType: &v1.Message_GetResponse{GetResponse: &v1.GetResponse{Result: value}},
+			}
+		})
+
+		It("round trips a message", func() {
+			data, err := codec.MarshalMessage(message)
+			Expect(err).To(BeNil())
+
+			decoded, err := codec.UnmarshalMessage(data)
+			Expect(err).To(BeNil())
+			Expect(decoded.GetGetResponse().GetResult().GetStringResult()).To(Equal("x"))
+		})
+
+		It("writes a message as it is marshalled", func() {
+			data, err := codec.MarshalMessage(message)
+			Expect(err).To(BeNil())
+
+			written := new(bytes.Buffer)
+			Expect(codec.WriteMessage(written, message)).To(Succeed())
+			Expect(written.Bytes()).To(Equal(data))
+		})
+
+		It("does not share reused buffers with messages already read", func() {
+			value, err := codec.EncodeValue([]byte("first"))
+			Expect(err).To(BeNil())
+			first := &v1.Message{MessageType: &v1.Message_GetResponse{GetResponse: &v1.GetResponse{Result: value}}}
+
+			written := new(bytes.Buffer)
+			Expect(codec.WriteMessage(written, first)).To(Succeed())
+			read, err := codec.ReadMessage(bytes.NewReader(written.Bytes()))
+			Expect(err).To(BeNil())
+
+			written.Reset()
+			Expect(codec.WriteMessage(written, message)).To(Succeed())
+			_, err = codec.ReadMessage(bytes.NewReader(written.Bytes()))
+			Expect(err).To(BeNil())
+
+			Expect(read.GetGetResponse().GetResult().GetBinaryResult()).To(Equal([]byte("first")))
+		})
+
+		It("reads a message which arrives in pieces", func() {
+			data, err := codec.MarshalMessage(message)
+			Expect(err).To(BeNil())
+
+			decoded, err := codec.ReadMessage(io.MultiReader(bytes.NewReader(data[:1]), bytes.NewReader(data[1:])))
+			Expect(err).To(BeNil())
+			Expect(decoded.GetGetResponse().GetResult().GetStringResult()).To(Equal("x"))
+		})
+
+		It("reads consecutive messages from a buffered reader", func() {
+			data, err := codec.MarshalMessage(message)
+			Expect(err).To(BeNil())
+
+			reader := bufio.NewReader(bytes.NewReader(append(append([]byte{}, data...), data...)))
+			for i := 0; i < 2; i++ {
+				decoded, err := codec.ReadMessage(reader)
+				Expect(err).To(BeNil())
+				Expect(decoded.GetGetResponse().GetResult().GetStringResult()).To(Equal("x"))
+			}
+
+			_, err = codec.ReadMessage(reader)
+			Expect(err).To(Equal(io.EOF))
+		})
+
+		It("rejects data beyond the end of a message read through a buffer", func() {
+			data, err := codec.MarshalMessage(message)
+			Expect(err).To(BeNil())
+			both := append(append([]byte{}, data...), data...)
+
+			_, err = codec.ReadMessage(struct{ io.Reader }{bytes.NewReader(both)})
+			Expect(err).To(Equal(codec.ErrTrailingData))
+
+			read, err := codec.ReadDelimited(struct{ io.Reader }{bytes.NewReader(both)})
+			Expect(err).To(BeNil())
+			Expect(read).To(Equal(data))
+		})
+
+		It("returns an error for a truncated message", func() {
+			data, err := codec.MarshalMessage(message)
+			Expect(err).To(BeNil())
+
+			_, err = codec.ReadMessage(bytes.NewReader(data[:len(data)-1]))
+			Expect(err).To(Equal(io.ErrUnexpectedEOF))
+		})
+
+		It("reads a message much larger than a single read", func() {
+			value, err := codec.EncodeValue(bytes.Repeat([]byte("x"), 1<<20))
+			Expect(err).To(BeNil())
+			data, err := codec.MarshalMessage(&v1.Message{
+				MessageType: &v1.Message_GetResponse{GetResponse: &v1.GetResponse{Result: value}},
+			})
+			Expect(err).To(BeNil())
+
+			read, err := codec.ReadDelimited(iotest.HalfReader(bytes.NewReader(data)))
+			Expect(err).To(BeNil())
+			Expect(read).To(Equal(data))
+		})
+
+		It("reads a length which arrives a byte at a time", func() {
+			data, err := codec.MarshalMessage(message)
+			Expect(err).To(BeNil())
+
+			read, err := codec.ReadDelimited(iotest.OneByteReader(bytes.NewReader(data)))
+			Expect(err).To(BeNil())
+			Expect(read).To(Equal(data))
+		})
+
+		It("rejects invalid lengths", func() {
+			_, err := codec.ReadDelimited(bytes.NewReader(bytes.Repeat([]byte{0xff}, 11)))
+			Expect(err).To(MatchError("invalid message length"))
+
+			_, err = codec.ReadDelimited(bytes.NewReader([]byte{0x80, 0x80, 0x80, 0x80, 0x08}))
+			Expect(err).To(MatchError("message length 2147483648 exceeds the maximum of 1073741824"))
+
+			_, err = codec.ReadDelimited(bytes.NewReader([]byte{0x80}))
+			Expect(err).To(Equal(io.ErrUnexpectedEOF))
+
+			_, err = codec.ReadDelimited(bytes.NewReader(nil))
+			Expect(err).To(Equal(io.EOF))
+		})
+	})
+})
+
+func toInterfaces(slice interface{}) []interface{} {
+	v := reflect.ValueOf(slice)
+	result := make([]interface{}, v.Len())
+	for i := range result {
+		result[i] = v.Index(i).Interface()
+	}
+	return result
+}
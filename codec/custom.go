@@ -0,0 +1,128 @@
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// A ValueEncoder converts a value of a registered type to one which EncodeValue encodes, such
+// as an int64 or a string.
+type ValueEncoder func(value interface{}) (interface{}, error)
+
+// A ValueDecoder converts a decoded value back to a registered type. It is passed the value
+// as DecodeValue returns it without a reference, such as an int64 or a string, or the
+// document as a json.RawMessage if the value is JSON.
+type ValueDecoder func(value interface{}) (interface{}, error)
+
+type valueCodec struct {
+	encoder ValueEncoder
+	decoder ValueDecoder
+}
+
+var valueCodecsLock sync.RWMutex
+var valueCodecs = make(map[reflect.Type]valueCodec)
+
+// RegisterValueCodec registers the conversion of a Go type, given by an example value, to and
+// from a value Geode can store. EncodeValue consults the encoder before its own encodings, so
+// that values of the type can be used as keys, values and parameters without converting them
+// by hand. DecodeValue consults the decoder when its reference is a pointer to the type,
+// storing the converted value there. Either may be nil to convert in one direction only.
+// Values of the type within structs, slices and maps, which are encoded as JSON, are not
+// converted.
+//
+// For example, to store a time.Time as an RFC 3339 string, rather than as a date:
+//
+//	codec.RegisterValueCodec(time.Time{},
+//		func(v interface{}) (interface{}, error) { return v.(time.Time).Format(time.RFC3339), nil },
+//		func(v interface{}) (interface{}, error) { return time.Parse(time.RFC3339, v.(string)) })
+func RegisterValueCodec(example interface{}, encoder ValueEncoder, decoder ValueDecoder) {
+	valueCodecsLock.Lock()
+	defer valueCodecsLock.Unlock()
+
+	valueCodecs[reflect.TypeOf(example)] = valueCodec{encoder: encoder, decoder: decoder}
+}
+
+// UnregisterValueCodec removes the conversion registered for the type of example.
+func UnregisterValueCodec(example interface{}) {
+	valueCodecsLock.Lock()
+	defer valueCodecsLock.Unlock()
+
+	delete(valueCodecs, reflect.TypeOf(example))
+}
+
+func lookupValueCodec(t reflect.Type) (valueCodec, bool) {
+	valueCodecsLock.RLock()
+	defer valueCodecsLock.RUnlock()
+
+	c, ok := valueCodecs[t]
+	return c, ok
+}
+
+// Encode a value with its registered encoder, if there is one
+func encodeCustomValue(val interface{}) (*v1.EncodedValue, bool, error) {
+	if val == nil {
+		return nil, false, nil
+	}
+
+	t := reflect.TypeOf(val)
+	c, ok := lookupValueCodec(t)
+	if !ok || c.encoder == nil {
+		return nil, false, nil
+	}
+
+	converted, err := c.encoder(val)
+	if err != nil {
+		return nil, true, errors.New(fmt.Sprintf("unable to encode %s: %s", t, err.Error()))
+	}
+	if converted != nil && reflect.TypeOf(converted) == t {
+		return nil, true, errors.New(fmt.Sprintf("the encoder of %s returned a %s", t, t))
+	}
+
+	ev, err := EncodeValue(converted)
+	return ev, true, err
+}
+
+// Decode a value with the registered decoder of the type ref points to, if there is one
+func decodeCustomValue(value *v1.EncodedValue, ref interface{}) (interface{}, bool, error) {
+	target := reflect.ValueOf(ref)
+	if ref == nil || target.Kind() != reflect.Ptr || target.IsNil() {
+		return nil, false, nil
+	}
+
+	t := target.Type().Elem()
+	c, ok := lookupValueCodec(t)
+	if !ok || c.decoder == nil {
+		return nil, false, nil
+	}
+
+	var decoded interface{}
+	switch v := value.GetValue().(type) {
+	case *v1.EncodedValue_NullResult, nil:
+		return nil, true, nil
+	case *v1.EncodedValue_JsonObjectResult:
+		decoded = json.RawMessage(v.JsonObjectResult)
+	default:
+		var err error
+		if decoded, err = DecodeValue(value, nil); err != nil {
+			return nil, true, err
+		}
+	}
+
+	converted, err := c.decoder(decoded)
+	if err != nil {
+		return nil, true, errors.New(fmt.Sprintf("unable to decode %s: %s", t, err.Error()))
+	}
+
+	result := reflect.ValueOf(converted)
+	if !result.IsValid() || !result.Type().AssignableTo(t) {
+		return nil, true, errors.New(fmt.Sprintf("the decoder of %s returned a %T", t, converted))
+	}
+	target.Elem().Set(result)
+
+	return ref, true, nil
+}
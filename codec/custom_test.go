@@ -0,0 +1,126 @@
+package codec_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gemfire/geode-go-client/codec"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type customID [4]byte
+
+type customPoint struct {
+	X, Y int
+}
+
+var _ = Describe("Custom value codecs", func() {
+
+	BeforeEach(func() {
+		codec.RegisterValueCodec(customID{},
+			func(v interface{}) (interface{}, error) {
+				id := v.(customID)
+				return hex.EncodeToString(id[:]), nil
+			},
+			func(v interface{}) (interface{}, error) {
+				s, ok := v.(string)
+				if !ok {
+					return nil, errors.New("not a string")
+				}
+				var id customID
+				_, err := hex.Decode(id[:], []byte(s))
+				return id, err
+			})
+	})
+
+	AfterEach(func() {
+		codec.UnregisterValueCodec(customID{})
+		codec.UnregisterValueCodec(time.Time{})
+		codec.UnregisterValueCodec(customPoint{})
+	})
+
+	It("encodes values with their registered encoder", func() {
+		ev, err := codec.EncodeValue(customID{0xca, 0xfe, 0xba, 0xbe})
+		Expect(err).To(BeNil())
+		Expect(ev.GetStringResult()).To(Equal("cafebabe"))
+	})
+
+	It("decodes values into a reference of the registered type", func() {
+		ev := &v1.EncodedValue{Value: &v1.EncodedValue_StringResult{StringResult: "cafebabe"}}
+
+		var id customID
+		decoded, err := codec.DecodeValue(ev, &id)
+		Expect(err).To(BeNil())
+		Expect(decoded).To(Equal(&id))
+		Expect(id).To(Equal(customID{0xca, 0xfe, 0xba, 0xbe}))
+
+		// Without a reference the value is returned as it was stored
+		decoded, err = codec.DecodeValue(ev, nil)
+		Expect(err).To(BeNil())
+		Expect(decoded).To(Equal("cafebabe"))
+	})
+
+	It("round trips times as epoch milliseconds", func() {
+		codec.RegisterValueCodec(time.Time{},
+			func(v interface{}) (interface{}, error) { return v.(time.Time).UnixMilli(), nil },
+			func(v interface{}) (interface{}, error) { return time.UnixMilli(v.(int64)), nil })
+
+		t := time.UnixMilli(1500000000123)
+		ev, err := codec.EncodeValue(t)
+		Expect(err).To(BeNil())
+		Expect(ev.GetLongResult()).To(Equal(int64(1500000000123)))
+
+		var decoded time.Time
+		_, err = codec.DecodeValue(ev, &decoded)
+		Expect(err).To(BeNil())
+		Expect(decoded.Equal(t)).To(BeTrue())
+	})
+
+	It("passes JSON documents to decoders undecoded", func() {
+		codec.RegisterValueCodec(customPoint{},
+			func(v interface{}) (interface{}, error) {
+				p := v.(customPoint)
+				return map[string]int{"x": p.X, "y": p.Y}, nil
+			},
+			func(v interface{}) (interface{}, error) {
+				var fields map[string]int
+				err := json.Unmarshal(v.(json.RawMessage), &fields)
+				return customPoint{X: fields["x"], Y: fields["y"]}, err
+			})
+
+		ev, err := codec.EncodeValue(customPoint{X: 1, Y: 2})
+		Expect(err).To(BeNil())
+		Expect(ev.GetJsonObjectResult()).To(Equal(`{"x":1,"y":2}`))
+
+		var p customPoint
+		_, err = codec.DecodeValue(ev, &p)
+		Expect(err).To(BeNil())
+		Expect(p).To(Equal(customPoint{X: 1, Y: 2}))
+	})
+
+	It("decodes null without calling the decoder", func() {
+		var id customID
+		decoded, err := codec.DecodeValue(&v1.EncodedValue{Value: &v1.EncodedValue_NullResult{}}, &id)
+		Expect(err).To(BeNil())
+		Expect(decoded).To(BeNil())
+	})
+
+	It("reports the errors of encoders and decoders", func() {
+		ev := &v1.EncodedValue{Value: &v1.EncodedValue_IntResult{IntResult: 7}}
+		var id customID
+		_, err := codec.DecodeValue(ev, &id)
+		Expect(err).To(MatchError("unable to decode codec_test.customID: not a string"))
+
+		codec.RegisterValueCodec(customID{}, func(v interface{}) (interface{}, error) {
+			return v, nil
+		}, nil)
+		_, err = codec.EncodeValue(customID{})
+		Expect(err).ToNot(BeNil())
+		Expect(strings.Contains(err.Error(), "the encoder of codec_test.customID returned")).To(BeTrue())
+	})
+})
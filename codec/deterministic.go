@@ -0,0 +1,29 @@
+package codec
+
+import (
+	"sync"
+)
+
+var deterministicLock sync.RWMutex
+var deterministic bool
+
+// SetDeterministic makes encodings which depend on the iteration order of a map, such as the
+// columns of EncodeTable and the entries of a connector's PutAll, sort the map's keys, so
+// that golden-file tests of wire payloads are stable across runs and Go versions. It is off
+// by default as sorting costs time and the order means nothing to the servers. Values encoded
+// as JSON are always deterministic, since encoding/json sorts map keys, but encrypted fields
+// are not, as each is sealed with a random nonce.
+func SetDeterministic(enabled bool) {
+	deterministicLock.Lock()
+	defer deterministicLock.Unlock()
+
+	deterministic = enabled
+}
+
+// Deterministic returns whether deterministic encoding is enabled. See SetDeterministic.
+func Deterministic() bool {
+	deterministicLock.RLock()
+	defer deterministicLock.RUnlock()
+
+	return deterministic
+}
@@ -0,0 +1,79 @@
+package codec_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/gemfire/geode-go-client/codec"
+)
+
+// Fuzz targets for the framing of messages read from servers. Run one with, for example:
+//
+//     go test ./codec -run '^$' -fuzz FuzzReadDelimited
+
+// Deliver data in reads of at most chunk bytes
+type chunkedReader struct {
+	data  []byte
+	chunk int
+}
+
+func (this *chunkedReader) Read(b []byte) (int, error) {
+	if len(this.data) == 0 {
+		return 0, io.EOF
+	}
+
+	n := this.chunk
+	if n > len(b) {
+		n = len(b)
+	}
+	n = copy(b[:n], this.data)
+	this.data = this.data[n:]
+	return n, nil
+}
+
+// Any stream, however it is split into reads, is either read as the message it starts with
+// or rejected, never misframed
+func FuzzReadDelimited(f *testing.F) {
+	f.Add([]byte{0x03, 'a', 'b', 'c'}, 1)
+	f.Add([]byte{0x03, 'a', 'b', 'c', 0x01, 'd'}, 4096)
+	f.Add([]byte{0x03, 'a', 'b'}, 2)
+	f.Add(append([]byte{0x80, 0x20}, bytes.Repeat([]byte{'x'}, 4096)...), 3)
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}, 4096)
+	f.Add([]byte{0x80}, 1)
+	f.Add([]byte{}, 1)
+
+	f.Fuzz(func(t *testing.T, data []byte, chunk int) {
+		if chunk <= 0 {
+			chunk = 1
+		}
+
+		read, err := codec.ReadDelimited(&chunkedReader{data: data, chunk: chunk})
+
+		m, n := binary.Uvarint(data)
+		if n <= 0 || m > codec.MaxMessageLength || uint64(len(data)-n) < m {
+			if err == nil {
+				t.Fatalf("read %d bytes from an incomplete or invalid message", len(read))
+			}
+			return
+		}
+
+		if err != nil {
+			t.Fatalf("unable to read a complete message: %s", err)
+		}
+		message, k := binary.Uvarint(read)
+		if message != m || !bytes.Equal(read[k:], data[n:n+int(m)]) {
+			t.Fatalf("read a different message from the one sent")
+		}
+
+		// A stream which is an io.ByteReader is left at the start of the next message
+		stream := bytes.NewReader(data)
+		if _, err := codec.ReadDelimited(stream); err != nil {
+			t.Fatalf("unable to read a complete message from a byte reader: %s", err)
+		}
+		if stream.Len() != len(data)-n-int(m) {
+			t.Fatalf("read %d bytes beyond the message", len(data)-n-int(m)-stream.Len())
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf16"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// DefaultTotalBuckets is the number of buckets of a partitioned region unless it is
+// configured with another number.
+const DefaultTotalBuckets = 113
+
+// HashCode returns the hash code a Geode server computes for a key, which is the Java
+// hashCode of the object the key is decoded as. Binary, JSON and null values have no
+// hash code which can be computed by the client, and return an error.
+func HashCode(key *v1.EncodedValue) (int32, error) {
+	switch v := key.GetValue().(type) {
+	case *v1.EncodedValue_IntResult:
+		return v.IntResult, nil
+	case *v1.EncodedValue_ShortResult:
+		return int32(int16(v.ShortResult)), nil
+	case *v1.EncodedValue_ByteResult:
+		// Java bytes are signed
+		return int32(int8(v.ByteResult)), nil
+	case *v1.EncodedValue_LongResult:
+		return int32(v.LongResult ^ int64(uint64(v.LongResult)>>32)), nil
+	case *v1.EncodedValue_BooleanResult:
+		if v.BooleanResult {
+			return 1231, nil
+		}
+		return 1237, nil
+	case *v1.EncodedValue_FloatResult:
+		f := v.FloatResult
+		if f != f {
+			// Java canonicalizes NaN
+			return 0x7fc00000, nil
+		}
+		return int32(math.Float32bits(f)), nil
+	case *v1.EncodedValue_DoubleResult:
+		bits := uint64(0x7ff8000000000000)
+		if d := v.DoubleResult; d == d {
+			bits = math.Float64bits(d)
+		}
+		return int32(bits ^ bits>>32), nil
+	case *v1.EncodedValue_StringResult:
+		// Java strings hash their UTF-16 code units
+		var h int32
+		for _, unit := range utf16.Encode([]rune(v.StringResult)) {
+			h = 31*h + int32(unit)
+		}
+		return h, nil
+	}
+
+	return 0, errors.New(fmt.Sprintf("unable to hash key of type: %T", key.GetValue()))
+}
+
+// KeyHashCode encodes a key as EncodeValue does and returns its HashCode. The hash code of a
+// RoutedKey is that of its Routing, which is what servers use to place it.
+func KeyHashCode(key interface{}) (int32, error) {
+	if routed, ok := key.(RoutedKey); ok {
+		if err := routed.validate(); err != nil {
+			return 0, err
+		}
+		key = routed.Routing
+	}
+
+	ev, err := EncodeValue(key)
+	if err != nil {
+		return 0, err
+	}
+	return HashCode(ev)
+}
+
+// BucketID returns the bucket of a partitioned region with totalBuckets buckets which holds
+// key, as Geode assigns it when the region has no partition resolver, or a
+// StringPrefixPartitionResolver for a RoutedKey. Keys with the same bucket are held by the
+// same server, so a workload may be split by bucket to keep each part on one server.
+func BucketID(key interface{}, totalBuckets int) (int, error) {
+	if totalBuckets <= 0 {
+		return 0, errors.New(fmt.Sprintf("invalid number of buckets: %d", totalBuckets))
+	}
+
+	h, err := KeyHashCode(key)
+	if err != nil {
+		return 0, err
+	}
+
+	bucket := int(h) % totalBuckets
+	if bucket < 0 {
+		bucket = -bucket
+	}
+	return bucket, nil
+}
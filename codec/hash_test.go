@@ -0,0 +1,62 @@
+package codec_test
+
+import (
+	"math"
+
+	"github.com/gemfire/geode-go-client/codec"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hashing", func() {
+
+	It("matches Java hash codes", func() {
+		// Values of Object.hashCode for the Java type each key is decoded as
+		for key, expected := range map[interface{}]int32{
+			"hello":             99162322,
+			"":                  0,
+			"€":                 8364,
+			"\U0001F600":        1772899,
+			7:                   7,
+			int32(-7):           -7,
+			int16(-2):           -2,
+			byte(200):           -56,
+			int64(1) << 32:      1,
+			int64(-1):           0,
+			true:                1231,
+			false:               1237,
+			float32(1):          1065353216,
+			float32(math.NaN()): 0x7fc00000,
+			1.0:                 1072693248,
+			math.Inf(1):         2146435072,
+		} {
+			h, err := codec.KeyHashCode(key)
+			Expect(err).To(BeNil())
+			Expect(h).To(Equal(expected), "hash of %#v", key)
+		}
+	})
+
+	It("cannot hash binary, JSON or null keys", func() {
+		for _, key := range []interface{}{[]byte("a"), &Person{Name: "Joe"}, nil} {
+			_, err := codec.KeyHashCode(key)
+			Expect(err).To(MatchError(HavePrefix("unable to hash key of type")))
+		}
+
+		_, err := codec.HashCode(&v1.EncodedValue{})
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("assigns keys to buckets", func() {
+		Expect(codec.BucketID("hello", codec.DefaultTotalBuckets)).To(Equal(99162322 % 113))
+		Expect(codec.BucketID(113, codec.DefaultTotalBuckets)).To(Equal(0))
+		// Negative hash codes give positive buckets
+		Expect(codec.BucketID(-115, codec.DefaultTotalBuckets)).To(Equal(2))
+		// Colliding strings share a bucket
+		aa, _ := codec.BucketID("Aa", 7)
+		Expect(codec.BucketID("BB", 7)).To(Equal(aa))
+
+		_, err := codec.BucketID("hello", 0)
+		Expect(err).To(MatchError("invalid number of buckets: 0"))
+	})
+})
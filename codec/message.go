@@ -0,0 +1,173 @@
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+)
+
+// MaxMessageLength is the longest message accepted. A longer length prefix indicates a
+// corrupt stream, which must not cause a huge allocation.
+const MaxMessageLength = 1 << 30
+
+// ErrTrailingData is returned by ReadMessage when more data had arrived than the message it
+// read. On a client connection, where each request is answered by a single response, this
+// means that the connection has lost track of which response answers which request, so it
+// must not be used again.
+var ErrTrailingData = errors.New("data received beyond the end of the message")
+
+// Size of the buffer through which readers which are not io.ByteReaders are read, which
+// holds most responses in a single read
+const readBufferSize = 4096
+
+// Largest buffer kept for reuse. Buffers grown for larger messages are left to the garbage
+// collector, so that an occasional large message does not hold its memory for good.
+const maxPooledBufferSize = 1 << 20
+
+// Buffers reused by WriteMessage and ReadMessage from one message to the next, so that
+// operations need not allocate them afresh
+var encodeBuffers = sync.Pool{New: func() interface{} {
+	return proto.NewBuffer(make([]byte, 0, readBufferSize))
+}}
+var messageBuffers = sync.Pool{New: func() interface{} {
+	return bytes.NewBuffer(make([]byte, 0, readBufferSize))
+}}
+var bufferedReaders = sync.Pool{New: func() interface{} {
+	return bufio.NewReaderSize(nil, readBufferSize)
+}}
+
+// MarshalMessage encodes a message prefixed with its length, as it is sent on the wire.
+func MarshalMessage(message proto.Message) ([]byte, error) {
+	p := proto.NewBuffer(nil)
+	if err := p.EncodeMessage(message); err != nil {
+		return nil, err
+	}
+
+	return p.Bytes(), nil
+}
+
+// WriteMessage writes a message to w prefixed with its length, as MarshalMessage encodes it,
+// in a single Write. The message is encoded into a reused buffer, so unlike MarshalMessage
+// it does not allocate one for every message.
+func WriteMessage(w io.Writer, message proto.Message) error {
+	p := encodeBuffers.Get().(*proto.Buffer)
+	defer func() {
+		if cap(p.Bytes()) <= maxPooledBufferSize {
+			encodeBuffers.Put(p)
+		}
+	}()
+
+	p.Reset()
+	if err := p.EncodeMessage(message); err != nil {
+		return err
+	}
+
+	_, err := w.Write(p.Bytes())
+	return err
+}
+
+// UnmarshalMessage decodes a length prefixed message, as produced by MarshalMessage.
+func UnmarshalMessage(data []byte) (*v1.Message, error) {
+	message := &v1.Message{}
+	if err := proto.NewBuffer(data).DecodeMessage(message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// ReadMessage reads a single length prefixed message from r, as ReadDelimited does, but
+// through a reused buffer since the decoded message does not refer to the data read. Rather
+// than discard data beyond the end of the message, it returns ErrTrailingData.
+func ReadMessage(r io.Reader) (*v1.Message, error) {
+	buffer := messageBuffers.Get().(*bytes.Buffer)
+	defer func() {
+		if buffer.Cap() <= maxPooledBufferSize {
+			messageBuffers.Put(buffer)
+		}
+	}()
+
+	buffer.Reset()
+	if err := readDelimited(r, buffer, true); err != nil {
+		return nil, err
+	}
+
+	return UnmarshalMessage(buffer.Bytes())
+}
+
+// ReadDelimited reads a single length prefixed message from reader, returning it with its
+// prefix but without decoding it. The length is read first, however many reads it arrives
+// in, and then exactly that many bytes, so messages may be of any size up to
+// MaxMessageLength. Unless reader is an io.ByteReader, such as a bufio.Reader, from which
+// exactly one message is read, it is read through a buffer and any data beyond the end of
+// the message is discarded, so reader should only contain one message at a time, as is the
+// case with a client connection.
+func ReadDelimited(reader io.Reader) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	if err := readDelimited(reader, buffer, false); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Read a length prefixed message from reader into buffer, as ReadDelimited. If strict, data
+// read through a buffer beyond the end of the message is an error rather than discarded.
+func readDelimited(reader io.Reader, buffer *bytes.Buffer, strict bool) error {
+	var buffered *bufio.Reader
+	byteReader, ok := reader.(io.ByteReader)
+	if !ok {
+		buffered = bufferedReaders.Get().(*bufio.Reader)
+		buffered.Reset(reader)
+		defer func() {
+			buffered.Reset(nil)
+			bufferedReaders.Put(buffered)
+		}()
+		reader, byteReader = buffered, buffered
+	}
+
+	// The length is a varint, read a byte at a time from the buffer so that it may span reads
+	var m uint64
+	for shift := uint(0); ; shift += 7 {
+		if shift >= 64 {
+			return errors.New("invalid message length")
+		}
+		b, err := byteReader.ReadByte()
+		if err != nil {
+			if err == io.EOF && shift > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		m |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			break
+		}
+	}
+	if m > MaxMessageLength {
+		return errors.New(fmt.Sprintf("message length %d exceeds the maximum of %d", m, MaxMessageLength))
+	}
+
+	// Grow the buffer as the message arrives, rather than trusting the length up front
+	prefix := proto.EncodeVarint(m)
+	buffer.Grow(len(prefix) + int(min(m, readBufferSize)))
+	buffer.Write(prefix)
+	if _, err := io.CopyN(buffer, reader, int64(m)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	if strict && buffered != nil && buffered.Buffered() > 0 {
+		return ErrTrailingData
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package codec_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/gemfire/geode-go-client/codec"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// Benchmarks for writing and reading a message, comparing the allocations made through the
+// reused buffers with marshalling each message afresh. Run them with, for example:
+//
+//     go test ./codec -run '^$' -bench Message -benchmem
+
+func benchmarkMessage(b *testing.B) *v1.Message {
+	value, err := codec.EncodeValue(`{"name":"Joe","age":42,"city":"Portland"}`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return &v1.Message{
+		MessageType: &v1.Message_PutRequest{PutRequest: &v1.PutRequest{
+			RegionName: "People",
+			Entry:      &v1.Entry{Key: value, Value: value},
+		}},
+	}
+}
+
+func BenchmarkMarshalMessage(b *testing.B) {
+	message := benchmarkMessage(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		data, err := codec.MarshalMessage(message)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.Discard.Write(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteMessage(b *testing.B) {
+	message := benchmarkMessage(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := codec.WriteMessage(io.Discard, message); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadDelimited(b *testing.B) {
+	data, err := codec.MarshalMessage(benchmarkMessage(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	reader := bytes.NewReader(data)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		reader.Reset(data)
+		read, err := codec.ReadDelimited(reader)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := codec.UnmarshalMessage(read); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadMessage(b *testing.B) {
+	data, err := codec.MarshalMessage(benchmarkMessage(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	reader := bytes.NewReader(data)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		reader.Reset(data)
+		if _, err := codec.ReadMessage(reader); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
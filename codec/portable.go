@@ -0,0 +1,138 @@
+package codec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// Type tags of a PortableValue, one for each kind of EncodedValue
+const (
+	PortableInt     = "int"
+	PortableLong    = "long"
+	PortableShort   = "short"
+	PortableByte    = "byte"
+	PortableBoolean = "boolean"
+	PortableDouble  = "double"
+	PortableFloat   = "float"
+	PortableBinary  = "binary"
+	PortableString  = "string"
+	PortableJSON    = "json"
+	PortableNull    = "null"
+	PortableCustom  = "custom"
+)
+
+// A PortableValue is a self-describing form of an EncodedValue, a type tag and the value's
+// bytes, which can be serialized with gob or JSON to pass raw Geode values through queues
+// and other intermediate systems. Numbers are big-endian and of their size in the protocol,
+// 4 bytes for ints, shorts, bytes and floats and 8 for longs and doubles; booleans are a
+// single byte, strings and JSON documents are UTF-8, and null has no data. The representation is stable, so
+// values exported by one version of the library can be imported by another.
+type PortableValue struct {
+	Type string `json:"type"`
+	Data []byte `json:"data,omitempty"`
+}
+
+// ExportValue converts an EncodedValue to a PortableValue.
+func ExportValue(value *v1.EncodedValue) (PortableValue, error) {
+	if value == nil {
+		return PortableValue{}, errors.New("cannot export a nil value")
+	}
+
+	switch v := value.Value.(type) {
+	case *v1.EncodedValue_IntResult:
+		return PortableValue{Type: PortableInt, Data: uint32Bytes(uint32(v.IntResult))}, nil
+	case *v1.EncodedValue_LongResult:
+		data := make([]byte, 8)
+		binary.BigEndian.PutUint64(data, uint64(v.LongResult))
+		return PortableValue{Type: PortableLong, Data: data}, nil
+	case *v1.EncodedValue_ShortResult:
+		return PortableValue{Type: PortableShort, Data: uint32Bytes(uint32(v.ShortResult))}, nil
+	case *v1.EncodedValue_ByteResult:
+		return PortableValue{Type: PortableByte, Data: uint32Bytes(uint32(v.ByteResult))}, nil
+	case *v1.EncodedValue_BooleanResult:
+		data := []byte{0}
+		if v.BooleanResult {
+			data[0] = 1
+		}
+		return PortableValue{Type: PortableBoolean, Data: data}, nil
+	case *v1.EncodedValue_DoubleResult:
+		data := make([]byte, 8)
+		binary.BigEndian.PutUint64(data, math.Float64bits(v.DoubleResult))
+		return PortableValue{Type: PortableDouble, Data: data}, nil
+	case *v1.EncodedValue_FloatResult:
+		return PortableValue{Type: PortableFloat, Data: uint32Bytes(math.Float32bits(v.FloatResult))}, nil
+	case *v1.EncodedValue_BinaryResult:
+		return PortableValue{Type: PortableBinary, Data: v.BinaryResult}, nil
+	case *v1.EncodedValue_StringResult:
+		return PortableValue{Type: PortableString, Data: []byte(v.StringResult)}, nil
+	case *v1.EncodedValue_JsonObjectResult:
+		return PortableValue{Type: PortableJSON, Data: []byte(v.JsonObjectResult)}, nil
+	case *v1.EncodedValue_NullResult:
+		return PortableValue{Type: PortableNull}, nil
+	case *v1.EncodedValue_CustomObjectResult:
+		return PortableValue{Type: PortableCustom, Data: v.CustomObjectResult}, nil
+	default:
+		return PortableValue{}, errors.New(fmt.Sprintf("cannot export value of type %T", value.Value))
+	}
+}
+
+// ImportValue converts a PortableValue back to an EncodedValue, failing if its type is
+// unknown or its data is not of the type's size.
+func ImportValue(value PortableValue) (*v1.EncodedValue, error) {
+	size := map[string]int{
+		PortableInt:     4,
+		PortableLong:    8,
+		PortableShort:   4,
+		PortableByte:    4,
+		PortableBoolean: 1,
+		PortableDouble:  8,
+		PortableFloat:   4,
+		PortableNull:    0,
+	}
+	if n, ok := size[value.Type]; ok && len(value.Data) != n {
+		return nil, errors.New(fmt.Sprintf("%s value has %d bytes, expected %d", value.Type, len(value.Data), n))
+	}
+
+	ev := &v1.EncodedValue{}
+	data := value.Data
+	switch value.Type {
+	case PortableInt:
+		ev.Value = &v1.EncodedValue_IntResult{IntResult: int32(binary.BigEndian.Uint32(data))}
+	case PortableLong:
+		ev.Value = &v1.EncodedValue_LongResult{LongResult: int64(binary.BigEndian.Uint64(data))}
+	case PortableShort:
+		ev.Value = &v1.EncodedValue_ShortResult{ShortResult: int32(binary.BigEndian.Uint32(data))}
+	case PortableByte:
+		ev.Value = &v1.EncodedValue_ByteResult{ByteResult: int32(binary.BigEndian.Uint32(data))}
+	case PortableBoolean:
+		ev.Value = &v1.EncodedValue_BooleanResult{BooleanResult: data[0] != 0}
+	case PortableDouble:
+		ev.Value = &v1.EncodedValue_DoubleResult{DoubleResult: math.Float64frombits(binary.BigEndian.Uint64(data))}
+	case PortableFloat:
+		ev.Value = &v1.EncodedValue_FloatResult{FloatResult: math.Float32frombits(binary.BigEndian.Uint32(data))}
+	case PortableBinary:
+		ev.Value = &v1.EncodedValue_BinaryResult{BinaryResult: data}
+	case PortableString:
+		ev.Value = &v1.EncodedValue_StringResult{StringResult: string(data)}
+	case PortableJSON:
+		ev.Value = &v1.EncodedValue_JsonObjectResult{JsonObjectResult: string(data)}
+	case PortableNull:
+		ev.Value = &v1.EncodedValue_NullResult{}
+	case PortableCustom:
+		ev.Value = &v1.EncodedValue_CustomObjectResult{CustomObjectResult: data}
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown portable value type %q", value.Type))
+	}
+
+	return ev, nil
+}
+
+func uint32Bytes(n uint32) []byte {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, n)
+	return data
+}
@@ -0,0 +1,101 @@
+package codec_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math"
+
+	"github.com/gemfire/geode-go-client/codec"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Portable values", func() {
+
+	values := []*v1.EncodedValue{
+		{Value: &v1.EncodedValue_IntResult{IntResult: -7}},
+		{Value: &v1.EncodedValue_LongResult{LongResult: math.MaxInt64}},
+		{Value: &v1.EncodedValue_ShortResult{ShortResult: -300}},
+		{Value: &v1.EncodedValue_ByteResult{ByteResult: -1}},
+		{Value: &v1.EncodedValue_ByteResult{ByteResult: 255}},
+		{Value: &v1.EncodedValue_BooleanResult{BooleanResult: true}},
+		{Value: &v1.EncodedValue_DoubleResult{DoubleResult: 4.5}},
+		{Value: &v1.EncodedValue_FloatResult{FloatResult: -0.25}},
+		{Value: &v1.EncodedValue_BinaryResult{BinaryResult: []byte{0, 1, 2}}},
+		{Value: &v1.EncodedValue_StringResult{StringResult: "héllo"}},
+		{Value: &v1.EncodedValue_JsonObjectResult{JsonObjectResult: `{"name":"Fred"}`}},
+		{Value: &v1.EncodedValue_NullResult{}},
+		{Value: &v1.EncodedValue_CustomObjectResult{CustomObjectResult: []byte{9, 8}}},
+	}
+
+	It("round trips every type through gob", func() {
+		for _, value := range values {
+			exported, err := codec.ExportValue(value)
+			Expect(err).ToNot(HaveOccurred())
+
+			var buffer bytes.Buffer
+			Expect(gob.NewEncoder(&buffer).Encode(exported)).To(Succeed())
+			var decoded codec.PortableValue
+			Expect(gob.NewDecoder(&buffer).Decode(&decoded)).To(Succeed())
+
+			imported, err := codec.ImportValue(decoded)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(imported).To(Equal(value))
+		}
+	})
+
+	It("round trips every type through JSON", func() {
+		for _, value := range values {
+			exported, err := codec.ExportValue(value)
+			Expect(err).ToNot(HaveOccurred())
+
+			data, err := json.Marshal(exported)
+			Expect(err).ToNot(HaveOccurred())
+			var decoded codec.PortableValue
+			Expect(json.Unmarshal(data, &decoded)).To(Succeed())
+
+			imported, err := codec.ImportValue(decoded)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(imported).To(Equal(value))
+		}
+	})
+
+	It("has a stable representation", func() {
+		exported, err := codec.ExportValue(&v1.EncodedValue{Value: &v1.EncodedValue_IntResult{IntResult: 258}})
+		Expect(err).ToNot(HaveOccurred())
+
+		data, err := json.Marshal(exported)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(`{"type":"int","data":"AAABAg=="}`))
+	})
+
+	It("imports values encoded by EncodeValue", func() {
+		ev, err := codec.EncodeValue("three")
+		Expect(err).ToNot(HaveOccurred())
+
+		exported, err := codec.ExportValue(ev)
+		Expect(err).ToNot(HaveOccurred())
+		imported, err := codec.ImportValue(exported)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(codec.DecodeValue(imported, nil)).To(Equal("three"))
+	})
+
+	It("rejects values without a type", func() {
+		_, err := codec.ExportValue(&v1.EncodedValue{})
+		Expect(err).To(HaveOccurred())
+
+		_, err = codec.ExportValue(nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects unknown types and data of the wrong size", func() {
+		_, err := codec.ImportValue(codec.PortableValue{Type: "decimal"})
+		Expect(err).To(MatchError(`unknown portable value type "decimal"`))
+
+		_, err = codec.ImportValue(codec.PortableValue{Type: codec.PortableLong, Data: []byte{1}})
+		Expect(err).To(MatchError("long value has 1 bytes, expected 8"))
+	})
+})
@@ -0,0 +1,47 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RoutingDelimiter separates the routing object from the rest of a key, as expected by
+// Geode's StringPrefixPartitionResolver.
+const RoutingDelimiter = "|"
+
+// A RoutedKey is a key whose entry is placed by its Routing rather than the whole key, so
+// that entries with the same Routing share a bucket, including across co-located regions.
+// It is encoded as the string Routing + "|" + Key, which servers place correctly only when
+// the region is configured with org.apache.geode.cache.util.StringPrefixPartitionResolver.
+// RoutedKeys may also be passed in the key filter of a function executed on a region, so
+// that it runs on the servers holding those entries.
+//
+// Keys read back from a region, for example by GetAll, are strings, which ParseRoutedKey
+// turns back into RoutedKeys.
+type RoutedKey struct {
+	Routing string
+	Key     string
+}
+
+// String returns the key as it is stored.
+func (this RoutedKey) String() string {
+	return this.Routing + RoutingDelimiter + this.Key
+}
+
+func (this RoutedKey) validate() error {
+	if this.Routing == "" || strings.Contains(this.Routing, RoutingDelimiter) {
+		return errors.New(fmt.Sprintf("invalid routing %q: must be non-empty and not contain %q", this.Routing, RoutingDelimiter))
+	}
+	return nil
+}
+
+// ParseRoutedKey splits a stored key into its routing and the rest of the key, returning
+// false if it has no routing.
+func ParseRoutedKey(s string) (RoutedKey, bool) {
+	i := strings.Index(s, RoutingDelimiter)
+	if i <= 0 {
+		return RoutedKey{}, false
+	}
+	return RoutedKey{Routing: s[:i], Key: s[i+len(RoutingDelimiter):]}, true
+}
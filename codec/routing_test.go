@@ -0,0 +1,42 @@
+package codec_test
+
+import (
+	"github.com/gemfire/geode-go-client/codec"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Routed keys", func() {
+
+	It("encodes keys with their routing prefix", func() {
+		ev, err := codec.EncodeValue(codec.RoutedKey{Routing: "customer-7", Key: "order|12"})
+		Expect(err).To(BeNil())
+		Expect(ev.GetStringResult()).To(Equal("customer-7|order|12"))
+
+		key, ok := codec.ParseRoutedKey("customer-7|order|12")
+		Expect(ok).To(BeTrue())
+		Expect(key).To(Equal(codec.RoutedKey{Routing: "customer-7", Key: "order|12"}))
+
+		_, ok = codec.ParseRoutedKey("order-12")
+		Expect(ok).To(BeFalse())
+		_, ok = codec.ParseRoutedKey("|order-12")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("rejects routing which would be split differently by the server", func() {
+		for _, routing := range []string{"", "a|b"} {
+			_, err := codec.EncodeValue(codec.RoutedKey{Routing: routing, Key: "k"})
+			Expect(err).To(MatchError(HavePrefix("invalid routing")))
+
+			_, err = codec.KeyHashCode(codec.RoutedKey{Routing: routing, Key: "k"})
+			Expect(err).ToNot(BeNil())
+		}
+	})
+
+	It("places keys by their routing", func() {
+		order, err := codec.BucketID(codec.RoutedKey{Routing: "customer-7", Key: "order-12"}, codec.DefaultTotalBuckets)
+		Expect(err).To(BeNil())
+		Expect(codec.BucketID(codec.RoutedKey{Routing: "customer-7", Key: "invoice-3"}, codec.DefaultTotalBuckets)).To(Equal(order))
+		Expect(codec.BucketID("customer-7", codec.DefaultTotalBuckets)).To(Equal(order))
+	})
+})
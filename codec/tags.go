@@ -0,0 +1,319 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Options of the geode struct tag, which is written geode:"name,option,..."
+const (
+	// Omit the field when its JSON is null, false, 0, an empty string, array or object
+	TagOmitEmpty = "omitempty"
+	// The field identifies the value, see IdentityFields
+	TagIdentity = "identity"
+)
+
+// A field of a struct as it is written by encoding/json and sent to the server
+type geodeField struct {
+	// Key written by encoding/json and the key sent to the server
+	key, name string
+	// Tagged geode:"-", so not sent to the server
+	skip      bool
+	omitEmpty bool
+	identity  bool
+	fieldType reflect.Type
+}
+
+func (this *geodeField) renamed() bool {
+	return this.name != this.key || this.skip || this.omitEmpty
+}
+
+// The fields of a struct, in order
+type geodeStruct struct {
+	fields []geodeField
+}
+
+// How the JSON of a type is rewritten: as an object holding the fields of a struct, or as
+// an array or object whose elements are all of one type
+type geodeShape struct {
+	fields   *geodeStruct
+	elements reflect.Type
+}
+
+var geodeTypes = struct {
+	sync.RWMutex
+	structs map[reflect.Type]*geodeStruct
+	// nil for types whose JSON needs no rewriting
+	shapes map[reflect.Type]*geodeShape
+}{
+	structs: make(map[reflect.Type]*geodeStruct),
+	shapes:  make(map[reflect.Type]*geodeShape),
+}
+
+var jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+var jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Return the shape of the JSON of a type, or nil if its JSON needs no rewriting
+func shapeOf(t reflect.Type) *geodeShape {
+	if t == nil {
+		return nil
+	}
+
+	geodeTypes.RLock()
+	shape, ok := geodeTypes.shapes[t]
+	geodeTypes.RUnlock()
+	if ok {
+		return shape
+	}
+
+	geodeTypes.Lock()
+	defer geodeTypes.Unlock()
+
+	if needsRewrite(t, make(map[reflect.Type]bool)) {
+		if indirect := indirectType(t); indirect.Kind() == reflect.Struct {
+			shape = &geodeShape{fields: buildStruct(indirect)}
+		} else {
+			shape = &geodeShape{elements: indirect.Elem()}
+		}
+	}
+	geodeTypes.shapes[t] = shape
+
+	return shape
+}
+
+// Return whether t, or any type within it, has fields which are tagged to be written
+// differently. Types already visited are skipped, so that recursive types terminate.
+// MUST hold the geodeTypes lock when calling
+func needsRewrite(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	t = indirectType(t)
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+
+	// Types which write their own JSON are left as they are
+	if t.Implements(jsonMarshaler) || reflect.PtrTo(t).Implements(jsonMarshaler) ||
+		reflect.PtrTo(t).Implements(jsonUnmarshaler) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for _, f := range buildStruct(t).fields {
+			if f.renamed() || needsRewrite(f.fieldType, visiting) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		return needsRewrite(t.Elem(), visiting)
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && needsRewrite(t.Elem(), visiting)
+	}
+
+	return false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// MUST hold the geodeTypes lock when calling
+func buildStruct(t reflect.Type) *geodeStruct {
+	if s, ok := geodeTypes.structs[t]; ok {
+		return s
+	}
+
+	s := &geodeStruct{}
+	s.collect(t)
+	geodeTypes.structs[t] = s
+
+	return s
+}
+
+// Add the fields of t which encoding/json writes, including those promoted from embedded
+// structs
+func (this *geodeStruct) collect(t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		key := field.Name
+		jsonName := ""
+		if jsonTag, ok := field.Tag.Lookup("json"); ok {
+			jsonName = strings.Split(jsonTag, ",")[0]
+			if jsonName == "-" {
+				continue
+			}
+			if jsonName != "" {
+				key = jsonName
+			}
+		}
+
+		// encoding/json promotes the fields of untagged embedded structs
+		if field.Anonymous && jsonName == "" && indirectType(field.Type).Kind() == reflect.Struct {
+			this.collect(indirectType(field.Type))
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		f := geodeField{key: key, name: key, fieldType: field.Type}
+		if tag, ok := field.Tag.Lookup("geode"); ok {
+			parts := strings.Split(tag, ",")
+			switch parts[0] {
+			case "":
+			case "-":
+				f.skip = true
+			default:
+				f.name = parts[0]
+			}
+			for _, option := range parts[1:] {
+				switch option {
+				case TagOmitEmpty:
+					f.omitEmpty = true
+				case TagIdentity:
+					f.identity = true
+				}
+			}
+		}
+
+		this.fields = append(this.fields, f)
+	}
+}
+
+// IdentityFields returns the names sent to the server of the fields of a struct, or of the
+// struct a value points to, which are tagged geode:",identity". Identity fields are not used
+// by the JSON encoding, but are declared for the PDX serialization of values, in which they
+// decide whether two values are equal.
+func IdentityFields(value interface{}) []string {
+	t := reflect.TypeOf(value)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	geodeTypes.Lock()
+	defer geodeTypes.Unlock()
+
+	var names []string
+	for _, f := range buildStruct(t).fields {
+		if f.identity && !f.skip {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// Rewrite a JSON document written by encoding/json for the server, or a document from the
+// server for encoding/json to read, according to the geode tags of the type
+func rewriteJSON(t reflect.Type, document json.RawMessage, toServer bool) (json.RawMessage, error) {
+	shape := shapeOf(t)
+	document = bytes.TrimSpace(document)
+	if shape == nil || len(document) == 0 {
+		return document, nil
+	}
+
+	if shape.elements != nil {
+		return rewriteElements(shape.elements, document, toServer)
+	}
+
+	if document[0] != '{' {
+		return document, nil
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(document, &fields); err != nil {
+		return nil, err
+	}
+
+	// Keys which are not fields, such as a schema version, are kept
+	rewritten := make(map[string]json.RawMessage, len(fields))
+	for key, raw := range fields {
+		rewritten[key] = raw
+	}
+	for _, f := range shape.fields.fields {
+		from, to := f.key, f.name
+		if !toServer {
+			from, to = to, from
+		}
+		if !f.skip || toServer {
+			delete(rewritten, from)
+		}
+	}
+
+	for _, f := range shape.fields.fields {
+		from, to := f.key, f.name
+		if !toServer {
+			from, to = to, from
+		}
+		raw, ok := fields[from]
+		if !ok || f.skip {
+			continue
+		}
+		if toServer && f.omitEmpty && isEmptyJSON(raw) {
+			continue
+		}
+
+		raw, err := rewriteJSON(f.fieldType, raw, toServer)
+		if err != nil {
+			return nil, err
+		}
+		rewritten[to] = raw
+	}
+
+	return json.Marshal(rewritten)
+}
+
+// Rewrite each element of a JSON array, or each value of a JSON object
+func rewriteElements(t reflect.Type, document json.RawMessage, toServer bool) (json.RawMessage, error) {
+	switch document[0] {
+	case '[':
+		var elements []json.RawMessage
+		if err := json.Unmarshal(document, &elements); err != nil {
+			return nil, err
+		}
+		for i, element := range elements {
+			rewritten, err := rewriteJSON(t, element, toServer)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = rewritten
+		}
+		return json.Marshal(elements)
+	case '{':
+		elements := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(document, &elements); err != nil {
+			return nil, err
+		}
+		for key, element := range elements {
+			rewritten, err := rewriteJSON(t, element, toServer)
+			if err != nil {
+				return nil, err
+			}
+			elements[key] = rewritten
+		}
+		return json.Marshal(elements)
+	}
+
+	return document, nil
+}
+
+func isEmptyJSON(raw json.RawMessage) bool {
+	switch string(bytes.TrimSpace(raw)) {
+	case "null", "false", `""`, "[]", "{}":
+		return true
+	}
+
+	n, err := strconv.ParseFloat(string(bytes.TrimSpace(raw)), 64)
+	return err == nil && n == 0
+}
@@ -0,0 +1,117 @@
+package codec_test
+
+import (
+	"encoding/json"
+
+	"github.com/gemfire/geode-go-client/codec"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type taggedAddress struct {
+	Street string `json:"street" geode:"line1"`
+}
+
+type taggedCustomer struct {
+	ID        string          `json:"id" geode:"customerId,identity"`
+	Name      string          `geode:"fullName"`
+	Nickname  string          `json:"nickname" geode:",omitempty"`
+	Session   string          `json:"session" geode:"-"`
+	Address   *taggedAddress  `json:"address"`
+	Previous  []taggedAddress `json:"previous" geode:"history"`
+	Unchanged int             `json:"unchanged"`
+}
+
+type taggedNode struct {
+	Value string      `json:"value" geode:"v"`
+	Next  *taggedNode `json:"next"`
+}
+
+var _ = Describe("geode struct tags", func() {
+
+	encode := func(v interface{}) map[string]interface{} {
+		ev, err := codec.EncodeValue(v)
+		Expect(err).To(BeNil())
+
+		fields := make(map[string]interface{})
+		Expect(json.Unmarshal([]byte(ev.GetJsonObjectResult()), &fields)).To(Succeed())
+		return fields
+	}
+
+	It("sends fields to the server under their geode names", func() {
+		customer := &taggedCustomer{
+			ID:       "c1",
+			Name:     "Joe Bloggs",
+			Session:  "secret",
+			Address:  &taggedAddress{Street: "1 Main St"},
+			Previous: []taggedAddress{{Street: "2 Side St"}},
+		}
+
+		Expect(encode(customer)).To(Equal(map[string]interface{}{
+			"customerId": "c1",
+			"fullName":   "Joe Bloggs",
+			"address":    map[string]interface{}{"line1": "1 Main St"},
+			"history":    []interface{}{map[string]interface{}{"line1": "2 Side St"}},
+			"unchanged":  float64(0),
+		}))
+
+		customer.Nickname = "Joe"
+		Expect(encode(customer)).To(HaveKeyWithValue("nickname", "Joe"))
+	})
+
+	It("decodes fields from their geode names", func() {
+		ev := &v1.EncodedValue{Value: &v1.EncodedValue_JsonObjectResult{JsonObjectResult: `{
+			"customerId": "c1", "fullName": "Joe Bloggs", "nickname": "Joe", "session": "ignored",
+			"address": {"line1": "1 Main St"}, "history": [{"line1": "2 Side St"}], "unchanged": 7}`}}
+
+		decoded, err := codec.DecodeValue(ev, &taggedCustomer{})
+		Expect(err).To(BeNil())
+		Expect(decoded).To(Equal(&taggedCustomer{
+			ID:        "c1",
+			Name:      "Joe Bloggs",
+			Nickname:  "Joe",
+			Session:   "ignored",
+			Address:   &taggedAddress{Street: "1 Main St"},
+			Previous:  []taggedAddress{{Street: "2 Side St"}},
+			Unchanged: 7,
+		}))
+	})
+
+	It("rewrites recursive types at every depth", func() {
+		list := &taggedNode{Value: "a", Next: &taggedNode{Value: "b"}}
+		Expect(encode(list)).To(Equal(map[string]interface{}{
+			"v":    "a",
+			"next": map[string]interface{}{"v": "b", "next": nil},
+		}))
+
+		ev, err := codec.EncodeValue(list)
+		Expect(err).To(BeNil())
+		decoded, err := codec.DecodeValue(ev, &taggedNode{})
+		Expect(err).To(BeNil())
+		Expect(decoded).To(Equal(list))
+	})
+
+	It("rewrites the elements of slices and maps", func() {
+		ev, err := codec.EncodeValue(map[string][]taggedAddress{"home": {{Street: "1 Main St"}}})
+		Expect(err).To(BeNil())
+		Expect(ev.GetJsonObjectResult()).To(Equal(`{"home":[{"line1":"1 Main St"}]}`))
+
+		var decoded map[string][]taggedAddress
+		_, err = codec.DecodeValue(ev, &decoded)
+		Expect(err).To(BeNil())
+		Expect(decoded).To(Equal(map[string][]taggedAddress{"home": {{Street: "1 Main St"}}}))
+	})
+
+	It("leaves the JSON of untagged types as encoding/json writes it", func() {
+		ev, err := codec.EncodeValue(&Person{Name: "Joe", Age: 42})
+		Expect(err).To(BeNil())
+		Expect(ev.GetJsonObjectResult()).To(Equal(`{"name":"Joe","age":42}`))
+	})
+
+	It("lists identity fields", func() {
+		Expect(codec.IdentityFields(&taggedCustomer{})).To(Equal([]string{"customerId"}))
+		Expect(codec.IdentityFields(Person{})).To(BeEmpty())
+		Expect(codec.IdentityFields("not a struct")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,229 @@
+package connector
+
+import (
+	"fmt"
+	"time"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+)
+
+// Kinds of Recommendation
+const (
+	// Values are read far more often than written, so caching them on the client, as a near
+	// cache would, saves round trips
+	RecommendNearCache = "nearCache"
+	// GetAll and PutAll operate on many entries at once, see Protobuf.SetBulkChunkSize
+	RecommendChunking = "chunking"
+	// Values are large enough that transferring them dominates the time taken
+	RecommendSmallerValues = "smallerValues"
+)
+
+// The fewest operations on a region in a window for which recommendations are made
+const minAnalysedOperations = 100
+
+// Thresholds above which recommendations are made
+const (
+	nearCacheReadRatio   = 0.9
+	chunkingBulkEntries  = 1000
+	smallerValuesAverage = 1024 * 1024
+)
+
+// RegionAccess describes how a region was used during a window of SetAccessAnalysis. Only
+// Get, GetAll, Put, PutAll, PutIfAbsent and Remove operations which succeeded are counted.
+type RegionAccess struct {
+	Reads  int64 `json:"reads"`
+	Writes int64 `json:"writes"`
+	// Reads as a fraction of all the operations counted
+	ReadRatio float64 `json:"readRatio"`
+	// Average size in bytes of each encoded entry read and written, including its key
+	AverageReadSize  int64 `json:"averageReadSize"`
+	AverageWriteSize int64 `json:"averageWriteSize"`
+	// Average number of entries of each GetAll and PutAll
+	AverageBulkEntries int64 `json:"averageBulkEntries"`
+	// Configuration which may suit the region, given how it was used
+	Recommendations []Recommendation `json:"recommendations,omitempty"`
+}
+
+// A Recommendation suggests configuration suiting how a region is used.
+type Recommendation struct {
+	// One of RecommendNearCache, RecommendChunking or RecommendSmallerValues
+	Kind   string `json:"kind"`
+	Reason string `json:"reason"`
+}
+
+// Totals for a region within a window
+type accessCounts struct {
+	reads, writes         int64
+	readEntries           int64
+	writeEntries          int64
+	readBytes, writeBytes int64
+	bulkOperations        int64
+	bulkEntries           int64
+}
+
+// Access counts for the current and previous windows
+type accessAnalysis struct {
+	window time.Duration
+	// Clock reading when the current window started
+	start     time.Duration
+	current   map[string]*accessCounts
+	last      map[string]*accessCounts
+	completed bool
+}
+
+// SetAccessAnalysis records the reads and writes of each region over windows of the given
+// length, for AccessAnalysis and DebugSnapshot to report with recommendations. This helps to
+// choose the configuration of a region from how an application uses it. Analysis is disabled
+// by default, and by a window of 0.
+func (this *Pool) SetAccessAnalysis(window time.Duration) {
+	now := this.GetClock().Now()
+
+	this.debug.Lock()
+	defer this.debug.Unlock()
+
+	if window <= 0 {
+		this.debug.access = nil
+		return
+	}
+	this.debug.access = &accessAnalysis{
+		window:  window,
+		start:   now,
+		current: make(map[string]*accessCounts),
+	}
+}
+
+// AccessAnalysis returns how each region was used during the last complete window of
+// SetAccessAnalysis or, until a window completes, so far. It returns nil if analysis is
+// disabled.
+func (this *Pool) AccessAnalysis() map[string]RegionAccess {
+	now := this.GetClock().Now()
+
+	this.debug.Lock()
+	defer this.debug.Unlock()
+
+	return this.debug.accessAnalysis(now)
+}
+
+// Count an operation, if analysis is enabled and the operation is a read or write
+// MUST hold the debugStats lock when calling
+func (this *debugStats) recordAccess(request, response *v1.Message, region string, now time.Duration) {
+	if this.access == nil || response == nil {
+		return
+	}
+
+	var read, bulk bool
+	switch request.MessageType.(type) {
+	case *v1.Message_GetRequest:
+		read = true
+	case *v1.Message_GetAllRequest:
+		read, bulk = true, true
+	case *v1.Message_PutAllRequest:
+		bulk = true
+	case *v1.Message_PutRequest, *v1.Message_PutIfAbsentRequest, *v1.Message_RemoveRequest:
+	default:
+		return
+	}
+
+	this.access.roll(now)
+	counts, ok := this.access.current[region]
+	if !ok {
+		counts = &accessCounts{}
+		this.access.current[region] = counts
+	}
+
+	entries, _ := requestKeyCount(request)
+	if read {
+		counts.reads++
+		counts.readEntries += int64(entries)
+		counts.readBytes += int64(proto.Size(response))
+	} else {
+		counts.writes++
+		counts.writeEntries += int64(entries)
+		counts.writeBytes += int64(proto.Size(request))
+	}
+	if bulk {
+		counts.bulkOperations++
+		counts.bulkEntries += int64(entries)
+	}
+}
+
+// MUST hold the debugStats lock when calling
+func (this *debugStats) accessAnalysis(now time.Duration) map[string]RegionAccess {
+	if this.access == nil {
+		return nil
+	}
+
+	this.access.roll(now)
+	counts := this.access.current
+	if this.access.completed {
+		counts = this.access.last
+	}
+
+	regions := make(map[string]RegionAccess, len(counts))
+	for region, c := range counts {
+		regions[region] = c.analyse()
+	}
+	return regions
+}
+
+// Start a new window if the current one has ended
+func (this *accessAnalysis) roll(now time.Duration) {
+	windows := (now - this.start) / this.window
+	if windows < 1 {
+		return
+	}
+
+	if windows == 1 {
+		this.last = this.current
+	} else {
+		// Nothing happened in the previous window
+		this.last = make(map[string]*accessCounts)
+	}
+	this.current = make(map[string]*accessCounts)
+	this.start += windows * this.window
+	this.completed = true
+}
+
+func (this *accessCounts) analyse() RegionAccess {
+	access := RegionAccess{Reads: this.reads, Writes: this.writes}
+	operations := this.reads + this.writes
+	if operations > 0 {
+		access.ReadRatio = float64(this.reads) / float64(operations)
+	}
+	if this.readEntries > 0 {
+		access.AverageReadSize = this.readBytes / this.readEntries
+	}
+	if this.writeEntries > 0 {
+		access.AverageWriteSize = this.writeBytes / this.writeEntries
+	}
+	if this.bulkOperations > 0 {
+		access.AverageBulkEntries = this.bulkEntries / this.bulkOperations
+	}
+
+	if operations < minAnalysedOperations {
+		return access
+	}
+
+	if access.ReadRatio >= nearCacheReadRatio {
+		access.Recommendations = append(access.Recommendations, Recommendation{
+			Kind:   RecommendNearCache,
+			Reason: fmt.Sprintf("%.0f%% of operations are reads", access.ReadRatio*100),
+		})
+	}
+	if access.AverageBulkEntries >= chunkingBulkEntries {
+		access.Recommendations = append(access.Recommendations, Recommendation{
+			Kind:   RecommendChunking,
+			Reason: fmt.Sprintf("GetAll and PutAll average %d entries", access.AverageBulkEntries),
+		})
+	}
+	if access.AverageReadSize >= smallerValuesAverage || access.AverageWriteSize >= smallerValuesAverage {
+		access.Recommendations = append(access.Recommendations, Recommendation{
+			Kind: RecommendSmallerValues,
+			Reason: fmt.Sprintf("entries average %d bytes read and %d bytes written",
+				access.AverageReadSize, access.AverageWriteSize),
+		})
+	}
+
+	return access
+}
@@ -0,0 +1,123 @@
+package connector_test
+
+import (
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Access analysis", func() {
+
+	var pool *connector.Pool
+	var connection *connector.Protobuf
+	var clock *manualClock
+	var response *v1.Message
+
+	getResponse := &v1.Message{
+		MessageType: &v1.Message_GetResponse{GetResponse: &v1.GetResponse{
+			Result: &v1.EncodedValue{Value: &v1.EncodedValue_StringResult{StringResult: "value"}},
+		}},
+	}
+	putResponse := &v1.Message{
+		MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}},
+	}
+
+	BeforeEach(func() {
+		response = putResponse
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(response, b)
+		}
+
+		clock = &manualClock{}
+		pool = connector.NewPool()
+		pool.SetClock(clock)
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+	})
+
+	It("is disabled by default", func() {
+		Expect(connection.Put("foo", "A", 1)).To(Succeed())
+		Expect(pool.AccessAnalysis()).To(BeNil())
+		Expect(pool.DebugSnapshot().Access).To(BeNil())
+	})
+
+	It("counts reads and writes per region", func() {
+		pool.SetAccessAnalysis(time.Minute)
+
+		Expect(connection.Put("foo", "A", 1)).To(Succeed())
+		Expect(connection.Put("bar", "A", 1)).To(Succeed())
+		response = getResponse
+		_, err := connection.Get("foo", "A", nil)
+		Expect(err).To(BeNil())
+		_, err = connection.Get("foo", "B", nil)
+		Expect(err).To(BeNil())
+
+		analysis := pool.AccessAnalysis()
+		Expect(analysis).To(HaveLen(2))
+		Expect(analysis["foo"].Reads).To(Equal(int64(2)))
+		Expect(analysis["foo"].Writes).To(Equal(int64(1)))
+		Expect(analysis["foo"].ReadRatio).To(BeNumerically("~", 2.0/3.0))
+		Expect(analysis["foo"].AverageReadSize).To(BeNumerically(">", 0))
+		Expect(analysis["foo"].AverageWriteSize).To(BeNumerically(">", 0))
+		Expect(analysis["foo"].Recommendations).To(BeEmpty())
+		Expect(analysis["bar"].Writes).To(Equal(int64(1)))
+
+		Expect(pool.DebugSnapshot().Access).To(Equal(analysis))
+	})
+
+	It("reports the last complete window", func() {
+		pool.SetAccessAnalysis(time.Minute)
+		Expect(connection.Put("foo", "A", 1)).To(Succeed())
+
+		clock.now = time.Minute
+		Expect(connection.Put("foo", "A", 1)).To(Succeed())
+		Expect(connection.Put("foo", "A", 1)).To(Succeed())
+		Expect(pool.AccessAnalysis()["foo"].Writes).To(Equal(int64(1)))
+
+		clock.now = 2 * time.Minute
+		Expect(pool.AccessAnalysis()["foo"].Writes).To(Equal(int64(2)))
+
+		clock.now = 4 * time.Minute
+		Expect(pool.AccessAnalysis()).To(BeEmpty())
+	})
+
+	It("recommends a near cache for regions which are mostly read", func() {
+		pool.SetAccessAnalysis(time.Minute)
+		response = getResponse
+		for i := 0; i < 99; i++ {
+			_, err := connection.Get("foo", "A", nil)
+			Expect(err).To(BeNil())
+		}
+		Expect(pool.AccessAnalysis()["foo"].Recommendations).To(BeEmpty())
+
+		response = putResponse
+		Expect(connection.Put("foo", "A", 1)).To(Succeed())
+		Expect(pool.AccessAnalysis()["foo"].Recommendations).To(Equal([]connector.Recommendation{
+			{Kind: connector.RecommendNearCache, Reason: "99% of operations are reads"},
+		}))
+	})
+
+	It("recommends chunking for large bulk operations", func() {
+		pool.SetAccessAnalysis(time.Minute)
+		response = &v1.Message{MessageType: &v1.Message_PutAllResponse{PutAllResponse: &v1.PutAllResponse{}}}
+		entries := make(map[int]int, 1000)
+		for i := 0; i < 1000; i++ {
+			entries[i] = i
+		}
+		for i := 0; i < 100; i++ {
+			_, err := connection.PutAll("foo", entries)
+			Expect(err).To(BeNil())
+		}
+
+		analysis := pool.AccessAnalysis()["foo"]
+		Expect(analysis.AverageBulkEntries).To(Equal(int64(1000)))
+		Expect(analysis.Recommendations).To(Equal([]connector.Recommendation{
+			{Kind: connector.RecommendChunking, Reason: "GetAll and PutAll average 1000 entries"},
+		}))
+	})
+})
@@ -0,0 +1,126 @@
+package connector
+
+import (
+	"sync"
+	"time"
+)
+
+// AdaptiveSizing configures an AdaptiveSizer.
+type AdaptiveSizing struct {
+	// Bounds for the pool's connection limit
+	MinConnections int
+	MaxConnections int
+	// The limit is raised when callers wait longer than this on average
+	TargetWait time.Duration
+	// The limit is lowered when nobody waited and the fraction of connections in use is
+	// below this, between 0 and 1
+	LowUtilization float64
+	// How often the limit is adjusted by Start
+	Interval time.Duration
+}
+
+// An AdaptiveSizer adjusts the connection limit of a Pool (see Pool.SetMaxConnections) based
+// on how long callers wait for connections and how many connections are in use, so that
+// the limit need not be tuned by hand.
+type AdaptiveSizer struct {
+	sync.Mutex
+	pool   *Pool
+	config AdaptiveSizing
+	last   PoolStats
+}
+
+// NewAdaptiveSizer creates a sizer for the pool and brings the pool's current limit within
+// the configured bounds.
+func NewAdaptiveSizer(pool *Pool, config AdaptiveSizing) *AdaptiveSizer {
+	if config.MinConnections < 1 {
+		config.MinConnections = 1
+	}
+	if config.MaxConnections < config.MinConnections {
+		config.MaxConnections = config.MinConnections
+	}
+
+	limit := pool.GetMaxConnections()
+	if limit < config.MinConnections {
+		pool.SetMaxConnections(config.MinConnections)
+	} else if limit > config.MaxConnections {
+		pool.SetMaxConnections(config.MaxConnections)
+	}
+
+	return &AdaptiveSizer{
+		pool:   pool,
+		config: config,
+		last:   pool.Stats(),
+	}
+}
+
+// Adjust compares the pool's statistics with those at the previous adjustment and raises
+// or lowers the limit as needed, returning the new limit. The limit grows by a quarter
+// (at least one connection) at a time and shrinks by one. Callers which are still waiting
+// count towards the average wait, so the limit keeps growing while they are starved.
+func (this *AdaptiveSizer) Adjust() int {
+	this.Lock()
+	defer this.Unlock()
+
+	stats := this.pool.Stats()
+	waits := stats.Waits - this.last.Waits
+	waitTime := stats.WaitTime - this.last.WaitTime
+	this.last = stats
+
+	limit := stats.MaxConnections
+
+	// Callers waiting since an earlier adjustment are not counted in waits
+	callers := waits
+	if int64(stats.Waiting) > callers {
+		callers = int64(stats.Waiting)
+	}
+
+	if callers > 0 && waitTime/time.Duration(callers) > this.config.TargetWait {
+		step := limit / 4
+		if step < 1 {
+			step = 1
+		}
+		limit += step
+	} else if waits == 0 && stats.Waiting == 0 &&
+		float64(stats.InUse) < this.config.LowUtilization*float64(limit) {
+		limit--
+	}
+
+	if limit < this.config.MinConnections {
+		limit = this.config.MinConnections
+	} else if limit > this.config.MaxConnections {
+		limit = this.config.MaxConnections
+	}
+
+	if limit != stats.MaxConnections {
+		this.pool.SetMaxConnections(limit)
+	}
+
+	return limit
+}
+
+// Start calls Adjust at the configured interval until the returned function is called.
+func (this *AdaptiveSizer) Start() (stop func()) {
+	interval := this.config.Interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				this.Adjust()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
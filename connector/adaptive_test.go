@@ -0,0 +1,97 @@
+package connector_test
+
+import (
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Adaptive pool sizing", func() {
+
+	var pool *connector.Pool
+	var conns []*connectorfakes.FakeConn
+	var config connector.AdaptiveSizing
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+		conns = []*connectorfakes.FakeConn{new(connectorfakes.FakeConn), new(connectorfakes.FakeConn)}
+		for _, c := range conns {
+			pool.AddConnection(c, true)
+		}
+		pool.SetClock(&steppingClock{step: 10 * time.Millisecond})
+
+		config = connector.AdaptiveSizing{
+			MinConnections: 1,
+			MaxConnections: 3,
+			TargetWait:     5 * time.Millisecond,
+			LowUtilization: 0.5,
+		}
+	})
+
+	It("brings the limit within bounds", func() {
+		connector.NewAdaptiveSizer(pool, config)
+		Expect(pool.GetMaxConnections()).To(Equal(1))
+	})
+
+	It("grows when callers wait too long and shrinks when idle", func() {
+		sizer := connector.NewAdaptiveSizer(pool, config)
+
+		held, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			c, err := pool.GetConnection()
+			Expect(err).To(BeNil())
+			pool.ReturnConnection(c)
+			close(done)
+		}()
+		Eventually(func() int { return pool.Stats().Waiting }).Should(Equal(1))
+
+		pool.ReturnConnection(held)
+		Eventually(done).Should(BeClosed())
+
+		Expect(sizer.Adjust()).To(Equal(2))
+		Expect(pool.GetMaxConnections()).To(Equal(2))
+
+		Expect(sizer.Adjust()).To(Equal(1))
+		Expect(sizer.Adjust()).To(Equal(1))
+	})
+
+	It("grows while callers are still waiting", func() {
+		sizer := connector.NewAdaptiveSizer(pool, config)
+
+		held, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			c, err := pool.GetConnection()
+			Expect(err).To(BeNil())
+			pool.ReturnConnection(c)
+			close(done)
+		}()
+		Eventually(func() int { return pool.Stats().Waiting }).Should(Equal(1))
+
+		// There is no server to open another connection to, so the caller remains starved
+		Expect(sizer.Adjust()).To(Equal(2))
+		Eventually(func() int { return pool.Stats().Waiting }).Should(Equal(1))
+		Expect(sizer.Adjust()).To(Equal(3))
+
+		pool.ReturnConnection(held)
+		Eventually(done).Should(BeClosed())
+	})
+
+	It("closes idle connections beyond a lowered limit", func() {
+		pool.SetMaxConnections(2)
+		pool.SetMaxConnections(1)
+
+		Expect(pool.Stats().Connections).To(Equal(1))
+		Expect(conns[0].CloseCallCount() + conns[1].CloseCallCount()).To(Equal(1))
+	})
+})
@@ -0,0 +1,95 @@
+package connector
+
+import (
+	"sync"
+)
+
+// Property carrying a token, as read by Geode's security managers
+const securityTokenProperty = "security-token"
+
+// An Authenticator supplies the properties, such as "security-username" and
+// "security-password", which authenticate a new connection, and is asked for them each time
+// a connection is authenticated. This lets credentials which expire, such as OAuth tokens,
+// be refreshed without rebuilding the pool. An error fails the connection.
+//
+// Credentials is called while the pool is locked, so it should not block for long; an
+// Authenticator which fetches tokens from a remote service should cache them.
+type Authenticator interface {
+	Credentials() (map[string]string, error)
+}
+
+// AuthenticatorFunc adapts a function to an Authenticator.
+type AuthenticatorFunc func() (map[string]string, error)
+
+func (f AuthenticatorFunc) Credentials() (map[string]string, error) {
+	return f()
+}
+
+// TokenAuthenticator returns an Authenticator which sends the token returned by token as the
+// "security-token" property.
+func TokenAuthenticator(token func() (string, error)) Authenticator {
+	return AuthenticatorFunc(func() (map[string]string, error) {
+		t, err := token()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{securityTokenProperty: t}, nil
+	})
+}
+
+// RotatingCredentials is an Authenticator for a username and password which may be
+// replaced, such as when a secret is rotated. Connections which are already authenticated
+// are unaffected by a change.
+type RotatingCredentials struct {
+	sync.RWMutex
+	username string
+	password string
+}
+
+func NewRotatingCredentials(username, password string) *RotatingCredentials {
+	return &RotatingCredentials{username: username, password: password}
+}
+
+// Set replaces the username and password used for new connections.
+func (this *RotatingCredentials) Set(username, password string) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.username = username
+	this.password = password
+}
+
+func (this *RotatingCredentials) Credentials() (map[string]string, error) {
+	this.RLock()
+	defer this.RUnlock()
+
+	return map[string]string{
+		"security-username": this.username,
+		"security-password": this.password,
+	}, nil
+}
+
+// SetAuthenticator authenticates new connections with the credentials of an Authenticator,
+// in place of those given to AddCredentials. A nil Authenticator disables authentication.
+func (this *Pool) SetAuthenticator(authenticator Authenticator) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.authenticator = authenticator
+	this.authenticationEnabled = authenticator != nil
+	this.syncPartitions()
+}
+
+// Return the properties identifying the user, from the authenticator if there is one
+// MUST hold the pool lock when calling
+func (this *Pool) userCredentials() (credentials map[string]string, err error) {
+	if this.authenticator == nil {
+		return map[string]string{
+			"security-username": this.username,
+			"security-password": this.password,
+		}, nil
+	}
+
+	defer RecoverCallback("Authenticator.Credentials", &err)
+	return this.authenticator.Credentials()
+}
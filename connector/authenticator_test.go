@@ -0,0 +1,115 @@
+package connector_test
+
+import (
+	"errors"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authenticator", func() {
+
+	var pool *connector.Pool
+	var fakeConn *connectorfakes.FakeConn
+	var credentials map[string]string
+
+	BeforeEach(func() {
+		credentials = nil
+		fakeConn = new(connectorfakes.FakeConn)
+		fakeConn.WriteStub = func(b []byte) (int, error) {
+			message := &v1.Message{}
+			if err := proto.NewBuffer(b).DecodeMessage(message); err != nil {
+				return 0, err
+			}
+			if handshake := message.GetHandshakeRequest(); handshake != nil {
+				credentials = handshake.Credentials
+			}
+			return len(b), nil
+		}
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_HandshakeResponse{
+					HandshakeResponse: &v1.HandshakeResponse{Authenticated: true},
+				},
+			}, b)
+		}
+
+		pool = connector.NewPool()
+	})
+
+	authenticate := func(pool *connector.Pool) error {
+		pool.AddConnection(fakeConn, true)
+		c, err := pool.GetConnection()
+		if err == nil {
+			pool.ReturnConnection(c)
+		}
+		return err
+	}
+
+	It("sends tokens", func() {
+		pool.SetAuthenticator(connector.TokenAuthenticator(func() (string, error) {
+			return "abc123", nil
+		}))
+
+		Expect(authenticate(pool)).To(Succeed())
+		Expect(credentials).To(HaveKeyWithValue("security-token", "abc123"))
+		Expect(credentials).ToNot(HaveKey("security-username"))
+	})
+
+	It("is asked for credentials for each connection", func() {
+		calls := 0
+		pool.SetAuthenticator(connector.AuthenticatorFunc(func() (map[string]string, error) {
+			calls++
+			return map[string]string{"security-token": "t"}, nil
+		}))
+
+		Expect(authenticate(pool)).To(Succeed())
+		Expect(authenticate(pool)).To(Succeed())
+		Expect(calls).To(Equal(2))
+	})
+
+	It("uses rotated credentials for new connections", func() {
+		rotating := connector.NewRotatingCredentials("cluster", "old")
+		pool.SetAuthenticator(rotating)
+		Expect(authenticate(pool)).To(Succeed())
+		Expect(credentials).To(HaveKeyWithValue("security-password", "old"))
+
+		rotating.Set("cluster", "new")
+		Expect(authenticate(pool)).To(Succeed())
+		Expect(credentials).To(HaveKeyWithValue("security-password", "new"))
+	})
+
+	It("fails connections when it fails", func() {
+		failure := errors.New("token service unavailable")
+		pool.SetAuthenticator(connector.AuthenticatorFunc(func() (map[string]string, error) {
+			return nil, failure
+		}))
+
+		Expect(authenticate(pool)).To(Equal(failure))
+		Expect(credentials).To(BeNil())
+	})
+
+	It("is replaced by AddCredentials", func() {
+		pool.SetAuthenticator(connector.TokenAuthenticator(func() (string, error) {
+			return "abc123", nil
+		}))
+		pool.AddCredentials("cluster", "secret")
+
+		Expect(authenticate(pool)).To(Succeed())
+		Expect(credentials).To(HaveKeyWithValue("security-username", "cluster"))
+		Expect(credentials).ToNot(HaveKey("security-token"))
+	})
+
+	It("is shared with partitions", func() {
+		pool.SetAuthenticator(connector.TokenAuthenticator(func() (string, error) {
+			return "abc123", nil
+		}))
+
+		Expect(authenticate(pool.Partition("bulk"))).To(Succeed())
+		Expect(credentials).To(HaveKeyWithValue("security-token", "abc123"))
+	})
+})
@@ -0,0 +1,143 @@
+package connector
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// A LoadBalancing strategy chooses the server of each connection a pool hands out, so that
+// operations are spread over the servers instead of reusing whichever connection happens to
+// be idle, which can leave all of the traffic on one server.
+type LoadBalancing string
+
+const (
+	// Reuse any idle connection, opening one only when none is idle. The default.
+	ReuseConnections LoadBalancing = ""
+	// Take turns between the servers.
+	RoundRobin LoadBalancing = "roundRobin"
+	// Choose the server with the fewest connections in use.
+	LeastInUse LoadBalancing = "leastInUse"
+	// Choose a server at random.
+	RandomServer LoadBalancing = "random"
+)
+
+func (this LoadBalancing) validate() error {
+	switch this {
+	case ReuseConnections, RoundRobin, LeastInUse, RandomServer:
+		return nil
+	}
+	return errors.New(fmt.Sprintf("invalid loadBalancing %s", string(this)))
+}
+
+// SetLoadBalancing sets the strategy choosing the server of each connection. The pool uses an
+// idle connection to the chosen server if it has one and otherwise opens one, unless it
+// already holds as many connections as it may (see SetMaxConnections), in which case any idle
+// connection is used. Only servers which are healthy, available according to the
+// FailureDetector, not quarantined and, with subsetting, in the subset are chosen; when there
+// are none, or the chosen server cannot be reached, connections are acquired as they are
+// without load balancing.
+func (this *Pool) SetLoadBalancing(strategy LoadBalancing) error {
+	if err := strategy.validate(); err != nil {
+		return err
+	}
+
+	this.Lock()
+	defer this.Unlock()
+
+	this.balancing = strategy
+	this.syncPartitions()
+	return nil
+}
+
+// GetLoadBalancing returns the pool's load balancing strategy.
+func (this *Pool) GetLoadBalancing() LoadBalancing {
+	this.RLock()
+	defer this.RUnlock()
+
+	return this.balancing
+}
+
+// Return an idle or new connection to the server chosen by the load balancing strategy, or nil
+// if no server can be chosen or a new connection cannot be opened to it
+// MUST hold the pool lock when calling
+func (this *Pool) balancedConnection() *GeodeConnection {
+	if this.balancing == ReuseConnections {
+		return nil
+	}
+
+	target := this.chooseServer()
+	if target == nil {
+		return nil
+	}
+
+	for _, c := range this.recentConnections {
+		if !c.inUse && c.provider == target {
+			return c
+		}
+	}
+
+	if this.maxConnections > 0 && len(this.recentConnections) >= this.maxConnections {
+		return nil
+	}
+
+	gConn := this.connectProvider(target)
+	if gConn != nil {
+		this.recentConnections = append(this.recentConnections, gConn)
+		this.metricsPublisher().Add(MetricConnectionsCreated, 1)
+		this.metricsPublisher().Add(MetricOpenConnections, 1)
+		this.connectionOpened()
+	}
+	return gConn
+}
+
+// Choose a server by the load balancing strategy among those which may be used
+// MUST hold the pool lock when calling
+func (this *Pool) chooseServer() ConnectionProvider {
+	detector := this.failureDetector()
+	servers := make([]ConnectionProvider, 0, len(this.providers))
+	for i := len(this.providers) - 1; i >= 0; i-- {
+		provider := this.providers[i]
+		address := providerAddress(provider)
+		if providerHealthy(provider) && this.inSubset(provider) && detector.Available(address) && !this.isQuarantined(address) {
+			servers = append(servers, provider)
+		}
+	}
+	if len(servers) == 0 {
+		return nil
+	}
+
+	switch this.balancing {
+	case RoundRobin:
+		this.balanceTurn++
+		return servers[this.balanceTurn%uint64(len(servers))]
+	case RandomServer:
+		return servers[rand.Intn(len(servers))]
+	case LeastInUse:
+		return this.leastInUse(servers)
+	}
+	return nil
+}
+
+// Return the server with the fewest connections in use, preferring one with an idle
+// connection and then the first
+// MUST hold the pool lock when calling
+func (this *Pool) leastInUse(servers []ConnectionProvider) ConnectionProvider {
+	inUse := make(map[ConnectionProvider]int, len(servers))
+	idle := make(map[ConnectionProvider]bool, len(servers))
+	for _, c := range this.recentConnections {
+		if c.inUse {
+			inUse[c.provider]++
+		} else {
+			idle[c.provider] = true
+		}
+	}
+
+	least := servers[0]
+	for _, server := range servers[1:] {
+		if inUse[server] < inUse[least] || (inUse[server] == inUse[least] && idle[server] && !idle[least]) {
+			least = server
+		}
+	}
+	return least
+}
@@ -0,0 +1,103 @@
+package connector_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Load balancing", func() {
+
+	var pool *connector.Pool
+	var providers []*fakeProvider
+
+	// Acquire and return a connection n times in turn
+	cycle := func(n int) {
+		for i := 0; i < n; i++ {
+			gConn, err := pool.GetConnection()
+			Expect(err).To(BeNil())
+			pool.ReturnConnection(gConn)
+		}
+	}
+
+	opened := func() []int {
+		counts := make([]int, 0, len(providers))
+		for _, provider := range providers {
+			counts = append(counts, len(provider.opened))
+		}
+		return counts
+	}
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+		providers = nil
+		for i := 0; i < 3; i++ {
+			provider := &fakeProvider{name: fmt.Sprintf("server-%d:40404", i), healthy: true}
+			providers = append(providers, provider)
+			pool.AddProvider(provider)
+		}
+	})
+
+	It("reuses the idle connection by default", func() {
+		cycle(6)
+		Expect(opened()).To(ConsistOf(1, 0, 0))
+	})
+
+	It("takes turns between the servers", func() {
+		Expect(pool.SetLoadBalancing(connector.RoundRobin)).To(Succeed())
+
+		cycle(6)
+		Expect(opened()).To(Equal([]int{1, 1, 1}))
+	})
+
+	It("chooses the server with the fewest connections in use", func() {
+		Expect(pool.SetLoadBalancing(connector.LeastInUse)).To(Succeed())
+
+		for i := 0; i < 3; i++ {
+			gConn, err := pool.GetConnection()
+			Expect(err).To(BeNil())
+			defer pool.ReturnConnection(gConn)
+		}
+		Expect(opened()).To(Equal([]int{1, 1, 1}))
+
+		cycle(3)
+		Expect(opened()).To(ConsistOf(2, 1, 1))
+	})
+
+	It("spreads connections over the servers at random", func() {
+		Expect(pool.SetLoadBalancing(connector.RandomServer)).To(Succeed())
+
+		cycle(50)
+		Expect(opened()).To(Equal([]int{1, 1, 1}))
+	})
+
+	It("uses an idle connection rather than wait at the connection limit", func() {
+		Expect(pool.SetLoadBalancing(connector.RoundRobin)).To(Succeed())
+		pool.SetMaxConnections(1)
+
+		cycle(3)
+		Expect(opened()).To(ConsistOf(1, 0, 0))
+	})
+
+	It("skips servers which are not healthy", func() {
+		Expect(pool.SetLoadBalancing(connector.RoundRobin)).To(Succeed())
+		providers[1].healthy = false
+
+		cycle(6)
+		Expect(opened()).To(Equal([]int{1, 0, 1}))
+	})
+
+	It("is selected by the pool's configuration", func() {
+		config := &connector.Config{}
+		Expect(json.Unmarshal([]byte(`{"loadBalancing": "leastInUse"}`), config)).To(Succeed())
+		Expect(pool.Configure(config)).To(Succeed())
+		Expect(pool.GetLoadBalancing()).To(Equal(connector.LeastInUse))
+
+		invalid := connector.LoadBalancing("fastest")
+		Expect(pool.Configure(&connector.Config{LoadBalancing: &invalid})).To(MatchError(ContainSubstring("invalid loadBalancing")))
+		Expect(pool.SetLoadBalancing(invalid)).NotTo(Succeed())
+	})
+})
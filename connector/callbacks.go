@@ -0,0 +1,117 @@
+package connector
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// A CallbackPanicError is returned when a callback supplied to the client, such as a
+// transform, quota, schema migration, key provider or config source, panics. Panics in
+// callbacks which cannot fail, such as a MetricsPublisher or FailureDetector, are only passed
+// to the handler set with SetCallbackPanicHandler. Either way the client, and any pool lock
+// held at the time, remains usable.
+type CallbackPanicError struct {
+	// The kind of callback, for example "MetricsPublisher"
+	Callback string
+	// The value passed to panic and the stack of the panicking goroutine
+	Value interface{}
+	Stack []byte
+}
+
+func (e *CallbackPanicError) Error() string {
+	return fmt.Sprintf("%s panicked: %v", e.Callback, e.Value)
+}
+
+var callbackPanicLock sync.RWMutex
+var callbackPanicHandler func(*CallbackPanicError)
+
+// SetCallbackPanicHandler sets a function to be told of every panic recovered from a
+// callback, for example to log it. It is called on the goroutine which panicked, possibly
+// while a pool lock is held, so it must not use the client. A panic in the handler itself
+// is ignored.
+func SetCallbackPanicHandler(handler func(*CallbackPanicError)) {
+	callbackPanicLock.Lock()
+	defer callbackPanicLock.Unlock()
+
+	callbackPanicHandler = handler
+}
+
+// RecoverCallback recovers a panic in a callback, naming the callback in the resulting
+// CallbackPanicError. It must be deferred directly by the function which invokes the
+// callback. The error is stored in err, unless err is nil, and passed to the handler set
+// with SetCallbackPanicHandler.
+func RecoverCallback(callback string, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	panicErr := &CallbackPanicError{Callback: callback, Value: r, Stack: debug.Stack()}
+	if err != nil {
+		*err = panicErr
+	}
+
+	callbackPanicLock.RLock()
+	handler := callbackPanicHandler
+	callbackPanicLock.RUnlock()
+
+	if handler != nil {
+		defer func() { recover() }()
+		handler(panicErr)
+	}
+}
+
+// A MetricsPublisher whose panics are recovered
+type guardedPublisher struct {
+	publisher MetricsPublisher
+}
+
+func (this guardedPublisher) Add(name string, delta int64) {
+	defer RecoverCallback("MetricsPublisher", nil)
+	this.publisher.Add(name, delta)
+}
+
+func (this guardedPublisher) AddKeyed(name, key string, delta int64) {
+	defer RecoverCallback("MetricsPublisher", nil)
+	this.publisher.AddKeyed(name, key, delta)
+}
+
+func (this guardedPublisher) ObserveLatency(name, operation string, latency time.Duration) {
+	defer RecoverCallback("MetricsPublisher", nil)
+	if publisher, ok := this.publisher.(LatencyPublisher); ok {
+		publisher.ObserveLatency(name, operation, latency)
+	}
+}
+
+func (this guardedPublisher) AddError(name, operation, code string, delta int64) {
+	defer RecoverCallback("MetricsPublisher", nil)
+	if publisher, ok := this.publisher.(ErrorPublisher); ok {
+		publisher.AddError(name, operation, code, delta)
+	} else {
+		this.publisher.AddKeyed(name, code, delta)
+	}
+}
+
+// A FailureDetector whose panics are recovered. A server is considered available if the
+// detector panics, so that a faulty detector cannot take every server out of use.
+type guardedDetector struct {
+	detector FailureDetector
+}
+
+func (this guardedDetector) Success(server string, rtt time.Duration) {
+	defer RecoverCallback("FailureDetector", nil)
+	this.detector.Success(server, rtt)
+}
+
+func (this guardedDetector) Failure(server string) {
+	defer RecoverCallback("FailureDetector", nil)
+	this.detector.Failure(server)
+}
+
+func (this guardedDetector) Available(server string) (available bool) {
+	available = true
+	defer RecoverCallback("FailureDetector", nil)
+	return this.detector.Available(server)
+}
@@ -0,0 +1,143 @@
+package connector_test
+
+import (
+	"net"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type panickingPublisher struct{}
+
+func (panickingPublisher) Add(name string, delta int64)           { panic("Add") }
+func (panickingPublisher) AddKeyed(name, key string, delta int64) { panic("AddKeyed") }
+func (panickingPublisher) ObserveLatency(name, operation string, latency time.Duration) {
+	panic("ObserveLatency")
+}
+
+type panickingDetector struct{}
+
+func (panickingDetector) Success(server string, rtt time.Duration) { panic("Success") }
+func (panickingDetector) Failure(server string)                    { panic("Failure") }
+func (panickingDetector) Available(server string) bool             { panic("Available") }
+
+type panickingKeyProvider struct{}
+
+func (panickingKeyProvider) GetKey() ([]byte, error) { panic("GetKey") }
+
+var _ = Describe("Callback panics", func() {
+
+	var panics chan *connector.CallbackPanicError
+
+	BeforeEach(func() {
+		panics = make(chan *connector.CallbackPanicError, 100)
+		connector.SetCallbackPanicHandler(func(err *connector.CallbackPanicError) {
+			panics <- err
+		})
+	})
+
+	AfterEach(func() {
+		connector.SetCallbackPanicHandler(nil)
+	})
+
+	It("keeps the pool usable when the metrics publisher panics", func() {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}},
+			}, b)
+		}
+		pool := connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		pool.SetMetricsPublisher(panickingPublisher{})
+		connection := connector.NewConnector(pool)
+
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+		Expect(connection.Put("foo", "B", 1)).To(BeNil())
+		Expect(pool.Stats().InUse).To(Equal(0))
+
+		var err *connector.CallbackPanicError
+		Expect(panics).To(Receive(&err))
+		Expect(err.Callback).To(Equal("MetricsPublisher"))
+		Expect(err.Stack).ToNot(BeEmpty())
+	})
+
+	It("keeps using servers when the failure detector panics", func() {
+		server := newHandshakeServer()
+		defer server.listener.Close()
+
+		pool := connector.NewPool()
+		pool.SetFailureDetector(panickingDetector{})
+		host, port, err := net.SplitHostPort(server.address())
+		Expect(err).To(BeNil())
+		p, err := net.LookupPort("tcp", port)
+		Expect(err).To(BeNil())
+		pool.AddServer(host, p)
+
+		c, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		pool.ReturnConnection(c)
+
+		var panicErr *connector.CallbackPanicError
+		Expect(panics).To(Receive(&panicErr))
+		Expect(panicErr.Callback).To(Equal("FailureDetector"))
+	})
+
+	Context("Values", func() {
+		var connection *connector.Protobuf
+		var store *fakeStore
+
+		BeforeEach(func() {
+			fakeConn := new(connectorfakes.FakeConn)
+			pool := connector.NewPool()
+			pool.AddConnection(fakeConn, true)
+			connection = connector.NewConnector(pool)
+			store = newFakeStore(fakeConn)
+		})
+
+		It("returns an error when a schema migration panics", func() {
+			registry := connector.NewSchemaRegistry()
+			registry.Register(&PersonV3{}, 2)
+			registry.AddMigration(&PersonV3{}, 1, func(fields map[string]interface{}) error {
+				panic("migration")
+			})
+			connection.SetSchemaRegistry(registry)
+			store.value = &v1.EncodedValue{
+				Value: &v1.EncodedValue_JsonObjectResult{JsonObjectResult: `{"firstName":"Joe"}`},
+			}
+
+			_, err := connection.Get("foo", "A", &PersonV3{})
+			Expect(err).To(BeAssignableToTypeOf(&connector.CallbackPanicError{}))
+			Expect(err.(*connector.CallbackPanicError).Callback).To(Equal("SchemaMigration"))
+			Expect(err.(*connector.CallbackPanicError).Value).To(Equal("migration"))
+		})
+
+		It("returns an error when the key provider panics", func() {
+			type Secret struct {
+				Value string `json:"value" geode:",encrypt"`
+			}
+			connection.SetKeyProvider(panickingKeyProvider{})
+
+			err := connection.Put("foo", "A", &Secret{Value: "x"})
+			Expect(err).To(BeAssignableToTypeOf(&connector.CallbackPanicError{}))
+			Expect(err.(*connector.CallbackPanicError).Callback).To(Equal("KeyProvider"))
+		})
+	})
+
+	It("ignores a panic in the handler", func() {
+		connector.SetCallbackPanicHandler(func(err *connector.CallbackPanicError) {
+			panic("handler")
+		})
+
+		var err error
+		func() {
+			defer connector.RecoverCallback("test", &err)
+			panic("callback")
+		}()
+		Expect(err).To(MatchError("test panicked: callback"))
+	})
+})
@@ -0,0 +1,75 @@
+package connector
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+)
+
+// Marks a binary value as a checksummed envelope: the magic, the serialized EncodedValue
+// and, finally, a big-endian CRC32 (IEEE) of everything preceding it.
+var checksumMagic = []byte{0x00, 'g', 'c', 'r', 'c', '3', '2', 0x01}
+
+const checksumLength = 4
+
+// An IntegrityError is returned when a value read from a region does not match the checksum
+// which was stored alongside it, indicating that it was altered after being written.
+type IntegrityError struct {
+	Expected uint32
+	Actual   uint32
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("value failed integrity check: expected checksum %08x, got %08x", e.Expected, e.Actual)
+}
+
+// Wrap an encoded value in a checksummed envelope. The result is stored on the server as a
+// byte array and so can no longer be used in server-side queries.
+func wrapChecksum(ev *v1.EncodedValue) (*v1.EncodedValue, error) {
+	inner, err := proto.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := make([]byte, 0, len(checksumMagic)+len(inner)+checksumLength)
+	envelope = append(envelope, checksumMagic...)
+	envelope = append(envelope, inner...)
+
+	sum := make([]byte, checksumLength)
+	binary.BigEndian.PutUint32(sum, crc32.ChecksumIEEE(envelope))
+	envelope = append(envelope, sum...)
+
+	return &v1.EncodedValue{Value: &v1.EncodedValue_BinaryResult{BinaryResult: envelope}}, nil
+}
+
+// Verify and unwrap a checksummed envelope. Values which were not written with a checksum
+// are returned unchanged.
+func unwrapChecksum(ev *v1.EncodedValue) (*v1.EncodedValue, error) {
+	envelope := ev.GetBinaryResult()
+	if !bytes.HasPrefix(envelope, checksumMagic) {
+		return ev, nil
+	}
+
+	if len(envelope) < len(checksumMagic)+checksumLength {
+		return nil, errors.New("checksummed value is truncated")
+	}
+
+	body := envelope[:len(envelope)-checksumLength]
+	expected := binary.BigEndian.Uint32(envelope[len(body):])
+	actual := crc32.ChecksumIEEE(body)
+	if expected != actual {
+		return nil, &IntegrityError{Expected: expected, Actual: actual}
+	}
+
+	inner := &v1.EncodedValue{}
+	if err := proto.Unmarshal(body[len(checksumMagic):], inner); err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to decode checksummed value: %s", err.Error()))
+	}
+
+	return inner, nil
+}
@@ -0,0 +1,48 @@
+package connector_test
+
+import (
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checksum verification", func() {
+
+	var connection *connector.Protobuf
+	var fakeConn *connectorfakes.FakeConn
+	var store *fakeStore
+
+	BeforeEach(func() {
+		fakeConn = new(connectorfakes.FakeConn)
+		pool := connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+		connection.SetChecksumVerification(true)
+
+		store = newFakeStore(fakeConn)
+	})
+
+	It("round trips checksummed values", func() {
+		Expect(connection.Put("foo", "A", "Hello World")).To(BeNil())
+		Expect(store.value.GetBinaryResult()).ToNot(BeEmpty())
+
+		Expect(connection.Get("foo", "A", nil)).To(Equal("Hello World"))
+	})
+
+	It("returns an IntegrityError for corrupted values", func() {
+		Expect(connection.Put("foo", "A", &TestStruct{Value: 1, Message: "Hello"})).To(BeNil())
+
+		corrupted := store.value.GetBinaryResult()
+		corrupted[len(corrupted)/2] ^= 0xff
+
+		_, err := connection.Get("foo", "A", &TestStruct{})
+		Expect(err).To(BeAssignableToTypeOf(&connector.IntegrityError{}))
+	})
+
+	It("passes through values written without a checksum", func() {
+		store.value, _ = connector.EncodeValue(int32(7))
+
+		Expect(connection.Get("foo", "A", nil)).To(Equal(int32(7)))
+	})
+})
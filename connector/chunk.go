@@ -0,0 +1,76 @@
+package connector
+
+import (
+	"fmt"
+)
+
+// A ChunkError describes the failure of a single chunk of a chunked PutAll or GetAll, such as
+// a connection error or an error response from the server.
+type ChunkError struct {
+	// Position of the chunk within the operation, starting at 0
+	Index int
+	// Address of the server which processed the chunk, if known
+	Server string
+	Keys   []interface{}
+	Err    error
+}
+
+func (this *ChunkError) Error() string {
+	return fmt.Sprintf("chunk %d failed: %s", this.Index, this.Err.Error())
+}
+
+func (this *ChunkError) Unwrap() error {
+	return this.Err
+}
+
+// A MultiError is returned by a chunked PutAll or GetAll when one or more chunks fail as a
+// whole. The results of the successful chunks are still returned alongside it. Keys which
+// fail individually are only reported in the map of failed keys, as they are when the
+// operation is not chunked.
+type MultiError struct {
+	Operation string
+	Region    string
+	// Total number of chunks in the operation
+	Total  int
+	Chunks []*ChunkError
+}
+
+func (this *MultiError) Error() string {
+	return fmt.Sprintf("%s on region %s failed for %d of %d chunks", this.Operation, this.Region, len(this.Chunks), this.Total)
+}
+
+// Unwrap returns the individual ChunkErrors.
+func (this *MultiError) Unwrap() []error {
+	errs := make([]error, len(this.Chunks))
+	for i, c := range this.Chunks {
+		errs[i] = c
+	}
+	return errs
+}
+
+// SetBulkChunkSize splits PutAll and GetAll operations into requests of at most size entries
+// each. When chunking applies and any chunk fails as a whole, the results of the other chunks
+// are returned along with a *MultiError describing the failed chunks, whose keys are also
+// included in the map of failed keys. A size of 0 disables chunking.
+func (this *Protobuf) SetBulkChunkSize(size int) {
+	this.chunkSize = size
+}
+
+// Return the bounds of each chunk of n items
+func (this *Protobuf) chunks(n int) [][2]int {
+	size := this.chunkSize
+	if size <= 0 || n <= size {
+		return [][2]int{{0, n}}
+	}
+
+	bounds := make([][2]int, 0, (n+size-1)/size)
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		bounds = append(bounds, [2]int{start, end})
+	}
+
+	return bounds
+}
@@ -0,0 +1,123 @@
+package connector_test
+
+import (
+	"errors"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Chunked bulk operations", func() {
+
+	var connection *connector.Protobuf
+	var fakeConn *connectorfakes.FakeConn
+	var requests []*v1.Message
+
+	errorResponse := &v1.Message{
+		MessageType: &v1.Message_ErrorResponse{
+			ErrorResponse: &v1.ErrorResponse{
+				Error: &v1.Error{ErrorCode: 1, Message: "error from fake"},
+			},
+		},
+	}
+
+	BeforeEach(func() {
+		requests = nil
+		fakeConn = new(connectorfakes.FakeConn)
+		fakeConn.WriteStub = func(b []byte) (int, error) {
+			request := &v1.Message{}
+			if err := proto.NewBuffer(b).DecodeMessage(request); err != nil {
+				return 0, err
+			}
+			requests = append(requests, request)
+			return len(b), nil
+		}
+
+		// A failed chunk discards its connection, so provide a spare which shares the stubs
+		spareConn := new(connectorfakes.FakeConn)
+		spareConn.WriteStub = fakeConn.WriteStub
+		spareConn.ReadStub = func(b []byte) (int, error) {
+			return fakeConn.ReadStub(b)
+		}
+
+		pool := connector.NewPool()
+		pool.AddConnection(spareConn, true)
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+		connection.SetBulkChunkSize(2)
+	})
+
+	It("splits GetAll into chunks and reports failed chunks", func() {
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			request := requests[len(requests)-1].GetGetAllRequest()
+			if len(requests) == 2 {
+				return writeFakeMessage(errorResponse, b)
+			}
+
+			response := &v1.GetAllResponse{}
+			for _, k := range request.Key {
+				response.Entries = append(response.Entries, &v1.Entry{Key: k, Value: k})
+			}
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_GetAllResponse{GetAllResponse: response},
+			}, b)
+		}
+
+		entries, failures, err := connection.GetAll("foo", []string{"A", "B", "C", "D", "E"})
+		Expect(requests).To(HaveLen(3))
+		Expect(entries).To(Equal(map[interface{}]interface{}{"A": "A", "B": "B", "E": "E"}))
+		Expect(failures).To(HaveLen(2))
+		Expect(failures["C"]).To(MatchError("error from fake (1)"))
+
+		var multi *connector.MultiError
+		Expect(errors.As(err, &multi)).To(BeTrue())
+		Expect(multi.Operation).To(Equal("GetAll"))
+		Expect(multi.Total).To(Equal(3))
+		Expect(multi.Chunks).To(HaveLen(1))
+		Expect(multi.Chunks[0].Index).To(Equal(1))
+		Expect(multi.Chunks[0].Keys).To(Equal([]interface{}{"C", "D"}))
+
+		var chunk *connector.ChunkError
+		Expect(errors.As(err, &chunk)).To(BeTrue())
+		Expect(chunk.Err).To(MatchError("error from fake (1)"))
+	})
+
+	It("reports per-key PutAll failures without an error, as when not chunked", func() {
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			request := requests[len(requests)-1].GetPutAllRequest()
+			response := &v1.PutAllResponse{
+				FailedKeys: []*v1.KeyedError{
+					{Key: request.Entry[0].Key, Error: &v1.Error{ErrorCode: 2, Message: "bad key"}},
+				},
+			}
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_PutAllResponse{PutAllResponse: response},
+			}, b)
+		}
+
+		failures, err := connection.PutAll("foo", map[string]int{"A": 1, "B": 2, "C": 3})
+		Expect(requests).To(HaveLen(2))
+		Expect(failures).To(HaveLen(2))
+		Expect(err).To(BeNil())
+
+		connection.SetBulkChunkSize(0)
+		failures, err = connection.PutAll("foo", map[string]int{"A": 1, "B": 2, "C": 3})
+		Expect(requests).To(HaveLen(3))
+		Expect(failures).To(HaveLen(1))
+		Expect(err).To(BeNil())
+	})
+
+	It("does not chunk operations within the chunk size", func() {
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(errorResponse, b)
+		}
+
+		_, err := connection.PutAll("foo", map[string]int{"A": 1, "B": 2})
+		Expect(requests).To(HaveLen(1))
+		Expect(err).To(MatchError("error from fake (1)"))
+	})
+})
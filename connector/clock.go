@@ -0,0 +1,51 @@
+package connector
+
+import (
+	"time"
+)
+
+// A Clock is a source of monotonic time used to measure latencies. Unlike wall clock time,
+// readings are unaffected by adjustments to the system clock. A custom Clock may be used to
+// drive latency metrics from simulated time in tests.
+type Clock interface {
+	// Now returns the time elapsed since an arbitrary but fixed origin. Successive readings
+	// must never decrease.
+	Now() time.Duration
+}
+
+type systemClock struct {
+	origin time.Time
+}
+
+func (this *systemClock) Now() time.Duration {
+	// time.Since uses the monotonic clock reading of origin
+	return time.Since(this.origin)
+}
+
+// SystemClock is the default Clock, based on the monotonic clock of the runtime.
+var SystemClock Clock = &systemClock{origin: time.Now()}
+
+// SetClock changes the Clock used to measure operation latencies.
+func (this *Pool) SetClock(clock Clock) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.clock = clock
+	this.syncPartitions()
+}
+
+// GetClock returns the Clock used by this pool.
+func (this *Pool) GetClock() Clock {
+	this.RLock()
+	defer this.RUnlock()
+
+	return this.currentClock()
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) currentClock() Clock {
+	if this.clock == nil {
+		return SystemClock
+	}
+	return this.clock
+}
@@ -0,0 +1,172 @@
+package connector
+
+import (
+	"sync"
+)
+
+// A ClusterEventType is a kind of change to the servers known to a Pool.
+type ClusterEventType int
+
+const (
+	// A server was added, with AddServer or by Configure
+	ServerAdded ClusterEventType = iota
+	// A server was removed by Configure
+	ServerRemoved
+	// The FailureDetector stopped considering a server available
+	ServerUnavailable
+	// A server considered unavailable became available again
+	ServerAvailable
+)
+
+func (this ClusterEventType) String() string {
+	switch this {
+	case ServerAdded:
+		return "ServerAdded"
+	case ServerRemoved:
+		return "ServerRemoved"
+	case ServerUnavailable:
+		return "ServerUnavailable"
+	case ServerAvailable:
+		return "ServerAvailable"
+	}
+	return "Unknown"
+}
+
+// A ClusterEvent reports a change to the servers known to a Pool. Server is the server's
+// host:port.
+type ClusterEvent struct {
+	Type   ClusterEventType
+	Server string
+}
+
+// The subscribers to a pool's cluster events, shared with its partitions, along with the
+// servers last seen to be unavailable
+type clusterEvents struct {
+	sync.Mutex
+	subscribers map[chan ClusterEvent]bool
+	unavailable map[string]bool
+}
+
+// ClusterEvents returns a channel receiving changes to the pool's servers, such as servers
+// being added or removed by a reloaded configuration or suspected of failing, so that an
+// application can react to them, for example by calling EvictIdle to open connections to a
+// new server. Availability is assessed by the FailureDetector whenever the pool connects to a
+// server or completes an operation, and by health checks (see StartHealthChecks).
+//
+// Up to buffer events are held for the receiver; later events are dropped, and counted as
+// MetricDroppedClusterEvents, rather than delaying the pool. Call cancel to stop receiving
+// events and close the channel. Events from the pool's partitions are included.
+func (this *Pool) ClusterEvents(buffer int) (events <-chan ClusterEvent, cancel func()) {
+	this.Lock()
+	subscribers := this.clusterEvents()
+	this.Unlock()
+
+	c := make(chan ClusterEvent, buffer)
+	subscribers.Lock()
+	subscribers.subscribers[c] = true
+	subscribers.Unlock()
+
+	var once sync.Once
+	return c, func() {
+		once.Do(func() {
+			subscribers.Lock()
+			defer subscribers.Unlock()
+
+			delete(subscribers.subscribers, c)
+			close(c)
+		})
+	}
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) clusterEvents() *clusterEvents {
+	if this.events == nil {
+		this.events = &clusterEvents{
+			subscribers: make(map[chan ClusterEvent]bool),
+			unavailable: make(map[string]bool),
+		}
+	}
+	return this.events
+}
+
+func (this *clusterEvents) publish(event ClusterEvent, publisher MetricsPublisher) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.send(event, publisher)
+}
+
+// MUST hold the clusterEvents lock when calling
+func (this *clusterEvents) send(event ClusterEvent, publisher MetricsPublisher) {
+	for c := range this.subscribers {
+		select {
+		case c <- event:
+		default:
+			publisher.Add(MetricDroppedClusterEvents, 1)
+		}
+	}
+}
+
+// Record whether a server is available, publishing the change if it was not already known and
+// returning whether it was
+func (this *clusterEvents) availability(server string, available bool, publisher MetricsPublisher) (changed bool) {
+	this.Lock()
+	defer this.Unlock()
+
+	if this.unavailable[server] == !available {
+		return false
+	}
+
+	if available {
+		delete(this.unavailable, server)
+		this.send(ClusterEvent{Type: ServerAvailable, Server: server}, publisher)
+	} else {
+		this.unavailable[server] = true
+		this.send(ClusterEvent{Type: ServerUnavailable, Server: server}, publisher)
+	}
+	return true
+}
+
+// Publish the servers added and removed when the providers change from before to after
+// MUST hold the pool lock when calling
+func (this *Pool) publishServerChanges(before, after []ConnectionProvider) {
+	old := make(map[string]bool, len(before))
+	for _, p := range before {
+		old[providerAddress(p)] = true
+	}
+	current := make(map[string]bool, len(after))
+	for _, p := range after {
+		current[providerAddress(p)] = true
+	}
+
+	events := this.clusterEvents()
+	publisher := this.metricsPublisher()
+	for _, p := range after {
+		if address := providerAddress(p); !old[address] {
+			events.publish(ClusterEvent{Type: ServerAdded, Server: address}, publisher)
+		}
+	}
+	for _, p := range before {
+		if address := providerAddress(p); !current[address] {
+			events.Lock()
+			delete(events.unavailable, address)
+			events.Unlock()
+			events.publish(ClusterEvent{Type: ServerRemoved, Server: address}, publisher)
+		}
+	}
+}
+
+// Publish a change in the availability of a server after reporting to the detector
+// MUST hold the pool lock when calling
+func (this *Pool) checkAvailability(server string) {
+	available := this.failureDetector().Available(server)
+	if !this.clusterEvents().availability(server, available, this.metricsPublisher()) {
+		return
+	}
+
+	if available {
+		this.log(LogInfo, "server available", "server", server)
+	} else {
+		this.log(LogWarn, "server unavailable", "server", server)
+	}
+}
@@ -0,0 +1,119 @@
+package connector_test
+
+import (
+	"net"
+
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cluster events", func() {
+
+	var pool *connector.Pool
+	var publisher *recordingPublisher
+
+	// Return the events received so far
+	received := func(events <-chan connector.ClusterEvent) []connector.ClusterEvent {
+		var result []connector.ClusterEvent
+		for {
+			select {
+			case event := <-events:
+				result = append(result, event)
+			default:
+				return result
+			}
+		}
+	}
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+		publisher = &recordingPublisher{counters: make(map[string]int64)}
+		pool.SetMetricsPublisher(publisher)
+	})
+
+	It("reports servers added and removed", func() {
+		events, cancel := pool.ClusterEvents(10)
+		defer cancel()
+
+		Expect(pool.Configure(&connector.Config{Servers: []string{"a:40404", "b:40404"}})).To(BeNil())
+		Expect(received(events)).To(Equal([]connector.ClusterEvent{
+			{Type: connector.ServerAdded, Server: "a:40404"},
+			{Type: connector.ServerAdded, Server: "b:40404"},
+		}))
+
+		Expect(pool.Configure(&connector.Config{Servers: []string{"b:40404", "c:40404"}})).To(BeNil())
+		Expect(received(events)).To(Equal([]connector.ClusterEvent{
+			{Type: connector.ServerAdded, Server: "c:40404"},
+			{Type: connector.ServerRemoved, Server: "a:40404"},
+		}))
+
+		pool.AddServer("d", 40404)
+		Expect(received(events)).To(Equal([]connector.ClusterEvent{
+			{Type: connector.ServerAdded, Server: "d:40404"},
+		}))
+		Expect(connector.ServerAdded.String()).To(Equal("ServerAdded"))
+	})
+
+	It("reports servers becoming unavailable and available again", func() {
+		server := newHandshakeServer()
+		address := server.address()
+		server.listener.Close()
+
+		Expect(pool.Configure(&connector.Config{Servers: []string{address}})).To(BeNil())
+		events, cancel := pool.ClusterEvents(10)
+		defer cancel()
+
+		_, err := pool.GetConnection()
+		Expect(err).ToNot(BeNil())
+		Expect(received(events)).To(Equal([]connector.ClusterEvent{
+			{Type: connector.ServerUnavailable, Server: address},
+		}))
+
+		// Repeated failures are not reported again
+		pool.CheckHealth()
+		Expect(received(events)).To(BeEmpty())
+
+		listener, err := net.Listen("tcp", address)
+		Expect(err).To(BeNil())
+		defer listener.Close()
+		serveHandshakes(listener)
+
+		pool.CheckHealth()
+		Expect(received(events)).To(Equal([]connector.ClusterEvent{
+			{Type: connector.ServerAvailable, Server: address},
+		}))
+	})
+
+	It("includes events from partitions", func() {
+		events, cancel := pool.ClusterEvents(10)
+		defer cancel()
+
+		server := newHandshakeServer()
+		address := server.address()
+		server.listener.Close()
+		pool.AddServer("127.0.0.1", server.listener.Addr().(*net.TCPAddr).Port)
+		received(events)
+
+		_, err := pool.Partition("bulk").GetConnection()
+		Expect(err).ToNot(BeNil())
+		Expect(received(events)).To(Equal([]connector.ClusterEvent{
+			{Type: connector.ServerUnavailable, Server: address},
+		}))
+	})
+
+	It("drops events which the receiver has no room for", func() {
+		events, cancel := pool.ClusterEvents(1)
+
+		pool.AddServer("a", 40404)
+		pool.AddServer("b", 40404)
+		Expect(received(events)).To(HaveLen(1))
+		Expect(publisher.counters[connector.MetricDroppedClusterEvents]).To(Equal(int64(1)))
+
+		cancel()
+		cancel()
+		pool.AddServer("c", 40404)
+		_, open := <-events
+		Expect(open).To(BeFalse())
+	})
+})
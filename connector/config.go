@@ -0,0 +1,153 @@
+package connector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"time"
+)
+
+// A Config describes changes to the configuration of a Pool. It can be applied to a running
+// Pool with Pool.Configure. Fields which are nil, or absent from a JSON document, leave the
+// current setting unchanged.
+//
+// Only pool settings can be reloaded. Operation timeouts are set on the connector (see
+// SetRetryBudget and WithContext), so are not configured here, and of logging only the level
+// is: the Logger itself is set with Pool.SetLogger.
+type Config struct {
+	// Servers to connect to, in the form host:port
+	Servers []string `json:"servers"`
+	// Credentials used to authenticate new connections. If Username is empty, authentication
+	// is disabled.
+	Username *string `json:"username"`
+	Password *string `json:"password"`
+	// Maximum number of connections; see Pool.SetMaxConnections. Setting this overrides any
+	// limit chosen by an AdaptiveSizer until its next adjustment.
+	MaxConnections *int `json:"maxConnections"`
+	// Time allowed to connect to a server, such as "5s". 0 leaves it to the operating system.
+	ConnectTimeout *Duration `json:"connectTimeout"`
+	// Time allowed to read each response and write each request; see Pool.SetReadTimeout.
+	// 0 is no limit.
+	ReadTimeout  *Duration `json:"readTimeout"`
+	WriteTimeout *Duration `json:"writeTimeout"`
+	// Idle connections kept open, and the time after which others are closed; see
+	// Pool.SetMinIdle and Pool.SetIdleTimeout.
+	MinIdle     *int      `json:"minIdle"`
+	IdleTimeout *Duration `json:"idleTimeout"`
+	// Strategy choosing the server of each connection, such as "roundRobin"; see
+	// Pool.SetLoadBalancing.
+	LoadBalancing *LoadBalancing `json:"loadBalancing"`
+	// Lowest level of the records logged, such as "WARN"; see Pool.SetLogLevel.
+	LogLevel *LogLevel `json:"logLevel"`
+}
+
+// A Duration is a time.Duration which is written in JSON as a string such as "1m30s".
+type Duration time.Duration
+
+func (this Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(this).String())
+}
+
+func (this *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.New(fmt.Sprintf("invalid duration %s", string(data)))
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*this = Duration(d)
+
+	return nil
+}
+
+// A ConfigSource provides the current Config, for example when a client is reloaded.
+type ConfigSource interface {
+	Load() (*Config, error)
+}
+
+// A FileConfigSource loads a Config from the JSON file at the given path.
+type FileConfigSource string
+
+var _ ConfigSource = FileConfigSource("")
+
+func (this FileConfigSource) Load() (*Config, error) {
+	data, err := ioutil.ReadFile(string(this))
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to parse configuration %s: %s", string(this), err.Error()))
+	}
+
+	return config, nil
+}
+
+func (this *Config) validate() error {
+	if this.Servers != nil && len(this.Servers) == 0 {
+		return errors.New("configuration has no servers")
+	}
+
+	if this.MaxConnections != nil && *this.MaxConnections < 0 {
+		return errors.New(fmt.Sprintf("invalid maxConnections %d", *this.MaxConnections))
+	}
+
+	if this.ConnectTimeout != nil && *this.ConnectTimeout < 0 {
+		return errors.New(fmt.Sprintf("invalid connectTimeout %s", time.Duration(*this.ConnectTimeout)))
+	}
+
+	if this.ReadTimeout != nil && *this.ReadTimeout < 0 {
+		return errors.New(fmt.Sprintf("invalid readTimeout %s", time.Duration(*this.ReadTimeout)))
+	}
+
+	if this.WriteTimeout != nil && *this.WriteTimeout < 0 {
+		return errors.New(fmt.Sprintf("invalid writeTimeout %s", time.Duration(*this.WriteTimeout)))
+	}
+
+	if this.MinIdle != nil && *this.MinIdle < 0 {
+		return errors.New(fmt.Sprintf("invalid minIdle %d", *this.MinIdle))
+	}
+
+	if this.IdleTimeout != nil && *this.IdleTimeout < 0 {
+		return errors.New(fmt.Sprintf("invalid idleTimeout %s", time.Duration(*this.IdleTimeout)))
+	}
+
+	if this.LogLevel != nil && (*this.LogLevel < LogDebug || *this.LogLevel > LogError) {
+		return errors.New(fmt.Sprintf("invalid logLevel %s", *this.LogLevel))
+	}
+
+	if this.LoadBalancing != nil {
+		if err := this.LoadBalancing.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, server := range this.Servers {
+		if _, _, err := parseServer(server); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseServer(server string) (string, int, error) {
+	host, portString, err := net.SplitHostPort(server)
+	if err != nil {
+		return "", 0, errors.New(fmt.Sprintf("invalid server %s: %s", server, err.Error()))
+	}
+
+	port, err := strconv.Atoi(portString)
+	if err != nil {
+		return "", 0, errors.New(fmt.Sprintf("invalid server port %s", server))
+	}
+
+	return host, port, nil
+}
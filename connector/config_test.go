@@ -0,0 +1,187 @@
+package connector_test
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/protobuf"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// A listener which accepts connections and acknowledges the protocol handshake. Any
+// credentials presented are accepted and the username reported on the authenticated
+// channel. Closed connections are reported on the closed channel.
+type handshakeServer struct {
+	listener      net.Listener
+	accepted      chan net.Conn
+	authenticated chan string
+	closed        chan net.Conn
+}
+
+func newHandshakeServer() *handshakeServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(BeNil())
+
+	return serveHandshakes(listener)
+}
+
+func serveHandshakes(listener net.Listener) *handshakeServer {
+	server := &handshakeServer{
+		listener:      listener,
+		accepted:      make(chan net.Conn, 10),
+		authenticated: make(chan string, 10),
+		closed:        make(chan net.Conn, 10),
+	}
+
+	go func() {
+		for {
+			c, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			server.accepted <- c
+			go server.serve(c)
+		}
+	}()
+
+	return server
+}
+
+func (this *handshakeServer) serve(c net.Conn) {
+	b := make([]byte, 1024)
+	if _, err := c.Read(b); err == nil {
+		ack := &org_apache_geode_internal_protocol_protobuf.VersionAcknowledgement{
+			ServerMajorVersion: 1,
+			ServerMinorVersion: 1,
+			VersionAccepted:    true,
+		}
+		p := proto.NewBuffer(nil)
+		p.EncodeMessage(ack)
+		c.Write(p.Bytes())
+
+		for err == nil {
+			var n int
+			if n, err = c.Read(b); err != nil {
+				break
+			}
+
+			request := &v1.Message{}
+			if proto.NewBuffer(b[:n]).DecodeMessage(request) == nil && request.GetHandshakeRequest() != nil {
+				this.authenticated <- request.GetHandshakeRequest().Credentials["security-username"]
+				n, _ = writeFakeMessage(&v1.Message{
+					MessageType: &v1.Message_HandshakeResponse{
+						HandshakeResponse: &v1.HandshakeResponse{Authenticated: true},
+					},
+				}, b)
+				_, err = c.Write(b[:n])
+			}
+		}
+	}
+	this.closed <- c
+}
+
+func (this *handshakeServer) address() string {
+	return this.listener.Addr().String()
+}
+
+var _ = Describe("Config", func() {
+
+	It("loads configuration from a file", func() {
+		dir, err := ioutil.TempDir("", "config")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "geode.json")
+		ioutil.WriteFile(path, []byte(`{"servers": ["localhost:40404"], "username": "jbloggs", "connectTimeout": "5s", "logLevel": "warn"}`), 0600)
+
+		config, err := connector.FileConfigSource(path).Load()
+		Expect(err).To(BeNil())
+		Expect(config.Servers).To(Equal([]string{"localhost:40404"}))
+		Expect(*config.Username).To(Equal("jbloggs"))
+		Expect(*config.ConnectTimeout).To(Equal(connector.Duration(5 * time.Second)))
+		Expect(*config.LogLevel).To(Equal(connector.LogWarn))
+		Expect(config.Password).To(BeNil())
+		Expect(config.MaxConnections).To(BeNil())
+	})
+
+	It("rejects invalid servers", func() {
+		pool := connector.NewPool()
+
+		Expect(pool.Configure(&connector.Config{Servers: []string{}})).ToNot(BeNil())
+		Expect(pool.Configure(&connector.Config{Servers: []string{"localhost"}})).ToNot(BeNil())
+		Expect(pool.Configure(&connector.Config{Servers: []string{"localhost:port"}})).ToNot(BeNil())
+	})
+
+	It("moves to new servers without interrupting connections in use", func() {
+		serverA := newHandshakeServer()
+		defer serverA.listener.Close()
+		serverB := newHandshakeServer()
+		defer serverB.listener.Close()
+
+		pool := connector.NewPool()
+		Expect(pool.Configure(&connector.Config{Servers: []string{serverA.address()}})).To(BeNil())
+
+		idle, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		busy, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		Expect(serverA.accepted).To(HaveLen(2))
+		pool.ReturnConnection(idle)
+
+		Expect(pool.Configure(&connector.Config{Servers: []string{serverB.address()}})).To(BeNil())
+
+		// The idle connection is closed immediately, the busy one once it is returned
+		Eventually(serverA.closed).Should(HaveLen(1))
+		Consistently(serverA.closed).Should(HaveLen(1))
+
+		c, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		Expect(serverB.accepted).To(HaveLen(1))
+		pool.ReturnConnection(c)
+
+		pool.ReturnConnection(busy)
+		Eventually(serverA.closed).Should(HaveLen(2))
+	})
+
+	It("leaves settings which are not configured unchanged", func() {
+		server := newHandshakeServer()
+		defer server.listener.Close()
+
+		username := "jbloggs"
+		max := 3
+		pool := connector.NewPool()
+		Expect(pool.Configure(&connector.Config{
+			Servers:        []string{server.address()},
+			Username:       &username,
+			MaxConnections: &max,
+		})).To(BeNil())
+
+		timeout := connector.Duration(time.Second)
+		Expect(pool.Configure(&connector.Config{ConnectTimeout: &timeout})).To(BeNil())
+		Expect(pool.GetMaxConnections()).To(Equal(3))
+
+		c, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		Expect(server.accepted).To(HaveLen(1))
+		Expect(server.authenticated).To(Receive(Equal("jbloggs")))
+		pool.ReturnConnection(c)
+
+		// Clearing the username disables authentication for new connections
+		empty := ""
+		Expect(pool.Configure(&connector.Config{Username: &empty})).To(BeNil())
+		pool.DiscardConnection(c)
+
+		c, err = pool.GetConnection()
+		Expect(err).To(BeNil())
+		Expect(server.accepted).To(HaveLen(2))
+		Consistently(server.authenticated).ShouldNot(Receive())
+		pool.ReturnConnection(c)
+	})
+})
@@ -0,0 +1,132 @@
+package connector
+
+import (
+	"context"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/gemfire/geode-go-client/query"
+)
+
+// The Ctx variants of each operation are bound by ctx, as if called on WithContext(ctx). The
+// operation is abandoned once ctx is done, even while waiting for a server to respond, and
+// returns ctx.Err().
+
+func (this *Protobuf) PutCtx(ctx context.Context, region string, k, v interface{}) error {
+	return this.WithContext(ctx).Put(region, k, v)
+}
+
+func (this *Protobuf) PutRawCtx(ctx context.Context, region string, key, value *v1.EncodedValue) error {
+	return this.WithContext(ctx).PutRaw(region, key, value)
+}
+
+func (this *Protobuf) PutIfAbsentCtx(ctx context.Context, region string, k, v interface{}) error {
+	return this.WithContext(ctx).PutIfAbsent(region, k, v)
+}
+
+func (this *Protobuf) GetCtx(ctx context.Context, region string, k interface{}, value interface{}) (interface{}, error) {
+	return this.WithContext(ctx).Get(region, k, value)
+}
+
+func (this *Protobuf) GetOptionalCtx(ctx context.Context, region string, k interface{}, value interface{}) (Optional, error) {
+	return this.WithContext(ctx).GetOptional(region, k, value)
+}
+
+func (this *Protobuf) PutNullCtx(ctx context.Context, region string, k interface{}) error {
+	return this.WithContext(ctx).PutNull(region, k)
+}
+
+func (this *Protobuf) GetRawCtx(ctx context.Context, region string, key *v1.EncodedValue) (*v1.EncodedValue, error) {
+	return this.WithContext(ctx).GetRaw(region, key)
+}
+
+func (this *Protobuf) GetAllCtx(ctx context.Context, region string, keys interface{}) (map[interface{}]interface{}, map[interface{}]error, error) {
+	return this.WithContext(ctx).GetAll(region, keys)
+}
+
+func (this *Protobuf) GetAllFuncCtx(ctx context.Context, region string, keys interface{}, fn func(key, value interface{}, err error) bool) error {
+	return this.WithContext(ctx).GetAllFunc(region, keys, fn)
+}
+
+func (this *Protobuf) GetAllIntoCtx(ctx context.Context, region string, keys interface{}, newRef func() interface{}, fn func(key, value interface{}, err error) bool) error {
+	return this.WithContext(ctx).GetAllInto(region, keys, newRef, fn)
+}
+
+func (this *Protobuf) GetAllLazyCtx(ctx context.Context, region string, keys interface{}) (map[interface{}]*LazyValue, map[interface{}]error, error) {
+	return this.WithContext(ctx).GetAllLazy(region, keys)
+}
+
+func (this *Protobuf) KeysCtx(ctx context.Context, region string) ([]interface{}, error) {
+	return this.WithContext(ctx).Keys(region)
+}
+
+func (this *Protobuf) KeysFuncCtx(ctx context.Context, region string, fn func(key interface{}) bool) error {
+	return this.WithContext(ctx).KeysFunc(region, fn)
+}
+
+func (this *Protobuf) PutAllCtx(ctx context.Context, region string, entries interface{}) (map[interface{}]error, error) {
+	return this.WithContext(ctx).PutAll(region, entries)
+}
+
+func (this *Protobuf) RegionNamesCtx(ctx context.Context) ([]string, error) {
+	return this.WithContext(ctx).RegionNames()
+}
+
+func (this *Protobuf) RemoveCtx(ctx context.Context, region string, k interface{}) error {
+	return this.WithContext(ctx).Remove(region, k)
+}
+
+func (this *Protobuf) RemoveAllCtx(ctx context.Context, region string, keys interface{}) (map[interface{}]error, error) {
+	return this.WithContext(ctx).RemoveAll(region, keys)
+}
+
+func (this *Protobuf) ClearCtx(ctx context.Context, region string) error {
+	return this.WithContext(ctx).Clear(region)
+}
+
+func (this *Protobuf) SizeCtx(ctx context.Context, region string) (int32, error) {
+	return this.WithContext(ctx).Size(region)
+}
+
+func (this *Protobuf) ExecuteOnRegionCtx(ctx context.Context, functionId, region string, functionArgs interface{}, keyFilter []interface{}) ([]interface{}, error) {
+	return this.WithContext(ctx).ExecuteOnRegion(functionId, region, functionArgs, keyFilter)
+}
+
+func (this *Protobuf) ExecuteOnMembersCtx(ctx context.Context, functionId string, members []string, functionArgs interface{}) ([]interface{}, error) {
+	return this.WithContext(ctx).ExecuteOnMembers(functionId, members, functionArgs)
+}
+
+func (this *Protobuf) ExecuteOnGroupsCtx(ctx context.Context, functionId string, groups []string, functionArgs interface{}) ([]interface{}, error) {
+	return this.WithContext(ctx).ExecuteOnGroups(functionId, groups, functionArgs)
+}
+
+func (this *Protobuf) ExecuteOnRegionFuncCtx(ctx context.Context, functionId, region string, functionArgs interface{}, keyFilter []interface{}, fn func(result interface{}) bool) error {
+	return this.WithContext(ctx).ExecuteOnRegionFunc(functionId, region, functionArgs, keyFilter, fn)
+}
+
+func (this *Protobuf) ExecuteOnMembersFuncCtx(ctx context.Context, functionId string, members []string, functionArgs interface{}, fn func(result interface{}) bool) error {
+	return this.WithContext(ctx).ExecuteOnMembersFunc(functionId, members, functionArgs, fn)
+}
+
+func (this *Protobuf) ExecuteOnGroupsFuncCtx(ctx context.Context, functionId string, groups []string, functionArgs interface{}, fn func(result interface{}) bool) error {
+	return this.WithContext(ctx).ExecuteOnGroupsFunc(functionId, groups, functionArgs, fn)
+}
+
+func (this *Protobuf) QuerySingleResultCtx(ctx context.Context, query *query.Query) (interface{}, error) {
+	return this.WithContext(ctx).QuerySingleResult(query)
+}
+
+func (this *Protobuf) QueryListResultCtx(ctx context.Context, query *query.Query) ([]interface{}, error) {
+	return this.WithContext(ctx).QueryListResult(query)
+}
+
+func (this *Protobuf) QueryTableResultCtx(ctx context.Context, query *query.Query) (map[string][]interface{}, error) {
+	return this.WithContext(ctx).QueryTableResult(query)
+}
+
+func (this *Protobuf) QueryListResultLazyCtx(ctx context.Context, query *query.Query) ([]*LazyValue, error) {
+	return this.WithContext(ctx).QueryListResultLazy(query)
+}
+
+func (this *Protobuf) QueryTableResultLazyCtx(ctx context.Context, query *query.Query) (map[string][]*LazyValue, error) {
+	return this.WithContext(ctx).QueryTableResultLazy(query)
+}
@@ -0,0 +1,74 @@
+package connector_test
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// A timeout error, as returned by a net.Conn whose deadline has passed
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+var _ = Describe("Context variants", func() {
+
+	var pool *connector.Pool
+	var connection *connector.Protobuf
+	var fakeConn *connectorfakes.FakeConn
+
+	BeforeEach(func() {
+		fakeConn = new(connectorfakes.FakeConn)
+		pool = connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+	})
+
+	It("applies the deadline of the context", func() {
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}},
+			}, b)
+		}
+		deadline := time.Now().Add(time.Hour)
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+
+		Expect(connection.PutCtx(ctx, "foo", "A", 1)).To(BeNil())
+		Expect(fakeConn.SetDeadlineArgsForCall(0)).To(Equal(deadline))
+		Expect(pool.Stats().Connections).To(Equal(1))
+	})
+
+	It("abandons an operation waiting for a response when the context is done", func() {
+		// Block reads until the deadline is moved into the past
+		interrupted := make(chan struct{})
+		fakeConn.SetDeadlineStub = func(t time.Time) error {
+			if !t.IsZero() && t.Before(time.Now()) {
+				close(interrupted)
+			}
+			return nil
+		}
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			<-interrupted
+			return 0, timeoutError{}
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		_, err := connection.GetCtx(ctx, "foo", "A", nil)
+		Expect(err).To(Equal(context.Canceled))
+		Expect(pool.Stats().Connections).To(Equal(0))
+		Expect(fakeConn.CloseCallCount()).To(Equal(1))
+	})
+})
@@ -0,0 +1,101 @@
+package connector
+
+import (
+	"fmt"
+
+	"github.com/gemfire/geode-go-client/codec"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+)
+
+// A ResponseMismatchError is returned when the response read from a connection cannot be
+// the answer to the request sent on it, as when a response is of another type, reports keys
+// which were not requested or arrives with more data behind it. The protocol carries no
+// request identifiers, so such a connection has lost track of which response answers which
+// request, perhaps because an earlier response was only partly read. The response is not
+// delivered and the connection is closed.
+type ResponseMismatchError struct {
+	Operation string
+	Reason    string
+}
+
+func (this *ResponseMismatchError) Error() string {
+	return fmt.Sprintf("response does not match %s request: %s", this.Operation, this.Reason)
+}
+
+// Return a ResponseMismatchError if response cannot answer request, or nil. Error responses
+// may answer any request.
+func checkCorrelation(request, response *v1.Message) error {
+	if response.GetErrorResponse() != nil {
+		return nil
+	}
+
+	operation := operationName(request)
+	if operationName(response) != operation {
+		return &ResponseMismatchError{Operation: operation, Reason: fmt.Sprintf("unexpected response %T", response.GetMessageType())}
+	}
+
+	switch x := request.GetMessageType().(type) {
+	case *v1.Message_GetAllRequest:
+		requested := keySet(x.GetAllRequest.GetKey())
+		result := response.GetGetAllResponse()
+		if len(result.GetEntries())+len(result.GetFailures()) > len(x.GetAllRequest.GetKey()) {
+			return &ResponseMismatchError{Operation: operation, Reason: "more entries than keys requested"}
+		}
+		for _, entry := range result.GetEntries() {
+			if !requested[keyOf(entry.GetKey())] {
+				return &ResponseMismatchError{Operation: operation, Reason: "entry for a key which was not requested"}
+			}
+		}
+		for _, failure := range result.GetFailures() {
+			if !requested[keyOf(failure.GetKey())] {
+				return &ResponseMismatchError{Operation: operation, Reason: "failure for a key which was not requested"}
+			}
+		}
+	case *v1.Message_PutAllRequest:
+		sent := make(map[string]bool, len(x.PutAllRequest.GetEntry()))
+		for _, entry := range x.PutAllRequest.GetEntry() {
+			sent[keyOf(entry.GetKey())] = true
+		}
+		for _, failure := range response.GetPutAllResponse().GetFailedKeys() {
+			if !sent[keyOf(failure.GetKey())] {
+				return &ResponseMismatchError{Operation: operation, Reason: "failure for a key which was not sent"}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Return a response read with trailing data as a ResponseMismatchError
+func mismatchedRead(request *v1.Message, err error) error {
+	if err == codec.ErrTrailingData {
+		return &ResponseMismatchError{Operation: operationName(request), Reason: err.Error()}
+	}
+	return err
+}
+
+func keySet(keys []*v1.EncodedValue) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[keyOf(key)] = true
+	}
+	return set
+}
+
+// Return an encoded key in a form which can be compared
+func keyOf(key *v1.EncodedValue) string {
+	data, _ := proto.Marshal(key)
+	return string(data)
+}
+
+// Count and log a response which did not match its request
+func (this *Pool) responseMismatched(gConn *GeodeConnection, err error) {
+	if _, ok := err.(*ResponseMismatchError); !ok {
+		return
+	}
+
+	guardedPublisher{this.GetMetricsPublisher()}.Add(MetricMismatchedResponses, 1)
+	logTo(this.GetLogger(), LogError, "response does not match request, closing connection",
+		"server", connectionAddress(gConn), "error", err)
+}
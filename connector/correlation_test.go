@@ -0,0 +1,96 @@
+package connector_test
+
+import (
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Response correlation", func() {
+
+	var pool *connector.Pool
+	var fakeConn *connectorfakes.FakeConn
+	var connection *connector.Protobuf
+	var publisher *recordingPublisher
+
+	respond := func(response *v1.Message) {
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(response, b)
+		}
+	}
+
+	expectMismatch := func(err error) {
+		Expect(err).To(BeAssignableToTypeOf(&connector.ResponseMismatchError{}))
+		Expect(connector.ErrorCode(err)).To(Equal(connector.ErrorCodeConnectionError))
+		Expect(fakeConn.CloseCallCount()).To(Equal(1))
+		Expect(pool.Stats().Connections).To(Equal(0))
+		Expect(publisher.counters[connector.MetricMismatchedResponses]).To(Equal(int64(1)))
+	}
+
+	BeforeEach(func() {
+		fakeConn = new(connectorfakes.FakeConn)
+		pool = connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		publisher = &recordingPublisher{counters: make(map[string]int64)}
+		pool.SetMetricsPublisher(publisher)
+		connection = connector.NewConnector(pool)
+	})
+
+	It("closes a connection which answers with a response of another type", func() {
+		respond(&v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}})
+
+		value, err := connection.Get("foo", "A", nil)
+		Expect(value).To(BeNil())
+		expectMismatch(err)
+		Expect(err).To(MatchError(ContainSubstring("unexpected response")))
+	})
+
+	It("rejects entries for keys which were not requested", func() {
+		key, _ := connector.EncodeValue("B")
+		value, _ := connector.EncodeValue("b")
+		respond(&v1.Message{MessageType: &v1.Message_GetAllResponse{GetAllResponse: &v1.GetAllResponse{
+			Entries: []*v1.Entry{{Key: key, Value: value}},
+		}}})
+
+		_, _, err := connection.GetAll("foo", []string{"A"})
+		expectMismatch(err)
+	})
+
+	It("rejects failures for keys which were not sent", func() {
+		key, _ := connector.EncodeValue("B")
+		respond(&v1.Message{MessageType: &v1.Message_PutAllResponse{PutAllResponse: &v1.PutAllResponse{
+			FailedKeys: []*v1.KeyedError{{Key: key, Error: &v1.Error{Message: "failed"}}},
+		}}})
+
+		_, err := connection.PutAll("foo", map[string]string{"A": "a"})
+		expectMismatch(err)
+	})
+
+	It("rejects a response followed by more data", func() {
+		response := &v1.Message{MessageType: &v1.Message_GetResponse{GetResponse: &v1.GetResponse{}}}
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			p := proto.NewBuffer(nil)
+			p.EncodeMessage(response)
+			p.EncodeMessage(response)
+			return copy(b, p.Bytes()), nil
+		}
+
+		_, err := connection.Get("foo", "A", nil)
+		expectMismatch(err)
+	})
+
+	It("keeps connections whose responses match", func() {
+		key, _ := connector.EncodeValue("A")
+		respond(&v1.Message{MessageType: &v1.Message_GetAllResponse{GetAllResponse: &v1.GetAllResponse{
+			Failures: []*v1.KeyedError{{Key: key, Error: &v1.Error{Message: "failed"}}},
+		}}})
+
+		_, failures, err := connection.GetAll("foo", []string{"A"})
+		Expect(err).To(BeNil())
+		Expect(failures).To(HaveKey("A"))
+		Expect(fakeConn.CloseCallCount()).To(Equal(0))
+	})
+})
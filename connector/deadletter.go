@@ -0,0 +1,56 @@
+package connector
+
+import (
+	"sync"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// A DeadLetter records an entry from a batch result (GetAll, query or function execution)
+// which could not be decoded. The raw values are retained so that they may be inspected or
+// re-processed later.
+type DeadLetter struct {
+	// The operation which produced the entry: "GetAll", "Query" or "Function"
+	Operation string
+	Region    string
+	// The raw key of the entry. Only set for GetAll.
+	Key   *v1.EncodedValue
+	Value *v1.EncodedValue
+	Err   error
+}
+
+// A DeadLetterQueue collects entries which failed to decode. It is safe for concurrent use.
+type DeadLetterQueue struct {
+	sync.Mutex
+	letters []*DeadLetter
+}
+
+func NewDeadLetterQueue() *DeadLetterQueue {
+	return &DeadLetterQueue{}
+}
+
+func (this *DeadLetterQueue) add(letter *DeadLetter) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.letters = append(this.letters, letter)
+}
+
+// Len returns the number of dead letters currently queued.
+func (this *DeadLetterQueue) Len() int {
+	this.Lock()
+	defer this.Unlock()
+
+	return len(this.letters)
+}
+
+// Drain removes and returns all queued dead letters.
+func (this *DeadLetterQueue) Drain() []*DeadLetter {
+	this.Lock()
+	defer this.Unlock()
+
+	letters := this.letters
+	this.letters = nil
+
+	return letters
+}
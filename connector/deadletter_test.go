@@ -0,0 +1,105 @@
+package connector_test
+
+import (
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/gemfire/geode-go-client/query"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dead letters", func() {
+
+	var connection *connector.Protobuf
+	var fakeConn *connectorfakes.FakeConn
+	var queue *connector.DeadLetterQueue
+
+	poison := &v1.EncodedValue{
+		Value: &v1.EncodedValue_CustomObjectResult{CustomObjectResult: []byte{0xde, 0xad}},
+	}
+
+	BeforeEach(func() {
+		fakeConn = new(connectorfakes.FakeConn)
+		pool := connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+		queue = connector.NewDeadLetterQueue()
+		connection.SetDeadLetterQueue(queue)
+	})
+
+	It("collects undecodable GetAll entries", func() {
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			k1, _ := connector.EncodeValue("A")
+			v1_, _ := connector.EncodeValue(1)
+			k2, _ := connector.EncodeValue("B")
+			response := &v1.Message{
+				MessageType: &v1.Message_GetAllResponse{
+					GetAllResponse: &v1.GetAllResponse{
+						Entries: []*v1.Entry{
+							{Key: k1, Value: v1_},
+							{Key: k2, Value: poison},
+						},
+					},
+				},
+			}
+			return writeFakeMessage(response, b)
+		}
+
+		entries, failures, err := connection.GetAll("foo", []string{"A", "B"})
+		Expect(err).To(BeNil())
+		Expect(failures).To(BeNil())
+		Expect(entries).To(HaveLen(1))
+
+		letters := queue.Drain()
+		Expect(letters).To(HaveLen(1))
+		Expect(letters[0].Operation).To(Equal("GetAll"))
+		Expect(letters[0].Region).To(Equal("foo"))
+		Expect(letters[0].Key.GetStringResult()).To(Equal("B"))
+		Expect(letters[0].Err).ToNot(BeNil())
+		Expect(queue.Len()).To(Equal(0))
+	})
+
+	It("collects undecodable query results", func() {
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			good, _ := connector.EncodeValue("hey")
+			response := &v1.Message{
+				MessageType: &v1.Message_OqlQueryResponse{
+					OqlQueryResponse: &v1.OQLQueryResponse{
+						Result: &v1.OQLQueryResponse_ListResult{
+							ListResult: &v1.EncodedValueList{Element: []*v1.EncodedValue{poison, good}},
+						},
+					},
+				},
+			}
+			return writeFakeMessage(response, b)
+		}
+
+		result, err := connection.QueryListResult(query.NewQuery("select foo"))
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal([]interface{}{"hey"}))
+		Expect(queue.Len()).To(Equal(1))
+	})
+
+	It("collects undecodable function results", func() {
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			good, _ := connector.EncodeValue(777)
+			response := &v1.Message{
+				MessageType: &v1.Message_ExecuteFunctionOnRegionResponse{
+					ExecuteFunctionOnRegionResponse: &v1.ExecuteFunctionOnRegionResponse{
+						Results: []*v1.EncodedValue{good, poison},
+					},
+				},
+			}
+			return writeFakeMessage(response, b)
+		}
+
+		result, err := connection.ExecuteOnRegion("fn", "foo", nil, nil)
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal([]interface{}{int32(777)}))
+
+		letters := queue.Drain()
+		Expect(letters).To(HaveLen(1))
+		Expect(letters[0].Operation).To(Equal("Function"))
+	})
+})
@@ -0,0 +1,166 @@
+package connector
+
+import (
+	"sync"
+	"time"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// The number of errors kept for DebugSnapshot
+const recentErrorCount = 20
+
+// RegionStats counts the operations on a region. Operations which are not on a region, such
+// as queries and functions executed on servers, are counted under "".
+type RegionStats struct {
+	Operations int64 `json:"operations"`
+	Errors     int64 `json:"errors"`
+	// Total time taken by the operations, including retries
+	Latency Duration `json:"latency"`
+}
+
+// An OperationError is an error returned by an operation, recorded for DebugSnapshot.
+type OperationError struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Region    string    `json:"region,omitempty"`
+	Error     string    `json:"error"`
+}
+
+// A DebugSnapshot describes the state of a Pool for debugging, and can be written as JSON.
+type DebugSnapshot struct {
+	Stats   PoolStats              `json:"stats"`
+	Regions map[string]RegionStats `json:"regions"`
+	// The most recent errors, oldest first
+	RecentErrors []OperationError `json:"recentErrors"`
+	// The most recent operations, oldest first, if enabled with SetJournalSize
+	Journal []JournalEntry `json:"journal,omitempty"`
+	// How each region is used, if enabled with SetAccessAnalysis
+	Access map[string]RegionAccess `json:"access,omitempty"`
+	// The current configuration, with the password redacted
+	Config Config `json:"config"`
+	TLS    bool   `json:"tls"`
+	// The statistics of each partition, see Pool.Partition
+	Partitions map[string]PoolStats `json:"partitions,omitempty"`
+}
+
+// The value reported in place of a password
+const redacted = "REDACTED"
+
+// Operation counts, recent errors and the journal, kept apart from the pool lock so that recording them
+// does not contend with acquiring connections
+type debugStats struct {
+	sync.Mutex
+	regions map[string]*RegionStats
+	errors  []OperationError
+	// Index of the oldest error once errors is full
+	next int
+	// The last journalSize operations, see SetJournalSize
+	journal     []JournalEntry
+	journalNext int
+	journalSize int
+	// Reads and writes of each region, if enabled with SetAccessAnalysis
+	access *accessAnalysis
+}
+
+// Record an operation which completed at now, according to the pool's clock
+func (this *debugStats) record(request, response *v1.Message, now, latency time.Duration, err error) {
+	region, operation := requestRegion(request), operationName(request)
+
+	this.Lock()
+	defer this.Unlock()
+
+	this.journalOperation(request, region, operation, latency, err)
+	this.recordAccess(request, response, region, now)
+
+	if this.regions == nil {
+		this.regions = make(map[string]*RegionStats)
+	}
+	stats, ok := this.regions[region]
+	if !ok {
+		stats = &RegionStats{}
+		this.regions[region] = stats
+	}
+	stats.Operations++
+	stats.Latency += Duration(latency)
+
+	if err == nil {
+		return
+	}
+	stats.Errors++
+
+	e := OperationError{Time: time.Now(), Operation: operation, Region: region, Error: err.Error()}
+	if len(this.errors) < recentErrorCount {
+		this.errors = append(this.errors, e)
+	} else {
+		this.errors[this.next] = e
+		this.next = (this.next + 1) % recentErrorCount
+	}
+}
+
+func (this *debugStats) snapshot(snapshot *DebugSnapshot, now time.Duration) {
+	this.Lock()
+	defer this.Unlock()
+
+	snapshot.Access = this.accessAnalysis(now)
+
+	snapshot.Regions = make(map[string]RegionStats, len(this.regions))
+	for region, stats := range this.regions {
+		snapshot.Regions[region] = *stats
+	}
+
+	snapshot.RecentErrors = make([]OperationError, 0, len(this.errors))
+	snapshot.RecentErrors = append(snapshot.RecentErrors, this.errors[this.next:]...)
+	snapshot.RecentErrors = append(snapshot.RecentErrors, this.errors[:this.next]...)
+
+	if this.journalSize > 0 {
+		snapshot.Journal = this.journalEntries()
+	}
+}
+
+// DebugSnapshot returns the pool's statistics, the operations on each region, recent errors
+// and, if enabled, the journal of the connectors using the pool, and its configuration. See
+// also the debug package, which serves snapshots over HTTP.
+func (this *Pool) DebugSnapshot() DebugSnapshot {
+	snapshot := DebugSnapshot{Stats: this.Stats()}
+	this.debug.snapshot(&snapshot, this.GetClock().Now())
+
+	this.RLock()
+	defer this.RUnlock()
+
+	snapshot.Config.Servers = make([]string, 0, len(this.providers))
+	for _, p := range this.providers {
+		if _, ok := p.(*serverConnectionProvider); ok {
+			snapshot.Config.Servers = append(snapshot.Config.Servers, providerAddress(p))
+		}
+	}
+
+	username, password := this.username, this.password
+	if password != "" {
+		password = redacted
+	}
+	maxConnections := this.maxConnections
+	connectTimeout := Duration(this.connectTimeout)
+	readTimeout, writeTimeout := Duration(this.readTimeout), Duration(this.writeTimeout)
+	snapshot.Config.Username = &username
+	snapshot.Config.Password = &password
+	snapshot.Config.MaxConnections = &maxConnections
+	snapshot.Config.ConnectTimeout = &connectTimeout
+	snapshot.Config.ReadTimeout = &readTimeout
+	snapshot.Config.WriteTimeout = &writeTimeout
+	minIdle, idleTimeout := this.minIdle, Duration(this.idleTimeout)
+	snapshot.Config.MinIdle = &minIdle
+	snapshot.Config.IdleTimeout = &idleTimeout
+	balancing := this.balancing
+	snapshot.Config.LoadBalancing = &balancing
+	snapshot.TLS = this.tlsConfig != nil
+
+	if len(this.partitions) > 0 {
+		snapshot.Partitions = make(map[string]PoolStats, len(this.partitions))
+		for name, partition := range this.partitions {
+			snapshot.Partitions[name] = partition.Stats()
+		}
+	}
+
+	return snapshot
+}
@@ -0,0 +1,153 @@
+package connector_test
+
+import (
+	"fmt"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DebugSnapshot", func() {
+
+	var pool *connector.Pool
+	var connection *connector.Protobuf
+	var fakeConn *connectorfakes.FakeConn
+	var failures int
+
+	BeforeEach(func() {
+		failures = 0
+		fakeConn = new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			if failures > 0 {
+				failures--
+				return writeFakeMessage(&v1.Message{
+					MessageType: &v1.Message_ErrorResponse{ErrorResponse: &v1.ErrorResponse{
+						Error: &v1.Error{ErrorCode: 2100, Message: fmt.Sprintf("failure %d", failures)},
+					}},
+				}, b)
+			}
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}},
+			}, b)
+		}
+
+		pool = connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+	})
+
+	It("counts operations and errors per region", func() {
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+		Expect(connection.Put("foo", "B", 1)).To(BeNil())
+		failures = 1
+		Expect(connection.Put("bar", "A", 1)).ToNot(BeNil())
+
+		snapshot := pool.DebugSnapshot()
+		Expect(snapshot.Regions).To(HaveLen(2))
+		Expect(snapshot.Regions["foo"].Operations).To(Equal(int64(2)))
+		Expect(snapshot.Regions["foo"].Errors).To(Equal(int64(0)))
+		Expect(snapshot.Regions["bar"].Errors).To(Equal(int64(1)))
+
+		Expect(snapshot.RecentErrors).To(HaveLen(1))
+		Expect(snapshot.RecentErrors[0].Operation).To(Equal("Put"))
+		Expect(snapshot.RecentErrors[0].Region).To(Equal("bar"))
+		Expect(snapshot.RecentErrors[0].Error).To(ContainSubstring("failure 0"))
+	})
+
+	It("keeps only the most recent errors, oldest first", func() {
+		failures = 25
+		for i := 0; i < 25; i++ {
+			// Each failure discards the connection
+			pool.AddConnection(fakeConn, true)
+			connection.Put("foo", "A", 1)
+		}
+
+		errors := pool.DebugSnapshot().RecentErrors
+		Expect(errors).To(HaveLen(20))
+		Expect(errors[0].Error).To(ContainSubstring("failure 19"))
+		Expect(errors[19].Error).To(ContainSubstring("failure 0"))
+	})
+
+	It("reports the configuration without the password", func() {
+		pool.AddServer("localhost", 40404)
+		pool.AddCredentials("jbloggs", "t0p53cr3t")
+		pool.SetMaxConnections(5)
+
+		snapshot := pool.DebugSnapshot()
+		Expect(snapshot.Config.Servers).To(Equal([]string{"localhost:40404"}))
+		Expect(*snapshot.Config.Username).To(Equal("jbloggs"))
+		Expect(*snapshot.Config.Password).To(Equal("REDACTED"))
+		Expect(*snapshot.Config.MaxConnections).To(Equal(5))
+		Expect(snapshot.TLS).To(BeFalse())
+		Expect(snapshot.Stats.Connections).To(Equal(1))
+	})
+})
+
+var _ = Describe("Journal", func() {
+
+	var pool *connector.Pool
+	var connection *connector.Protobuf
+
+	BeforeEach(func() {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}},
+			}, b)
+		}
+
+		pool = connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+	})
+
+	It("is disabled by default", func() {
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+		Expect(pool.DebugSnapshot().Journal).To(BeEmpty())
+	})
+
+	It("records the most recent operations", func() {
+		pool.SetJournalSize(2)
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+		Expect(connection.Put("foo", "B", 1)).To(BeNil())
+		Expect(connection.Put("bar", "A", "a longer value")).To(BeNil())
+
+		journal := pool.DebugSnapshot().Journal
+		Expect(journal).To(HaveLen(2))
+		Expect(journal[0].Operation).To(Equal("Put"))
+		Expect(journal[0].Region).To(Equal("foo"))
+		Expect(journal[1].Region).To(Equal("bar"))
+		Expect(journal[1].Size).To(BeNumerically(">", journal[0].Size))
+		Expect(journal[1].Error).To(BeEmpty())
+	})
+
+	It("identifies keys by their hash", func() {
+		pool.SetJournalSize(10)
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+		Expect(connection.Put("bar", "A", 2)).To(BeNil())
+		Expect(connection.Put("foo", "B", 1)).To(BeNil())
+
+		journal := pool.DebugSnapshot().Journal
+		Expect(journal[0].KeyHash).ToNot(BeEmpty())
+		Expect(journal[0].KeyHash).ToNot(ContainSubstring("A"))
+		Expect(journal[1].KeyHash).To(Equal(journal[0].KeyHash))
+		Expect(journal[2].KeyHash).ToNot(Equal(journal[0].KeyHash))
+	})
+
+	It("keeps the most recent entries when resized", func() {
+		pool.SetJournalSize(3)
+		for _, key := range []string{"A", "B", "C", "D"} {
+			Expect(connection.Put("foo", key, 1)).To(BeNil())
+		}
+		pool.SetJournalSize(2)
+		Expect(connection.Put("bar", "E", 1)).To(BeNil())
+
+		journal := pool.DebugSnapshot().Journal
+		Expect(journal).To(HaveLen(2))
+		Expect(journal[0].Region).To(Equal("foo"))
+		Expect(journal[1].Region).To(Equal("bar"))
+	})
+})
@@ -0,0 +1,61 @@
+package connector
+
+import (
+	"sync"
+	"sync/atomic"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// Fewest values for which decoding is spread over the decode workers; smaller results are
+// not worth the goroutines
+const minParallelDecode = 64
+
+// SetDecodeWorkers decodes the values of GetAll responses and of list query results on up to
+// workers goroutines at once, which shortens reading large results of JSON values on
+// machines with several cores. Results are returned, and passed to GetAllFunc's function,
+// in the same order as without workers. The values of a whole response are decoded before
+// the first is passed on, so GetAllFunc holds them all at once unless chunking applies (see
+// SetBulkChunkSize). A count of 0 or 1, the default, decodes on the calling goroutine.
+func (this *Protobuf) SetDecodeWorkers(workers int) {
+	this.decodeWorkers = workers
+}
+
+// The outcome of decoding one value
+type decodedValue struct {
+	value interface{}
+	err   error
+}
+
+// Decode values with decode on the decode workers, returning their outcomes in the same
+// order, or nil if there are too few values or no workers, in which case the caller should
+// decode each value itself.
+func (this *Protobuf) decodeParallel(values []*v1.EncodedValue, decode func(*v1.EncodedValue) (interface{}, error)) []decodedValue {
+	workers := this.decodeWorkers
+	if workers <= 1 || len(values) < minParallelDecode {
+		return nil
+	}
+	if workers > len(values) {
+		workers = len(values)
+	}
+
+	decoded := make([]decodedValue, len(values))
+	var next int64 = -1
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(values) {
+					return
+				}
+				decoded[i].value, decoded[i].err = decode(values[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	return decoded
+}
@@ -0,0 +1,90 @@
+package connector_test
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/gemfire/geode-go-client/query"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Decode workers", func() {
+
+	var connection *connector.Protobuf
+	var fakeConn *connectorfakes.FakeConn
+
+	const n = 500
+
+	type Person struct {
+		Name string `json:"name"`
+	}
+
+	encodedPeople := func() []*v1.EncodedValue {
+		values := make([]*v1.EncodedValue, n)
+		for i := range values {
+			values[i], _ = connector.EncodeValue(&Person{Name: fmt.Sprintf("person-%d", i)})
+		}
+		return values
+	}
+
+	// The responses are larger than a single read, so are read across as many as they need
+	respond := func(response *v1.Message) {
+		p := proto.NewBuffer(nil)
+		Expect(p.EncodeMessage(response)).To(Succeed())
+		fakeConn.ReadStub = bytes.NewReader(p.Bytes()).Read
+	}
+
+	BeforeEach(func() {
+		fakeConn = new(connectorfakes.FakeConn)
+		pool := connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+		connection.SetDecodeWorkers(4)
+	})
+
+	It("passes GetAll entries on in order", func() {
+		keys := make([]string, n)
+		response := &v1.GetAllResponse{}
+		for i, value := range encodedPeople() {
+			keys[i] = fmt.Sprintf("key-%d", i)
+			key, _ := connector.EncodeValue(keys[i])
+			response.Entries = append(response.Entries, &v1.Entry{Key: key, Value: value})
+		}
+		respond(&v1.Message{MessageType: &v1.Message_GetAllResponse{GetAllResponse: response}})
+
+		var got []interface{}
+		err := connection.GetAllInto("foo", keys, func() interface{} { return &Person{} }, func(key, value interface{}, err error) bool {
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal(&Person{Name: fmt.Sprintf("person-%d", len(got))}))
+			got = append(got, key)
+			return true
+		})
+
+		Expect(err).To(BeNil())
+		Expect(got).To(HaveLen(n))
+		Expect(got[n-1]).To(Equal(keys[n-1]))
+	})
+
+	It("returns list query results in order", func() {
+		respond(&v1.Message{MessageType: &v1.Message_OqlQueryResponse{
+			OqlQueryResponse: &v1.OQLQueryResponse{
+				Result: &v1.OQLQueryResponse_ListResult{ListResult: &v1.EncodedValueList{Element: encodedPeople()}},
+			},
+		}})
+
+		q := query.NewQuery("select * from /foo")
+		q.Reference = &Person{}
+		results, err := connection.QueryListResult(q)
+
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(n))
+		for i, result := range results {
+			Expect(result).To(Equal(&Person{Name: fmt.Sprintf("person-%d", i)}))
+		}
+	})
+})
@@ -0,0 +1,330 @@
+package connector
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Prefix marking a JSON field value as having been encrypted by this client. Values without
+// the prefix are passed through untouched on read so that data written before encryption was
+// enabled can still be decoded.
+const encryptedFieldPrefix = "geode-enc:v1:"
+
+// A KeyProvider supplies the symmetric key used to encrypt struct fields tagged with
+// `geode:",encrypt"`. The key must be 16, 24 or 32 bytes long, selecting AES-128, AES-192 or
+// AES-256 respectively. GetKey is called for every encode and decode so that providers may
+// fetch keys from an external secret store.
+type KeyProvider interface {
+	GetKey() ([]byte, error)
+}
+
+// StaticKeyProvider is a KeyProvider which always returns the same key.
+type StaticKeyProvider []byte
+
+func (k StaticKeyProvider) GetKey() ([]byte, error) {
+	return []byte(k), nil
+}
+
+// Parse a `geode:"name,opt1,opt2"` struct tag into its name and options.
+func parseGeodeTag(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+func hasTagOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// The path of JSON object keys leading to a field tagged for encryption. Its dotted form is
+// used as the additional data when sealing the field, so that an encrypted value cannot be
+// moved to another field.
+type encryptedField []string
+
+func (f encryptedField) String() string {
+	return strings.Join(f, ".")
+}
+
+// Returns the paths of all fields of the given type which are tagged for encryption,
+// including those of embedded and nested structs. An error is returned for tagged fields
+// which cannot be located in the JSON document, such as those within slices or maps, rather
+// than writing them unencrypted.
+func encryptedFields(t reflect.Type) ([]encryptedField, error) {
+	fields := make([]encryptedField, 0)
+	recursive := false
+	if err := collectEncryptedFields(t, nil, make(map[reflect.Type]bool), &fields, &recursive); err != nil {
+		return nil, err
+	}
+
+	if recursive && len(fields) > 0 {
+		return nil, errors.New(fmt.Sprintf("unable to encrypt fields of recursive type %s", t))
+	}
+
+	return fields, nil
+}
+
+func collectEncryptedFields(t reflect.Type, path encryptedField, visiting map[reflect.Type]bool, fields *[]encryptedField, recursive *bool) error {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	if visiting[t] {
+		*recursive = true
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := field.Name
+		jsonName := ""
+		if jsonTag, ok := field.Tag.Lookup("json"); ok {
+			jsonName, _ = parseGeodeTag(jsonTag)
+			if jsonName == "-" {
+				continue
+			}
+			if jsonName != "" {
+				name = jsonName
+			}
+		}
+
+		tagged := false
+		geodeName := ""
+		if tag, ok := field.Tag.Lookup("geode"); ok {
+			var options []string
+			geodeName, options = parseGeodeTag(tag)
+			tagged = hasTagOption(options, "encrypt")
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		// Untagged embedded structs have their fields promoted into the enclosing object
+		if field.Anonymous && jsonName == "" && fieldType.Kind() == reflect.Struct && !tagged {
+			if err := collectEncryptedFields(fieldType, path, visiting, fields, recursive); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if field.PkgPath != "" || geodeName == "-" {
+			// Unexported fields are not encoded, and nor are those left out with geode:"-"
+			continue
+		}
+		if geodeName != "" {
+			// Documents are encrypted as they are sent to the server
+			name = geodeName
+		}
+
+		fieldPath := append(append(encryptedField{}, path...), name)
+		if tagged {
+			*fields = append(*fields, fieldPath)
+			continue
+		}
+
+		switch fieldType.Kind() {
+		case reflect.Struct:
+			if err := collectEncryptedFields(fieldType, fieldPath, visiting, fields, recursive); err != nil {
+				return err
+			}
+		case reflect.Slice, reflect.Array, reflect.Map:
+			nested := make([]encryptedField, 0)
+			if err := collectEncryptedFields(fieldType.Elem(), nil, visiting, &nested, recursive); err != nil {
+				return err
+			}
+			if len(nested) > 0 {
+				return errors.New(fmt.Sprintf("unable to encrypt fields within %s", fieldPath))
+			}
+		}
+	}
+
+	return nil
+}
+
+// Apply fn to the JSON of the field at path within document. Missing and null objects along
+// the path are left untouched.
+func updateField(document json.RawMessage, path encryptedField, fn func(json.RawMessage) (json.RawMessage, error)) (json.RawMessage, error) {
+	if len(document) == 0 || string(document) == "null" {
+		return document, nil
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(document, &fields); err != nil {
+		return nil, err
+	}
+
+	raw, ok := fields[path[0]]
+	if !ok {
+		return document, nil
+	}
+
+	var err error
+	if len(path) == 1 {
+		raw, err = fn(raw)
+	} else {
+		raw, err = updateField(raw, path[1:], fn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	fields[path[0]] = raw
+
+	return json.Marshal(fields)
+}
+
+func newGCM(keyProvider KeyProvider) (cipher.AEAD, error) {
+	key, err := getKey(keyProvider)
+	if _, ok := err.(*CallbackPanicError); ok {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to retrieve encryption key: %s", err.Error()))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func getKey(keyProvider KeyProvider) (key []byte, err error) {
+	defer RecoverCallback("KeyProvider", &err)
+	return keyProvider.GetKey()
+}
+
+// Encrypt the tagged fields of a JSON document produced from a value of type t. Each
+// encrypted field is replaced by a string holding the base64 encoded nonce and ciphertext
+// of the field's original JSON.
+func encryptFields(t reflect.Type, document string, keyProvider KeyProvider) (string, error) {
+	fields, err := encryptedFields(t)
+	if err != nil || len(fields) == 0 {
+		return document, err
+	}
+
+	gcm, err := newGCM(keyProvider)
+	if err != nil {
+		return "", err
+	}
+
+	result := json.RawMessage(document)
+	for _, field := range fields {
+		result, err = updateField(result, field, func(raw json.RawMessage) (json.RawMessage, error) {
+			nonce := make([]byte, gcm.NonceSize())
+			if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+				return nil, err
+			}
+
+			sealed := gcm.Seal(nonce, nonce, raw, []byte(field.String()))
+			return json.Marshal(encryptedFieldPrefix + base64.StdEncoding.EncodeToString(sealed))
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return string(result), nil
+}
+
+// Reverse the effect of encryptFields, restoring the original JSON of each encrypted field.
+func decryptFields(t reflect.Type, document string, keyProvider KeyProvider) (string, error) {
+	fields, err := encryptedFields(t)
+	if err != nil || len(fields) == 0 {
+		return document, err
+	}
+
+	var gcm cipher.AEAD
+	result := json.RawMessage(document)
+	for _, field := range fields {
+		result, err = updateField(result, field, func(raw json.RawMessage) (json.RawMessage, error) {
+			var encrypted string
+			if err := json.Unmarshal(raw, &encrypted); err != nil || !strings.HasPrefix(encrypted, encryptedFieldPrefix) {
+				return raw, nil
+			}
+
+			sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, encryptedFieldPrefix))
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("unable to decode encrypted field %s: %s", field, err.Error()))
+			}
+
+			if gcm == nil {
+				gcm, err = newGCM(keyProvider)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if len(sealed) < gcm.NonceSize() {
+				return nil, errors.New(fmt.Sprintf("encrypted field %s is too short", field))
+			}
+
+			nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+			plain, err := gcm.Open(nil, nonce, ciphertext, []byte(field.String()))
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("unable to decrypt field %s: %s", field, err.Error()))
+			}
+
+			return plain, nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return string(result), nil
+}
+
+// Return an error if any value in a decoded JSON document is still encrypted, which happens
+// when the field it was written to is no longer tagged for encryption or has been moved.
+func checkDecrypted(document string) error {
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(document), &decoded); err != nil {
+		return err
+	}
+
+	if path, ok := findEncrypted(decoded, nil); ok {
+		return errors.New(fmt.Sprintf("field %s is encrypted but not tagged for encryption in the reference type", path))
+	}
+
+	return nil
+}
+
+func findEncrypted(value interface{}, path encryptedField) (encryptedField, bool) {
+	switch v := value.(type) {
+	case string:
+		return path, strings.HasPrefix(v, encryptedFieldPrefix)
+	case map[string]interface{}:
+		for name, field := range v {
+			if found, ok := findEncrypted(field, append(append(encryptedField{}, path...), name)); ok {
+				return found, true
+			}
+		}
+	case []interface{}:
+		for i, element := range v {
+			if found, ok := findEncrypted(element, append(append(encryptedField{}, path...), fmt.Sprintf("%d", i))); ok {
+				return found, true
+			}
+		}
+	}
+
+	return nil, false
+}
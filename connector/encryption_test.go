@@ -0,0 +1,134 @@
+package connector_test
+
+import (
+	"encoding/json"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type SecretStruct struct {
+	Name string `json:"name"`
+	SSN  string `json:"ssn" geode:",encrypt"`
+}
+
+type Address struct {
+	Street string `json:"street" geode:",encrypt"`
+}
+
+type Customer struct {
+	SecretStruct
+	Home  Address  `json:"home"`
+	Work  *Address `json:"work"`
+	Email string   `json:"email" geode:",encrypt"`
+}
+
+type RenamedSecret struct {
+	SSN string `json:"ssn" geode:"socialSecurityNumber,encrypt"`
+}
+
+type Household struct {
+	Addresses []Address `json:"addresses"`
+}
+
+var _ = Describe("Field encryption", func() {
+
+	var connection *connector.Protobuf
+	var fakeConn *connectorfakes.FakeConn
+	var store *fakeStore
+
+	BeforeEach(func() {
+		fakeConn = new(connectorfakes.FakeConn)
+		pool := connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+		connection.SetKeyProvider(connector.StaticKeyProvider("0123456789abcdef"))
+
+		store = newFakeStore(fakeConn)
+	})
+
+	It("encrypts tagged fields on write", func() {
+		Expect(connection.Put("foo", "A", &SecretStruct{Name: "Joe", SSN: "123-45-6789"})).To(BeNil())
+
+		document := store.value.GetJsonObjectResult()
+		Expect(document).To(ContainSubstring("Joe"))
+		Expect(document).ToNot(ContainSubstring("123-45-6789"))
+	})
+
+	It("decrypts tagged fields on read", func() {
+		original := &SecretStruct{Name: "Joe", SSN: "123-45-6789"}
+		Expect(connection.Put("foo", "A", original)).To(BeNil())
+
+		ref := &SecretStruct{}
+		v, err := connection.Get("foo", "A", ref)
+		Expect(err).To(BeNil())
+		Expect(v).To(Equal(original))
+	})
+
+	It("encrypts renamed fields under their geode names", func() {
+		original := &RenamedSecret{SSN: "123-45-6789"}
+		Expect(connection.Put("foo", "A", original)).To(BeNil())
+
+		fields := make(map[string]string)
+		Expect(json.Unmarshal([]byte(store.value.GetJsonObjectResult()), &fields)).To(Succeed())
+		Expect(fields).To(HaveKey("socialSecurityNumber"))
+		Expect(fields["socialSecurityNumber"]).ToNot(ContainSubstring("123-45-6789"))
+
+		v, err := connection.Get("foo", "A", &RenamedSecret{})
+		Expect(err).To(BeNil())
+		Expect(v).To(Equal(original))
+	})
+
+	It("fails to decrypt with the wrong key", func() {
+		Expect(connection.Put("foo", "A", &SecretStruct{Name: "Joe", SSN: "123-45-6789"})).To(BeNil())
+
+		connection.SetKeyProvider(connector.StaticKeyProvider("fedcba9876543210"))
+		_, err := connection.Get("foo", "A", &SecretStruct{})
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("encrypts tagged fields of embedded and nested structs", func() {
+		original := &Customer{
+			SecretStruct: SecretStruct{Name: "Joe", SSN: "123-45-6789"},
+			Home:         Address{Street: "1 Main St"},
+			Work:         &Address{Street: "2 High St"},
+			Email:        "joe@example.com",
+		}
+		Expect(connection.Put("foo", "A", original)).To(BeNil())
+
+		document := store.value.GetJsonObjectResult()
+		Expect(document).To(ContainSubstring("Joe"))
+		Expect(document).ToNot(ContainSubstring("123-45-6789"))
+		Expect(document).ToNot(ContainSubstring("Main St"))
+		Expect(document).ToNot(ContainSubstring("High St"))
+		Expect(document).ToNot(ContainSubstring("example.com"))
+
+		v, err := connection.Get("foo", "A", &Customer{})
+		Expect(err).To(BeNil())
+		Expect(v).To(Equal(original))
+	})
+
+	It("refuses to write tagged fields it cannot encrypt", func() {
+		err := connection.Put("foo", "A", &Household{Addresses: []Address{{Street: "1 Main St"}}})
+		Expect(err).To(MatchError(ContainSubstring("unable to encrypt fields within addresses")))
+		Expect(store.value).To(BeNil())
+	})
+
+	It("does not decrypt a value moved to another field", func() {
+		Expect(connection.Put("foo", "A", &Customer{Email: "joe@example.com", SecretStruct: SecretStruct{SSN: "123-45-6789"}})).To(BeNil())
+
+		document := store.value.GetJsonObjectResult()
+		fields := make(map[string]interface{})
+		Expect(json.Unmarshal([]byte(document), &fields)).To(Succeed())
+		fields["ssn"] = fields["email"]
+		swapped, err := json.Marshal(fields)
+		Expect(err).To(BeNil())
+		store.value = &v1.EncodedValue{Value: &v1.EncodedValue_JsonObjectResult{JsonObjectResult: string(swapped)}}
+
+		_, err = connection.Get("foo", "A", &Customer{})
+		Expect(err).To(MatchError(ContainSubstring("unable to decrypt field ssn")))
+	})
+})
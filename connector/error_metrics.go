@@ -0,0 +1,85 @@
+package connector
+
+import (
+	"context"
+	"io"
+	"net"
+)
+
+// Codes reported by ErrorCode for errors which did not come from a server
+const (
+	ErrorCodeTimeout         = "TIMEOUT"
+	ErrorCodeCanceled        = "CANCELED"
+	ErrorCodeConnectionError = "CONNECTION_ERROR"
+	ErrorCodeClientError     = "CLIENT_ERROR"
+)
+
+// An ErrorPublisher is a MetricsPublisher which also counts failed operations by operation
+// and error code together, for example to alert on AUTHENTICATION_FAILED separately from
+// NO_AVAILABLE_SERVER. Publishers which do not implement this interface receive the count
+// keyed by error code alone, as MetricErrorCodes.
+type ErrorPublisher interface {
+	MetricsPublisher
+	AddError(name, operation, code string, delta int64)
+}
+
+// ErrorCode classifies the error returned by an operation for metrics and alerting. Error
+// responses from a server report the name of their Geode error code, such as
+// "AUTHENTICATION_FAILED" or "SERVER_ERROR", including those which caused retries to be
+// abandoned. Other errors report TIMEOUT, CANCELED, CONNECTION_ERROR or CLIENT_ERROR.
+func ErrorCode(err error) string {
+	switch e := err.(type) {
+	case nil:
+		return ""
+	case *ServerError:
+		return e.Code.String()
+	case *ThrottledError:
+		return e.Err.Code.String()
+	case *RetryBudgetError:
+		return ErrorCode(e.Err)
+	case *RetryableError:
+		return ErrorCode(e.Err)
+	case *ChunkError:
+		return ErrorCode(e.Err)
+	case *UnavailableError:
+		return ErrorCode(e.Err)
+	case *MultiError:
+		if len(e.Chunks) > 0 {
+			return ErrorCode(e.Chunks[0])
+		}
+	case AuthenticationError:
+		return "AUTHENTICATION_FAILED"
+	case *ResponseMismatchError:
+		return ErrorCodeConnectionError
+	case net.Error:
+		if e.Timeout() {
+			return ErrorCodeTimeout
+		}
+		return ErrorCodeConnectionError
+	}
+
+	switch err {
+	case context.DeadlineExceeded:
+		return ErrorCodeTimeout
+	case context.Canceled:
+		return ErrorCodeCanceled
+	case io.EOF, io.ErrUnexpectedEOF:
+		return ErrorCodeConnectionError
+	}
+
+	return ErrorCodeClientError
+}
+
+// Count a failed operation by its error code, and the retries it made
+func countOperationErrors(publisher MetricsPublisher, operation string, retries int, err error) {
+	guarded := guardedPublisher{publisher}
+	if retries > 0 {
+		guarded.AddKeyed(MetricRetries, operation, int64(retries))
+	}
+	if err == nil {
+		return
+	}
+
+	guarded.AddKeyed(MetricOperationErrors, operation, 1)
+	guarded.AddError(MetricErrorCodes, operation, ErrorCode(err), 1)
+}
@@ -0,0 +1,90 @@
+package connector_test
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type errorPublisher struct {
+	recordingPublisher
+}
+
+func (this *errorPublisher) AddError(name, operation, code string, delta int64) {
+	this.Add(name+"/"+operation+"/"+code, delta)
+}
+
+var _ = Describe("Error metrics", func() {
+
+	var pool *connector.Pool
+
+	addConnection := func(code v1.ErrorCode) {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_ErrorResponse{
+					ErrorResponse: &v1.ErrorResponse{Error: &v1.Error{ErrorCode: code, Message: "failed"}},
+				},
+			}, b)
+		}
+		pool.AddConnection(fakeConn, true)
+	}
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+	})
+
+	It("counts failed operations by operation and error code", func() {
+		publisher := &errorPublisher{recordingPublisher{counters: make(map[string]int64)}}
+		pool.SetMetricsPublisher(publisher)
+		addConnection(v1.ErrorCode_AUTHORIZATION_FAILED)
+
+		Expect(connector.NewConnector(pool).Put("foo", "A", 1)).ToNot(BeNil())
+
+		Expect(publisher.counters[connector.MetricOperationErrors+"/Put"]).To(Equal(int64(1)))
+		Expect(publisher.counters[connector.MetricErrorCodes+"/Put/AUTHORIZATION_FAILED"]).To(Equal(int64(1)))
+	})
+
+	It("counts by error code alone for other publishers", func() {
+		publisher := &recordingPublisher{counters: make(map[string]int64)}
+		pool.SetMetricsPublisher(publisher)
+		addConnection(v1.ErrorCode_NO_AVAILABLE_SERVER)
+
+		Expect(connector.NewConnector(pool).Put("foo", "A", 1)).ToNot(BeNil())
+
+		Expect(publisher.counters[connector.MetricErrorCodes+"/NO_AVAILABLE_SERVER"]).To(Equal(int64(1)))
+	})
+
+	It("counts retries by operation", func() {
+		publisher := &recordingPublisher{counters: make(map[string]int64)}
+		pool.SetMetricsPublisher(publisher)
+		addConnection(v1.ErrorCode_SERVER_ERROR)
+		addConnection(v1.ErrorCode_SERVER_ERROR)
+		connection := connector.NewConnector(pool)
+		connection.SetRetryBudget(&connector.RetryBudget{
+			MaxRetries: 1,
+			Retryable:  func(error) bool { return true },
+		})
+
+		Expect(connection.Put("foo", "A", 1)).ToNot(BeNil())
+
+		Expect(publisher.counters[connector.MetricRetries+"/Put"]).To(Equal(int64(1)))
+		Expect(publisher.counters[connector.MetricErrorCodes+"/SERVER_ERROR"]).To(Equal(int64(1)))
+	})
+
+	It("classifies errors which did not come from a server", func() {
+		Expect(connector.ErrorCode(nil)).To(Equal(""))
+		Expect(connector.ErrorCode(connector.AuthenticationError("denied"))).To(Equal("AUTHENTICATION_FAILED"))
+		Expect(connector.ErrorCode(context.DeadlineExceeded)).To(Equal(connector.ErrorCodeTimeout))
+		Expect(connector.ErrorCode(context.Canceled)).To(Equal(connector.ErrorCodeCanceled))
+		Expect(connector.ErrorCode(io.EOF)).To(Equal(connector.ErrorCodeConnectionError))
+		Expect(connector.ErrorCode(&connector.RetryableError{Err: io.EOF})).To(Equal(connector.ErrorCodeConnectionError))
+		Expect(connector.ErrorCode(errors.New("bad key"))).To(Equal(connector.ErrorCodeClientError))
+	})
+})
@@ -0,0 +1,137 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// A FailoverPolicy makes operations whose connection fails part way through, as when a
+// server dies, try again on another server instead of returning the error. Only operations
+// which can safely be performed twice fail over, since the failed server may have performed
+// the operation before its connection broke: reads, queries, Put, PutAll, Remove and Clear.
+// PutIfAbsent and function executions fail over only when made with an idempotency key (see
+// WithIdempotencyKey).
+//
+// The server whose connection failed is quarantined: its idle connections are closed and new
+// connections are opened to other servers, unless none of those can be reached, until the
+// quarantine ends or a health check (see Pool.CheckHealth) reaches the server again.
+type FailoverPolicy struct {
+	// Time for which a server is avoided after a connection to it fails. Defaults to 30s.
+	Quarantine time.Duration
+	// Maximum number of times an operation fails over. Defaults to 2.
+	MaxFailovers int
+}
+
+// SetFailover enables failing over operations whose connection fails. By default only
+// failures which are retryable (see IsRetryable) are retried, on any connection. Failover is
+// not applied to pinned connectors, whose connection cannot be replaced, nor to operations
+// on multiplexed connections, whose failures are always retryable.
+func (this *Protobuf) SetFailover(policy *FailoverPolicy) {
+	this.failover = policy
+}
+
+func (this *FailoverPolicy) quarantine() time.Duration {
+	if this.Quarantine <= 0 {
+		return 30 * time.Second
+	}
+	return this.Quarantine
+}
+
+func (this *FailoverPolicy) maxFailovers() int {
+	if this.MaxFailovers <= 0 {
+		return 2
+	}
+	return this.MaxFailovers
+}
+
+// Return whether an operation which failed with err, after failing over failovers times,
+// should fail over again
+func (this *FailoverPolicy) allows(ctx context.Context, request *v1.Message, err error, failovers int) bool {
+	if this == nil || failovers >= this.maxFailovers() {
+		return false
+	}
+	return isConnectionFailure(err) && mayRepeat(ctx, request)
+}
+
+// Return whether err is the failure of a connection, rather than an error response, a
+// timeout or the end of the operation's context
+func isConnectionFailure(err error) bool {
+	if retryable, ok := err.(*RetryableError); ok {
+		err = retryable.Err
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, net.ErrClosed) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && !netErr.Timeout()
+}
+
+// Return whether request may be sent again when the server it was sent to may already have
+// performed it
+func mayRepeat(ctx context.Context, request *v1.Message) bool {
+	switch request.GetMessageType().(type) {
+	case *v1.Message_GetRequest, *v1.Message_GetAllRequest, *v1.Message_GetSizeRequest, *v1.Message_KeySetRequest,
+		*v1.Message_OqlQueryRequest, *v1.Message_GetRegionNamesRequest, *v1.Message_GetServerRequest,
+		*v1.Message_PutRequest, *v1.Message_PutAllRequest, *v1.Message_RemoveRequest, *v1.Message_ClearRequest:
+		return true
+	}
+
+	_, ok := IdempotencyKeyFromContext(ctx)
+	return ok
+}
+
+// Avoid the server of a failed connection for period, closing its idle connections.
+// Connections which were added directly, rather than opened by a provider, belong to no
+// server and are not quarantined.
+func (this *Pool) quarantineServer(gConn *GeodeConnection, period time.Duration) {
+	if gConn.provider == nil {
+		return
+	}
+
+	this.Lock()
+	defer this.Unlock()
+
+	server := providerAddress(gConn.provider)
+	if this.quarantined == nil {
+		this.quarantined = make(map[string]time.Duration)
+	}
+	this.quarantined[server] = this.currentClock().Now() + period
+
+	for i := len(this.recentConnections) - 1; i >= 0; i-- {
+		c := this.recentConnections[i]
+		if !c.inUse && c.provider == gConn.provider {
+			this.discardConnection(c)
+			this.metricsPublisher().Add(MetricDiscardedConnections, 1)
+		}
+	}
+	this.log(LogWarn, "server quarantined", "server", server, "period", period)
+}
+
+// Return whether a server is quarantined, ending its quarantine if the period is over
+// MUST hold the pool lock when calling
+func (this *Pool) isQuarantined(server string) bool {
+	until, ok := this.quarantined[server]
+	if !ok {
+		return false
+	}
+	if this.currentClock().Now() >= until {
+		delete(this.quarantined, server)
+		return false
+	}
+	return true
+}
+
+// End the quarantine of a server which has been reached again
+// MUST hold the pool lock when calling
+func (this *Pool) liftQuarantine(server string) {
+	if _, ok := this.quarantined[server]; ok {
+		delete(this.quarantined, server)
+		this.log(LogInfo, "server quarantine lifted", "server", server)
+	}
+}
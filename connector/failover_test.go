@@ -0,0 +1,133 @@
+package connector_test
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	"github.com/gemfire/geode-go-client/protobuf"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// A ConnectionProvider whose connections accept the handshake and then either answer every
+// request with response or, if broken, fail as a connection reset by the server would
+type answeringProvider struct {
+	name     string
+	broken   bool
+	response *v1.Message
+	opened   int
+}
+
+func (this *answeringProvider) GetGeodeConnection() *connector.GeodeConnection {
+	this.opened++
+	handshaken := false
+	conn := new(connectorfakes.FakeConn)
+	conn.ReadStub = func(b []byte) (int, error) {
+		if !handshaken {
+			handshaken = true
+			return writeFakeMessage(&org_apache_geode_internal_protocol_protobuf.VersionAcknowledgement{
+				VersionAccepted: true,
+			}, b)
+		}
+		if this.broken {
+			return 0, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+		}
+		return writeFakeMessage(this.response, b)
+	}
+
+	return connector.NewGeodeConnection(conn)
+}
+
+func (this *answeringProvider) Healthy() bool {
+	return true
+}
+
+func (this *answeringProvider) Close() error {
+	return nil
+}
+
+func (this *answeringProvider) String() string {
+	return this.name
+}
+
+var _ = Describe("Failover", func() {
+
+	var pool *connector.Pool
+	var connection *connector.Protobuf
+	var healthy, broken *answeringProvider
+
+	BeforeEach(func() {
+		value, _ := connector.EncodeValue("A")
+		healthy = &answeringProvider{name: "server-1", response: &v1.Message{
+			MessageType: &v1.Message_GetResponse{GetResponse: &v1.GetResponse{Result: value}},
+		}}
+		broken = &answeringProvider{name: "server-2", broken: true}
+
+		pool = connector.NewPool()
+		pool.AddProvider(healthy)
+		// The most recently added provider is tried first
+		pool.AddProvider(broken)
+		connection = connector.NewConnector(pool)
+	})
+
+	It("returns connection failures without a policy", func() {
+		_, err := connection.Get("foo", "A", nil)
+		Expect(err).To(MatchError(ContainSubstring("connection reset")))
+	})
+
+	It("tries another server and quarantines the failed one", func() {
+		connection.SetFailover(&connector.FailoverPolicy{Quarantine: time.Hour})
+
+		value, err := connection.Get("foo", "A", nil)
+		Expect(err).To(BeNil())
+		Expect(value).To(Equal("A"))
+		Expect(broken.opened).To(Equal(1))
+
+		first, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		defer pool.ReturnConnection(first)
+		second, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		defer pool.ReturnConnection(second)
+		Expect(broken.opened).To(Equal(1))
+		Expect(healthy.opened).To(Equal(2))
+	})
+
+	It("ends the quarantine once a health check reaches the server", func() {
+		connection.SetFailover(&connector.FailoverPolicy{Quarantine: time.Hour})
+		_, err := connection.Get("foo", "A", nil)
+		Expect(err).To(BeNil())
+
+		pool.CheckHealth()
+		opened := broken.opened
+		first, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		defer pool.ReturnConnection(first)
+		second, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		defer pool.ReturnConnection(second)
+
+		Expect(broken.opened).To(Equal(opened + 1))
+	})
+
+	It("does not fail over writes which may not be repeated", func() {
+		healthy.response = &v1.Message{
+			MessageType: &v1.Message_PutIfAbsentResponse{PutIfAbsentResponse: &v1.PutIfAbsentResponse{}},
+		}
+		connection.SetFailover(&connector.FailoverPolicy{})
+
+		err := connection.PutIfAbsent("foo", "A", 1)
+		Expect(err).To(MatchError(ContainSubstring("connection reset")))
+
+		pool.CheckHealth()
+		ctx := connector.WithIdempotencyKey(context.Background(), "request-1")
+		err = connection.WithContext(ctx).PutIfAbsent("foo", "A", 1)
+		Expect(err).To(BeNil())
+		Expect(broken.opened).To(Equal(3))
+	})
+})
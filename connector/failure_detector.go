@@ -0,0 +1,359 @@
+package connector
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// A FailureDetector decides which servers a Pool should connect to. It is told the outcome
+// of every attempt to connect to a server, of every operation and of every health check
+// (see Pool.CheckHealth), identified by the server's host:port. Servers which are not
+// Available are skipped when opening connections, unless no server is available.
+type FailureDetector interface {
+	// Success records a successful interaction with a server which took rtt
+	Success(server string, rtt time.Duration)
+	// Failure records a failure to connect or communicate with a server
+	Failure(server string)
+	Available(server string) bool
+}
+
+const (
+	// Default suspicion level above which a PhiAccrualDetector considers a server failed
+	DefaultPhiThreshold = 8.0
+	// Number of intervals between successes kept for each server
+	phiWindowSize = 100
+	// Interval assumed before a server has a history, and the floor of the deviation
+	phiFirstInterval = time.Second
+	phiMinDeviation  = 100 * time.Millisecond
+)
+
+var _ FailureDetector = (*PhiAccrualDetector)(nil)
+
+// A PhiAccrualDetector is the default FailureDetector. Rather than marking a server failed
+// after a fixed number of errors, it learns the usual interval between successful
+// interactions with each server and, once a failure has been seen, suspects the server when
+// the time since the last success becomes improbable given that history. The level of
+// suspicion is phi = -log10(P), where P is the probability of a gap at least this long; a
+// threshold of 8 corresponds to a chance of 1 in 10^8 of wrongly suspecting a server.
+//
+// Busy servers are therefore suspected quickly after they fail, while servers which are only
+// occasionally used, or which are intermittently slow, are given proportionally longer. A
+// suspected server becomes available again on its next success, which for a server that is
+// no longer used comes from health checks. Round trip times do not affect the suspicion.
+type PhiAccrualDetector struct {
+	sync.Mutex
+	threshold float64
+	clock     Clock
+	servers   map[string]*serverHistory
+}
+
+type serverHistory struct {
+	intervals   []time.Duration
+	lastSuccess time.Duration
+	succeeded   bool
+	// Failures since the last success
+	failures int
+}
+
+// NewPhiAccrualDetector creates a detector which suspects a server once phi exceeds
+// threshold. A threshold of 0 uses DefaultPhiThreshold.
+func NewPhiAccrualDetector(threshold float64) *PhiAccrualDetector {
+	if threshold <= 0 {
+		threshold = DefaultPhiThreshold
+	}
+
+	return &PhiAccrualDetector{
+		threshold: threshold,
+		clock:     SystemClock,
+		servers:   make(map[string]*serverHistory),
+	}
+}
+
+// SetClock changes the Clock used to time intervals between successes.
+func (this *PhiAccrualDetector) SetClock(clock Clock) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.clock = clock
+}
+
+func (this *PhiAccrualDetector) history(server string) *serverHistory {
+	h, ok := this.servers[server]
+	if !ok {
+		h = &serverHistory{}
+		this.servers[server] = h
+	}
+	return h
+}
+
+func (this *PhiAccrualDetector) Success(server string, rtt time.Duration) {
+	this.Lock()
+	defer this.Unlock()
+
+	now := this.clock.Now()
+	h := this.history(server)
+	if h.succeeded {
+		h.intervals = append(h.intervals, now-h.lastSuccess)
+		if len(h.intervals) > phiWindowSize {
+			h.intervals = h.intervals[1:]
+		}
+	}
+	h.lastSuccess = now
+	h.succeeded = true
+	h.failures = 0
+}
+
+func (this *PhiAccrualDetector) Failure(server string) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.history(server).failures++
+}
+
+func (this *PhiAccrualDetector) Available(server string) bool {
+	return this.Phi(server) < this.threshold
+}
+
+// Phi returns the current suspicion level of a server. It is 0 for servers whose last
+// interaction succeeded and +Inf for servers which have failed without ever succeeding.
+func (this *PhiAccrualDetector) Phi(server string) float64 {
+	this.Lock()
+	defer this.Unlock()
+
+	h, ok := this.servers[server]
+	if !ok || h.failures == 0 {
+		return 0
+	}
+	if !h.succeeded {
+		return math.Inf(1)
+	}
+
+	mean, deviation := h.distribution()
+	return phi(float64(this.clock.Now()-h.lastSuccess), mean, deviation)
+}
+
+// Return the mean and standard deviation of the intervals between successes
+func (this *serverHistory) distribution() (float64, float64) {
+	if len(this.intervals) == 0 {
+		return float64(phiFirstInterval), float64(phiFirstInterval / 4)
+	}
+
+	var sum float64
+	for _, interval := range this.intervals {
+		sum += float64(interval)
+	}
+	mean := sum / float64(len(this.intervals))
+
+	var squares float64
+	for _, interval := range this.intervals {
+		squares += (float64(interval) - mean) * (float64(interval) - mean)
+	}
+	deviation := math.Sqrt(squares / float64(len(this.intervals)))
+
+	return mean, math.Max(deviation, float64(phiMinDeviation))
+}
+
+// The probability of an interval of at least elapsed, using a logistic approximation of the
+// normal distribution, expressed as phi.
+func phi(elapsed, mean, deviation float64) float64 {
+	y := (elapsed - mean) / deviation
+	e := math.Exp(-y * (1.5976 + 0.070566*y*y))
+	if elapsed > mean {
+		return -math.Log10(e / (1 + e))
+	}
+	return -math.Log10(1 - 1/(1+e))
+}
+
+// SetFailureDetector replaces the FailureDetector used to choose servers. By default each
+// pool has its own PhiAccrualDetector.
+func (this *Pool) SetFailureDetector(detector FailureDetector) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.detector = detector
+	this.syncPartitions()
+}
+
+// GetFailureDetector returns the FailureDetector used by this pool.
+func (this *Pool) GetFailureDetector() FailureDetector {
+	this.Lock()
+	defer this.Unlock()
+
+	return this.configuredDetector()
+}
+
+// Return the detector, guarded against panics
+// MUST hold the pool lock when calling
+func (this *Pool) failureDetector() FailureDetector {
+	return guardedDetector{this.configuredDetector()}
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) configuredDetector() FailureDetector {
+	if this.detector == nil {
+		this.detector = NewPhiAccrualDetector(0)
+	}
+	return this.detector
+}
+
+// Identify the server of a provider for the FailureDetector
+func providerAddress(provider ConnectionProvider) string {
+	if server, ok := provider.(*serverConnectionProvider); ok {
+		return net.JoinHostPort(server.host, strconv.Itoa(server.port))
+	}
+	if stringer, ok := provider.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%p", provider)
+}
+
+// Open a connection from the first provider, starting with the most recently added, whose
+// server is in the pool's subset (see SetSubset), the FailureDetector considers available and
+// is not quarantined (see FailoverPolicy). If none is, or none of those can connect, the
+// suspected and quarantined servers are tried too, so that a cluster which has recovered is
+// found again, and then the servers outside the subset.
+// MUST hold the pool lock when calling
+func (this *Pool) openConnection() *GeodeConnection {
+	detector := this.failureDetector()
+	suspected := make([]ConnectionProvider, 0)
+	outside := make([]ConnectionProvider, 0)
+
+	for i := len(this.providers) - 1; i >= 0; i-- {
+		if !providerHealthy(this.providers[i]) {
+			continue
+		}
+		if !this.inSubset(this.providers[i]) {
+			outside = append(outside, this.providers[i])
+			continue
+		}
+		if detector.Available(providerAddress(this.providers[i])) && !this.isQuarantined(providerAddress(this.providers[i])) {
+			if gConn := this.connectProvider(this.providers[i]); gConn != nil {
+				return gConn
+			}
+		} else {
+			suspected = append(suspected, this.providers[i])
+		}
+	}
+
+	for _, provider := range suspected {
+		if gConn := this.connectProvider(provider); gConn != nil {
+			return gConn
+		}
+	}
+
+	for _, provider := range outside {
+		if gConn := this.connectProvider(provider); gConn != nil {
+			return gConn
+		}
+	}
+
+	return nil
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) connectProvider(provider ConnectionProvider) *GeodeConnection {
+	gConn := provider.GetGeodeConnection()
+	if gConn == nil {
+		this.log(LogWarn, "connection failed", "server", providerAddress(provider))
+		this.failureDetector().Failure(providerAddress(provider))
+		this.checkAvailability(providerAddress(provider))
+		return nil
+	}
+
+	gConn.provider = provider
+	this.log(LogDebug, "connection opened", "server", providerAddress(provider))
+	return gConn
+}
+
+// Report the outcome of an exchange on a connection to the FailureDetector. Connections
+// which were added directly, rather than opened by a provider, are not tracked. Server error
+// responses are successful exchanges; only failures to communicate should be reported.
+func (this *Pool) reportOutcome(gConn *GeodeConnection, rtt time.Duration, err error) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.recordOutcome(gConn, rtt, err)
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) recordOutcome(gConn *GeodeConnection, rtt time.Duration, err error) {
+	if gConn.provider == nil {
+		return
+	}
+
+	if err != nil {
+		this.failureDetector().Failure(providerAddress(gConn.provider))
+	} else {
+		this.failureDetector().Success(providerAddress(gConn.provider), rtt)
+	}
+	this.checkAvailability(providerAddress(gConn.provider))
+}
+
+// CheckHealth connects to every server, reporting the outcome and round trip time to the
+// FailureDetector, so that servers which are not in use, and in particular those suspected
+// of having failed, are still assessed. Servers reached end any quarantine (see
+// FailoverPolicy). The connections are closed afterwards.
+func (this *Pool) CheckHealth() {
+	this.RLock()
+	providers := append([]ConnectionProvider{}, this.providers...)
+	this.RUnlock()
+
+	clock := this.GetClock()
+	this.Lock()
+	detector := this.failureDetector()
+	handshaker := this.currentHandshaker()
+	this.Unlock()
+
+	for _, provider := range providers {
+		if !providerHealthy(provider) {
+			continue
+		}
+		start := clock.Now()
+		reached := false
+		gConn := provider.GetGeodeConnection()
+		if gConn == nil {
+			detector.Failure(providerAddress(provider))
+		} else if err := runHandshaker(handshaker, gConn.rawConn, nil); err != nil {
+			_ = gConn.rawConn.Close()
+			detector.Failure(providerAddress(provider))
+		} else {
+			_ = gConn.rawConn.Close()
+			detector.Success(providerAddress(provider), clock.Now()-start)
+			reached = true
+		}
+
+		this.Lock()
+		if reached {
+			this.liftQuarantine(providerAddress(provider))
+		}
+		this.checkAvailability(providerAddress(provider))
+		this.Unlock()
+	}
+}
+
+// StartHealthChecks calls CheckHealth at the given interval until the returned function is
+// called.
+func (this *Pool) StartHealthChecks(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				this.CheckHealth()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
@@ -0,0 +1,166 @@
+package connector_test
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// A detector which records failures and otherwise behaves as the default
+type recordingDetector struct {
+	sync.Mutex
+	*connector.PhiAccrualDetector
+	failures map[string]int
+}
+
+func (this *recordingDetector) Failure(server string) {
+	this.Lock()
+	this.failures[server]++
+	this.Unlock()
+
+	this.PhiAccrualDetector.Failure(server)
+}
+
+func (this *recordingDetector) failuresOf(server string) int {
+	this.Lock()
+	defer this.Unlock()
+
+	return this.failures[server]
+}
+
+var _ = Describe("FailureDetector", func() {
+
+	Context("PhiAccrualDetector", func() {
+		var detector *connector.PhiAccrualDetector
+		var clock *steppingClock
+
+		BeforeEach(func() {
+			clock = &steppingClock{}
+			detector = connector.NewPhiAccrualDetector(0)
+			detector.SetClock(clock)
+		})
+
+		It("trusts servers until they fail", func() {
+			Expect(detector.Available("a:1")).To(BeTrue())
+
+			detector.Failure("a:1")
+			Expect(detector.Available("a:1")).To(BeFalse())
+		})
+
+		It("suspects a regularly used server soon after it fails", func() {
+			for i := 0; i < 20; i++ {
+				clock.now += 100 * time.Millisecond
+				detector.Success("a:1", time.Millisecond)
+			}
+			detector.Failure("a:1")
+
+			clock.now += 100 * time.Millisecond
+			Expect(detector.Available("a:1")).To(BeTrue())
+
+			clock.now += time.Second
+			Expect(detector.Available("a:1")).To(BeFalse())
+			Expect(detector.Phi("a:1")).To(BeNumerically(">", connector.DefaultPhiThreshold))
+
+			detector.Success("a:1", time.Millisecond)
+			Expect(detector.Available("a:1")).To(BeTrue())
+		})
+
+		It("gives a server with irregular activity longer", func() {
+			for i := 0; i < 20; i++ {
+				clock.now += time.Duration(100+(i%2)*1900) * time.Millisecond
+				detector.Success("a:1", time.Millisecond)
+			}
+			detector.Failure("a:1")
+
+			clock.now += 1100 * time.Millisecond
+			Expect(detector.Available("a:1")).To(BeTrue())
+
+			clock.now += 10 * time.Second
+			Expect(detector.Available("a:1")).To(BeFalse())
+		})
+
+		It("tracks servers independently", func() {
+			detector.Failure("a:1")
+			Expect(detector.Available("a:1")).To(BeFalse())
+			Expect(detector.Available("b:1")).To(BeTrue())
+		})
+	})
+
+	Context("Pool", func() {
+		var pool *connector.Pool
+		var server *handshakeServer
+		var detector *recordingDetector
+
+		BeforeEach(func() {
+			pool = connector.NewPool()
+			server = newHandshakeServer()
+
+			detector = &recordingDetector{
+				PhiAccrualDetector: connector.NewPhiAccrualDetector(0),
+				failures:           make(map[string]int),
+			}
+			pool.SetFailureDetector(detector)
+		})
+
+		AfterEach(func() {
+			server.listener.Close()
+		})
+
+		addServer := func(address string) {
+			host, port, err := net.SplitHostPort(address)
+			Expect(err).To(BeNil())
+			p, err := net.LookupPort("tcp", port)
+			Expect(err).To(BeNil())
+			pool.AddServer(host, p)
+		}
+
+		It("skips a server which failed without removing it", func() {
+			closed, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).To(BeNil())
+			down := closed.Addr().String()
+			closed.Close()
+
+			addServer(server.address())
+			addServer(down)
+
+			c, err := pool.GetConnection()
+			Expect(err).To(BeNil())
+			Expect(detector.failuresOf(down)).To(Equal(1))
+			pool.DiscardConnection(c)
+
+			c, err = pool.GetConnection()
+			Expect(err).To(BeNil())
+			Expect(detector.failuresOf(down)).To(Equal(1))
+			pool.DiscardConnection(c)
+
+			server.listener.Close()
+			_, err = pool.GetConnection()
+			Expect(err).ToNot(BeNil())
+			Expect(detector.failuresOf(down)).To(Equal(2))
+		})
+
+		It("makes a suspected server available again after a health check", func() {
+			addServer(server.address())
+			detector.Failure(server.address())
+			Expect(detector.Available(server.address())).To(BeFalse())
+
+			pool.CheckHealth()
+			Expect(detector.Available(server.address())).To(BeTrue())
+			Eventually(server.closed).Should(Receive())
+		})
+
+		It("runs health checks periodically", func() {
+			addServer(server.address())
+			detector.Failure(server.address())
+
+			stop := pool.StartHealthChecks(10 * time.Millisecond)
+			defer stop()
+
+			Eventually(func() bool { return detector.Available(server.address()) }).Should(BeTrue())
+		})
+	})
+})
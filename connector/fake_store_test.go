@@ -0,0 +1,54 @@
+package connector_test
+
+import (
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+)
+
+// A fakeStore serves a single region entry over a fake connection. Put requests replace the
+// stored value, which is returned exactly as written by Get requests; tests may also set the
+// value directly.
+type fakeStore struct {
+	value    *v1.EncodedValue
+	response *v1.Message
+}
+
+func newFakeStore(fakeConn *connectorfakes.FakeConn) *fakeStore {
+	store := &fakeStore{}
+	fakeConn.WriteStub = store.write
+	fakeConn.ReadStub = store.read
+
+	return store
+}
+
+func (this *fakeStore) write(b []byte) (int, error) {
+	request := &v1.Message{}
+	if err := proto.NewBuffer(b).DecodeMessage(request); err != nil {
+		return 0, err
+	}
+
+	if put := request.GetPutRequest(); put != nil {
+		this.value = put.GetEntry().GetValue()
+		this.response = &v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}}
+	} else {
+		this.response = nil
+	}
+
+	return len(b), nil
+}
+
+func (this *fakeStore) read(b []byte) (int, error) {
+	response := this.response
+	if response == nil {
+		response = &v1.Message{
+			MessageType: &v1.Message_GetResponse{
+				GetResponse: &v1.GetResponse{
+					Result: this.value,
+				},
+			},
+		}
+	}
+
+	return writeFakeMessage(response, b)
+}
@@ -0,0 +1,118 @@
+package connector_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/gemfire/geode-go-client/query"
+	"github.com/golang/protobuf/proto"
+)
+
+// Fuzz targets for the paths which handle data from servers. Malformed or truncated
+// responses must produce errors, never panics. Run one with, for example:
+//
+//     go test ./connector -run '^$' -fuzz FuzzResponse
+
+// Serve data as the response to every request, split into reads of at most chunk bytes
+func fuzzConnection(data []byte, chunk int) *connector.Protobuf {
+	if chunk <= 0 {
+		chunk = 1
+	}
+
+	remaining := data
+	fakeConn := new(connectorfakes.FakeConn)
+	fakeConn.ReadStub = func(b []byte) (int, error) {
+		if len(remaining) == 0 {
+			return 0, io.EOF
+		}
+
+		n := chunk
+		if n > len(remaining) {
+			n = len(remaining)
+		}
+		n = copy(b, remaining[:n])
+		remaining = remaining[n:]
+		return n, nil
+	}
+
+	pool := connector.NewPool()
+	pool.AddConnection(fakeConn, true)
+	return connector.NewConnector(pool)
+}
+
+func fuzzSeed(message *v1.Message) []byte {
+	p := proto.NewBuffer(nil)
+	p.EncodeMessage(message)
+	return p.Bytes()
+}
+
+func FuzzResponse(f *testing.F) {
+	value, _ := connector.EncodeValue("a value")
+	json := &v1.EncodedValue{Value: &v1.EncodedValue_JsonObjectResult{JsonObjectResult: `{"name": "a"}`}}
+	list, _ := connector.EncodeValueList([]interface{}{1, "two"})
+
+	seeds := []*v1.Message{
+		{MessageType: &v1.Message_GetResponse{GetResponse: &v1.GetResponse{Result: value}}},
+		{MessageType: &v1.Message_GetResponse{GetResponse: &v1.GetResponse{Result: json}}},
+		{MessageType: &v1.Message_GetAllResponse{GetAllResponse: &v1.GetAllResponse{
+			Entries: []*v1.Entry{{Key: value, Value: json}},
+		}}},
+		{MessageType: &v1.Message_OqlQueryResponse{OqlQueryResponse: &v1.OQLQueryResponse{
+			Result: &v1.OQLQueryResponse_TableResult{TableResult: &v1.Table{
+				FieldName: []string{"a", "b"},
+				Row:       []*v1.EncodedValueList{list, list},
+			}},
+		}}},
+		{MessageType: &v1.Message_ErrorResponse{ErrorResponse: &v1.ErrorResponse{
+			Error: &v1.Error{ErrorCode: 1, Message: "failed"},
+		}}},
+	}
+	for _, seed := range seeds {
+		data := fuzzSeed(seed)
+		f.Add(data, 4096)
+		f.Add(data, 1)
+		f.Add(data[:len(data)/2], 3)
+	}
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}, 4096)
+	f.Add([]byte{0x80}, 1)
+
+	type Named struct {
+		Name string `json:"name"`
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, chunk int) {
+		fuzzConnection(data, chunk).Get("foo", "A", &Named{})
+		fuzzConnection(data, chunk).GetAll("foo", []string{"A"})
+		fuzzConnection(data, chunk).Size("foo")
+
+		q := query.NewQuery("select * from /foo")
+		q.Trace = true
+		fuzzConnection(data, chunk).QueryTableResult(q)
+		fuzzConnection(data, chunk).QueryListResult(q)
+		fuzzConnection(data, chunk).QuerySingleResult(q)
+	})
+}
+
+func FuzzDecodeValue(f *testing.F) {
+	for _, v := range []interface{}{1, int64(2), "three", []byte{4}, true, 6.0} {
+		ev, _ := connector.EncodeValue(v)
+		data, _ := proto.Marshal(ev)
+		f.Add(data)
+	}
+	json, _ := proto.Marshal(&v1.EncodedValue{Value: &v1.EncodedValue_JsonObjectResult{JsonObjectResult: `{"a": [1, {"b": null}]}`}})
+	f.Add(json)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ev := &v1.EncodedValue{}
+		if proto.Unmarshal(data, ev) != nil {
+			return
+		}
+
+		connector.DecodeValue(ev, nil)
+		connector.DecodeValue(ev, &map[string]interface{}{})
+		connector.DecodeValueList(&v1.EncodedValueList{Element: []*v1.EncodedValue{ev, nil}}, nil)
+	})
+}
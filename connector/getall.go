@@ -0,0 +1,77 @@
+package connector
+
+import (
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// GetAllFunc gets entries as GetAll, but passes each to fn as it is decoded rather than
+// collecting them in maps, so that the entries of a large response need not all be held
+// twice. Keys which could not be read are passed with their error and a nil value. fn may
+// return false to stop early.
+//
+// When chunking applies (see SetBulkChunkSize) the chunks are requested one at a time, so
+// only one response is held at once. The keys of a chunk which fails as a whole are passed
+// to fn with the chunk's error, which may include keys already passed with their values, and
+// a *MultiError is returned once the other chunks are done.
+func (this *Protobuf) GetAllFunc(region string, keys interface{}, fn func(key, value interface{}, err error) bool) error {
+	return this.GetAllInto(region, keys, nil, fn)
+}
+
+// GetAllInto is GetAllFunc, but decodes each JSON value into a new reference returned by
+// newRef, such as a pointer to a new struct.
+func (this *Protobuf) GetAllInto(region string, keys interface{}, newRef func() interface{}, fn func(key, value interface{}, err error) bool) error {
+	timed, finish := this.timed("GetAll")
+	defer finish()
+
+	return timed.getAllEach(region, keys, timed.valueDecoder(newRef), fn)
+}
+
+// Get entries in chunks, decoding each value with decode and passing it to fn
+func (this *Protobuf) getAllEach(region string, keys interface{}, decode func(*v1.EncodedValue) (interface{}, error), fn func(key, value interface{}, err error) bool) error {
+	keySlice, encodedKeys, err := encodeKeys(keys)
+	if err != nil {
+		return err
+	}
+
+	chunks := this.chunks(len(encodedKeys))
+	multi := &MultiError{Operation: "GetAll", Region: region, Total: len(chunks)}
+
+	for i, bounds := range chunks {
+		response, server, err := this.doTrackedOperation(getAllRequest(region, encodedKeys[bounds[0]:bounds[1]]))
+		// The encoded keys are no longer needed, and may take as much memory as the entries
+		for j := bounds[0]; j < bounds[1]; j++ {
+			encodedKeys[j] = nil
+		}
+
+		if err == nil {
+			var more bool
+			if more, err = this.eachGetAllEntry(region, response, decode, fn); err == nil && !more {
+				return nil
+			}
+		}
+		if err == nil {
+			continue
+		}
+
+		if len(chunks) == 1 {
+			return err
+		}
+
+		chunkKeys := make([]interface{}, 0, bounds[1]-bounds[0])
+		for j := bounds[0]; j < bounds[1]; j++ {
+			chunkKeys = append(chunkKeys, keySlice.Index(j).Interface())
+		}
+		multi.Chunks = append(multi.Chunks, &ChunkError{Index: i, Server: server, Keys: chunkKeys, Err: err})
+
+		for _, k := range chunkKeys {
+			if !fn(k, nil, err) {
+				return multi
+			}
+		}
+	}
+
+	if len(multi.Chunks) > 0 {
+		return multi
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package connector_test
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/gemfire/geode-go-client/codec"
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// Benchmarks for reading many entries at once, comparing the memory used by GetAll with
+// GetAllFunc. Run them with, for example:
+//
+//     go test ./connector -run '^$' -bench GetAll -benchmem
+
+const benchmarkEntries = 100000
+
+// A connection answering each request with the next of its responses. Unlike a FakeConn it
+// does not record the buffers passed to Read and Write, which would be counted as held.
+type responseConn struct {
+	*connectorfakes.FakeConn
+	responses [][]byte
+	requests  int
+	reader    bytes.Reader
+}
+
+func (this *responseConn) Write(p []byte) (int, error) {
+	this.reader.Reset(this.responses[this.requests%len(this.responses)])
+	this.requests++
+	return len(p), nil
+}
+
+func (this *responseConn) Read(p []byte) (int, error) {
+	return this.reader.Read(p)
+}
+
+// Return a connector answering GetAll requests for chunks of the given size, in order, with
+// the entries of those keys, along with the keys
+func getAllConnection(b *testing.B, n, chunk int) (*connector.Protobuf, []string) {
+	keys := make([]string, n)
+	var responses [][]byte
+	response := &v1.GetAllResponse{}
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+		k, _ := connector.EncodeValue(keys[i])
+		v, _ := connector.EncodeValue(fmt.Sprintf("%064d", i))
+		response.Entries = append(response.Entries, &v1.Entry{Key: k, Value: v})
+
+		if len(response.Entries) == chunk || i == n-1 {
+			data, err := codec.MarshalMessage(&v1.Message{
+				MessageType: &v1.Message_GetAllResponse{GetAllResponse: response},
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			responses = append(responses, data)
+			response = &v1.GetAllResponse{}
+		}
+	}
+
+	pool := connector.NewPool()
+	pool.AddConnection(&responseConn{FakeConn: new(connectorfakes.FakeConn), responses: responses}, true)
+
+	connection := connector.NewConnector(pool)
+	if chunk < n {
+		connection.SetBulkChunkSize(chunk)
+	}
+	return connection, keys
+}
+
+// Return the heap in use after a collection
+func liveHeap() uint64 {
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+// Report the heap held beyond baseline, as held-B, once all the entries have been read. It is
+// measured once, since it is the memory held rather than the total allocated which matters.
+func reportHeldHeap(b *testing.B, baseline uint64) {
+	b.StopTimer()
+	defer b.StartTimer()
+
+	held := int64(liveHeap()) - int64(baseline)
+	if held < 0 {
+		held = 0
+	}
+	b.ReportMetric(float64(held), "held-B")
+}
+
+func BenchmarkGetAll(b *testing.B) {
+	connection, keys := getAllConnection(b, benchmarkEntries, benchmarkEntries)
+	baseline := liveHeap()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		entries, _, err := connection.GetAll("foo", keys)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(entries) != benchmarkEntries {
+			b.Fatalf("got %d entries", len(entries))
+		}
+		if i == 0 {
+			reportHeldHeap(b, baseline)
+			runtime.KeepAlive(entries)
+		}
+	}
+}
+
+func benchmarkGetAllFunc(b *testing.B, chunk int) {
+	connection, keys := getAllConnection(b, benchmarkEntries, chunk)
+	baseline := liveHeap()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		n := 0
+		err := connection.GetAllFunc("foo", keys, func(key, value interface{}, err error) bool {
+			n++
+			if i == 0 && n == benchmarkEntries {
+				reportHeldHeap(b, baseline)
+			}
+			return err == nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if n != benchmarkEntries {
+			b.Fatalf("got %d entries", n)
+		}
+	}
+}
+
+func BenchmarkGetAllFunc(b *testing.B) {
+	benchmarkGetAllFunc(b, benchmarkEntries)
+}
+
+func BenchmarkGetAllFuncChunked(b *testing.B) {
+	benchmarkGetAllFunc(b, benchmarkEntries/10)
+}
+
+// Compare with BenchmarkGetAll to see the effect of decoding on several goroutines
+func BenchmarkGetAllDecodeWorkers(b *testing.B) {
+	connection, keys := getAllConnection(b, benchmarkEntries, benchmarkEntries)
+	connection.SetDecodeWorkers(runtime.NumCPU())
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		entries, _, err := connection.GetAll("foo", keys)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(entries) != benchmarkEntries {
+			b.Fatalf("got %d entries", len(entries))
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package connector_test
+
+import (
+	"errors"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetAllFunc", func() {
+
+	var connection *connector.Protobuf
+	var fakeConn *connectorfakes.FakeConn
+	var requests []*v1.Message
+
+	// Answer each GetAll request with an entry per key, except for key "X" which fails
+	answer := func(b []byte) (int, error) {
+		request := requests[len(requests)-1].GetGetAllRequest()
+		response := &v1.GetAllResponse{}
+		for _, k := range request.Key {
+			if k.GetStringResult() == "X" {
+				response.Failures = append(response.Failures, &v1.KeyedError{Key: k, Error: &v1.Error{ErrorCode: 1, Message: "getall failure"}})
+				continue
+			}
+			response.Entries = append(response.Entries, &v1.Entry{Key: k, Value: k})
+		}
+		return writeFakeMessage(&v1.Message{
+			MessageType: &v1.Message_GetAllResponse{GetAllResponse: response},
+		}, b)
+	}
+
+	BeforeEach(func() {
+		requests = nil
+		fakeConn = new(connectorfakes.FakeConn)
+		fakeConn.WriteStub = func(b []byte) (int, error) {
+			request := &v1.Message{}
+			if err := proto.NewBuffer(b).DecodeMessage(request); err != nil {
+				return 0, err
+			}
+			requests = append(requests, request)
+			return len(b), nil
+		}
+		fakeConn.ReadStub = answer
+
+		// A failed chunk discards its connection, so provide a spare which shares the stubs
+		spareConn := new(connectorfakes.FakeConn)
+		spareConn.WriteStub = fakeConn.WriteStub
+		spareConn.ReadStub = func(b []byte) (int, error) {
+			return fakeConn.ReadStub(b)
+		}
+
+		pool := connector.NewPool()
+		pool.AddConnection(spareConn, true)
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+	})
+
+	It("passes each entry and failure to the function", func() {
+		entries := make(map[interface{}]interface{})
+		failures := make(map[interface{}]error)
+		err := connection.GetAllFunc("foo", []string{"A", "X", "B"}, func(key, value interface{}, err error) bool {
+			if err != nil {
+				failures[key] = err
+			} else {
+				entries[key] = value
+			}
+			return true
+		})
+
+		Expect(err).To(BeNil())
+		Expect(entries).To(Equal(map[interface{}]interface{}{"A": "A", "B": "B"}))
+		Expect(failures).To(HaveLen(1))
+		Expect(failures["X"]).To(MatchError("getall failure (1)"))
+	})
+
+	It("stops when the function returns false", func() {
+		connection.SetBulkChunkSize(2)
+
+		var keys []interface{}
+		err := connection.GetAllFunc("foo", []string{"A", "B", "C", "D"}, func(key, value interface{}, err error) bool {
+			keys = append(keys, key)
+			return len(keys) < 3
+		})
+
+		Expect(err).To(BeNil())
+		Expect(keys).To(Equal([]interface{}{"A", "B", "C"}))
+		Expect(requests).To(HaveLen(2))
+	})
+
+	It("reports the keys of failed chunks", func() {
+		connection.SetBulkChunkSize(2)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			if len(requests) == 1 {
+				return writeFakeMessage(&v1.Message{
+					MessageType: &v1.Message_ErrorResponse{
+						ErrorResponse: &v1.ErrorResponse{Error: &v1.Error{ErrorCode: 1, Message: "error from fake"}},
+					},
+				}, b)
+			}
+			return answer(b)
+		}
+
+		entries := make(map[interface{}]interface{})
+		failures := make(map[interface{}]error)
+		err := connection.GetAllFunc("foo", []string{"A", "B", "C"}, func(key, value interface{}, err error) bool {
+			if err != nil {
+				failures[key] = err
+			} else {
+				entries[key] = value
+			}
+			return true
+		})
+
+		Expect(entries).To(Equal(map[interface{}]interface{}{"C": "C"}))
+		Expect(failures).To(HaveLen(2))
+		Expect(failures["A"]).To(MatchError("error from fake (1)"))
+
+		var multi *connector.MultiError
+		Expect(errors.As(err, &multi)).To(BeTrue())
+		Expect(multi.Chunks).To(HaveLen(1))
+		Expect(multi.Chunks[0].Keys).To(Equal([]interface{}{"A", "B"}))
+	})
+
+	It("returns the error when the keys are not a slice", func() {
+		err := connection.GetAllFunc("foo", "A", func(key, value interface{}, err error) bool {
+			return true
+		})
+		Expect(err).To(MatchError("keys must be a slice or array"))
+	})
+})
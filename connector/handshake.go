@@ -0,0 +1,152 @@
+package connector
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/gemfire/geode-go-client/codec"
+	"github.com/gemfire/geode-go-client/protobuf"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+)
+
+// ErrVersionRejected is the Err of a HandshakeError when a server does not accept the
+// client's protocol version.
+var ErrVersionRejected = errors.New("handshake did not succeed")
+
+// A HandshakeError is returned when a connection's protocol version cannot be agreed with its
+// server. A server which rejects the connection's credentials returns an AuthenticationError
+// instead.
+type HandshakeError struct {
+	// "write" or "read" if exchanging the versions failed, or "" if the server rejected the
+	// client's version
+	Op  string
+	Err error
+}
+
+func (this *HandshakeError) Error() string {
+	if this.Op == "" {
+		return this.Err.Error()
+	}
+	return fmt.Sprintf("unable to %s handshake: %s", this.Op, this.Err.Error())
+}
+
+func (this *HandshakeError) Unwrap() error {
+	return this.Err
+}
+
+// HandshakeOptions configure Handshake.
+type HandshakeOptions struct {
+	// Skip agreeing the protocol version, for connections on which it has been agreed already
+	SkipVersion bool
+	// Properties sent to authenticate the connection, including any client metadata. The
+	// connection is not authenticated if nil.
+	Credentials map[string]string
+	// Time allowed for the whole handshake. 0 is no limit.
+	Timeout time.Duration
+}
+
+// A Handshaker prepares a new connection for operations. See Pool.SetHandshaker.
+type Handshaker func(conn net.Conn, options *HandshakeOptions) error
+
+// Handshake prepares a new connection for operations: it agrees the protocol version with the
+// server, then authenticates the connection if options carries credentials. A nil options
+// only agrees the version. Pools handshake the connections they open themselves; Handshake is
+// for connections established otherwise, for example through a tunnel, which can then be
+// given to a pool with Pool.AddConnection as ready.
+func Handshake(conn net.Conn, options *HandshakeOptions) error {
+	if options == nil {
+		options = &HandshakeOptions{}
+	}
+
+	if options.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(options.Timeout))
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	if !options.SkipVersion {
+		if err := agreeVersion(conn); err != nil {
+			return err
+		}
+	}
+
+	if options.Credentials != nil {
+		return authenticate(conn, options.Credentials)
+	}
+
+	return nil
+}
+
+func agreeVersion(conn net.Conn) error {
+	request := &org_apache_geode_internal_protocol_protobuf.NewConnectionClientVersion{
+		MajorVersion: MAJOR_VERSION,
+		MinorVersion: MINOR_VERSION,
+	}
+
+	if err := writeMessage(conn, request); err != nil {
+		return &HandshakeError{Op: "write", Err: err}
+	}
+
+	data, err := codec.ReadDelimited(conn)
+	if err != nil {
+		return &HandshakeError{Op: "read", Err: err}
+	}
+
+	ack := &org_apache_geode_internal_protocol_protobuf.VersionAcknowledgement{}
+	if err := proto.NewBuffer(data).DecodeMessage(ack); err != nil {
+		return &HandshakeError{Op: "read", Err: err}
+	}
+
+	if !ack.GetVersionAccepted() {
+		return &HandshakeError{Err: ErrVersionRejected}
+	}
+
+	return nil
+}
+
+func authenticate(conn net.Conn, credentials map[string]string) error {
+	request := &v1.Message{
+		MessageType: &v1.Message_HandshakeRequest{
+			HandshakeRequest: &v1.HandshakeRequest{
+				Credentials: credentials,
+			},
+		},
+	}
+
+	response, err := doOperationWithConnection(conn, request)
+	if err != nil {
+		return err
+	}
+
+	if !response.GetHandshakeResponse().GetAuthenticated() {
+		return AuthenticationError("connection not authenticated")
+	}
+
+	return nil
+}
+
+// SetHandshaker replaces the handshake of the connections the pool opens, for servers reached
+// in ways which need more, or less, than Handshake does. nil restores Handshake.
+func (this *Pool) SetHandshaker(handshaker Handshaker) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.handshaker = handshaker
+	this.syncPartitions()
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) currentHandshaker() Handshaker {
+	if this.handshaker == nil {
+		return Handshake
+	}
+	return this.handshaker
+}
+
+// Run a handshaker, recovering from a panic in it
+func runHandshaker(handshaker Handshaker, conn net.Conn, options *HandshakeOptions) (err error) {
+	defer RecoverCallback("Handshaker", &err)
+	return handshaker(conn, options)
+}
@@ -0,0 +1,198 @@
+package connector_test
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	"github.com/gemfire/geode-go-client/protobuf"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Handshake", func() {
+
+	var fakeConn *connectorfakes.FakeConn
+
+	versionAck := func(accepted bool) proto.Message {
+		return &org_apache_geode_internal_protocol_protobuf.VersionAcknowledgement{
+			ServerMajorVersion: 1,
+			ServerMinorVersion: 1,
+			VersionAccepted:    accepted,
+		}
+	}
+
+	authenticated := func(accepted bool) proto.Message {
+		return &v1.Message{
+			MessageType: &v1.Message_HandshakeResponse{
+				HandshakeResponse: &v1.HandshakeResponse{
+					Authenticated: accepted,
+				},
+			},
+		}
+	}
+
+	respondWith := func(responses ...proto.Message) {
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			response := responses[0]
+			responses = responses[1:]
+			return writeFakeMessage(response, b)
+		}
+	}
+
+	BeforeEach(func() {
+		fakeConn = new(connectorfakes.FakeConn)
+	})
+
+	It("agrees the version when options are nil", func() {
+		respondWith(versionAck(true))
+
+		Expect(connector.Handshake(fakeConn, nil)).To(Succeed())
+		Expect(fakeConn.WriteCallCount()).To(Equal(1))
+		Expect(fakeConn.SetDeadlineCallCount()).To(Equal(0))
+	})
+
+	It("authenticates after agreeing the version", func() {
+		respondWith(versionAck(true), authenticated(true))
+
+		err := connector.Handshake(fakeConn, &connector.HandshakeOptions{
+			Credentials: map[string]string{"security-username": "cluster"},
+		})
+		Expect(err).To(BeNil())
+		Expect(fakeConn.WriteCallCount()).To(Equal(2))
+
+		request := &v1.Message{}
+		Expect(proto.NewBuffer(fakeConn.WriteArgsForCall(1)).DecodeMessage(request)).To(Succeed())
+		Expect(request.GetHandshakeRequest().GetCredentials()).To(HaveKeyWithValue("security-username", "cluster"))
+	})
+
+	It("only authenticates when the version is skipped", func() {
+		respondWith(authenticated(true))
+
+		err := connector.Handshake(fakeConn, &connector.HandshakeOptions{
+			SkipVersion: true,
+			Credentials: map[string]string{},
+		})
+		Expect(err).To(BeNil())
+		Expect(fakeConn.WriteCallCount()).To(Equal(1))
+	})
+
+	It("returns a HandshakeError when the version is rejected", func() {
+		respondWith(versionAck(false))
+
+		err := connector.Handshake(fakeConn, nil)
+		Expect(err).To(BeAssignableToTypeOf(&connector.HandshakeError{}))
+		Expect(errors.Is(err, connector.ErrVersionRejected)).To(BeTrue())
+		Expect(err.Error()).To(Equal("handshake did not succeed"))
+	})
+
+	It("returns a HandshakeError when the version cannot be written", func() {
+		fakeConn.WriteReturns(0, errors.New("connection reset by peer"))
+
+		err := connector.Handshake(fakeConn, nil)
+		Expect(err).To(BeAssignableToTypeOf(&connector.HandshakeError{}))
+		Expect(err.(*connector.HandshakeError).Op).To(Equal("write"))
+		Expect(err.Error()).To(Equal("unable to write handshake: connection reset by peer"))
+	})
+
+	It("returns an AuthenticationError when the credentials are rejected", func() {
+		respondWith(versionAck(true), authenticated(false))
+
+		err := connector.Handshake(fakeConn, &connector.HandshakeOptions{
+			Credentials: map[string]string{"security-username": "bad"},
+		})
+		Expect(err).To(BeAssignableToTypeOf(connector.AuthenticationError("")))
+	})
+
+	It("sets and clears a deadline for the timeout", func() {
+		respondWith(versionAck(true))
+
+		err := connector.Handshake(fakeConn, &connector.HandshakeOptions{Timeout: time.Minute})
+		Expect(err).To(BeNil())
+		Expect(fakeConn.SetDeadlineCallCount()).To(Equal(2))
+		Expect(fakeConn.SetDeadlineArgsForCall(0)).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+		Expect(fakeConn.SetDeadlineArgsForCall(1).IsZero()).To(BeTrue())
+	})
+
+	Context("a pool's handshaker", func() {
+
+		var pool *connector.Pool
+
+		BeforeEach(func() {
+			pool = connector.NewPool()
+			pool.AddConnection(fakeConn, false)
+		})
+
+		It("is used for connections which are not ready", func() {
+			var handshaken []net.Conn
+			var given *connector.HandshakeOptions
+			pool.SetHandshaker(func(conn net.Conn, options *connector.HandshakeOptions) error {
+				handshaken = append(handshaken, conn)
+				given = options
+				return nil
+			})
+
+			gConn, err := pool.GetConnection()
+			Expect(err).To(BeNil())
+			Expect(gConn.GetRawConnection()).To(Equal(fakeConn))
+			Expect(handshaken).To(HaveLen(1))
+			Expect(given.SkipVersion).To(BeFalse())
+			Expect(fakeConn.WriteCallCount()).To(Equal(0))
+
+			pool.ReturnConnection(gConn)
+			_, err = pool.GetConnection()
+			Expect(err).To(BeNil())
+			Expect(handshaken).To(HaveLen(1))
+		})
+
+		It("is given the pool's credentials", func() {
+			pool.AddCredentials("cluster", "secret")
+
+			var given *connector.HandshakeOptions
+			pool.SetHandshaker(func(conn net.Conn, options *connector.HandshakeOptions) error {
+				given = options
+				return nil
+			})
+
+			_, err := pool.GetConnection()
+			Expect(err).To(BeNil())
+			Expect(given.Credentials).To(HaveKeyWithValue("security-username", "cluster"))
+		})
+
+		It("discards the connection when it fails", func() {
+			pool.SetHandshaker(func(conn net.Conn, options *connector.HandshakeOptions) error {
+				return &connector.HandshakeError{Op: "read", Err: errors.New("tunnel closed")}
+			})
+
+			_, err := pool.GetConnection()
+			Expect(err).To(MatchError("unable to read handshake: tunnel closed"))
+			Expect(fakeConn.CloseCallCount()).To(Equal(1))
+		})
+
+		It("returns a CallbackPanicError when it panics", func() {
+			pool.SetHandshaker(func(conn net.Conn, options *connector.HandshakeOptions) error {
+				panic("boom")
+			})
+
+			_, err := pool.GetConnection()
+			Expect(err).To(BeAssignableToTypeOf(&connector.CallbackPanicError{}))
+			Expect(err.(*connector.CallbackPanicError).Callback).To(Equal("Handshaker"))
+		})
+
+		It("is restored to Handshake by nil", func() {
+			pool.SetHandshaker(func(conn net.Conn, options *connector.HandshakeOptions) error {
+				return errors.New("not used")
+			})
+			pool.SetHandshaker(nil)
+			respondWith(versionAck(true))
+
+			_, err := pool.GetConnection()
+			Expect(err).To(BeNil())
+			Expect(fakeConn.WriteCallCount()).To(Equal(1))
+		})
+	})
+})
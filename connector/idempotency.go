@@ -0,0 +1,165 @@
+package connector
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+)
+
+type idempotencyContextKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx which identifies the writes made with it, for
+// deduplication by connectors on which SetDeduplication is enabled. The same key should be
+// used when a write is replayed, for example by a pipeline which delivers messages at least
+// once.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key of ctx, if any.
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyContextKey{}).(string)
+	return key, ok && key != ""
+}
+
+// The writes recently acknowledged by servers, shared by the copies of a connector. The
+// least recently acknowledged are forgotten once size is reached.
+type deduplicator struct {
+	sync.Mutex
+	size     int
+	writes   map[string]*list.Element
+	recently *list.List
+}
+
+// SetDeduplication remembers the last size writes made with an idempotency key (see
+// WithIdempotencyKey) which servers acknowledged. A write repeating one of them, with the
+// same idempotency key, operation, region and entry keys, is not sent again and succeeds at
+// once. Values are not compared, as a replayed write is expected to carry the same values.
+// Writes are Put, PutIfAbsent, PutAll and Remove, including those made by PutNull and
+// RemoveAll; each chunk of a chunked PutAll is remembered separately. Writes which failed are
+// not remembered, so they are sent again. A size of 0, the default, disables deduplication.
+func (this *Protobuf) SetDeduplication(size int) {
+	this.dedup.Lock()
+	defer this.dedup.Unlock()
+
+	if size < 0 {
+		size = 0
+	}
+	this.dedup.size = size
+	if this.dedup.recently == nil {
+		this.dedup.writes = make(map[string]*list.Element)
+		this.dedup.recently = list.New()
+	}
+	this.dedup.trim()
+}
+
+// Return the identity of a write made with an idempotency key, or "" if request is not such
+// a write or deduplication is disabled
+func (this *deduplicator) fingerprint(ctx context.Context, request *v1.Message) string {
+	if this == nil {
+		return ""
+	}
+
+	this.Lock()
+	enabled := this.size > 0
+	this.Unlock()
+	if !enabled {
+		return ""
+	}
+
+	idempotencyKey, ok := IdempotencyKeyFromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	var keys []*v1.EncodedValue
+	switch r := request.GetMessageType().(type) {
+	case *v1.Message_PutRequest, *v1.Message_PutIfAbsentRequest, *v1.Message_RemoveRequest:
+		keys = []*v1.EncodedValue{requestKey(request)}
+	case *v1.Message_PutAllRequest:
+		for _, entry := range r.PutAllRequest.GetEntry() {
+			keys = append(keys, entry.GetKey())
+		}
+	default:
+		return ""
+	}
+
+	// The entries of a PutAll may be sent in any order
+	encoded := make([][]byte, len(keys))
+	for i, key := range keys {
+		data, err := proto.Marshal(key)
+		if err != nil {
+			return ""
+		}
+		encoded[i] = data
+	}
+	sort.Slice(encoded, func(i, j int) bool {
+		return bytes.Compare(encoded[i], encoded[j]) < 0
+	})
+
+	h := fnv.New64a()
+	for _, data := range encoded {
+		fmt.Fprintf(h, "%d:", len(data))
+		h.Write(data)
+	}
+
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%x", idempotencyKey, operationName(request), requestRegion(request), h.Sum64())
+}
+
+// Return whether a write has been acknowledged, making it the most recent if so
+func (this *deduplicator) acknowledged(fingerprint string) bool {
+	this.Lock()
+	defer this.Unlock()
+
+	element, ok := this.writes[fingerprint]
+	if ok {
+		this.recently.MoveToFront(element)
+	}
+	return ok
+}
+
+func (this *deduplicator) acknowledge(fingerprint string) {
+	this.Lock()
+	defer this.Unlock()
+
+	if this.size == 0 {
+		return
+	}
+	if element, ok := this.writes[fingerprint]; ok {
+		this.recently.MoveToFront(element)
+		return
+	}
+	this.writes[fingerprint] = this.recently.PushFront(fingerprint)
+	this.trim()
+}
+
+// MUST hold the deduplicator lock when calling
+func (this *deduplicator) trim() {
+	for this.recently.Len() > this.size {
+		oldest := this.recently.Back()
+		this.recently.Remove(oldest)
+		delete(this.writes, oldest.Value.(string))
+	}
+}
+
+// Return the response a server would have sent had it been sent the write again
+func deduplicatedResponse(request *v1.Message) *v1.Message {
+	switch request.GetMessageType().(type) {
+	case *v1.Message_PutRequest:
+		return &v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}}
+	case *v1.Message_PutIfAbsentRequest:
+		return &v1.Message{MessageType: &v1.Message_PutIfAbsentResponse{PutIfAbsentResponse: &v1.PutIfAbsentResponse{}}}
+	case *v1.Message_PutAllRequest:
+		return &v1.Message{MessageType: &v1.Message_PutAllResponse{PutAllResponse: &v1.PutAllResponse{}}}
+	case *v1.Message_RemoveRequest:
+		return &v1.Message{MessageType: &v1.Message_RemoveResponse{RemoveResponse: &v1.RemoveResponse{}}}
+	}
+	return nil
+}
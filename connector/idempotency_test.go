@@ -0,0 +1,98 @@
+package connector_test
+
+import (
+	"context"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deduplication", func() {
+
+	var connection *connector.Protobuf
+	var fakeConn *connectorfakes.FakeConn
+	var response *v1.Message
+	var ctx context.Context
+
+	BeforeEach(func() {
+		response = &v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}}
+		fakeConn = new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(response, b)
+		}
+
+		pool := connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+		connection.SetDeduplication(2)
+		ctx = connector.WithIdempotencyKey(context.Background(), "message-1")
+	})
+
+	It("does not send a write again once it is acknowledged", func() {
+		Expect(connection.PutCtx(ctx, "foo", "A", 1)).To(Succeed())
+		Expect(connection.PutCtx(ctx, "foo", "A", 1)).To(Succeed())
+		Expect(fakeConn.WriteCallCount()).To(Equal(1))
+	})
+
+	It("sends writes without an idempotency key", func() {
+		Expect(connection.Put("foo", "A", 1)).To(Succeed())
+		Expect(connection.Put("foo", "A", 1)).To(Succeed())
+		Expect(fakeConn.WriteCallCount()).To(Equal(2))
+	})
+
+	It("tells apart writes of other keys, regions and idempotency keys", func() {
+		Expect(connection.PutCtx(ctx, "foo", "A", 1)).To(Succeed())
+		Expect(connection.PutCtx(ctx, "foo", "B", 1)).To(Succeed())
+		Expect(connection.PutCtx(ctx, "bar", "A", 1)).To(Succeed())
+		other := connector.WithIdempotencyKey(context.Background(), "message-2")
+		Expect(connection.PutCtx(other, "foo", "A", 1)).To(Succeed())
+		Expect(fakeConn.WriteCallCount()).To(Equal(4))
+	})
+
+	It("sends a write again if it failed", func() {
+		response = &v1.Message{MessageType: &v1.Message_ErrorResponse{ErrorResponse: &v1.ErrorResponse{
+			Error: &v1.Error{ErrorCode: v1.ErrorCode_SERVER_ERROR, Message: "failed"},
+		}}}
+		Expect(connection.PutCtx(ctx, "foo", "A", 1)).ToNot(Succeed())
+
+		response = &v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}}
+		pool := connection.GetPool()
+		pool.AddConnection(fakeConn, true)
+		Expect(connection.PutCtx(ctx, "foo", "A", 1)).To(Succeed())
+		Expect(fakeConn.WriteCallCount()).To(Equal(2))
+	})
+
+	It("deduplicates PutAll whatever the order of its entries", func() {
+		response = &v1.Message{MessageType: &v1.Message_PutAllResponse{PutAllResponse: &v1.PutAllResponse{}}}
+		entries := map[string]int{"A": 1, "B": 2, "C": 3, "D": 4}
+		for i := 0; i < 5; i++ {
+			failures, err := connection.WithContext(ctx).PutAll("foo", entries)
+			Expect(err).To(BeNil())
+			Expect(failures).To(BeEmpty())
+		}
+		Expect(fakeConn.WriteCallCount()).To(Equal(1))
+	})
+
+	It("forgets the least recently acknowledged writes", func() {
+		Expect(connection.PutCtx(ctx, "foo", "A", 1)).To(Succeed())
+		Expect(connection.PutCtx(ctx, "foo", "B", 1)).To(Succeed())
+		Expect(connection.PutCtx(ctx, "foo", "A", 1)).To(Succeed())
+		Expect(connection.PutCtx(ctx, "foo", "C", 1)).To(Succeed())
+		Expect(fakeConn.WriteCallCount()).To(Equal(3))
+
+		// B was forgotten to make room for C
+		Expect(connection.PutCtx(ctx, "foo", "B", 1)).To(Succeed())
+		Expect(connection.PutCtx(ctx, "foo", "C", 1)).To(Succeed())
+		Expect(fakeConn.WriteCallCount()).To(Equal(4))
+	})
+
+	It("is disabled by a size of 0", func() {
+		Expect(connection.PutCtx(ctx, "foo", "A", 1)).To(Succeed())
+		connection.SetDeduplication(0)
+		Expect(connection.PutCtx(ctx, "foo", "A", 1)).To(Succeed())
+		Expect(fakeConn.WriteCallCount()).To(Equal(2))
+	})
+})
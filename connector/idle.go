@@ -0,0 +1,125 @@
+package connector
+
+import (
+	"time"
+)
+
+// SetIdleTimeout closes connections which have been idle for longer than timeout whenever
+// EvictIdle runs, for example with StartIdleEviction, so that a pool which grew under load
+// does not hold on to its sockets. 0, the default, keeps idle connections open.
+func (this *Pool) SetIdleTimeout(timeout time.Duration) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.setIdleTimeout(timeout)
+}
+
+// Idle times are only tracked while there is a timeout, so connections which are idle when
+// it is set count as idle from then
+// MUST hold the pool lock when calling
+func (this *Pool) setIdleTimeout(timeout time.Duration) {
+	if this.idleTimeout <= 0 && timeout > 0 {
+		now := this.currentClock().Now()
+		for _, c := range this.recentConnections {
+			if !c.inUse {
+				c.idleSince = now
+			}
+		}
+	}
+	this.idleTimeout = timeout
+}
+
+// SetMinIdle keeps at least n idle connections open. EvictIdle does not close them and opens
+// new connections, within the connection limit, to make up the number.
+func (this *Pool) SetMinIdle(n int) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.minIdle = n
+}
+
+// EvictIdle closes connections idle for longer than the idle timeout, keeping the minimum
+// number of idle connections, then opens connections until there are that many. The pool's
+// partitions are evicted in turn.
+func (this *Pool) EvictIdle() {
+	this.Lock()
+	this.evictIdle()
+	partitions := make([]*Pool, 0, len(this.partitions))
+	for _, partition := range this.partitions {
+		partitions = append(partitions, partition)
+	}
+	this.Unlock()
+
+	for _, partition := range partitions {
+		partition.EvictIdle()
+	}
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) evictIdle() {
+	now := this.currentClock().Now()
+
+	idle := 0
+	for _, c := range this.recentConnections {
+		if !c.inUse {
+			idle++
+		}
+	}
+
+	// The pool uses the most recently added idle connection first, so evict from the front
+	for i := 0; this.idleTimeout > 0 && idle > this.minIdle && i < len(this.recentConnections); {
+		c := this.recentConnections[i]
+		if c.inUse || now-c.idleSince <= this.idleTimeout {
+			i++
+			continue
+		}
+
+		this.discardConnection(c)
+		this.metricsPublisher().Add(MetricDiscardedConnections, 1)
+		idle--
+	}
+
+	for idle < this.minIdle && (this.maxConnections == 0 || len(this.recentConnections) < this.maxConnections) {
+		if this.reconnect != nil && this.reconnect.delay(now, len(this.recentConnections) == 0) > 0 {
+			// Leave the remaining connections for a later eviction
+			return
+		}
+		gConn := this.openConnection()
+		if gConn == nil {
+			return
+		}
+		this.recentConnections = append(this.recentConnections, gConn)
+		this.metricsPublisher().Add(MetricConnectionsCreated, 1)
+		this.metricsPublisher().Add(MetricOpenConnections, 1)
+		this.connectionOpened()
+
+		if err := this.prepareConnection(gConn); err != nil {
+			return
+		}
+		gConn.idleSince = now
+		idle++
+	}
+}
+
+// StartIdleEviction calls EvictIdle at the given interval until the returned function is
+// called.
+func (this *Pool) StartIdleEviction(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				this.EvictIdle()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
@@ -0,0 +1,145 @@
+package connector_test
+
+import (
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// A clock which only moves when told to
+type manualClock struct {
+	now time.Duration
+}
+
+func (this *manualClock) Now() time.Duration {
+	return this.now
+}
+
+var _ = Describe("Idle eviction", func() {
+
+	var pool *connector.Pool
+	var clock *manualClock
+	var publisher *recordingPublisher
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+		clock = &manualClock{}
+		pool.SetClock(clock)
+		publisher = &recordingPublisher{counters: make(map[string]int64)}
+		pool.SetMetricsPublisher(publisher)
+	})
+
+	It("closes connections idle for longer than the timeout", func() {
+		pool.SetIdleTimeout(time.Minute)
+		stale := new(connectorfakes.FakeConn)
+		pool.AddConnection(stale, true)
+
+		clock.now = 30 * time.Second
+		fresh := new(connectorfakes.FakeConn)
+		pool.AddConnection(fresh, true)
+
+		clock.now = 75 * time.Second
+		pool.EvictIdle()
+
+		Expect(stale.CloseCallCount()).To(Equal(1))
+		Expect(fresh.CloseCallCount()).To(Equal(0))
+		Expect(pool.Stats().Connections).To(Equal(1))
+		Expect(publisher.counters[connector.MetricDiscardedConnections]).To(Equal(int64(1)))
+	})
+
+	It("does not close connections in use", func() {
+		pool.SetIdleTimeout(time.Minute)
+		pool.AddConnection(new(connectorfakes.FakeConn), true)
+		c, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+
+		clock.now = time.Hour
+		pool.EvictIdle()
+		Expect(pool.Stats().Connections).To(Equal(1))
+
+		pool.ReturnConnection(c)
+		pool.EvictIdle()
+		Expect(pool.Stats().Connections).To(Equal(1))
+
+		clock.now += 2 * time.Minute
+		pool.EvictIdle()
+		Expect(pool.Stats().Connections).To(Equal(0))
+	})
+
+	It("counts connections as idle from when the timeout is set", func() {
+		pool.AddConnection(new(connectorfakes.FakeConn), true)
+
+		clock.now = time.Hour
+		pool.SetIdleTimeout(time.Minute)
+		pool.EvictIdle()
+		Expect(pool.Stats().Connections).To(Equal(1))
+	})
+
+	It("keeps the minimum number of idle connections", func() {
+		pool.SetIdleTimeout(time.Minute)
+		pool.SetMinIdle(1)
+		pool.AddConnection(new(connectorfakes.FakeConn), true)
+		pool.AddConnection(new(connectorfakes.FakeConn), true)
+
+		clock.now = time.Hour
+		pool.EvictIdle()
+		Expect(pool.Stats().Connections).To(Equal(1))
+	})
+
+	It("opens connections up to the minimum number idle", func() {
+		server := newHandshakeServer()
+		defer server.listener.Close()
+
+		minIdle := 2
+		maxConnections := 3
+		Expect(pool.Configure(&connector.Config{
+			Servers:        []string{server.address()},
+			MinIdle:        &minIdle,
+			MaxConnections: &maxConnections,
+		})).To(BeNil())
+
+		c, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+
+		pool.EvictIdle()
+		Expect(pool.Stats()).To(Equal(connector.PoolStats{MaxConnections: 3, Connections: 3, InUse: 1}))
+		Expect(publisher.counters[connector.MetricConnectionsCreated]).To(Equal(int64(3)))
+
+		pool.ReturnConnection(c)
+	})
+
+	It("evicts idle connections in the background", func() {
+		pool.SetIdleTimeout(time.Minute)
+		pool.AddConnection(new(connectorfakes.FakeConn), true)
+		clock.now = time.Hour
+
+		stop := pool.StartIdleEviction(time.Millisecond)
+		defer stop()
+
+		Eventually(func() int { return pool.Stats().Connections }).Should(Equal(0))
+	})
+
+	It("copies the idle settings to new partitions", func() {
+		pool.SetIdleTimeout(time.Minute)
+		pool.SetMinIdle(1)
+		bulk := pool.Partition("bulk")
+		bulk.AddConnection(new(connectorfakes.FakeConn), true)
+		bulk.AddConnection(new(connectorfakes.FakeConn), true)
+
+		clock.now = time.Hour
+		pool.EvictIdle()
+		Expect(bulk.Stats().Connections).To(Equal(1))
+	})
+
+	It("rejects negative settings", func() {
+		servers := []string{"localhost:40404"}
+		minIdle := -1
+		Expect(pool.Configure(&connector.Config{Servers: servers, MinIdle: &minIdle})).To(MatchError("invalid minIdle -1"))
+
+		timeout := connector.Duration(-time.Second)
+		Expect(pool.Configure(&connector.Config{Servers: servers, IdleTimeout: &timeout})).To(MatchError("invalid idleTimeout -1s"))
+	})
+})
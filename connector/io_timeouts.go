@@ -0,0 +1,105 @@
+package connector
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// SetConnectTimeout limits the time allowed to connect to a server. 0 leaves it to the
+// operating system. It applies to new connections, including those to servers already
+// added.
+func (this *Pool) SetConnectTimeout(timeout time.Duration) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.setConnectTimeout(timeout)
+	this.syncPartitions()
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) setConnectTimeout(timeout time.Duration) {
+	this.connectTimeout = timeout
+	for _, p := range this.providers {
+		if server, ok := p.(*serverConnectionProvider); ok {
+			server.timeout = timeout
+		}
+	}
+}
+
+// SetReadTimeout limits the time allowed to read each response. A response which takes
+// longer fails the attempt with a RetryableError and the connection is discarded, so the
+// operation is retried as allowed by the connector's RetryBudget. Without a budget it is
+// retried until the operation's deadline passes or its context is done. 0, the default, is
+// no limit; operations are then only bound by their deadline.
+func (this *Pool) SetReadTimeout(timeout time.Duration) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.readTimeout = timeout
+}
+
+// SetWriteTimeout limits the time allowed to write each request, as SetReadTimeout.
+func (this *Pool) SetWriteTimeout(timeout time.Duration) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.writeTimeout = timeout
+}
+
+// The limits on a single exchange of a request and response
+type ioLimits struct {
+	// The exchange is abandoned once ctx is done
+	ctx context.Context
+	// The deadline of the whole operation, or zero
+	deadline     time.Time
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+}
+
+func (this *Pool) ioLimits(ctx context.Context, deadline time.Time) ioLimits {
+	this.RLock()
+	defer this.RUnlock()
+
+	return ioLimits{
+		ctx:          ctx,
+		deadline:     deadline,
+		readTimeout:  this.readTimeout,
+		writeTimeout: this.writeTimeout,
+	}
+}
+
+// Set the deadline for a read or write which may take timeout, unless the operation's
+// deadline is sooner. Returns whether the timeout applies, rather than the operation's
+// deadline, or an error if the deadline cannot be set or ctx is already done.
+func (this ioLimits) limit(setDeadline func(time.Time) error, timeout time.Duration) (bool, error) {
+	if timeout <= 0 {
+		return false, nil
+	}
+
+	limited := true
+	deadline := time.Now().Add(timeout)
+	if !this.deadline.IsZero() && this.deadline.Before(deadline) {
+		deadline = this.deadline
+		limited = false
+	}
+	if err := setDeadline(deadline); err != nil {
+		return false, err
+	}
+
+	// ctx is done before its interruption of the operation (see attemptOnce) takes effect, so
+	// checking it now ensures that the interruption is not undone by the new deadline
+	if this.ctx != nil && this.ctx.Err() != nil {
+		return false, this.ctx.Err()
+	}
+
+	return limited, nil
+}
+
+// Report a read or write which exceeded its timeout as retryable
+func timeoutError(err error, limited bool) error {
+	if netErr, ok := err.(net.Error); ok && limited && netErr.Timeout() {
+		return &RetryableError{err}
+	}
+	return err
+}
@@ -0,0 +1,113 @@
+package connector_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Read and write timeouts", func() {
+
+	var pool *connector.Pool
+	var connection *connector.Protobuf
+
+	respond := func(b []byte) (int, error) {
+		return writeFakeMessage(&v1.Message{
+			MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}},
+		}, b)
+	}
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+		connection = connector.NewConnector(pool)
+	})
+
+	It("sets a deadline for each read and write", func() {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = respond
+		pool.AddConnection(fakeConn, true)
+		pool.SetReadTimeout(time.Minute)
+		pool.SetWriteTimeout(time.Hour)
+
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+		Expect(fakeConn.SetReadDeadlineArgsForCall(0)).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+		Expect(fakeConn.SetWriteDeadlineArgsForCall(0)).To(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+
+		// The deadlines are cleared for the next user of the connection
+		Expect(fakeConn.SetDeadlineArgsForCall(fakeConn.SetDeadlineCallCount() - 1).IsZero()).To(BeTrue())
+	})
+
+	It("fails an operation whose deadline cannot be set", func() {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = respond
+		fakeConn.SetWriteDeadlineReturns(errors.New("use of closed network connection"))
+		pool.AddConnection(fakeConn, true)
+		pool.SetWriteTimeout(time.Minute)
+
+		Expect(connection.Put("foo", "A", 1)).To(MatchError("use of closed network connection"))
+		Expect(fakeConn.WriteCallCount()).To(Equal(0))
+		Expect(fakeConn.CloseCallCount()).To(Equal(1))
+	})
+
+	It("does not extend the deadline of the operation", func() {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = respond
+		pool.AddConnection(fakeConn, true)
+		pool.SetReadTimeout(time.Hour)
+		connection.SetTimeout(time.Minute)
+
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+		Expect(fakeConn.SetReadDeadlineArgsForCall(0)).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+	})
+
+	It("retries an operation whose read times out", func() {
+		slow := new(connectorfakes.FakeConn)
+		slow.ReadReturns(0, timeoutError{})
+		fast := new(connectorfakes.FakeConn)
+		fast.ReadStub = respond
+		pool.AddConnection(fast, true)
+		pool.AddConnection(slow, true)
+		pool.SetReadTimeout(time.Second)
+
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+		Expect(slow.CloseCallCount()).To(Equal(1))
+		Expect(fast.WriteCallCount()).To(Equal(1))
+	})
+
+	It("does not retry an operation whose deadline has passed", func() {
+		spare := new(connectorfakes.FakeConn)
+		spare.ReadStub = respond
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadReturns(0, timeoutError{})
+		pool.AddConnection(spare, true)
+		pool.AddConnection(fakeConn, true)
+		pool.SetReadTimeout(time.Hour)
+		connection.SetTimeout(time.Minute)
+
+		err := connection.Put("foo", "A", 1)
+		Expect(err).To(Equal(timeoutError{}))
+		Expect(spare.WriteCallCount()).To(Equal(0))
+	})
+
+	It("can be configured", func() {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = respond
+		pool.AddConnection(fakeConn, true)
+
+		timeout := connector.Duration(time.Minute)
+		Expect(pool.Configure(&connector.Config{ReadTimeout: &timeout})).To(BeNil())
+		Expect(*pool.DebugSnapshot().Config.ReadTimeout).To(Equal(timeout))
+
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+		Expect(fakeConn.SetReadDeadlineArgsForCall(0)).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+		Expect(fakeConn.SetWriteDeadlineCallCount()).To(Equal(0))
+
+		negative := connector.Duration(-time.Second)
+		Expect(pool.Configure(&connector.Config{WriteTimeout: &negative})).To(MatchError(ContainSubstring("invalid writeTimeout")))
+	})
+})
@@ -0,0 +1,111 @@
+package connector
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+)
+
+// A JournalEntry records an operation for the journal kept by SetJournalSize.
+type JournalEntry struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Region    string    `json:"region,omitempty"`
+	// A hash of the encoded key, for operations on a single key. Keys are not recorded, so
+	// that the journal does not expose data, but operations on the same key can be matched.
+	KeyHash string `json:"keyHash,omitempty"`
+	// The size of the encoded request in bytes
+	Size    int      `json:"size"`
+	Latency Duration `json:"latency"`
+	// Empty if the operation succeeded
+	Error string `json:"error,omitempty"`
+}
+
+// SetJournalSize keeps a journal of the last size operations performed with the pool, which
+// is included in its DebugSnapshot. This helps to reconstruct what happened just before an
+// incident. The journal is disabled by default, and by a size of 0.
+func (this *Pool) SetJournalSize(size int) {
+	this.debug.Lock()
+	defer this.debug.Unlock()
+
+	if size < 0 {
+		size = 0
+	}
+	this.debug.journalSize = size
+
+	// Keep the most recent entries which fit
+	entries := this.debug.journalEntries()
+	if len(entries) > size {
+		entries = entries[len(entries)-size:]
+	}
+	this.debug.journal = entries
+	this.debug.journalNext = 0
+}
+
+// Add an operation to the journal, if it is enabled
+// MUST hold the debugStats lock when calling
+func (this *debugStats) journalOperation(request *v1.Message, region, operation string, latency time.Duration, err error) {
+	if this.journalSize == 0 {
+		return
+	}
+
+	entry := JournalEntry{
+		Time:      time.Now(),
+		Operation: operation,
+		Region:    region,
+		KeyHash:   keyHash(requestKey(request)),
+		Size:      proto.Size(request),
+		Latency:   Duration(latency),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if len(this.journal) < this.journalSize {
+		this.journal = append(this.journal, entry)
+	} else {
+		this.journal[this.journalNext] = entry
+		this.journalNext = (this.journalNext + 1) % this.journalSize
+	}
+}
+
+// Return the journal, oldest first
+// MUST hold the debugStats lock when calling
+func (this *debugStats) journalEntries() []JournalEntry {
+	entries := make([]JournalEntry, 0, len(this.journal))
+	entries = append(entries, this.journal[this.journalNext:]...)
+	return append(entries, this.journal[:this.journalNext]...)
+}
+
+// Return the key of a request which operates on a single key, or nil
+func requestKey(request *v1.Message) *v1.EncodedValue {
+	switch r := request.GetMessageType().(type) {
+	case *v1.Message_GetRequest:
+		return r.GetRequest.GetKey()
+	case *v1.Message_PutRequest:
+		return r.PutRequest.GetEntry().GetKey()
+	case *v1.Message_PutIfAbsentRequest:
+		return r.PutIfAbsentRequest.GetEntry().GetKey()
+	case *v1.Message_RemoveRequest:
+		return r.RemoveRequest.GetKey()
+	}
+	return nil
+}
+
+func keyHash(key *v1.EncodedValue) string {
+	if key == nil {
+		return ""
+	}
+
+	encoded, err := proto.Marshal(key)
+	if err != nil {
+		return ""
+	}
+
+	h := fnv.New64a()
+	h.Write(encoded)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
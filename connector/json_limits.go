@@ -0,0 +1,78 @@
+package connector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// JSONLimits bound the JSON values a connector will decode, protecting it from pathological
+// values written by other applications. A limit of 0 is unlimited.
+type JSONLimits struct {
+	// Maximum nesting of objects and arrays
+	MaxDepth int
+	// Maximum length in bytes of any string, including object keys
+	MaxStringLength int
+	// Maximum length in bytes of the whole document
+	MaxSize int
+}
+
+// A JSONLimitError is returned when a JSON value read from a region exceeds one of the
+// connector's JSONLimits. Limit names the limit: "depth", "string length" or "size".
+type JSONLimitError struct {
+	Limit string
+	Max   int
+}
+
+func (e *JSONLimitError) Error() string {
+	return fmt.Sprintf("JSON value exceeds the maximum %s of %d", e.Limit, e.Max)
+}
+
+// SetJSONLimits limits the JSON values this connector decodes. Values exceeding a limit fail
+// to decode with a JSONLimitError; within a batch result they are sent to the dead letter
+// queue, if there is one, like any other value which cannot be decoded. nil, the default,
+// removes the limits.
+func (this *Protobuf) SetJSONLimits(limits *JSONLimits) {
+	this.jsonLimits = limits
+}
+
+// Check a document against the limits before it is decoded
+func (this *JSONLimits) check(document string) error {
+	if this.MaxSize > 0 && len(document) > this.MaxSize {
+		return &JSONLimitError{Limit: "size", Max: this.MaxSize}
+	}
+	if this.MaxDepth <= 0 && this.MaxStringLength <= 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			// Leave reporting malformed documents to the decoder
+			return nil
+		}
+
+		switch t := token.(type) {
+		case json.Delim:
+			if t == '{' || t == '[' {
+				depth++
+				if this.MaxDepth > 0 && depth > this.MaxDepth {
+					return &JSONLimitError{Limit: "depth", Max: this.MaxDepth}
+				}
+			} else {
+				depth--
+			}
+		case string:
+			if this.MaxStringLength > 0 && len(t) > this.MaxStringLength {
+				return &JSONLimitError{Limit: "string length", Max: this.MaxStringLength}
+			}
+		}
+	}
+}
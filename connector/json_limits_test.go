@@ -0,0 +1,81 @@
+package connector_test
+
+import (
+	"strings"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON limits", func() {
+
+	var connection *connector.Protobuf
+	var store *fakeStore
+
+	type Node struct {
+		Name  string `json:"name"`
+		Child *Node  `json:"child"`
+	}
+
+	setJSON := func(document string) {
+		store.value = &v1.EncodedValue{Value: &v1.EncodedValue_JsonObjectResult{JsonObjectResult: document}}
+	}
+
+	BeforeEach(func() {
+		fakeConn := new(connectorfakes.FakeConn)
+		pool := connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+		store = newFakeStore(fakeConn)
+
+		connection.SetJSONLimits(&connector.JSONLimits{MaxDepth: 2, MaxStringLength: 10, MaxSize: 100})
+	})
+
+	It("decodes values within the limits", func() {
+		setJSON(`{"name": "a", "child": {"name": "b"}}`)
+
+		v, err := connection.Get("foo", "A", &Node{})
+		Expect(err).To(BeNil())
+		Expect(v.(*Node).Child.Name).To(Equal("b"))
+	})
+
+	It("rejects values nested too deeply", func() {
+		setJSON(`{"name": "a", "child": {"name": "b", "child": {"name": "c"}}}`)
+
+		_, err := connection.Get("foo", "A", &Node{})
+		Expect(err).To(Equal(&connector.JSONLimitError{Limit: "depth", Max: 2}))
+	})
+
+	It("rejects long strings", func() {
+		setJSON(`{"name": "` + strings.Repeat("a", 11) + `"}`)
+
+		_, err := connection.Get("foo", "A", &Node{})
+		Expect(err).To(Equal(&connector.JSONLimitError{Limit: "string length", Max: 10}))
+	})
+
+	It("rejects long keys", func() {
+		setJSON(`{"` + strings.Repeat("a", 11) + `": 1}`)
+
+		_, err := connection.Get("foo", "A", &Node{})
+		Expect(err).To(Equal(&connector.JSONLimitError{Limit: "string length", Max: 10}))
+	})
+
+	It("rejects large documents", func() {
+		setJSON(`{"name": "a"` + strings.Repeat(" ", 100) + `}`)
+
+		_, err := connection.Get("foo", "A", &Node{})
+		Expect(err).To(Equal(&connector.JSONLimitError{Limit: "size", Max: 100}))
+	})
+
+	It("does not limit values once the limits are removed", func() {
+		connection.SetJSONLimits(nil)
+		setJSON(`{"name": "` + strings.Repeat("a", 11) + `"}`)
+
+		v, err := connection.Get("foo", "A", &Node{})
+		Expect(err).To(BeNil())
+		Expect(v.(*Node).Name).To(HaveLen(11))
+	})
+})
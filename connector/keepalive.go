@@ -0,0 +1,114 @@
+package connector
+
+import (
+	"time"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// CheckIdle pings each idle connection, discarding those which do not answer within timeout,
+// so that connections closed by a firewall or a server's timeout are replaced before an
+// operation tries to use them. The protocol has no ping message, so a request for the
+// region names is sent instead. A timeout of 0 waits indefinitely. Connections are checked one at a time and are unavailable
+// to operations while being checked. The pool's partitions are checked in turn.
+func (this *Pool) CheckIdle(timeout time.Duration) {
+	checked := make(map[*GeodeConnection]bool)
+	for {
+		gConn := this.takeUnchecked(checked)
+		if gConn == nil {
+			break
+		}
+		checked[gConn] = true
+
+		if err := ping(gConn, timeout); err != nil {
+			logTo(this.GetLogger(), LogWarn, "ping failed", "server", connectionAddress(gConn), "error", err)
+			this.DiscardConnection(gConn)
+			this.metricsPublisher().Add(MetricFailedPings, 1)
+			continue
+		}
+
+		this.Lock()
+		gConn.inUse = false
+		this.putBack(gConn)
+		this.Unlock()
+	}
+
+	this.RLock()
+	partitions := make([]*Pool, 0, len(this.partitions))
+	for _, partition := range this.partitions {
+		partitions = append(partitions, partition)
+	}
+	this.RUnlock()
+
+	for _, partition := range partitions {
+		partition.CheckIdle(timeout)
+	}
+}
+
+// Take an idle, ready connection which has not been checked, marking it in use, or return nil
+// if there are none
+func (this *Pool) takeUnchecked(checked map[*GeodeConnection]bool) *GeodeConnection {
+	this.Lock()
+	defer this.Unlock()
+
+	for _, c := range this.recentConnections {
+		// Connections yet to be prepared are checked by preparing them
+		ready := c.handshakeDone && (c.authenticationDone || !this.authenticationEnabled)
+		if !c.inUse && ready && !checked[c] {
+			c.inUse = true
+			return c
+		}
+	}
+
+	return nil
+}
+
+func ping(gConn *GeodeConnection, timeout time.Duration) error {
+	request := &v1.Message{
+		MessageType: &v1.Message_GetRegionNamesRequest{
+			GetRegionNamesRequest: &v1.GetRegionNamesRequest{},
+		},
+	}
+
+	limits := ioLimits{readTimeout: timeout, writeTimeout: timeout}
+	response, err := exchange(gConn.rawConn, request, limits)
+	if err != nil {
+		return err
+	}
+
+	// Operations without timeouts do not set deadlines, so clear those of the ping
+	gConn.rawConn.SetDeadline(time.Time{})
+
+	if err := responseError(request, response); err != nil {
+		if _, ok := err.(*ServerError); ok {
+			// The server answered, if only to refuse the request
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// StartKeepAlive calls CheckIdle at the given interval until the returned function is
+// called.
+func (this *Pool) StartKeepAlive(interval, timeout time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				this.CheckIdle(timeout)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
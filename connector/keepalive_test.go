@@ -0,0 +1,122 @@
+package connector_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Keep-alive", func() {
+
+	var pool *connector.Pool
+	var publisher *recordingPublisher
+
+	// A connection which answers region names requests
+	alive := func() *connectorfakes.FakeConn {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_GetRegionNamesResponse{
+					GetRegionNamesResponse: &v1.GetRegionNamesResponse{},
+				},
+			}, b)
+		}
+		return fakeConn
+	}
+
+	// A connection which the server has closed
+	dead := func() *connectorfakes.FakeConn {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadReturns(0, errors.New("connection reset by peer"))
+		return fakeConn
+	}
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+		publisher = &recordingPublisher{counters: make(map[string]int64)}
+		pool.SetMetricsPublisher(publisher)
+	})
+
+	It("keeps connections which answer", func() {
+		conn := alive()
+		pool.AddConnection(conn, true)
+
+		pool.CheckIdle(time.Second)
+
+		Expect(conn.WriteCallCount()).To(Equal(1))
+		Expect(pool.Stats().Connections).To(Equal(1))
+		Expect(pool.Stats().InUse).To(Equal(0))
+	})
+
+	It("discards connections which do not answer", func() {
+		stale := dead()
+		pool.AddConnection(stale, true)
+		pool.AddConnection(alive(), true)
+
+		pool.CheckIdle(time.Second)
+
+		Expect(stale.CloseCallCount()).To(Equal(1))
+		Expect(pool.Stats().Connections).To(Equal(1))
+		Expect(publisher.counters[connector.MetricFailedPings]).To(Equal(int64(1)))
+	})
+
+	It("keeps connections which answer with an error", func() {
+		conn := new(connectorfakes.FakeConn)
+		conn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_ErrorResponse{
+					ErrorResponse: &v1.ErrorResponse{Error: &v1.Error{Message: "not authorized"}},
+				},
+			}, b)
+		}
+		pool.AddConnection(conn, true)
+
+		pool.CheckIdle(time.Second)
+
+		Expect(pool.Stats().Connections).To(Equal(1))
+	})
+
+	It("does not check connections in use", func() {
+		conn := dead()
+		pool.AddConnection(conn, true)
+		c, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+
+		pool.CheckIdle(time.Second)
+
+		Expect(conn.WriteCallCount()).To(Equal(0))
+		pool.ReturnConnection(c)
+	})
+
+	It("does not check connections yet to be prepared", func() {
+		conn := dead()
+		pool.AddConnection(conn, false)
+
+		pool.CheckIdle(time.Second)
+
+		Expect(conn.WriteCallCount()).To(Equal(0))
+	})
+
+	It("checks partitions", func() {
+		bulk := pool.Partition("bulk")
+		bulk.AddConnection(dead(), true)
+
+		pool.CheckIdle(time.Second)
+
+		Expect(bulk.Stats().Connections).To(Equal(0))
+	})
+
+	It("checks connections in the background", func() {
+		pool.AddConnection(dead(), true)
+
+		stop := pool.StartKeepAlive(time.Millisecond, time.Second)
+		defer stop()
+
+		Eventually(func() int { return pool.Stats().Connections }).Should(Equal(0))
+	})
+})
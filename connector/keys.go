@@ -0,0 +1,59 @@
+package connector
+
+import (
+	"errors"
+	"fmt"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// Keys returns the keys of a region. See KeysFunc for large regions.
+func (this *Protobuf) Keys(region string) ([]interface{}, error) {
+	keys := make([]interface{}, 0)
+	err := this.KeysFunc(region, func(key interface{}) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// KeysFunc passes each key of a region to fn as it is decoded, until fn returns false. The
+// server sends every key in one response, which the protocol cannot page, but each key is
+// released from the response once decoded so that the keys are not held twice.
+func (this *Protobuf) KeysFunc(region string, fn func(key interface{}) bool) error {
+	request := &v1.Message{
+		MessageType: &v1.Message_KeySetRequest{
+			KeySetRequest: &v1.KeySetRequest{
+				RegionName: region,
+			},
+		},
+	}
+
+	response, err := this.doOperation(request)
+	if err != nil {
+		return err
+	}
+
+	keys := response.GetKeySetResponse().GetKeys()
+	for i, encoded := range keys {
+		keys[i] = nil
+
+		key, err := DecodeValue(encoded, nil)
+		if err != nil && this.deadLetters != nil {
+			this.deadLetters.add(&DeadLetter{Operation: "Keys", Region: region, Key: encoded, Err: err})
+			continue
+		} else if err != nil {
+			return errors.New(fmt.Sprintf("unable to decode key: %s", err.Error()))
+		}
+
+		if !fn(key) {
+			return nil
+		}
+	}
+
+	return nil
+}
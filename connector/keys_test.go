@@ -0,0 +1,93 @@
+package connector_test
+
+import (
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Keys", func() {
+
+	var connection *connector.Protobuf
+	var fakeConn *connectorfakes.FakeConn
+	var request *v1.KeySetRequest
+
+	respond := func(keys ...*v1.EncodedValue) {
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_KeySetResponse{KeySetResponse: &v1.KeySetResponse{Keys: keys}},
+			}, b)
+		}
+	}
+
+	encode := func(v interface{}) *v1.EncodedValue {
+		ev, err := connector.EncodeValue(v)
+		Expect(err).To(BeNil())
+		return ev
+	}
+
+	BeforeEach(func() {
+		fakeConn = new(connectorfakes.FakeConn)
+		fakeConn.WriteStub = func(b []byte) (int, error) {
+			message := &v1.Message{}
+			if err := proto.NewBuffer(b).DecodeMessage(message); err != nil {
+				return 0, err
+			}
+			request = message.GetKeySetRequest()
+			return len(b), nil
+		}
+		pool := connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+	})
+
+	It("returns the keys of a region", func() {
+		respond(encode("A"), encode(int32(2)))
+
+		keys, err := connection.Keys("foo")
+		Expect(err).To(BeNil())
+		Expect(request.RegionName).To(Equal("foo"))
+		Expect(keys).To(Equal([]interface{}{"A", int32(2)}))
+	})
+
+	It("returns no keys for an empty region", func() {
+		respond()
+
+		keys, err := connection.Keys("foo")
+		Expect(err).To(BeNil())
+		Expect(keys).To(BeEmpty())
+	})
+
+	It("passes keys to a function until it stops", func() {
+		respond(encode("A"), encode("B"), encode("C"))
+
+		var keys []interface{}
+		err := connection.KeysFunc("foo", func(key interface{}) bool {
+			keys = append(keys, key)
+			return len(keys) < 2
+		})
+		Expect(err).To(BeNil())
+		Expect(keys).To(Equal([]interface{}{"A", "B"}))
+	})
+
+	It("sends keys which cannot be decoded to the dead letter queue", func() {
+		respond(encode("A"), &v1.EncodedValue{Value: &v1.EncodedValue_CustomObjectResult{}})
+
+		_, err := connection.Keys("foo")
+		Expect(err).To(MatchError(HavePrefix("unable to decode key")))
+
+		queue := connector.NewDeadLetterQueue()
+		connection.SetDeadLetterQueue(queue)
+		keys, err := connection.Keys("foo")
+		Expect(err).To(BeNil())
+		Expect(keys).To(Equal([]interface{}{"A"}))
+
+		letters := queue.Drain()
+		Expect(letters).To(HaveLen(1))
+		Expect(letters[0].Operation).To(Equal("Keys"))
+		Expect(letters[0].Region).To(Equal("foo"))
+	})
+})
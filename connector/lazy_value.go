@@ -0,0 +1,177 @@
+package connector
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/gemfire/geode-go-client/query"
+)
+
+// A LazyValue is a value which has been read but not yet decoded, so that callers which only
+// use some of the values of GetAllLazy or a lazy query need not unmarshal every JSON document.
+// Values are decoded as they would be eagerly, with checksums verified and fields decrypted,
+// but errors are returned by Decode rather than sent to the dead letter queue.
+type LazyValue struct {
+	connector *Protobuf
+	encoded   *v1.EncodedValue
+	// Applied to the value once decoded, if set
+	read func(interface{}) (interface{}, error)
+}
+
+// Decode decodes the value into into, which must be a non-nil pointer. JSON documents are
+// unmarshalled into it, and other values must be assignable to what it points to; a
+// *interface{} accepts any value. A null value sets it to the zero value. Each call decodes
+// the value again.
+func (this *LazyValue) Decode(into interface{}) error {
+	target := reflect.ValueOf(into)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return errors.New(fmt.Sprintf("cannot decode into %T", into))
+	}
+
+	value, err := this.decode(into)
+	if err != nil {
+		return err
+	}
+
+	elem := target.Elem()
+	if value == nil {
+		elem.Set(reflect.Zero(elem.Type()))
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch {
+	case v.Kind() == reflect.Ptr && v.Pointer() == target.Pointer():
+		// Decoded in place
+	case v.Type().AssignableTo(elem.Type()):
+		elem.Set(v)
+	case v.Kind() == reflect.Ptr && v.Type().Elem().AssignableTo(elem.Type()):
+		elem.Set(v.Elem())
+	default:
+		return errors.New(fmt.Sprintf("cannot decode %T into %T", value, into))
+	}
+
+	return nil
+}
+
+// Value decodes the value as Get does without a reference, so JSON documents cannot be
+// decoded this way.
+func (this *LazyValue) Value() (interface{}, error) {
+	return this.decode(nil)
+}
+
+// Transform returns a LazyValue which applies fn to the value once decoded.
+func (this *LazyValue) Transform(fn func(interface{}) (interface{}, error)) *LazyValue {
+	read := this.read
+	return &LazyValue{
+		connector: this.connector,
+		encoded:   this.encoded,
+		read: func(value interface{}) (interface{}, error) {
+			var err error
+			if read != nil {
+				if value, err = read(value); err != nil {
+					return nil, err
+				}
+			}
+			return fn(value)
+		},
+	}
+}
+
+// Encoded returns the value as it was read, before any decoding.
+func (this *LazyValue) Encoded() *v1.EncodedValue {
+	return this.encoded
+}
+
+func (this *LazyValue) decode(ref interface{}) (interface{}, error) {
+	value, err := this.connector.decodeValue(this.encoded, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if this.read != nil {
+		return this.read(value)
+	}
+	return value, nil
+}
+
+func (this *Protobuf) lazyValue(ev *v1.EncodedValue) (interface{}, error) {
+	return &LazyValue{connector: this, encoded: ev}, nil
+}
+
+// GetAllLazy gets entries as GetAll, but returns their values undecoded. Entries which are
+// never decoded cost no more than the response they were read from.
+func (this *Protobuf) GetAllLazy(region string, keys interface{}) (map[interface{}]*LazyValue, map[interface{}]error, error) {
+	entries := make(map[interface{}]*LazyValue)
+	failures := make(map[interface{}]error)
+
+	err := this.getAllEach(region, keys, this.lazyValue, func(key, value interface{}, err error) bool {
+		if err != nil {
+			delete(entries, key)
+			failures[key] = err
+		} else {
+			entries[key] = value.(*LazyValue)
+		}
+		return true
+	})
+	if _, partial := err.(*MultiError); err != nil && !partial {
+		return nil, nil, err
+	}
+
+	if len(failures) == 0 {
+		failures = nil
+	}
+
+	return entries, failures, err
+}
+
+// QueryListResultLazy runs a query as QueryListResult, but returns the results undecoded.
+// The query's Reference is not used; pass a reference to Decode instead.
+func (this *Protobuf) QueryListResultLazy(query *query.Query) ([]*LazyValue, error) {
+	response, err := this.doQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedResultList := response.GetOqlQueryResponse().GetListResult().GetElement()
+	if query.LastTrace != nil {
+		query.LastTrace.Results = len(encodedResultList)
+	}
+
+	return this.lazyValues(encodedResultList), nil
+}
+
+// QueryTableResultLazy runs a query as QueryTableResult, but returns the results undecoded.
+func (this *Protobuf) QueryTableResultLazy(query *query.Query) (map[string][]*LazyValue, error) {
+	response, err := this.doQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	table := response.GetOqlQueryResponse().GetTableResult()
+	columns := table.GetFieldName()
+	valueList := table.GetRow()
+	if len(valueList) != len(columns) {
+		return nil, errors.New(fmt.Sprintf("unable to decode query result: %d columns named but %d received", len(columns), len(valueList)))
+	}
+	if query.LastTrace != nil && len(valueList) > 0 {
+		query.LastTrace.Results = len(valueList[0].GetElement())
+	}
+
+	results := make(map[string][]*LazyValue, len(columns))
+	for i, columnName := range columns {
+		results[columnName] = this.lazyValues(valueList[i].GetElement())
+	}
+
+	return results, nil
+}
+
+func (this *Protobuf) lazyValues(encoded []*v1.EncodedValue) []*LazyValue {
+	values := make([]*LazyValue, len(encoded))
+	for i, ev := range encoded {
+		values[i] = &LazyValue{connector: this, encoded: ev}
+	}
+	return values
+}
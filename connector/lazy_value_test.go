@@ -0,0 +1,155 @@
+package connector_test
+
+import (
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/gemfire/geode-go-client/query"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LazyValue", func() {
+
+	var connection *connector.Protobuf
+	var fakeConn *connectorfakes.FakeConn
+
+	respond := func(response *v1.Message) {
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(response, b)
+		}
+	}
+
+	getAllResponse := func(entries map[string]interface{}) *v1.Message {
+		response := &v1.GetAllResponse{}
+		for k, v := range entries {
+			key, _ := connector.EncodeValue(k)
+			value, _ := connector.EncodeValue(v)
+			response.Entries = append(response.Entries, &v1.Entry{Key: key, Value: value})
+		}
+		return &v1.Message{MessageType: &v1.Message_GetAllResponse{GetAllResponse: response}}
+	}
+
+	queryResponse := func(results ...interface{}) *v1.Message {
+		list, _ := connector.EncodeValueList(results)
+		return &v1.Message{
+			MessageType: &v1.Message_OqlQueryResponse{
+				OqlQueryResponse: &v1.OQLQueryResponse{
+					Result: &v1.OQLQueryResponse_ListResult{ListResult: list},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		fakeConn = new(connectorfakes.FakeConn)
+		pool := connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+	})
+
+	It("decodes GetAll values on demand", func() {
+		respond(getAllResponse(map[string]interface{}{
+			"A": &TestStruct{Value: 7, Message: "a"},
+			"B": int32(3),
+		}))
+
+		entries, failures, err := connection.GetAllLazy("foo", []string{"A", "B"})
+		Expect(err).To(BeNil())
+		Expect(failures).To(BeNil())
+		Expect(entries).To(HaveLen(2))
+
+		var a TestStruct
+		Expect(entries["A"].Decode(&a)).To(BeNil())
+		Expect(a).To(Equal(TestStruct{Value: 7, Message: "a"}))
+
+		var b int32
+		Expect(entries["B"].Decode(&b)).To(BeNil())
+		Expect(b).To(Equal(int32(3)))
+
+		var any interface{}
+		Expect(entries["B"].Decode(&any)).To(BeNil())
+		Expect(any).To(Equal(int32(3)))
+
+		value, err := entries["B"].Value()
+		Expect(err).To(BeNil())
+		Expect(value).To(Equal(int32(3)))
+	})
+
+	It("reports values which cannot be decoded into the reference", func() {
+		respond(getAllResponse(map[string]interface{}{"B": int32(3)}))
+
+		entries, _, err := connection.GetAllLazy("foo", []string{"B"})
+		Expect(err).To(BeNil())
+
+		var s string
+		Expect(entries["B"].Decode(&s)).To(MatchError("cannot decode int32 into *string"))
+		Expect(entries["B"].Decode(s)).To(MatchError("cannot decode into string"))
+	})
+
+	It("decodes null values as the zero value", func() {
+		respond(getAllResponse(map[string]interface{}{"A": nil}))
+
+		entries, _, err := connection.GetAllLazy("foo", []string{"A"})
+		Expect(err).To(BeNil())
+
+		s := "unchanged"
+		Expect(entries["A"].Decode(&s)).To(BeNil())
+		Expect(s).To(Equal(""))
+	})
+
+	It("applies transforms once decoded", func() {
+		respond(getAllResponse(map[string]interface{}{"A": int32(3)}))
+
+		entries, _, err := connection.GetAllLazy("foo", []string{"A"})
+		Expect(err).To(BeNil())
+
+		doubled := entries["A"].Transform(func(v interface{}) (interface{}, error) {
+			return v.(int32) * 2, nil
+		})
+
+		var n int32
+		Expect(doubled.Decode(&n)).To(BeNil())
+		Expect(n).To(Equal(int32(6)))
+		Expect(entries["A"].Decode(&n)).To(BeNil())
+		Expect(n).To(Equal(int32(3)))
+	})
+
+	It("returns query results undecoded", func() {
+		respond(queryResponse(&TestStruct{Value: 1}, &TestStruct{Value: 2}))
+
+		q := query.NewQuery("select * from /foo")
+		q.Trace = true
+		results, err := connection.QueryListResultLazy(q)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(2))
+		Expect(q.LastTrace.Results).To(Equal(2))
+
+		var second TestStruct
+		Expect(results[1].Decode(&second)).To(BeNil())
+		Expect(second.Value).To(Equal(int32(2)))
+	})
+
+	It("returns table query results undecoded", func() {
+		names, _ := connector.EncodeValueList([]interface{}{"a", "b"})
+		ages, _ := connector.EncodeValueList([]interface{}{int32(1), int32(2)})
+		respond(&v1.Message{
+			MessageType: &v1.Message_OqlQueryResponse{
+				OqlQueryResponse: &v1.OQLQueryResponse{
+					Result: &v1.OQLQueryResponse_TableResult{TableResult: &v1.Table{
+						FieldName: []string{"name", "age"},
+						Row:       []*v1.EncodedValueList{names, ages},
+					}},
+				},
+			},
+		})
+
+		results, err := connection.QueryTableResultLazy(query.NewQuery("select name, age from /foo"))
+		Expect(err).To(BeNil())
+		Expect(results["name"]).To(HaveLen(2))
+
+		var age int32
+		Expect(results["age"][1].Decode(&age)).To(BeNil())
+		Expect(age).To(Equal(int32(2)))
+	})
+})
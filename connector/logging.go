@@ -0,0 +1,158 @@
+package connector
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// The severity of a log record
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+func (this LogLevel) String() string {
+	switch this {
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarn:
+		return "WARN"
+	case LogError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(this))
+	}
+}
+
+// MarshalText writes a level by name, such as "WARN", as configurations are written.
+func (this LogLevel) MarshalText() ([]byte, error) {
+	return []byte(this.String()), nil
+}
+
+// UnmarshalText reads a level by name, in either case.
+func (this *LogLevel) UnmarshalText(text []byte) error {
+	for _, level := range []LogLevel{LogDebug, LogInfo, LogWarn, LogError} {
+		if strings.EqualFold(string(text), level.String()) {
+			*this = level
+			return nil
+		}
+	}
+	return errors.New(fmt.Sprintf("invalid log level %s", string(text)))
+}
+
+// A Logger receives records of a pool's connection lifecycle, handshakes, retries and
+// failures, each a message with alternating keys and values such as "server" and its
+// address, as slog takes them. Log may be called while the pool is locked, so it must not
+// call back into the pool, and should not block.
+type Logger interface {
+	Log(level LogLevel, message string, keyvals ...interface{})
+}
+
+// NewStdLogger returns a Logger which writes records at or above level to logger, as the
+// level, message and key=value pairs.
+func NewStdLogger(logger *log.Logger, level LogLevel) Logger {
+	return &stdLogger{logger: logger, level: level}
+}
+
+type stdLogger struct {
+	logger *log.Logger
+	level  LogLevel
+}
+
+func (this *stdLogger) Log(level LogLevel, message string, keyvals ...interface{}) {
+	if level < this.level {
+		return
+	}
+
+	var line strings.Builder
+	fmt.Fprintf(&line, "%s %s", level, message)
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 < len(keyvals) {
+			fmt.Fprintf(&line, " %v=%v", keyvals[i], keyvals[i+1])
+		} else {
+			fmt.Fprintf(&line, " %v", keyvals[i])
+		}
+	}
+	this.logger.Print(line.String())
+}
+
+// SetLogger sets the Logger which records this pool's activity. nil, the default, discards
+// the records.
+func (this *Pool) SetLogger(logger Logger) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.logger = logger
+	this.syncPartitions()
+}
+
+// SetLogLevel sets the lowest level of the records passed to the pool's Logger. The default,
+// LogDebug, passes every record, leaving the Logger to filter them.
+func (this *Pool) SetLogLevel(level LogLevel) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.logLevel = level
+	this.syncPartitions()
+}
+
+// GetLogger returns the Logger set for this pool, limited to the records at or above its
+// level (see SetLogLevel), or nil.
+func (this *Pool) GetLogger() Logger {
+	this.RLock()
+	defer this.RUnlock()
+
+	if this.logger == nil || this.logLevel <= LogDebug {
+		return this.logger
+	}
+	return &leveledLogger{Logger: this.logger, level: this.logLevel}
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) log(level LogLevel, message string, keyvals ...interface{}) {
+	if level < this.logLevel {
+		return
+	}
+	logTo(this.logger, level, message, keyvals...)
+}
+
+// A Logger which drops the records below a level
+type leveledLogger struct {
+	Logger
+	level LogLevel
+}
+
+func (this *leveledLogger) Log(level LogLevel, message string, keyvals ...interface{}) {
+	if level >= this.level {
+		this.Logger.Log(level, message, keyvals...)
+	}
+}
+
+// Log a record unless logger is nil, recovering from a panic in the Logger
+func logTo(logger Logger, level LogLevel, message string, keyvals ...interface{}) {
+	if logger == nil {
+		return
+	}
+
+	defer RecoverCallback("Logger", nil)
+	logger.Log(level, message, keyvals...)
+}
+
+// Return the address of a connection's server for logging
+func connectionAddress(gConn *GeodeConnection) string {
+	if gConn.provider != nil {
+		return providerAddress(gConn.provider)
+	}
+	if addr := gConn.rawConn.RemoteAddr(); addr != nil {
+		return addr.String()
+	}
+	return ""
+}
@@ -0,0 +1,33 @@
+package connector
+
+import (
+	"context"
+	"log/slog"
+)
+
+// NewSlogLogger returns a Logger which writes records to logger, at the corresponding slog
+// levels.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return slogLogger{logger}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (this slogLogger) Log(level LogLevel, message string, keyvals ...interface{}) {
+	this.logger.Log(context.Background(), slogLevel(level), message, keyvals...)
+}
+
+func slogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LogDebug:
+		return slog.LevelDebug
+	case LogInfo:
+		return slog.LevelInfo
+	case LogWarn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
@@ -0,0 +1,149 @@
+package connector_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"log/slog"
+	"net"
+	"sync"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// A Logger which records the messages it receives, with their first key and value
+type recordingLogger struct {
+	sync.Mutex
+	records []string
+}
+
+func (this *recordingLogger) Log(level connector.LogLevel, message string, keyvals ...interface{}) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.records = append(this.records, level.String()+" "+message)
+}
+
+func (this *recordingLogger) messages() []string {
+	this.Lock()
+	defer this.Unlock()
+
+	return append([]string{}, this.records...)
+}
+
+var _ = Describe("Logging", func() {
+
+	var pool *connector.Pool
+	var logger *recordingLogger
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+		logger = &recordingLogger{}
+		pool.SetLogger(logger)
+	})
+
+	It("logs failed handshakes", func() {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.WriteReturns(0, errors.New("connection reset by peer"))
+		pool.AddConnection(fakeConn, false)
+
+		_, err := pool.GetConnection()
+		Expect(err).To(HaveOccurred())
+
+		Expect(logger.messages()).To(Equal([]string{"ERROR handshake failed", "DEBUG connection discarded"}))
+	})
+
+	It("logs retries and failed operations", func() {
+		for i := 0; i < 2; i++ {
+			fakeConn := new(connectorfakes.FakeConn)
+			fakeConn.WriteReturns(0, &net.OpError{Op: "write", Err: errors.New("fake retryable write error")})
+			pool.AddConnection(fakeConn, true)
+		}
+		connection := connector.NewConnector(pool)
+		connection.SetRetryBudget(&connector.RetryBudget{MaxRetries: 1})
+
+		Expect(connection.Put("foo", "A", 1)).ToNot(Succeed())
+
+		Expect(logger.messages()).To(Equal([]string{
+			"DEBUG connection discarded",
+			"WARN retrying operation",
+			"DEBUG connection discarded",
+			"WARN operation failed",
+		}))
+	})
+
+	It("logs servers which cannot be reached", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		address := listener.Addr().String()
+		listener.Close()
+
+		Expect(pool.Configure(&connector.Config{Servers: []string{address}})).To(Succeed())
+		_, err = pool.GetConnection()
+		Expect(err).To(HaveOccurred())
+
+		Expect(logger.messages()).To(ContainElement("WARN connection failed"))
+	})
+
+	It("drops records below the configured level", func() {
+		level := connector.LogWarn
+		Expect(pool.Configure(&connector.Config{LogLevel: &level})).To(Succeed())
+
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.WriteReturns(0, errors.New("connection reset by peer"))
+		pool.AddConnection(fakeConn, false)
+		_, err := pool.GetConnection()
+		Expect(err).To(HaveOccurred())
+		pool.GetLogger().Log(connector.LogInfo, "dropped")
+		pool.GetLogger().Log(connector.LogWarn, "kept")
+
+		Expect(logger.messages()).To(Equal([]string{"ERROR handshake failed", "WARN kept"}))
+
+		invalid := connector.LogLevel(9)
+		Expect(pool.Configure(&connector.Config{LogLevel: &invalid})).To(MatchError(ContainSubstring("invalid logLevel")))
+	})
+
+	It("is shared with partitions", func() {
+		bulk := pool.Partition("bulk")
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.WriteReturns(0, errors.New("connection reset by peer"))
+		bulk.AddConnection(fakeConn, false)
+
+		_, err := bulk.GetConnection()
+		Expect(err).To(HaveOccurred())
+
+		Expect(logger.messages()).To(ContainElement("ERROR handshake failed"))
+	})
+
+	It("survives a panicking logger", func() {
+		pool.SetLogger(connector.NewStdLogger(nil, connector.LogDebug))
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.WriteReturns(0, errors.New("connection reset by peer"))
+		pool.AddConnection(fakeConn, false)
+
+		_, err := pool.GetConnection()
+		Expect(err).To(MatchError("unable to write handshake: connection reset by peer"))
+	})
+
+	It("writes records to a standard logger", func() {
+		var out bytes.Buffer
+		logger := connector.NewStdLogger(log.New(&out, "", 0), connector.LogInfo)
+
+		logger.Log(connector.LogDebug, "connection opened", "server", "localhost:40404")
+		logger.Log(connector.LogWarn, "retrying operation", "operation", "Put", "attempt", 2)
+
+		Expect(out.String()).To(Equal("WARN retrying operation operation=Put attempt=2\n"))
+	})
+
+	It("writes records to slog", func() {
+		var out bytes.Buffer
+		logger := connector.NewSlogLogger(slog.New(slog.NewTextHandler(&out, nil)))
+
+		logger.Log(connector.LogWarn, "server unavailable", "server", "localhost:40404")
+
+		Expect(out.String()).To(ContainSubstring(`level=WARN msg="server unavailable" server=localhost:40404`))
+	})
+})
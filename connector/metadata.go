@@ -0,0 +1,58 @@
+package connector
+
+import (
+	"os"
+	"strconv"
+)
+
+// LibraryVersion is the version of this library, reported to servers as client metadata.
+const LibraryVersion = "0.1.0"
+
+// Prefix of the handshake properties carrying client metadata, as opposed to credentials
+const clientMetadataPrefix = "client-"
+
+// SetClientTags sets tags, such as the name of the application or the environment it runs
+// in, which are reported to servers along with the client's library version, hostname and
+// process ID, so that operators can identify the clients connected to a cluster. Tags apply
+// to connections opened afterwards.
+//
+// The protocol's handshake only carries credentials, so the metadata is sent as additional
+// properties alongside them, named "client-library-version", "client-hostname", "client-pid"
+// and "client-tag-" followed by each tag's name, where a server's SecurityManager can log
+// it. It is therefore only sent when authentication is enabled.
+func (this *Pool) SetClientTags(tags map[string]string) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.clientTags = make(map[string]string, len(tags))
+	for name, value := range tags {
+		this.clientTags[name] = value
+	}
+	this.syncPartitions()
+}
+
+// Return the properties sent when authenticating a connection
+// MUST hold the pool lock when calling
+func (this *Pool) credentials() (map[string]string, error) {
+	user, err := this.userCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := map[string]string{
+		clientMetadataPrefix + "library-version": LibraryVersion,
+		clientMetadataPrefix + "pid":             strconv.Itoa(os.Getpid()),
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		credentials[clientMetadataPrefix+"hostname"] = hostname
+	}
+	for name, value := range this.clientTags {
+		credentials[clientMetadataPrefix+"tag-"+name] = value
+	}
+
+	for name, value := range user {
+		credentials[name] = value
+	}
+
+	return credentials, nil
+}
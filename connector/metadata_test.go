@@ -0,0 +1,76 @@
+package connector_test
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client metadata", func() {
+
+	var pool *connector.Pool
+	var fakeConn *connectorfakes.FakeConn
+	var credentials map[string]string
+
+	BeforeEach(func() {
+		credentials = nil
+		fakeConn = new(connectorfakes.FakeConn)
+		fakeConn.WriteStub = func(b []byte) (int, error) {
+			message := &v1.Message{}
+			if err := proto.NewBuffer(b).DecodeMessage(message); err != nil {
+				return 0, err
+			}
+			if handshake := message.GetHandshakeRequest(); handshake != nil {
+				credentials = handshake.Credentials
+			}
+			return len(b), nil
+		}
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_HandshakeResponse{
+					HandshakeResponse: &v1.HandshakeResponse{Authenticated: true},
+				},
+			}, b)
+		}
+
+		pool = connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		pool.AddCredentials("cluster", "secret")
+	})
+
+	It("is sent with the credentials", func() {
+		pool.SetClientTags(map[string]string{"app": "billing"})
+
+		c, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		pool.ReturnConnection(c)
+
+		hostname, _ := os.Hostname()
+		Expect(credentials).To(Equal(map[string]string{
+			"security-username":      "cluster",
+			"security-password":      "secret",
+			"client-library-version": connector.LibraryVersion,
+			"client-hostname":        hostname,
+			"client-pid":             strconv.Itoa(os.Getpid()),
+			"client-tag-app":         "billing",
+		}))
+	})
+
+	It("is shared with partitions", func() {
+		pool.SetClientTags(map[string]string{"app": "billing"})
+		bulk := pool.Partition("bulk")
+		bulk.AddConnection(fakeConn, true)
+
+		c, err := bulk.GetConnection()
+		Expect(err).To(BeNil())
+		bulk.ReturnConnection(c)
+
+		Expect(credentials).To(HaveKeyWithValue("client-tag-app", "billing"))
+	})
+})
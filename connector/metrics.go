@@ -0,0 +1,140 @@
+package connector
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// Names of the counters maintained by a Pool
+const (
+	MetricActiveConnections    = "activeConnections"
+	MetricConnectionsCreated   = "connectionsCreated"
+	MetricDiscardedConnections = "discardedConnections"
+	MetricOperationLatency     = "operationLatency"
+	// Time operations spend encoding and decoding values, and exchanging messages with
+	// servers, keyed by operation. Only recorded if enabled by SetOperationTiming.
+	MetricSerializationLatency = "serializationLatency"
+	MetricNetworkLatency       = "networkLatency"
+	// Connections held by the pool, in use or idle
+	MetricOpenConnections = "openConnections"
+	// Operations performed and those which failed, keyed by operation, such as "Put"
+	MetricOperations      = "operations"
+	MetricOperationErrors = "operationErrors"
+	// Retries made by operations, keyed by operation
+	MetricRetries = "retries"
+	// Failed operations keyed by error code, or by operation and error code. See ErrorCode.
+	MetricErrorCodes = "errorCodes"
+	// Bytes of the messages exchanged by operations, including their length prefixes
+	MetricBytesSent     = "bytesSent"
+	MetricBytesReceived = "bytesReceived"
+	// Attempts which a server rejected as overloaded, keyed by server. See ThrottlePolicy.
+	MetricThrottledAttempts = "throttledAttempts"
+	// Cluster events not delivered because a receiver's buffer was full. See ClusterEvents.
+	MetricDroppedClusterEvents = "droppedClusterEvents"
+	// Idle connections discarded because they did not answer a ping. See CheckIdle.
+	MetricFailedPings = "failedPings"
+	// Writes not sent again because they were acknowledged before, keyed by operation. See
+	// SetDeduplication.
+	MetricDeduplicatedWrites = "deduplicatedWrites"
+	// Operations tried again on another server after their connection failed, keyed by
+	// operation. See FailoverPolicy.
+	MetricFailovers = "failovers"
+	// Responses which did not match their request, whose connections were closed
+	MetricMismatchedResponses = "mismatchedResponses"
+	// Keys read from and not found in a near cache, keyed by region. See NearCache.
+	MetricNearCacheHits   = "nearCacheHits"
+	MetricNearCacheMisses = "nearCacheMisses"
+	// Keys read from expired near cache entries because the cluster could not be reached,
+	// keyed by region. See NearCache.MaxStaleness.
+	MetricNearCacheStaleHits = "nearCacheStaleHits"
+)
+
+// A MetricsPublisher receives updates to the counters maintained by the client. Add adjusts a
+// single counter; AddKeyed adjusts one entry of a family of counters, such as a count per
+// tenant. Implementations must be safe for concurrent use.
+type MetricsPublisher interface {
+	Add(name string, delta int64)
+	AddKeyed(name, key string, delta int64)
+}
+
+// A LatencyPublisher is a MetricsPublisher which also records latencies, for example in a
+// histogram. Latencies are only measured if the pool's publisher implements this interface.
+// operation is the kind of request, such as "Put" or "GetAll".
+type LatencyPublisher interface {
+	MetricsPublisher
+	ObserveLatency(name, operation string, latency time.Duration)
+}
+
+type noMetrics struct{}
+
+func (noMetrics) Add(name string, delta int64)           {}
+func (noMetrics) AddKeyed(name, key string, delta int64) {}
+
+// NoMetrics is a MetricsPublisher which discards all updates.
+var NoMetrics MetricsPublisher = noMetrics{}
+
+var defaultMetricsLock sync.RWMutex
+var defaultMetrics = builtinMetricsPublisher
+
+// SetDefaultMetricsPublisher changes the publisher used by pools which have not had one set
+// with SetMetricsPublisher. Unless the package is built with the noexpvar tag, the default
+// publishes to expvar using the unqualified counter names. Use NoMetrics to disable metrics.
+func SetDefaultMetricsPublisher(publisher MetricsPublisher) {
+	defaultMetricsLock.Lock()
+	defer defaultMetricsLock.Unlock()
+
+	defaultMetrics = publisher
+}
+
+// SetMetricsPublisher changes the publisher used by this pool and by anything using the
+// pool, such as a Client.
+func (this *Pool) SetMetricsPublisher(publisher MetricsPublisher) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.metrics = publisher
+	this.syncPartitions()
+}
+
+// GetMetricsPublisher returns the publisher used by this pool.
+func (this *Pool) GetMetricsPublisher() MetricsPublisher {
+	this.RLock()
+	defer this.RUnlock()
+
+	return this.configuredPublisher()
+}
+
+// Return the publisher, guarded against panics, to which the pool's counters are sent
+// MUST hold the pool lock when calling
+func (this *Pool) metricsPublisher() MetricsPublisher {
+	return guardedPublisher{this.configuredPublisher()}
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) configuredPublisher() MetricsPublisher {
+	if this.metrics != nil {
+		return this.metrics
+	}
+
+	defaultMetricsLock.RLock()
+	defer defaultMetricsLock.RUnlock()
+
+	return defaultMetrics
+}
+
+// Return the name of the operation performed by a request or answered by a response, for
+// example "Put" for a PutRequest or PutResponse.
+func operationName(message *v1.Message) string {
+	if message.GetMessageType() == nil {
+		return ""
+	}
+
+	name := reflect.TypeOf(message.MessageType).Elem().Name()
+	name = strings.TrimPrefix(name, "Message_")
+	name = strings.TrimSuffix(name, "Response")
+	return strings.TrimSuffix(name, "Request")
+}
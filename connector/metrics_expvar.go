@@ -0,0 +1,126 @@
+//go:build !noexpvar
+// +build !noexpvar
+
+package connector
+
+import (
+	"expvar"
+	"sync"
+	"time"
+)
+
+var builtinMetricsPublisher MetricsPublisher = NewExpvarPublisher("")
+
+// Serializes the creation of expvar variables, which may be shared between publishers
+var expvarLock sync.Mutex
+
+// Variables whose names were already published by other code with a different type. They are
+// kept here, unpublished, rather than replacing or clashing with the existing variable.
+var unpublishedVars = make(map[string]expvar.Var)
+
+// Upper bounds of the latency histogram buckets published to expvar
+var latencyBuckets = []struct {
+	bound time.Duration
+	name  string
+}{
+	{time.Millisecond, "1ms"},
+	{5 * time.Millisecond, "5ms"},
+	{10 * time.Millisecond, "10ms"},
+	{50 * time.Millisecond, "50ms"},
+	{100 * time.Millisecond, "100ms"},
+	{500 * time.Millisecond, "500ms"},
+	{time.Second, "1s"},
+	{5 * time.Second, "5s"},
+}
+
+var _ LatencyPublisher = (*ExpvarPublisher)(nil)
+
+// An ExpvarPublisher publishes counters as expvar variables. Variables are only created once
+// they are first updated, so nothing is published if the publisher is never used. A counter
+// whose name is already published with a different type is still maintained, but is not
+// visible through expvar.
+type ExpvarPublisher struct {
+	namespace string
+}
+
+// NewExpvarPublisher creates a publisher whose variable names are prefixed with namespace
+// and a '.', allowing the counters of several pools to be told apart. With an empty
+// namespace the counter names are used as is.
+func NewExpvarPublisher(namespace string) *ExpvarPublisher {
+	return &ExpvarPublisher{
+		namespace: namespace,
+	}
+}
+
+func (this *ExpvarPublisher) Add(name string, delta int64) {
+	expvarInt(this.qualify(name)).Add(delta)
+}
+
+func (this *ExpvarPublisher) AddKeyed(name, key string, delta int64) {
+	expvarMap(this.qualify(name)).Add(key, delta)
+}
+
+// ObserveLatency records a latency as a histogram in an expvar map. Each operation has
+// keys of the form <operation>.le_<bound> counting the latencies up to that bound, as well
+// as <operation>.count.
+func (this *ExpvarPublisher) ObserveLatency(name, operation string, latency time.Duration) {
+	for _, bucket := range latencyBuckets {
+		if latency <= bucket.bound {
+			this.AddKeyed(name, operation+".le_"+bucket.name, 1)
+		}
+	}
+	this.AddKeyed(name, operation+".count", 1)
+}
+
+func (this *ExpvarPublisher) qualify(name string) string {
+	if this.namespace == "" {
+		return name
+	}
+	return this.namespace + "." + name
+}
+
+// Return the Int with the given name, publishing a new one if there is none. If a variable
+// of another type is published under the name, an unpublished Int is returned instead.
+func expvarInt(name string) *expvar.Int {
+	expvarLock.Lock()
+	defer expvarLock.Unlock()
+
+	if v, ok := expvar.Get(name).(*expvar.Int); ok {
+		return v
+	}
+	if v, ok := unpublishedVars[name].(*expvar.Int); ok {
+		return v
+	}
+
+	v := new(expvar.Int)
+	publishVar(name, v)
+
+	return v
+}
+
+// Return the Map with the given name, as expvarInt.
+func expvarMap(name string) *expvar.Map {
+	expvarLock.Lock()
+	defer expvarLock.Unlock()
+
+	if v, ok := expvar.Get(name).(*expvar.Map); ok {
+		return v
+	}
+	if v, ok := unpublishedVars[name].(*expvar.Map); ok {
+		return v
+	}
+
+	v := new(expvar.Map).Init()
+	publishVar(name, v)
+
+	return v
+}
+
+// MUST hold expvarLock when calling
+func publishVar(name string, v expvar.Var) {
+	if expvar.Get(name) == nil {
+		expvar.Publish(name, v)
+	} else {
+		unpublishedVars[name] = v
+	}
+}
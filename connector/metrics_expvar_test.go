@@ -0,0 +1,63 @@
+//go:build !noexpvar
+// +build !noexpvar
+
+package connector_test
+
+import (
+	"expvar"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Expvar metrics", func() {
+
+	var pool *connector.Pool
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+		pool.AddConnection(new(connectorfakes.FakeConn), true)
+	})
+
+	It("publishes to expvar with a namespace", func() {
+		publisher := connector.NewExpvarPublisher("metricsTest")
+		before := int64(0)
+		if v, ok := expvar.Get("metricsTest.activeConnections").(*expvar.Int); ok {
+			before = v.Value()
+		}
+
+		pool.SetMetricsPublisher(publisher)
+		_, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+
+		Expect(expvar.Get("metricsTest.activeConnections").(*expvar.Int).Value()).To(Equal(before + 1))
+	})
+
+	It("does not clash with variables of another type", func() {
+		if expvar.Get("clashTest.activeConnections") == nil {
+			expvar.NewString("clashTest.activeConnections").Set("taken")
+			expvar.NewInt("clashTest.tenantQuotaRejections")
+		}
+		publisher := connector.NewExpvarPublisher("clashTest")
+
+		Expect(func() {
+			publisher.Add("activeConnections", 1)
+			publisher.AddKeyed("tenantQuotaRejections", "acme", 1)
+		}).ToNot(Panic())
+		Expect(expvar.Get("clashTest.activeConnections").String()).To(Equal(`"taken"`))
+	})
+
+	It("publishes latency histograms to expvar", func() {
+		publisher := connector.NewExpvarPublisher("latencyTest")
+		publisher.ObserveLatency("latency", "Get", 7*time.Millisecond)
+
+		histogram := expvar.Get("latencyTest.latency").(*expvar.Map)
+		count := histogram.Get("Get.count").String()
+		Expect(histogram.Get("Get.le_5ms")).To(BeNil())
+		Expect(histogram.Get("Get.le_10ms").String()).To(Equal(count))
+		Expect(histogram.Get("Get.le_1s").String()).To(Equal(count))
+	})
+})
@@ -0,0 +1,7 @@
+//go:build noexpvar
+// +build noexpvar
+
+package connector
+
+// Without expvar, metrics are discarded unless a publisher is provided
+var builtinMetricsPublisher = NoMetrics
@@ -0,0 +1,103 @@
+package connector_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type recordingPublisher struct {
+	sync.Mutex
+	counters map[string]int64
+}
+
+func (this *recordingPublisher) Add(name string, delta int64) {
+	this.Lock()
+	defer this.Unlock()
+	this.counters[name] += delta
+}
+
+func (this *recordingPublisher) AddKeyed(name, key string, delta int64) {
+	this.Add(name+"/"+key, delta)
+}
+
+type latencyPublisher struct {
+	recordingPublisher
+	latencies map[string][]time.Duration
+}
+
+func (this *latencyPublisher) ObserveLatency(name, operation string, latency time.Duration) {
+	this.Lock()
+	defer this.Unlock()
+	this.latencies[name+"/"+operation] = append(this.latencies[name+"/"+operation], latency)
+}
+
+// A clock which advances by a fixed step on every reading
+type steppingClock struct {
+	now  time.Duration
+	step time.Duration
+}
+
+func (this *steppingClock) Now() time.Duration {
+	this.now += this.step
+	return this.now
+}
+
+var _ = Describe("Metrics", func() {
+
+	var pool *connector.Pool
+	var fakeConn *connectorfakes.FakeConn
+
+	BeforeEach(func() {
+		fakeConn = new(connectorfakes.FakeConn)
+		pool = connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+	})
+
+	It("sends pool counters to a custom publisher", func() {
+		publisher := &recordingPublisher{counters: make(map[string]int64)}
+		pool.SetMetricsPublisher(publisher)
+
+		c, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		Expect(publisher.counters[connector.MetricActiveConnections]).To(Equal(int64(1)))
+
+		pool.DiscardConnection(c)
+		Expect(publisher.counters[connector.MetricDiscardedConnections]).To(Equal(int64(1)))
+	})
+
+	It("can be disabled", func() {
+		pool.SetMetricsPublisher(connector.NoMetrics)
+		_, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		Expect(pool.GetMetricsPublisher()).To(Equal(connector.NoMetrics))
+	})
+
+	It("measures operation latency with the pool's clock", func() {
+		publisher := &latencyPublisher{
+			recordingPublisher: recordingPublisher{counters: make(map[string]int64)},
+			latencies:          make(map[string][]time.Duration),
+		}
+		pool.SetMetricsPublisher(publisher)
+		pool.SetClock(&steppingClock{step: 7 * time.Millisecond})
+
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			response := &v1.Message{
+				MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}},
+			}
+			return writeFakeMessage(response, b)
+		}
+
+		connection := connector.NewConnector(pool)
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+
+		Expect(publisher.latencies).To(Equal(map[string][]time.Duration{
+			connector.MetricOperationLatency + "/Put": {7 * time.Millisecond},
+		}))
+	})
+})
@@ -0,0 +1,270 @@
+package connector
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gemfire/geode-go-client/codec"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// A connection shared by operations in flight. Servers answer the requests on a connection
+// in the order they were sent and responses carry no correlation id, so the response read
+// next belongs to the oldest pending request.
+type sharedConnection struct {
+	gConn *GeodeConnection
+	// Several responses may arrive together, so they are read through a buffer
+	reader *bufio.Reader
+	// Held while a request is queued and written, so that writes are in queue order
+	writeLock sync.Mutex
+	// The remaining fields are guarded by the pool lock
+	// Operations which have reserved a place on the connection, sent or not
+	inFlight int
+	// Receive the responses of the requests sent, oldest first
+	pending []chan sharedResult
+	// Returned to every pending operation once the connection has failed
+	err error
+}
+
+type sharedResult struct {
+	response *v1.Message
+	err      error
+}
+
+// SetMultiplexing lets up to maxInFlight operations share each connection, rather than each
+// operation holding a connection of its own. Requests are written one after another and their
+// responses read in turn by a goroutine per connection, so that many concurrent operations
+// need only a few sockets. A new connection is only acquired when those in use are full, and
+// a connection is returned to the pool once its last operation completes. A maxInFlight of 0
+// or 1 disables multiplexing, the default.
+//
+// Responses are returned in the order requests were sent, so a slow operation holds up those
+// behind it. An operation which times out or is cancelled therefore closes the connection it
+// shares, and the other operations on it fail with a RetryableError. Pinned connectors do
+// not multiplex.
+func (this *Pool) SetMultiplexing(maxInFlight int) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.maxInFlight = maxInFlight
+}
+
+// GetMultiplexing returns the number of operations which may share a connection, 0 or 1 if
+// multiplexing is disabled.
+func (this *Pool) GetMultiplexing() int {
+	this.RLock()
+	defer this.RUnlock()
+
+	return this.maxInFlight
+}
+
+// Reserve a place on the least loaded shared connection, acquiring a new one from the pool
+// if they are all full
+func (this *Pool) shareConnection(ctx context.Context, priority int) (*sharedConnection, error) {
+	this.Lock()
+	var shared *sharedConnection
+	for _, sc := range this.shared {
+		if sc.inFlight < this.maxInFlight && (shared == nil || sc.inFlight < shared.inFlight) {
+			shared = sc
+		}
+	}
+	if shared != nil {
+		shared.inFlight++
+		this.Unlock()
+		return shared, nil
+	}
+	this.Unlock()
+
+	gConn, err := this.AcquireConnection(ctx, priority)
+	if err != nil {
+		return nil, err
+	}
+
+	this.Lock()
+	defer this.Unlock()
+
+	shared = &sharedConnection{gConn: gConn, reader: bufio.NewReader(gConn.rawConn), inFlight: 1}
+	this.shared = append(this.shared, shared)
+	go this.readShared(shared)
+
+	return shared, nil
+}
+
+// Queue a request on a shared connection and write it. Its response, or the failure of the
+// connection, is delivered to result.
+func (this *Pool) sendShared(shared *sharedConnection, request *v1.Message, result chan sharedResult, limits ioLimits) {
+	shared.writeLock.Lock()
+	defer shared.writeLock.Unlock()
+
+	this.Lock()
+	if shared.err != nil {
+		result <- sharedResult{err: shared.err}
+		this.Unlock()
+		return
+	}
+	shared.pending = append(shared.pending, result)
+	this.Unlock()
+
+	rawConn := shared.gConn.rawConn
+	deadline := limits.deadline
+	if limits.writeTimeout > 0 {
+		if timeout := time.Now().Add(limits.writeTimeout); deadline.IsZero() || timeout.Before(deadline) {
+			deadline = timeout
+		}
+	}
+	if !deadline.IsZero() {
+		rawConn.SetWriteDeadline(deadline)
+		defer rawConn.SetWriteDeadline(time.Time{})
+	}
+
+	if err := writeMessage(rawConn, request); err != nil {
+		this.Lock()
+		this.failShared(shared, err)
+		this.Unlock()
+	}
+}
+
+// Read the responses on a shared connection, delivering each to the oldest pending request,
+// until the connection fails or no operation is left on it
+func (this *Pool) readShared(shared *sharedConnection) {
+	for {
+		response, err := codec.ReadMessage(shared.reader)
+
+		this.Lock()
+		if err == nil && len(shared.pending) == 0 {
+			err = errors.New("response received without a request")
+		}
+		if err != nil {
+			this.failShared(shared, err)
+			this.Unlock()
+			return
+		}
+
+		shared.pending[0] <- sharedResult{response: response}
+		shared.pending = shared.pending[1:]
+		shared.inFlight--
+		if shared.inFlight == 0 {
+			this.removeShared(shared)
+			this.release(shared.gConn)
+			this.Unlock()
+			return
+		}
+		this.Unlock()
+	}
+}
+
+// Fail every operation on a shared connection and discard it. Only the first failure counts.
+// MUST hold the pool lock when calling
+func (this *Pool) failShared(shared *sharedConnection, cause error) {
+	if shared.err != nil {
+		return
+	}
+
+	shared.err = &RetryableError{cause}
+	for _, result := range shared.pending {
+		result <- sharedResult{err: shared.err}
+	}
+	shared.pending = nil
+
+	this.removeShared(shared)
+	this.discardConnection(shared.gConn)
+	this.metricsPublisher().Add(MetricDiscardedConnections, 1)
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) removeShared(shared *sharedConnection) {
+	for i, sc := range this.shared {
+		if sc == shared {
+			this.shared = append(this.shared[:i], this.shared[i+1:]...)
+			return
+		}
+	}
+}
+
+func (this *Pool) isMultiplexing() bool {
+	this.RLock()
+	defer this.RUnlock()
+
+	return this.maxInFlight > 1
+}
+
+// Attempt an operation on a shared connection
+func (this *Protobuf) attemptShared(request *v1.Message, deadline time.Time) (*v1.Message, string, error) {
+	ctx := this.context()
+	shared, err := this.pool.shareConnection(ctx, this.priority)
+	if err != nil {
+		return nil, "", err
+	}
+
+	gConn := shared.gConn
+	server := ""
+	if addr := gConn.rawConn.RemoteAddr(); addr != nil {
+		server = addr.String()
+	}
+
+	limits := this.pool.ioLimits(ctx, deadline)
+	clock := this.pool.GetClock()
+	start := clock.Now()
+	defer this.timing.addNetwork(this.timing.now())
+
+	result := make(chan sharedResult, 1)
+	this.pool.sendShared(shared, request, result, limits)
+
+	// The response must arrive within the read timeout, unless the deadline is sooner
+	limited := false
+	wait := deadline
+	if limits.readTimeout > 0 {
+		if timeout := time.Now().Add(limits.readTimeout); wait.IsZero() || timeout.Before(wait) {
+			wait = timeout
+			limited = true
+		}
+	}
+	var expired <-chan time.Time
+	if !wait.IsZero() {
+		timer := time.NewTimer(time.Until(wait))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	var r sharedResult
+	select {
+	case r = <-result:
+	case <-ctx.Done():
+		this.pool.abandonShared(shared, ctx.Err())
+		return nil, server, ctx.Err()
+	case <-expired:
+		this.pool.abandonShared(shared, os.ErrDeadlineExceeded)
+		return nil, server, timeoutError(os.ErrDeadlineExceeded, limited)
+	}
+
+	if r.err == nil || ctx.Err() == nil {
+		this.pool.reportOutcome(gConn, clock.Now()-start, r.err)
+	}
+	this.countBytes(request, r.response)
+	if r.err != nil {
+		return nil, server, r.err
+	}
+
+	if err := responseError(request, r.response); err != nil {
+		if _, ok := err.(*ResponseMismatchError); ok {
+			// The responses behind this one may answer the wrong requests too
+			this.pool.responseMismatched(gConn, err)
+			this.pool.abandonShared(shared, err)
+		}
+		return nil, server, err
+	}
+	return r.response, server, nil
+}
+
+// Give up on an operation whose response has not arrived, failing the connection it shares
+// since the responses behind it would otherwise wait for it
+func (this *Pool) abandonShared(shared *sharedConnection, cause error) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.failShared(shared, cause)
+}
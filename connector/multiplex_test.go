@@ -0,0 +1,134 @@
+package connector_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"time"
+
+	"github.com/gemfire/geode-go-client/codec"
+	"github.com/gemfire/geode-go-client/connector"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Multiplexing", func() {
+
+	var pool *connector.Pool
+	var connection *connector.Protobuf
+	var server net.Conn
+	var requests chan *v1.Message
+
+	// Answer a Get request with its own key
+	answer := func(request *v1.Message) []byte {
+		key := request.GetGetRequest().GetKey().GetStringResult()
+		data, err := codec.MarshalMessage(&v1.Message{
+			MessageType: &v1.Message_GetResponse{
+				GetResponse: &v1.GetResponse{Result: &v1.EncodedValue{Value: &v1.EncodedValue_StringResult{StringResult: key}}},
+			},
+		})
+		Expect(err).To(BeNil())
+		return data
+	}
+
+	// Collect n requests, then answer them all with a single write
+	answerTogether := func(n int) {
+		var data []byte
+		for i := 0; i < n; i++ {
+			data = append(data, answer(<-requests)...)
+		}
+		server.Write(data)
+	}
+
+	BeforeEach(func() {
+		var client net.Conn
+		client, server = net.Pipe()
+		received := make(chan *v1.Message, 10)
+		requests = received
+		go func(server net.Conn) {
+			defer close(received)
+			reader := bufio.NewReader(server)
+			for {
+				request, err := codec.ReadMessage(reader)
+				if err != nil {
+					return
+				}
+				received <- request
+			}
+		}(server)
+
+		pool = connector.NewPool()
+		pool.SetMaxConnections(1)
+		pool.SetMultiplexing(4)
+		pool.AddConnection(client, true)
+		connection = connector.NewConnector(pool)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("shares a connection between operations in flight", func() {
+		keys := []string{"A", "B", "C"}
+		results := make(chan []interface{}, len(keys))
+		for _, key := range keys {
+			go func(key string) {
+				value, err := connection.Get("foo", key, nil)
+				results <- []interface{}{key, value, err}
+			}(key)
+		}
+
+		// Every request is sent before any response
+		answerTogether(len(keys))
+
+		for range keys {
+			var result []interface{}
+			Eventually(results).Should(Receive(&result))
+			Expect(result[2]).To(BeNil())
+			Expect(result[1]).To(Equal(result[0]))
+		}
+		Eventually(func() int { return pool.Stats().InUse }).Should(Equal(0))
+		Expect(pool.Stats().Connections).To(Equal(1))
+	})
+
+	It("fails the other operations on a connection when one is cancelled", func() {
+		connection.SetRetryBudget(&connector.RetryBudget{Retryable: func(error) bool { return false }})
+		ctx, cancel := context.WithCancel(context.Background())
+
+		cancelled := make(chan error, 1)
+		go func() {
+			_, err := connection.WithContext(ctx).Get("foo", "A", nil)
+			cancelled <- err
+		}()
+		Eventually(requests).Should(Receive())
+
+		other := make(chan error, 1)
+		go func() {
+			_, err := connection.Get("foo", "B", nil)
+			other <- err
+		}()
+		Eventually(requests).Should(Receive())
+
+		cancel()
+		Eventually(cancelled).Should(Receive(Equal(context.Canceled)))
+
+		var err error
+		Eventually(other).Should(Receive(&err))
+		Expect(err).To(BeAssignableToTypeOf(&connector.RetryableError{}))
+		Expect(pool.Stats().Connections).To(Equal(0))
+	})
+
+	It("times out operations whose response does not arrive", func() {
+		pool.SetReadTimeout(20 * time.Millisecond)
+		connection.SetRetryBudget(&connector.RetryBudget{Retryable: func(error) bool { return false }})
+
+		_, err := connection.Get("foo", "A", nil)
+		Expect(connector.IsRetryable(err)).To(BeTrue())
+		Expect(connector.ErrorCode(err)).To(Equal(connector.ErrorCodeTimeout))
+	})
+
+	It("is disabled by default", func() {
+		Expect(connector.NewPool().GetMultiplexing()).To(Equal(0))
+	})
+})
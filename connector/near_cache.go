@@ -0,0 +1,335 @@
+package connector
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// A NearCache configures an in-process cache of a region's entries, so that reads of hot,
+// read-mostly keys need not go to the cluster. Entries read by Get, GetOptional, GetRaw and
+// GetAll, including its Func, Into and Lazy forms, are kept as the server encoded them, so
+// values are still decoded on each read, into whatever reference the caller passes.
+//
+// The protocol has no subscriptions through which servers could report changes, so the cache
+// is kept coherent by time alone: an entry written by another client may be read stale for up
+// to TTL. Writes through this connector remove the entries they change, and Clear and
+// function executions on the region, which may change any entry, empty the cache. Pinned
+// connectors, such as those of transactions, neither read nor fill the cache, since they see
+// changes which may never be committed; the entries they write are removed again when their
+// connection is released, as a transaction ends.
+//
+// With a MaxStaleness, reads of single keys ride out brief outages: when the cluster cannot be
+// reached, an entry which expired no longer than MaxStaleness ago is returned instead of the
+// error, and GetOptional reports it as Stale along with its Age.
+type NearCache struct {
+	// Maximum number of entries kept, the least recently used being evicted first. Defaults
+	// to 10000.
+	MaxEntries int
+	// Time for which an entry is used before it is read from the cluster again. Defaults to
+	// 10s.
+	TTL time.Duration
+	// Time after an entry expires for which it is kept, to be read when the cluster cannot be
+	// reached. Defaults to 0, which removes entries once they expire.
+	MaxStaleness time.Duration
+}
+
+func (this *NearCache) maxEntries() int {
+	if this.MaxEntries <= 0 {
+		return 10000
+	}
+	return this.MaxEntries
+}
+
+func (this *NearCache) ttl() time.Duration {
+	if this.TTL <= 0 {
+		return 10 * time.Second
+	}
+	return this.TTL
+}
+
+// The near caches of a connector and the copies made of it, by region
+type nearCaches struct {
+	sync.RWMutex
+	regions map[string]*nearCache
+}
+
+// The cached entries of one region, in order of use
+type nearCache struct {
+	sync.Mutex
+	config  NearCache
+	entries map[string]*list.Element
+	lru     *list.List
+	// Incremented whenever entries are removed, so that a read which started before a write
+	// does not cache the value the write replaced
+	generation uint64
+}
+
+type nearEntry struct {
+	key     string
+	value   *v1.EncodedValue
+	fetched time.Duration
+}
+
+// Records whether a read was answered by an expired entry, and its age
+type staleRead struct {
+	stale bool
+	age   time.Duration
+}
+
+// Return whether err shows that the cluster could not be reached, rather than that it failed
+// the operation
+func isUnreachable(err error) bool {
+	if errors.Is(err, ErrNoConnections) || IsNoAvailableServer(err) {
+		return true
+	}
+	code := ErrorCode(err)
+	return code == ErrorCodeConnectionError || code == ErrorCodeTimeout
+}
+
+// SetNearCache caches the entries read from region in process, as configured by config, or
+// stops caching them if config is nil. The cache is shared with the connectors derived from
+// this one, such as by WithContext. See NearCache.
+func (this *Protobuf) SetNearCache(region string, config *NearCache) {
+	this.nearCaches.Lock()
+	defer this.nearCaches.Unlock()
+
+	if config == nil {
+		delete(this.nearCaches.regions, region)
+		return
+	}
+
+	if this.nearCaches.regions == nil {
+		this.nearCaches.regions = make(map[string]*nearCache)
+	}
+	this.nearCaches.regions[region] = &nearCache{
+		config:  *config,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// InvalidateNearCache removes every entry from the near cache of region, for example when the
+// application learns that another client has changed the region.
+func (this *Protobuf) InvalidateNearCache(region string) {
+	if cache := this.nearCache(region); cache != nil {
+		cache.clear()
+	}
+}
+
+// Return the near cache of region, or nil if its entries are not cached
+func (this *Protobuf) nearCache(region string) *nearCache {
+	if this.nearCaches == nil || region == "" {
+		return nil
+	}
+
+	this.nearCaches.RLock()
+	defer this.nearCaches.RUnlock()
+
+	return this.nearCaches.regions[region]
+}
+
+// Perform an operation on a region with a near cache, answering reads from the cache where
+// it can and removing the entries which writes change
+func (this *Protobuf) doNearCachedOperation(cache *nearCache, request *v1.Message) (*v1.Message, string, error) {
+	region := requestRegion(request)
+	publisher := guardedPublisher{this.pool.GetMetricsPublisher()}
+	now := this.pool.GetClock().Now()
+
+	switch x := request.GetMessageType().(type) {
+	case *v1.Message_GetRequest:
+		key := keyOf(x.GetRequest.GetKey())
+		if value, ok := cache.get(key, now); ok {
+			publisher.AddKeyed(MetricNearCacheHits, region, 1)
+			return &v1.Message{MessageType: &v1.Message_GetResponse{GetResponse: &v1.GetResponse{Result: value}}}, "", nil
+		}
+		publisher.AddKeyed(MetricNearCacheMisses, region, 1)
+
+		generation := cache.currentGeneration()
+		response, server, err := this.doRemoteOperation(request)
+		if err == nil {
+			cache.put(generation, key, response.GetGetResponse().GetResult(), this.pool.GetClock().Now())
+		} else if isUnreachable(err) {
+			if value, age, ok := cache.stale(key, this.pool.GetClock().Now()); ok {
+				publisher.AddKeyed(MetricNearCacheStaleHits, region, 1)
+				if this.staleRead != nil {
+					*this.staleRead = staleRead{stale: true, age: age}
+				}
+				return &v1.Message{MessageType: &v1.Message_GetResponse{GetResponse: &v1.GetResponse{Result: value}}}, "", nil
+			}
+		}
+		return response, server, err
+
+	case *v1.Message_GetAllRequest:
+		var cached []*v1.Entry
+		remaining := make([]*v1.EncodedValue, 0, len(x.GetAllRequest.GetKey()))
+		for _, key := range x.GetAllRequest.GetKey() {
+			if value, ok := cache.get(keyOf(key), now); ok {
+				cached = append(cached, &v1.Entry{Key: key, Value: value})
+			} else {
+				remaining = append(remaining, key)
+			}
+		}
+		publisher.AddKeyed(MetricNearCacheHits, region, int64(len(cached)))
+		publisher.AddKeyed(MetricNearCacheMisses, region, int64(len(remaining)))
+		if len(remaining) == 0 {
+			return &v1.Message{MessageType: &v1.Message_GetAllResponse{GetAllResponse: &v1.GetAllResponse{Entries: cached}}}, "", nil
+		}
+
+		generation := cache.currentGeneration()
+		response, server, err := this.doRemoteOperation(getAllRequest(region, remaining))
+		if err != nil {
+			return response, server, err
+		}
+		result := response.GetGetAllResponse()
+		fetched := this.pool.GetClock().Now()
+		for _, entry := range result.GetEntries() {
+			cache.put(generation, keyOf(entry.GetKey()), entry.GetValue(), fetched)
+		}
+		result.Entries = append(result.Entries, cached...)
+		return response, server, nil
+	}
+
+	response, server, err := this.doRemoteOperation(request)
+	// Remove the entries even if the write failed, since it may have been applied
+	cache.invalidate(request)
+	return response, server, err
+}
+
+// Perform an operation on a pinned connection, whose reads bypass the near cache. The entries
+// a write changes are removed at once, and again once the connection is released, so that
+// values read by other connectors before a transaction commits are not kept afterwards.
+func (this *Protobuf) doPinnedOperation(request *v1.Message) (*v1.Message, string, error) {
+	response, server, err := this.doRemoteOperation(request)
+	if cache := this.nearCache(requestRegion(request)); cache != nil && cache.invalidate(request) {
+		this.pinned.Lock()
+		this.pinned.writes = append(this.pinned.writes, pinnedWrite{cache: cache, request: request})
+		this.pinned.Unlock()
+	}
+	return response, server, err
+}
+
+// Return the cached value of key, if it has not expired. Expired entries are removed unless
+// they are kept to be read stale.
+func (this *nearCache) get(key string, now time.Duration) (*v1.EncodedValue, bool) {
+	this.Lock()
+	defer this.Unlock()
+
+	element, ok := this.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*nearEntry)
+	if now >= entry.fetched+this.config.ttl() {
+		if now >= entry.fetched+this.config.ttl()+this.config.MaxStaleness {
+			this.lru.Remove(element)
+			delete(this.entries, key)
+		}
+		return nil, false
+	}
+
+	this.lru.MoveToFront(element)
+	return entry.value, true
+}
+
+// Return the cached value of key and its age, if it expired no longer than MaxStaleness ago
+func (this *nearCache) stale(key string, now time.Duration) (*v1.EncodedValue, time.Duration, bool) {
+	this.Lock()
+	defer this.Unlock()
+
+	element, ok := this.entries[key]
+	if !ok {
+		return nil, 0, false
+	}
+
+	entry := element.Value.(*nearEntry)
+	if now >= entry.fetched+this.config.ttl()+this.config.MaxStaleness {
+		return nil, 0, false
+	}
+	return entry.value, now - entry.fetched, true
+}
+
+func (this *nearCache) currentGeneration() uint64 {
+	this.Lock()
+	defer this.Unlock()
+
+	return this.generation
+}
+
+// Cache the value of key read at now, unless entries have been removed since generation.
+// Missing entries are not cached, so that entries created by other clients are seen at once.
+func (this *nearCache) put(generation uint64, key string, value *v1.EncodedValue, now time.Duration) {
+	if value == nil {
+		return
+	}
+
+	this.Lock()
+	defer this.Unlock()
+
+	if generation != this.generation {
+		return
+	}
+
+	entry := &nearEntry{key: key, value: value, fetched: now}
+	if element, ok := this.entries[key]; ok {
+		element.Value = entry
+		this.lru.MoveToFront(element)
+		return
+	}
+
+	this.entries[key] = this.lru.PushFront(entry)
+	for this.lru.Len() > this.config.maxEntries() {
+		oldest := this.lru.Back()
+		this.lru.Remove(oldest)
+		delete(this.entries, oldest.Value.(*nearEntry).key)
+	}
+}
+
+// Remove the entries a request may change, returning whether it may change any
+func (this *nearCache) invalidate(request *v1.Message) bool {
+	switch x := request.GetMessageType().(type) {
+	case *v1.Message_PutRequest:
+		this.remove(x.PutRequest.GetEntry().GetKey())
+	case *v1.Message_PutIfAbsentRequest:
+		this.remove(x.PutIfAbsentRequest.GetEntry().GetKey())
+	case *v1.Message_RemoveRequest:
+		this.remove(x.RemoveRequest.GetKey())
+	case *v1.Message_PutAllRequest:
+		keys := make([]*v1.EncodedValue, 0, len(x.PutAllRequest.GetEntry()))
+		for _, entry := range x.PutAllRequest.GetEntry() {
+			keys = append(keys, entry.GetKey())
+		}
+		this.remove(keys...)
+	case *v1.Message_ClearRequest, *v1.Message_ExecuteFunctionOnRegionRequest:
+		this.clear()
+	default:
+		return false
+	}
+	return true
+}
+
+func (this *nearCache) remove(keys ...*v1.EncodedValue) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.generation++
+	for _, key := range keys {
+		if element, ok := this.entries[keyOf(key)]; ok {
+			this.lru.Remove(element)
+			delete(this.entries, keyOf(key))
+		}
+	}
+}
+
+func (this *nearCache) clear() {
+	this.Lock()
+	defer this.Unlock()
+
+	this.generation++
+	this.entries = make(map[string]*list.Element)
+	this.lru.Init()
+}
@@ -0,0 +1,195 @@
+package connector_test
+
+import (
+	"io"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Near cache", func() {
+
+	var fakeConn *connectorfakes.FakeConn
+	var connection *connector.Protobuf
+	var clock *steppingClock
+	var publisher *recordingPublisher
+	var exchanges int
+
+	respond := func(response *v1.Message) {
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			exchanges++
+			return writeFakeMessage(response, b)
+		}
+	}
+
+	getResponse := func(value interface{}) *v1.Message {
+		encoded, _ := connector.EncodeValue(value)
+		return &v1.Message{MessageType: &v1.Message_GetResponse{GetResponse: &v1.GetResponse{Result: encoded}}}
+	}
+
+	BeforeEach(func() {
+		fakeConn = new(connectorfakes.FakeConn)
+		pool := connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		clock = &steppingClock{}
+		pool.SetClock(clock)
+		publisher = &recordingPublisher{counters: make(map[string]int64)}
+		pool.SetMetricsPublisher(publisher)
+		connection = connector.NewConnector(pool)
+		connection.SetNearCache("foo", &connector.NearCache{MaxEntries: 2, TTL: time.Second})
+		exchanges = 0
+	})
+
+	It("answers repeated reads without going to the cluster", func() {
+		respond(getResponse("a"))
+
+		Expect(connection.Get("foo", "A", nil)).To(Equal("a"))
+		Expect(connection.Get("foo", "A", nil)).To(Equal("a"))
+		Expect(exchanges).To(Equal(1))
+		Expect(publisher.counters[connector.MetricNearCacheHits+"/foo"]).To(Equal(int64(1)))
+		Expect(publisher.counters[connector.MetricNearCacheMisses+"/foo"]).To(Equal(int64(1)))
+	})
+
+	It("reads entries again once they expire", func() {
+		respond(getResponse("a"))
+		Expect(connection.Get("foo", "A", nil)).To(Equal("a"))
+
+		respond(getResponse("b"))
+		clock.now += 999 * time.Millisecond
+		Expect(connection.Get("foo", "A", nil)).To(Equal("a"))
+		clock.now += time.Millisecond
+		Expect(connection.Get("foo", "A", nil)).To(Equal("b"))
+		Expect(exchanges).To(Equal(2))
+	})
+
+	It("evicts the least recently used entry", func() {
+		respond(getResponse("a"))
+		connection.Get("foo", "A", nil)
+		connection.Get("foo", "B", nil)
+		connection.Get("foo", "A", nil)
+		connection.Get("foo", "C", nil)
+		Expect(exchanges).To(Equal(3))
+
+		connection.Get("foo", "A", nil)
+		Expect(exchanges).To(Equal(3))
+		connection.Get("foo", "B", nil)
+		Expect(exchanges).To(Equal(4))
+	})
+
+	It("does not cache missing entries nor other regions", func() {
+		respond(&v1.Message{MessageType: &v1.Message_GetResponse{GetResponse: &v1.GetResponse{}}})
+		connection.Get("foo", "A", nil)
+		connection.Get("foo", "A", nil)
+		connection.Get("bar", "A", nil)
+		connection.Get("bar", "A", nil)
+		Expect(exchanges).To(Equal(4))
+	})
+
+	It("requests only the keys of GetAll which are not cached", func() {
+		respond(getResponse("a"))
+		connection.Get("foo", "A", nil)
+
+		key, _ := connector.EncodeValue("B")
+		value, _ := connector.EncodeValue("b")
+		var requested []*v1.EncodedValue
+		fakeConn.WriteStub = func(b []byte) (int, error) {
+			request := &v1.Message{}
+			if err := proto.NewBuffer(b).DecodeMessage(request); err != nil {
+				return 0, err
+			}
+			requested = request.GetGetAllRequest().GetKey()
+			return len(b), nil
+		}
+		respond(&v1.Message{MessageType: &v1.Message_GetAllResponse{GetAllResponse: &v1.GetAllResponse{
+			Entries: []*v1.Entry{{Key: key, Value: value}},
+		}}})
+
+		entries, failures, err := connection.GetAll("foo", []string{"A", "B"})
+		Expect(err).To(BeNil())
+		Expect(failures).To(BeEmpty())
+		Expect(entries).To(Equal(map[interface{}]interface{}{"A": "a", "B": "b"}))
+		Expect(requested).To(HaveLen(1))
+		Expect(requested[0].GetStringResult()).To(Equal("B"))
+
+		entries, _, err = connection.GetAll("foo", []string{"A", "B"})
+		Expect(err).To(BeNil())
+		Expect(entries).To(HaveLen(2))
+		Expect(exchanges).To(Equal(2))
+	})
+
+	It("removes the entries changed through the connector", func() {
+		respond(getResponse("a"))
+		connection.Get("foo", "A", nil)
+		connection.Get("foo", "B", nil)
+
+		respond(&v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}})
+		Expect(connection.Put("foo", "A", "b")).To(Succeed())
+
+		respond(getResponse("b"))
+		Expect(connection.Get("foo", "A", nil)).To(Equal("b"))
+		Expect(connection.Get("foo", "B", nil)).To(Equal("a"))
+
+		respond(&v1.Message{MessageType: &v1.Message_ClearResponse{ClearResponse: &v1.ClearResponse{}}})
+		Expect(connection.Clear("foo")).To(Succeed())
+
+		respond(getResponse("c"))
+		Expect(connection.Get("foo", "B", nil)).To(Equal("c"))
+	})
+
+	It("stops caching when disabled or invalidated", func() {
+		respond(getResponse("a"))
+		connection.Get("foo", "A", nil)
+		connection.InvalidateNearCache("foo")
+		connection.Get("foo", "A", nil)
+		connection.SetNearCache("foo", nil)
+		connection.Get("foo", "A", nil)
+		connection.Get("foo", "A", nil)
+		Expect(exchanges).To(Equal(4))
+	})
+
+	It("serves expired entries up to the maximum staleness while the cluster is unreachable", func() {
+		connection.SetNearCache("foo", &connector.NearCache{TTL: time.Second, MaxStaleness: time.Minute})
+		respond(getResponse("a"))
+		Expect(connection.Get("foo", "A", nil)).To(Equal("a"))
+
+		// The connection fails, and the pool can open no other
+		fakeConn.ReadReturns(0, io.EOF)
+		clock.now += 30 * time.Second
+		result, err := connection.GetOptional("foo", "A", nil)
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(connector.Optional{Value: "a", Present: true, Stale: true, Age: 30 * time.Second}))
+		Expect(publisher.counters[connector.MetricNearCacheStaleHits+"/foo"]).To(Equal(int64(1)))
+
+		clock.now += 31 * time.Second
+		_, err = connection.GetOptional("foo", "A", nil)
+		Expect(err).To(MatchError(connector.ErrNoConnections))
+	})
+
+	It("does not serve expired entries when the cluster fails the read", func() {
+		connection.SetNearCache("foo", &connector.NearCache{TTL: time.Second, MaxStaleness: time.Minute})
+		respond(getResponse("a"))
+		Expect(connection.Get("foo", "A", nil)).To(Equal("a"))
+
+		respond(&v1.Message{MessageType: &v1.Message_ErrorResponse{ErrorResponse: &v1.ErrorResponse{
+			Error: &v1.Error{ErrorCode: v1.ErrorCode_SERVER_ERROR, Message: "boom"},
+		}}})
+		clock.now += 2 * time.Second
+		_, err := connection.GetOptional("foo", "A", nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reports fresh entries as not stale", func() {
+		respond(getResponse("a"))
+		connection.Get("foo", "A", nil)
+
+		result, err := connection.GetOptional("foo", "A", nil)
+		Expect(err).To(BeNil())
+		Expect(result.Stale).To(BeFalse())
+		Expect(result.Age).To(BeZero())
+	})
+})
@@ -0,0 +1,69 @@
+package connector
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/gemfire/geode-go-client/query"
+)
+
+// Operations is the set of operations a client needs from a connector. Protobuf implements
+// it over Geode's protobuf protocol; other transports, or an in-memory fake, can implement
+// it to back the same client. The methods behave as those of Protobuf.
+type Operations interface {
+	Put(region string, k, v interface{}) error
+	PutIfAbsent(region string, k, v interface{}) error
+	PutRaw(region string, key, value *v1.EncodedValue) error
+	PutNull(region string, k interface{}) error
+	PutAll(region string, entries interface{}) (map[interface{}]error, error)
+
+	Get(region string, k interface{}, value interface{}) (interface{}, error)
+	GetOptional(region string, k interface{}, value interface{}) (Optional, error)
+	GetRaw(region string, key *v1.EncodedValue) (*v1.EncodedValue, error)
+	GetAll(region string, keys interface{}) (map[interface{}]interface{}, map[interface{}]error, error)
+	GetAllInto(region string, keys interface{}, newRef func() interface{}, fn func(key, value interface{}, err error) bool) error
+	GetAllLazy(region string, keys interface{}) (map[interface{}]*LazyValue, map[interface{}]error, error)
+
+	Remove(region string, k interface{}) error
+	RemoveAll(region string, keys interface{}) (map[interface{}]error, error)
+	Clear(region string) error
+
+	Keys(region string) ([]interface{}, error)
+	KeysFunc(region string, fn func(key interface{}) bool) error
+	Size(region string) (int32, error)
+	RegionNames() ([]string, error)
+
+	ExecuteOnRegionFunc(functionId, region string, functionArgs interface{}, keyFilter []interface{}, fn func(result interface{}) bool) error
+	ExecuteOnMembersFunc(functionId string, members []string, functionArgs interface{}, fn func(result interface{}) bool) error
+	ExecuteOnGroupsFunc(functionId string, groups []string, functionArgs interface{}, fn func(result interface{}) bool) error
+
+	QuerySingleResult(query *query.Query) (interface{}, error)
+	QueryListResult(query *query.Query) ([]interface{}, error)
+	QueryTableResult(query *query.Query) (map[string][]interface{}, error)
+	QueryListResultLazy(query *query.Query) ([]*LazyValue, error)
+	QueryTableResultLazy(query *query.Query) (map[string][]*LazyValue, error)
+	QueryRows(query *query.Query) (*Rows, error)
+
+	// BindContext returns operations bound by ctx, as WithContext.
+	BindContext(ctx context.Context) Operations
+	// BindTimeout returns operations bound by a timeout, as WithTimeout.
+	BindTimeout(timeout time.Duration) Operations
+	// BindResultRef returns operations which decode function results into new references
+	// from newRef, as WithResultRef.
+	BindResultRef(newRef func() interface{}) Operations
+}
+
+var _ Operations = (*Protobuf)(nil)
+
+func (this *Protobuf) BindContext(ctx context.Context) Operations {
+	return this.WithContext(ctx)
+}
+
+func (this *Protobuf) BindTimeout(timeout time.Duration) Operations {
+	return this.WithTimeout(timeout)
+}
+
+func (this *Protobuf) BindResultRef(newRef func() interface{}) Operations {
+	return this.WithResultRef(newRef)
+}
@@ -0,0 +1,61 @@
+package connector
+
+import (
+	"time"
+)
+
+// An Optional is the result of GetOptional, which tells a key with no entry apart from one
+// whose value is null.
+type Optional struct {
+	Value interface{}
+	// Whether the region has an entry for the key. Value may be nil even if it does.
+	Present bool
+	// Whether Value was read from a near cache entry which had expired, because the cluster
+	// could not be reached, and how long before it was read from the cluster. See
+	// NearCache.MaxStaleness.
+	Stale bool
+	Age   time.Duration
+}
+
+// IsNull returns whether the entry exists with a null value.
+func (o Optional) IsNull() bool {
+	return o.Present && o.Value == nil
+}
+
+// GetOptional gets an entry from a region as Get, but reports whether the entry exists. Note
+// that servers may not store null values, or may not return them, in which case an entry
+// written with PutNull reads as missing.
+func (this *Protobuf) GetOptional(region string, k interface{}, value interface{}) (Optional, error) {
+	timed, finish := this.timed("Get")
+	defer finish()
+
+	return timed.getOptional(region, k, value)
+}
+
+func (this *Protobuf) getOptional(region string, k interface{}, value interface{}) (Optional, error) {
+	key, err := EncodeValue(k)
+	if err != nil {
+		return Optional{}, err
+	}
+
+	// A connector which reports whether the value is served stale
+	c := *this
+	c.staleRead = &staleRead{}
+
+	v, err := c.GetRaw(region, key)
+	if err != nil || v == nil {
+		return Optional{}, err
+	}
+
+	decoded, err := this.decodeValue(v, value)
+	if err != nil {
+		return Optional{}, err
+	}
+
+	return Optional{Value: decoded, Present: true, Stale: c.staleRead.stale, Age: c.staleRead.age}, nil
+}
+
+// PutNull writes an entry whose value is null. Unlike Remove, the key is kept.
+func (this *Protobuf) PutNull(region string, k interface{}) error {
+	return this.Put(region, k, nil)
+}
@@ -0,0 +1,92 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// DefaultOptionalTimeout is the timeout of operations on an optional region for which no
+// region timeout is set.
+const DefaultOptionalTimeout = 500 * time.Millisecond
+
+// An UnavailableError is returned by an operation on an optional region which could not
+// reach a server, so that callers can carry on without the region. Err is the error of the
+// single attempt made.
+type UnavailableError struct {
+	Region string
+	Err    error
+}
+
+func (this *UnavailableError) Error() string {
+	return fmt.Sprintf("region %s is unavailable: %s", this.Region, this.Err.Error())
+}
+
+func (this *UnavailableError) Unwrap() error {
+	return this.Err
+}
+
+// IsUnavailable returns whether err is an *UnavailableError.
+func IsUnavailable(err error) bool {
+	_, ok := err.(*UnavailableError)
+	return ok
+}
+
+// SetOptionalRegion marks a region as optional, or critical, which is the default.
+// Operations on an optional region are attempted once, without retries, and fail with an
+// *UnavailableError if no server answers within the region timeout, see SetRegionTimeout,
+// or DefaultOptionalTimeout if none is set. A context deadline which is sooner still applies.
+// Errors returned by a server, which show that the region is reachable, are returned as
+// they are.
+func (this *Protobuf) SetOptionalRegion(region string, optional bool) {
+	this.timeouts.Lock()
+	defer this.timeouts.Unlock()
+
+	if !optional {
+		delete(this.timeouts.optional, region)
+		return
+	}
+	if this.timeouts.optional == nil {
+		this.timeouts.optional = make(map[string]bool)
+	}
+	this.timeouts.optional[region] = true
+}
+
+// IsOptionalRegion returns whether a region has been marked optional with SetOptionalRegion.
+func (this *Protobuf) IsOptionalRegion(region string) bool {
+	this.timeouts.RLock()
+	defer this.timeouts.RUnlock()
+
+	return this.timeouts.optional[region]
+}
+
+// Attempt an operation on an optional region once, within the region's timeout
+func (this *Protobuf) attemptOptional(request *v1.Message, region string) (*v1.Message, string, int, error) {
+	timeout := this.timeoutLevels(region).Region
+	if timeout <= 0 {
+		timeout = DefaultOptionalTimeout
+	}
+
+	// The context also bounds waiting for a connection
+	parent := this.context()
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+
+	c := *this
+	c.ctx = ctx
+	message, server, err := c.attemptOnce(request, deadline)
+	if err == nil || parent.Err() != nil {
+		return message, server, 0, err
+	}
+
+	switch err.(type) {
+	case *ServerError, AuthenticationError:
+		if !IsRelocated(err) {
+			return message, server, 0, err
+		}
+	}
+	return nil, server, 0, &UnavailableError{Region: region, Err: err}
+}
@@ -0,0 +1,121 @@
+package connector_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"time"
+
+	"github.com/gemfire/geode-go-client/codec"
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Optional regions", func() {
+
+	var pool *connector.Pool
+	var connection *connector.Protobuf
+	var reads int
+	var failures int
+	var response *v1.Message
+
+	BeforeEach(func() {
+		reads, failures = 0, 0
+		response = &v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}}
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			reads++
+			if failures > 0 {
+				failures--
+				return 0, io.EOF
+			}
+			return writeFakeMessage(response, b)
+		}
+
+		pool = connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+		connection.SetOptionalRegion("recommendations", true)
+	})
+
+	It("retries operations on critical regions", func() {
+		failures = 1
+		Expect(connection.Put("orders", "A", 1)).To(Succeed())
+		Expect(reads).To(Equal(2))
+	})
+
+	It("fails operations on optional regions after one attempt", func() {
+		failures = 1
+		err := connection.Put("recommendations", "A", 1)
+		Expect(connector.IsUnavailable(err)).To(BeTrue())
+		Expect(err).To(MatchError("region recommendations is unavailable: EOF"))
+		Expect(err.(*connector.UnavailableError).Region).To(Equal("recommendations"))
+		Expect(connector.ErrorCode(err)).To(Equal(connector.ErrorCodeConnectionError))
+		Expect(reads).To(Equal(1))
+	})
+
+	It("returns errors from servers as they are", func() {
+		response = &v1.Message{MessageType: &v1.Message_ErrorResponse{ErrorResponse: &v1.ErrorResponse{
+			Error: &v1.Error{ErrorCode: v1.ErrorCode_INVALID_REQUEST, Message: "no such region"},
+		}}}
+
+		err := connection.Put("recommendations", "A", 1)
+		Expect(err).To(BeAssignableToTypeOf(&connector.ServerError{}))
+	})
+
+	It("can be made critical again", func() {
+		Expect(connection.IsOptionalRegion("recommendations")).To(BeTrue())
+		connection.SetOptionalRegion("recommendations", false)
+		Expect(connection.IsOptionalRegion("recommendations")).To(BeFalse())
+
+		failures = 1
+		Expect(connection.Put("recommendations", "A", 1)).To(Succeed())
+	})
+
+	Context("when a server does not answer", func() {
+		var server net.Conn
+
+		BeforeEach(func() {
+			var client net.Conn
+			client, server = net.Pipe()
+			go func(server net.Conn) {
+				reader := bufio.NewReader(server)
+				for {
+					if _, err := codec.ReadMessage(reader); err != nil {
+						return
+					}
+				}
+			}(server)
+
+			pool = connector.NewPool()
+			pool.AddConnection(client, true)
+			connection = connector.NewConnector(pool)
+			connection.SetOptionalRegion("recommendations", true)
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("fails within the region timeout", func() {
+			connection.SetRegionTimeout("recommendations", 50*time.Millisecond)
+
+			start := time.Now()
+			err := connection.Put("recommendations", "A", 1)
+			Expect(connector.IsUnavailable(err)).To(BeTrue())
+			Expect(connector.ErrorCode(err)).To(Equal(connector.ErrorCodeTimeout))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		})
+
+		It("fails within the default timeout when no region timeout is set", func() {
+			start := time.Now()
+			err := connection.Put("recommendations", "A", 1)
+			Expect(connector.IsUnavailable(err)).To(BeTrue())
+			Expect(time.Since(start)).To(BeNumerically(">=", connector.DefaultOptionalTimeout))
+		})
+	})
+})
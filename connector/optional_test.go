@@ -0,0 +1,51 @@
+package connector_test
+
+import (
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Optional values", func() {
+
+	var connection *connector.Protobuf
+	var store *fakeStore
+
+	BeforeEach(func() {
+		fakeConn := new(connectorfakes.FakeConn)
+		pool := connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+
+		store = newFakeStore(fakeConn)
+	})
+
+	It("reports a missing entry", func() {
+		result, err := connection.GetOptional("foo", "A", nil)
+		Expect(err).To(BeNil())
+		Expect(result.Present).To(BeFalse())
+		Expect(result.IsNull()).To(BeFalse())
+	})
+
+	It("writes and reads a null value", func() {
+		Expect(connection.PutNull("foo", "A")).To(BeNil())
+		Expect(store.value.GetValue()).To(BeAssignableToTypeOf(&v1.EncodedValue_NullResult{}))
+
+		result, err := connection.GetOptional("foo", "A", nil)
+		Expect(err).To(BeNil())
+		Expect(result.Present).To(BeTrue())
+		Expect(result.IsNull()).To(BeTrue())
+	})
+
+	It("decodes a present value", func() {
+		Expect(connection.Put("foo", "A", &TestStruct{Value: 1, Message: "Hello"})).To(BeNil())
+
+		result, err := connection.GetOptional("foo", "A", &TestStruct{})
+		Expect(err).To(BeNil())
+		Expect(result.Present).To(BeTrue())
+		Expect(result.IsNull()).To(BeFalse())
+		Expect(result.Value).To(Equal(&TestStruct{Value: 1, Message: "Hello"}))
+	})
+})
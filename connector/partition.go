@@ -0,0 +1,106 @@
+package connector
+
+import (
+	"sort"
+)
+
+// Partition returns the named partition of the pool, creating it if necessary. A partition
+// is a Pool with its own connections, connection limit and read and write timeouts, which
+// connects to the servers of this pool with its credentials, TLS configuration, failure
+// detector, metrics publisher and clock. Those are kept in step with this pool and should
+// be changed here rather than on the partition.
+//
+// Partitions keep workloads from competing for connections. For example, batch jobs using
+// a "bulk" partition limited to a few connections cannot exhaust the connections needed by
+// interactive requests. A new partition starts with this pool's connection limit, read and
+// write timeouts, idle settings and multiplexing, and they are then independent.
+func (this *Pool) Partition(name string) *Pool {
+	this.Lock()
+	defer this.Unlock()
+
+	if partition, ok := this.partitions[name]; ok {
+		return partition
+	}
+
+	partition := NewPool()
+	partition.maxConnections = this.maxConnections
+	partition.readTimeout = this.readTimeout
+	partition.writeTimeout = this.writeTimeout
+	partition.minIdle = this.minIdle
+	partition.idleTimeout = this.idleTimeout
+	partition.maxInFlight = this.maxInFlight
+
+	if this.partitions == nil {
+		this.partitions = make(map[string]*Pool)
+	}
+	this.partitions[name] = partition
+	this.syncPartition(partition)
+
+	return partition
+}
+
+// Partitions returns the names of the pool's partitions, in order.
+func (this *Pool) Partitions() []string {
+	this.RLock()
+	defer this.RUnlock()
+
+	names := make([]string, 0, len(this.partitions))
+	for name := range this.partitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// WithPartition returns a copy of this connector whose operations use the named partition
+// of its pool. See Pool.Partition.
+func (this *Protobuf) WithPartition(name string) *Protobuf {
+	c := *this
+	c.pool = this.pool.Partition(name)
+	return &c
+}
+
+// Copy the shared configuration to every partition
+// MUST hold the pool lock when calling
+func (this *Pool) syncPartitions() {
+	for _, partition := range this.partitions {
+		this.syncPartition(partition)
+	}
+}
+
+// Copy the shared configuration to a partition, closing its idle connections to servers
+// which have been removed. The partition's lock is taken after this pool's, never before.
+// MUST hold the pool lock when calling
+func (this *Pool) syncPartition(partition *Pool) {
+	detector := this.configuredDetector()
+
+	partition.Lock()
+	defer partition.Unlock()
+
+	partition.providers = append([]ConnectionProvider{}, this.providers...)
+	partition.authenticationEnabled = this.authenticationEnabled
+	partition.username = this.username
+	partition.password = this.password
+	partition.authenticator = this.authenticator
+	partition.clientTags = this.clientTags
+	partition.tlsConfig = this.tlsConfig
+	partition.connectTimeout = this.connectTimeout
+	partition.detector = detector
+	partition.metrics = this.metrics
+	partition.logger = this.logger
+	partition.logLevel = this.logLevel
+	partition.tracer = this.tracer
+	partition.clock = this.clock
+	partition.events = this.clusterEvents()
+	partition.reconnect = this.reconnect
+	partition.handshaker = this.handshaker
+	partition.balancing = this.balancing
+	partition.subset = nil
+	if this.subset != nil {
+		partition.subset = &endpointSubset{size: this.subset.size, clientID: this.subset.clientID}
+	}
+
+	partition.discardRemovedConnections()
+	partition.syncPartitions()
+}
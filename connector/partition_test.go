@@ -0,0 +1,102 @@
+package connector_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Partitions", func() {
+
+	var server *handshakeServer
+	var pool *connector.Pool
+
+	BeforeEach(func() {
+		server = newHandshakeServer()
+		pool = connector.NewPool()
+	})
+
+	AfterEach(func() {
+		server.listener.Close()
+	})
+
+	It("limits the connections of each partition independently", func() {
+		Expect(pool.Configure(&connector.Config{Servers: []string{server.address()}})).To(BeNil())
+		bulk := pool.Partition("bulk")
+		bulk.SetMaxConnections(1)
+
+		c, err := bulk.GetConnection()
+		Expect(err).To(BeNil())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = bulk.AcquireConnection(ctx, 0)
+		Expect(err).To(Equal(context.DeadlineExceeded))
+
+		// The pool itself is not limited by the partition
+		other, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		pool.ReturnConnection(other)
+		bulk.ReturnConnection(c)
+
+		Expect(bulk.Stats().Connections).To(Equal(1))
+		Expect(pool.Stats().Connections).To(Equal(1))
+		Expect(pool.GetMaxConnections()).To(Equal(0))
+	})
+
+	It("follows the servers and credentials of the pool", func() {
+		bulk := pool.Partition("bulk")
+
+		username := "jbloggs"
+		Expect(pool.Configure(&connector.Config{Servers: []string{server.address()}, Username: &username})).To(BeNil())
+
+		c, err := bulk.GetConnection()
+		Expect(err).To(BeNil())
+		Expect(server.accepted).To(HaveLen(1))
+		Expect(server.authenticated).To(Receive(Equal("jbloggs")))
+		bulk.ReturnConnection(c)
+
+		other := newHandshakeServer()
+		defer other.listener.Close()
+		Expect(pool.Configure(&connector.Config{Servers: []string{other.address()}})).To(BeNil())
+
+		// The partition's idle connection to the removed server is closed
+		Eventually(server.closed).Should(HaveLen(1))
+
+		c, err = bulk.GetConnection()
+		Expect(err).To(BeNil())
+		Expect(other.accepted).To(HaveLen(1))
+		bulk.ReturnConnection(c)
+	})
+
+	It("starts with the pool's limits", func() {
+		pool.SetMaxConnections(5)
+		pool.SetReadTimeout(time.Second)
+
+		bulk := pool.Partition("bulk")
+		Expect(bulk.GetMaxConnections()).To(Equal(5))
+		Expect(bulk.DebugSnapshot().Config.ReadTimeout).To(Equal(durationPtr(time.Second)))
+
+		bulk.SetMaxConnections(2)
+		Expect(pool.GetMaxConnections()).To(Equal(5))
+	})
+
+	It("is selected by connectors", func() {
+		conn := connector.NewConnector(pool)
+
+		Expect(conn.WithPartition("bulk").GetPool()).To(BeIdenticalTo(pool.Partition("bulk")))
+		Expect(conn.WithPartition("latency").GetPool()).To(BeIdenticalTo(pool.Partition("latency")))
+		Expect(conn.GetPool()).To(BeIdenticalTo(pool))
+		Expect(pool.Partitions()).To(Equal([]string{"bulk", "latency"}))
+
+		Expect(pool.DebugSnapshot().Partitions).To(HaveKey("bulk"))
+	})
+})
+
+func durationPtr(d time.Duration) *connector.Duration {
+	duration := connector.Duration(d)
+	return &duration
+}
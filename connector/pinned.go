@@ -0,0 +1,90 @@
+package connector
+
+import (
+	"errors"
+	"sync"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// ErrPinnedConnectionLost is returned by the operations of a pinned connector once its
+// connection has been discarded or released.
+var ErrPinnedConnectionLost = errors.New("pinned connection lost")
+
+// A connection held for every operation of a connector
+type pinnedConnection struct {
+	sync.Mutex
+	gConn *GeodeConnection
+	lost  bool
+	// Writes whose near cache entries are removed again on release
+	writes []pinnedWrite
+}
+
+type pinnedWrite struct {
+	cache   *nearCache
+	request *v1.Message
+}
+
+// Pin returns a copy of this connector whose operations all use a single connection, acquired
+// now, so that they reach the same server and share any state it ties to the connection.
+// Call release to return the connection to the pool. Operations on the copy are not retried,
+// since a retry could only use the same connection. A failed operation discards the
+// connection, after which operations return ErrPinnedConnectionLost. The copy's operations
+// must not be run concurrently.
+func (this *Protobuf) Pin() (pinned *Protobuf, release func(), err error) {
+	gConn, err := this.pool.AcquireConnection(this.context(), this.priority)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := *this
+	c.pinned = &pinnedConnection{gConn: gConn}
+
+	var once sync.Once
+	return &c, func() {
+		once.Do(func() {
+			c.pinned.Lock()
+			c.pinned.lost = true
+			writes := c.pinned.writes
+			c.pinned.writes = nil
+			c.pinned.Unlock()
+
+			this.pool.ReturnConnection(gConn)
+			for _, write := range writes {
+				write.cache.invalidate(write.request)
+			}
+		})
+	}, nil
+}
+
+// Acquire a connection for an operation, the pinned one if any
+func (this *Protobuf) acquireConnection() (*GeodeConnection, error) {
+	if this.pinned == nil {
+		return this.pool.AcquireConnection(this.context(), this.priority)
+	}
+
+	this.pinned.Lock()
+	defer this.pinned.Unlock()
+
+	if this.pinned.lost {
+		return nil, ErrPinnedConnectionLost
+	}
+	return this.pinned.gConn, nil
+}
+
+// Return a connection after an operation, unless it is pinned
+func (this *Protobuf) returnConnection(gConn *GeodeConnection) {
+	if this.pinned == nil {
+		this.pool.ReturnConnection(gConn)
+	}
+}
+
+// Discard a connection which failed, marking it lost if it is pinned
+func (this *Protobuf) discardConnection(gConn *GeodeConnection) {
+	if this.pinned != nil {
+		this.pinned.Lock()
+		this.pinned.lost = true
+		this.pinned.Unlock()
+	}
+	this.pool.DiscardConnection(gConn)
+}
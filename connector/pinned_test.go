@@ -0,0 +1,82 @@
+package connector_test
+
+import (
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pinned connections", func() {
+
+	var pool *connector.Pool
+	var connection *connector.Protobuf
+
+	respondingConnection := func() *connectorfakes.FakeConn {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}},
+			}, b)
+		}
+		return fakeConn
+	}
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+		connection = connector.NewConnector(pool)
+	})
+
+	It("uses the same connection for every operation", func() {
+		first := respondingConnection()
+		pool.AddConnection(first, true)
+		second := respondingConnection()
+		pool.AddConnection(second, true)
+
+		pinned, release, err := connection.Pin()
+		Expect(err).To(BeNil())
+		Expect(pinned.Put("foo", "A", 1)).To(BeNil())
+		Expect(pinned.Put("foo", "B", 2)).To(BeNil())
+		Expect(second.WriteCallCount()).To(Equal(2))
+
+		Expect(connection.Put("foo", "C", 3)).To(BeNil())
+		Expect(first.WriteCallCount()).To(Equal(1))
+
+		release()
+		Expect(pool.Stats().InUse).To(Equal(0))
+	})
+
+	It("does not retry or replace a failed connection", func() {
+		pool.AddConnection(respondingConnection(), true)
+		failing := new(connectorfakes.FakeConn)
+		failing.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_ErrorResponse{
+					ErrorResponse: &v1.ErrorResponse{Error: &v1.Error{ErrorCode: v1.ErrorCode_SERVER_ERROR, Message: "failed"}},
+				},
+			}, b)
+		}
+		pool.AddConnection(failing, true)
+
+		pinned, release, err := connection.Pin()
+		Expect(err).To(BeNil())
+		defer release()
+
+		Expect(pinned.Put("foo", "A", 1)).To(MatchError("failed (100)"))
+		Expect(pinned.Put("foo", "A", 1)).To(Equal(connector.ErrPinnedConnectionLost))
+		Expect(failing.WriteCallCount()).To(Equal(1))
+	})
+
+	It("cannot be used once released", func() {
+		pool.AddConnection(respondingConnection(), true)
+
+		pinned, release, err := connection.Pin()
+		Expect(err).To(BeNil())
+		release()
+		release()
+
+		Expect(pinned.Put("foo", "A", 1)).To(Equal(connector.ErrPinnedConnectionLost))
+		Expect(pool.Stats()).To(Equal(connector.PoolStats{Connections: 1}))
+	})
+})
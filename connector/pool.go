@@ -1,26 +1,45 @@
 package connector
 
 import (
+	"context"
+	"crypto/tls"
 	"net"
+	"sort"
+	"strconv"
 	"sync"
+	"time"
 	"errors"
-	"expvar"
 )
 
-var activeConnections = expvar.NewInt("activeConnections")
-var connectionsCreated = expvar.NewInt("connectionsCreated")
-var discardedConnections = expvar.NewInt("discardedConnections")
-
 type AuthenticationError string
 
 func (e AuthenticationError) Error() string {
 	return string(e)
 }
 
+// A ConnectionProvider opens connections to a server for a pool. AddServer adds a provider
+// which dials a host and port; others, for example ones which find servers through a
+// service registry or connect through a service mesh, can be added with AddProvider. A
+// provider which implements fmt.Stringer is identified by its String in logs, cluster events
+// and the FailureDetector.
 type ConnectionProvider interface {
+	// Open a connection, see NewGeodeConnection, or return nil if none can be opened. The pool
+	// handshakes the connection before using it. May be called with the pool locked, so must
+	// not use the pool.
 	GetGeodeConnection() *GeodeConnection
+	// Report whether the provider should be used. Unhealthy providers are not asked for
+	// connections, nor checked by CheckHealth, until they are healthy again. May be called
+	// with the pool locked.
+	Healthy() bool
+	// Release the provider's resources once it has been removed from the pool with
+	// RemoveProvider. Connections it opened are closed by the pool.
+	Close() error
 }
 
+// ErrNoConnections is returned when a pool has no connection to lend and cannot open one,
+// as when none of its servers can be reached.
+var ErrNoConnections = errors.New("no connections available")
+
 type Pool struct {
 	sync.RWMutex
 	recentConnections     []*GeodeConnection
@@ -28,6 +47,64 @@ type Pool struct {
 	authenticationEnabled bool
 	username              string
 	password              string
+	authenticator         Authenticator
+	logger                Logger
+	logLevel              LogLevel
+	tracer                Tracer
+	metrics               MetricsPublisher
+	clock                 Clock
+	maxConnections        int
+	connectTimeout        time.Duration
+	tlsConfig             *tls.Config
+	readTimeout           time.Duration
+	writeTimeout          time.Duration
+	minIdle               int
+	idleTimeout           time.Duration
+	debug                 debugStats
+	detector              FailureDetector
+	waiters               []*waiter
+	waiterSeq             uint64
+	waits                 int64
+	waitTime              time.Duration
+	partitions            map[string]*Pool
+	events                *clusterEvents
+	reconnect             *reconnectGate
+	clientTags            map[string]string
+	maxInFlight           int
+	shared                []*sharedConnection
+	handshaker            Handshaker
+	quarantined           map[string]time.Duration
+	subset                *endpointSubset
+	balancing             LoadBalancing
+	balanceTurn           uint64
+}
+
+// PoolStats is a snapshot of the state of a Pool. Waits and WaitTime are cumulative over
+// the life of the pool.
+type PoolStats struct {
+	MaxConnections int
+	Connections    int
+	InUse          int
+	Waiting        int
+	// Number of callers which had to wait for a connection, and the total time they waited,
+	// including the time so far of those still waiting
+	Waits    int64
+	WaitTime time.Duration
+}
+
+// A caller of AcquireConnection waiting for a connection. Waiters are served in order of
+// priority and then arrival.
+type waiter struct {
+	priority int
+	seq      uint64
+	// Clock reading when the caller started waiting
+	since time.Duration
+	// Receives a connection, or nil if the waiter should try to acquire one again
+	ready chan *GeodeConnection
+}
+
+func (this *waiter) precedes(priority int, seq uint64) bool {
+	return this.priority > priority || (this.priority == priority && this.seq < seq)
 }
 
 func NewPool() *Pool {
@@ -43,8 +120,12 @@ func (this *Pool) AddConnection(c net.Conn, handshakeDone bool) {
 		authenticationDone: false,
 		inUse:              false,
 	}
+	if this.idleTimeout > 0 {
+		gConn.idleSince = this.GetClock().Now()
+	}
 
 	this.recentConnections = append(this.recentConnections, gConn)
+	guardedPublisher{this.GetMetricsPublisher()}.Add(MetricOpenConnections, 1)
 }
 
 func (this *Pool) AddLocator(host string, port int) {
@@ -52,71 +133,506 @@ func (this *Pool) AddLocator(host string, port int) {
 }
 
 func (this *Pool) AddServer(host string, port int) {
+	this.Lock()
+	defer this.Unlock()
+
+	before := this.providers
 	this.providers = append(this.providers, &serverConnectionProvider{
 		host,
 		port,
+		this.connectTimeout,
+		this.tlsConfig,
 	})
+	this.publishServerChanges(before, this.providers)
+	this.syncPartitions()
 }
 
-func (this *Pool) GetConnection() (*GeodeConnection, error) {
-	var gConn *GeodeConnection
-	var err error
-
+// SetMaxConnections limits the number of connections the pool will open. When all
+// connections are in use, callers wait for one to be returned and are served in order of
+// priority, then arrival, so that newcomers cannot starve callers which have been waiting
+// longer. A limit of 0 is unlimited; callers then never wait and an error is returned if no
+// connection can be made.
+func (this *Pool) SetMaxConnections(max int) {
 	this.Lock()
 	defer this.Unlock()
 
-	// First let's check the recent connections
+	this.setMaxConnections(max)
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) setMaxConnections(max int) {
+	this.maxConnections = max
+
+	// Close idle connections beyond the new limit
+	for i := len(this.recentConnections) - 1; max > 0 && i >= 0 && len(this.recentConnections) > max; i-- {
+		c := this.recentConnections[i]
+		if !c.inUse {
+			this.discardConnection(c)
+			this.metricsPublisher().Add(MetricDiscardedConnections, 1)
+		}
+	}
+
+	this.wakeWaiter()
+}
+
+// GetMaxConnections returns the current connection limit, 0 being unlimited.
+func (this *Pool) GetMaxConnections() int {
+	this.RLock()
+	defer this.RUnlock()
+
+	return this.maxConnections
+}
+
+// Stats returns a snapshot of the pool's connections and waiters.
+func (this *Pool) Stats() PoolStats {
+	this.RLock()
+	defer this.RUnlock()
+
+	stats := PoolStats{
+		MaxConnections: this.maxConnections,
+		Connections:    len(this.recentConnections),
+		Waiting:        len(this.waiters),
+		Waits:          this.waits,
+		WaitTime:       this.waitTime,
+	}
 	for _, c := range this.recentConnections {
-		if ! c.inUse {
-			gConn = c
+		if c.inUse {
+			stats.InUse++
+		}
+	}
+
+	if len(this.waiters) > 0 {
+		now := this.currentClock().Now()
+		for _, w := range this.waiters {
+			if wait := now - w.since; wait > 0 {
+				stats.WaitTime += wait
+			}
+		}
+	}
+
+	return stats
+}
+
+func (this *Pool) GetConnection() (*GeodeConnection, error) {
+	return this.AcquireConnection(context.Background(), 0)
+}
+
+// AcquireConnection returns a connection, waiting if necessary until one is available or
+// ctx is done. Waiting callers with a higher priority are served first. If ctx is from
+// WithSameServer, the connection is to the server its operations are tied to.
+func (this *Pool) AcquireConnection(ctx context.Context, priority int) (*GeodeConnection, error) {
+	affinity := sameServerOf(ctx)
+	if affinity == nil {
+		return this.acquire(ctx, priority, nil)
+	}
+
+	provider := affinity.current()
+	gConn, err := this.acquire(ctx, priority, provider)
+	if err == nil && provider == nil && gConn.provider != nil {
+		affinity.choose(gConn.provider)
+	}
+	return gConn, err
+}
+
+// Acquire a connection as AcquireConnection, from provider if it is not nil
+func (this *Pool) acquire(ctx context.Context, priority int, provider ConnectionProvider) (*GeodeConnection, error) {
+	clock := this.GetClock()
+	var waitStart time.Duration
+	waited := false
+
+	this.Lock()
+	this.waiterSeq++
+	seq := this.waiterSeq
+	this.Unlock()
+
+	for {
+		this.Lock()
+		if len(this.waiters) == 0 || !this.waiters[0].precedes(priority, seq) {
+			if delay := this.reconnectDelay(); delay > 0 {
+				this.Unlock()
+				timer := time.NewTimer(delay)
+				select {
+				case <-timer.C:
+					continue
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, ctx.Err()
+				}
+			}
+
+			gConn, err, wait := this.acquireConnection(provider)
+			if !wait {
+				if waited {
+					// Pass the turn on: there may be capacity left, or the next waiter
+					// should see the same error rather than wait for ever
+					this.wakeWaiter()
+				}
+				this.Unlock()
+				if waited {
+					this.recordWait(clock.Now() - waitStart)
+				}
+				return gConn, err
+			}
+		}
+
+		if !waited {
+			waited = true
+			waitStart = clock.Now()
+			this.waits++
+		}
+		w := &waiter{priority: priority, seq: seq, since: waitStart, ready: make(chan *GeodeConnection, 1)}
+		this.enqueueWaiter(w)
+		this.Unlock()
+
+		select {
+		case gConn := <-w.ready:
+			if gConn != nil && provider != nil && gConn.provider != provider {
+				// A connection to another server, which may be closed to make room
+				this.ReturnConnection(gConn)
+			} else if gConn != nil {
+				this.recordWait(clock.Now() - waitStart)
+				return gConn, nil
+			}
+		case <-ctx.Done():
+			this.recordWait(clock.Now() - waitStart)
+
+			this.Lock()
+			queued := this.dequeueWaiter(w)
+			this.Unlock()
+
+			if !queued {
+				// We were served concurrently, so pass the connection or wake-up on
+				if gConn := <-w.ready; gConn != nil {
+					this.ReturnConnection(gConn)
+				} else {
+					this.Lock()
+					this.wakeWaiter()
+					this.Unlock()
+				}
+			}
+
+			return nil, ctx.Err()
 		}
 	}
+}
+
+func (this *Pool) recordWait(wait time.Duration) {
+	this.Lock()
+	defer this.Unlock()
+
+	if wait > 0 {
+		this.waitTime += wait
+	}
+}
 
+// Acquire an idle or new connection, to provider's server if provider is not nil. If none is
+// available and the caller should wait, wait is true.
+// MUST hold the pool lock when calling
+func (this *Pool) acquireConnection(provider ConnectionProvider) (gConn *GeodeConnection, err error, wait bool) {
+	if provider == nil {
+		gConn = this.balancedConnection()
+	}
+
+	// Otherwise let's check the recent connections
 	if gConn == nil {
-		for i := len(this.providers) - 1; i >= 0; i-- {
-			gConn = this.providers[i].GetGeodeConnection()
-			if gConn != nil {
-				break
+		for _, c := range this.recentConnections {
+			if ! c.inUse && (provider == nil || c.provider == provider) {
+				gConn = c
 			}
-			this.providers = append(this.providers[:i], this.providers[i+1:]...)
 		}
+	}
+
+	if gConn == nil && provider != nil {
+		if !this.hasProvider(provider) {
+			return nil, ErrSameServerUnavailable, false
+		}
+		if this.maxConnections > 0 && len(this.recentConnections) >= this.maxConnections {
+			// Make room by closing an idle connection to another server
+			for _, c := range this.recentConnections {
+				if !c.inUse {
+					this.discardConnection(c)
+					this.metricsPublisher().Add(MetricDiscardedConnections, 1)
+					break
+				}
+			}
+		}
+	}
+
+	if gConn == nil && this.maxConnections > 0 && len(this.recentConnections) >= this.maxConnections {
+		return nil, nil, true
+	}
 
+	if gConn == nil {
+		if provider != nil {
+			if gConn = this.connectProvider(provider); gConn == nil {
+				return nil, ErrSameServerUnavailable, false
+			}
+		} else {
+			gConn = this.openConnection()
+		}
 		if gConn != nil {
 			this.recentConnections = append(this.recentConnections, gConn)
-			connectionsCreated.Add(1)
+			this.metricsPublisher().Add(MetricConnectionsCreated, 1)
+			this.metricsPublisher().Add(MetricOpenConnections, 1)
+			this.connectionOpened()
 		}
 	}
 
 	if gConn == nil {
-		return nil, errors.New("no connections available")
+		if this.maxConnections > 0 && len(this.recentConnections) > 0 {
+			return nil, nil, true
+		}
+		return nil, ErrNoConnections, false
 	}
 
-	err = gConn.handshake()
-	if err != nil {
-		this.discardConnection(gConn)
-		return nil, err
+	if err = this.prepareConnection(gConn); err != nil {
+		return nil, err, false
 	}
 
-	if this.authenticationEnabled {
-		err = gConn.authenticate(this.username, this.password)
-		if err != nil {
+	gConn.inUse = true
+	this.metricsPublisher().Add(MetricActiveConnections, 1)
+
+	return gConn, nil, false
+}
+
+// Handshake and authenticate a connection if necessary, discarding it if either fails
+// MUST hold the pool lock when calling
+func (this *Pool) prepareConnection(gConn *GeodeConnection) (err error) {
+	options := &HandshakeOptions{SkipVersion: gConn.handshakeDone}
+	if this.authenticationEnabled && !gConn.authenticationDone {
+		if options.Credentials, err = this.credentials(); err != nil {
+			this.log(LogError, "authentication failed", "server", connectionAddress(gConn), "error", err)
 			this.discardConnection(gConn)
-			return nil, err
+			return err
 		}
 	}
+	if options.SkipVersion && options.Credentials == nil {
+		return nil
+	}
 
-	gConn.inUse = true
-	activeConnections.Add(1)
+	start := this.currentClock().Now()
+	err = runHandshaker(this.currentHandshaker(), gConn.rawConn, options)
+	_, versionFailed := err.(*HandshakeError)
+	versionFailed = versionFailed || (err != nil && options.Credentials == nil)
+	if !options.SkipVersion {
+		// A server which rejects the credentials is still available
+		outcome := err
+		if !versionFailed {
+			outcome = nil
+		}
+		this.recordOutcome(gConn, this.currentClock().Now()-start, outcome)
+	}
+
+	if err != nil {
+		if versionFailed {
+			this.log(LogError, "handshake failed", "server", connectionAddress(gConn), "error", err)
+		} else {
+			this.log(LogError, "authentication failed", "server", connectionAddress(gConn), "error", err)
+		}
+		this.discardConnection(gConn)
+		return err
+	}
 
-	return gConn, nil
+	gConn.handshakeDone = true
+	if options.Credentials != nil {
+		gConn.authenticationDone = true
+	}
+
+	return nil
 }
 
 func (this *Pool) ReturnConnection(gConn *GeodeConnection) {
 	this.Lock()
 	defer this.Unlock()
 
+	this.release(gConn)
+}
+
+// Return a connection which is no longer in use
+// MUST hold the pool lock when calling
+func (this *Pool) release(gConn *GeodeConnection) {
 	gConn.inUse = false
-	activeConnections.Add(-1)
+	if this.idleTimeout > 0 {
+		gConn.idleSince = this.currentClock().Now()
+	}
+	this.metricsPublisher().Add(MetricActiveConnections, -1)
+
+	this.putBack(gConn)
+}
+
+// Make a connection which is no longer in use available, discarding it if its server has
+// been removed and handing it to the first waiter if there is one
+// MUST hold the pool lock when calling
+func (this *Pool) putBack(gConn *GeodeConnection) {
+	if !this.holds(gConn) {
+		// Already discarded
+		return
+	}
+
+	if gConn.provider != nil && !this.hasProvider(gConn.provider) {
+		this.discardConnection(gConn)
+		this.metricsPublisher().Add(MetricDiscardedConnections, 1)
+		return
+	}
+
+	if len(this.waiters) > 0 {
+		w := this.waiters[0]
+		this.waiters = this.waiters[1:]
+		gConn.inUse = true
+		this.metricsPublisher().Add(MetricActiveConnections, 1)
+		w.ready <- gConn
+	}
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) enqueueWaiter(w *waiter) {
+	this.waiters = append(this.waiters, w)
+	sort.SliceStable(this.waiters, func(i, j int) bool {
+		return this.waiters[i].precedes(this.waiters[j].priority, this.waiters[j].seq)
+	})
+}
+
+// Remove a waiter from the queue, returning false if it had already been served.
+// MUST hold the pool lock when calling
+func (this *Pool) dequeueWaiter(w *waiter) bool {
+	for i, x := range this.waiters {
+		if x == w {
+			this.waiters = append(this.waiters[:i], this.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Wake the first waiter so that it tries to acquire a connection again, for example because
+// a connection was discarded and a new one may be opened in its place. A woken waiter which
+// does not have to wait again wakes the next in turn.
+// MUST hold the pool lock when calling
+func (this *Pool) wakeWaiter() {
+	if len(this.waiters) > 0 {
+		w := this.waiters[0]
+		this.waiters = this.waiters[1:]
+		w.ready <- nil
+	}
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) holds(gConn *GeodeConnection) bool {
+	for _, c := range this.recentConnections {
+		if c == gConn {
+			return true
+		}
+	}
+	return false
+}
+
+// Configure applies a Config to the pool, replacing the servers, credentials, connection
+// limit and timeouts which it sets.
+// Operations in progress are not interrupted: connections to servers which are no longer
+// configured are closed once they are idle, and changed credentials are used for new
+// connections.
+func (this *Pool) Configure(config *Config) error {
+	if err := config.validate(); err != nil {
+		return err
+	}
+
+	this.Lock()
+	defer this.Unlock()
+
+	if config.ConnectTimeout != nil {
+		this.setConnectTimeout(time.Duration(*config.ConnectTimeout))
+	}
+	if config.ReadTimeout != nil {
+		this.readTimeout = time.Duration(*config.ReadTimeout)
+	}
+	if config.WriteTimeout != nil {
+		this.writeTimeout = time.Duration(*config.WriteTimeout)
+	}
+
+	if config.Servers != nil {
+		this.configureServers(config.Servers)
+	}
+
+	if config.Username != nil {
+		this.username = *config.Username
+		this.authenticator = nil
+		this.authenticationEnabled = this.username != ""
+	}
+	if config.Password != nil {
+		this.password = *config.Password
+	}
+
+	if config.MaxConnections != nil {
+		this.setMaxConnections(*config.MaxConnections)
+	}
+	if config.MinIdle != nil {
+		this.minIdle = *config.MinIdle
+	}
+	if config.IdleTimeout != nil {
+		this.setIdleTimeout(time.Duration(*config.IdleTimeout))
+	}
+	if config.LoadBalancing != nil {
+		this.balancing = *config.LoadBalancing
+	}
+	if config.LogLevel != nil {
+		this.logLevel = *config.LogLevel
+	}
+
+	this.syncPartitions()
+
+	return nil
+}
+
+// Replace the servers, keeping the providers of servers which remain.
+// MUST hold the pool lock when calling
+func (this *Pool) configureServers(servers []string) {
+	existing := make(map[string]ConnectionProvider)
+	for _, p := range this.providers {
+		if server, ok := p.(*serverConnectionProvider); ok {
+			existing[net.JoinHostPort(server.host, strconv.Itoa(server.port))] = p
+		}
+	}
+
+	// Providers added with AddProvider are kept
+	providers := make([]ConnectionProvider, 0, len(servers))
+	for _, p := range this.providers {
+		if _, ok := p.(*serverConnectionProvider); !ok {
+			providers = append(providers, p)
+		}
+	}
+	for _, server := range servers {
+		host, port, _ := parseServer(server)
+		if p, ok := existing[net.JoinHostPort(host, strconv.Itoa(port))]; ok {
+			providers = append(providers, p)
+		} else {
+			providers = append(providers, &serverConnectionProvider{host, port, this.connectTimeout, this.tlsConfig})
+		}
+	}
+	this.publishServerChanges(this.providers, providers)
+	this.providers = providers
+	this.discardRemovedConnections()
+}
+
+// Close idle connections to servers which are no longer configured
+// MUST hold the pool lock when calling
+func (this *Pool) discardRemovedConnections() {
+	for i := len(this.recentConnections) - 1; i >= 0; i-- {
+		c := this.recentConnections[i]
+		if !c.inUse && c.provider != nil && !this.hasProvider(c.provider) {
+			this.discardConnection(c)
+			this.metricsPublisher().Add(MetricDiscardedConnections, 1)
+		}
+	}
+}
+
+// MUST hold the pool lock when calling
+func (this *Pool) hasProvider(provider ConnectionProvider) bool {
+	for _, p := range this.providers {
+		if p == provider {
+			return true
+		}
+	}
+	return false
 }
 
 // MUST hold the pool lock when calling
@@ -124,24 +640,33 @@ func (this *Pool) discardConnection(gConn *GeodeConnection) {
 	for i, c := range this.recentConnections {
 		if gConn == c {
 			this.recentConnections = append(this.recentConnections[:i], this.recentConnections[i+1:]...)
+			this.metricsPublisher().Add(MetricOpenConnections, -1)
 			break
 		}
 	}
 
 	_ = gConn.rawConn.Close()
+	this.log(LogDebug, "connection discarded", "server", connectionAddress(gConn))
+	this.wakeWaiter()
 }
 
 // DiscardConnection is used publicly as it holds the necessary lock
 func (this *Pool) DiscardConnection(gConn *GeodeConnection) {
 	this.Lock()
 	this.discardConnection(gConn)
+	metrics := this.metricsPublisher()
 	this.Unlock()
 
-	discardedConnections.Add(1)
+	metrics.Add(MetricDiscardedConnections, 1)
 }
 
 func (this *Pool) AddCredentials(username, password string) {
+	this.Lock()
+	defer this.Unlock()
+
 	this.username = username
 	this.password = password
+	this.authenticator = nil
 	this.authenticationEnabled = true
+	this.syncPartitions()
 }
@@ -0,0 +1,141 @@
+package connector_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pool fairness", func() {
+
+	var pool *connector.Pool
+	var held *connector.GeodeConnection
+	var served chan string
+
+	// Start a caller which records its name once served and returns the connection
+	acquire := func(name string, priority int) {
+		p, s := pool, served
+		waiting := p.Stats().Waiting
+		go func() {
+			defer GinkgoRecover()
+			c, err := p.AcquireConnection(context.Background(), priority)
+			Expect(err).To(BeNil())
+			s <- name
+			p.ReturnConnection(c)
+		}()
+		// Let the caller join the queue
+		Eventually(func() int { return p.Stats().Waiting }).Should(Equal(waiting + 1))
+	}
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+		pool.AddConnection(new(connectorfakes.FakeConn), true)
+		pool.SetMaxConnections(1)
+		served = make(chan string, 10)
+
+		var err error
+		held, err = pool.GetConnection()
+		Expect(err).To(BeNil())
+	})
+
+	It("serves waiting callers in arrival order", func() {
+		acquire("first", 0)
+		acquire("second", 0)
+		acquire("third", 0)
+
+		pool.ReturnConnection(held)
+
+		Eventually(served).Should(Receive(Equal("first")))
+		Eventually(served).Should(Receive(Equal("second")))
+		Eventually(served).Should(Receive(Equal("third")))
+	})
+
+	It("serves higher priorities first", func() {
+		acquire("low", 0)
+		acquire("high", 5)
+		acquire("medium", 1)
+
+		pool.ReturnConnection(held)
+
+		Eventually(served).Should(Receive(Equal("high")))
+		Eventually(served).Should(Receive(Equal("medium")))
+		Eventually(served).Should(Receive(Equal("low")))
+	})
+
+	It("stops waiting when the context is done", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := pool.AcquireConnection(ctx, 0)
+		Expect(err).To(Equal(context.DeadlineExceeded))
+
+		// The abandoned wait does not affect later callers
+		acquire("later", 0)
+		pool.ReturnConnection(held)
+		Eventually(served).Should(Receive(Equal("later")))
+	})
+
+	It("does not wait without a connection limit", func() {
+		pool.SetMaxConnections(0)
+
+		_, err := pool.GetConnection()
+		Expect(err).To(MatchError("no connections available"))
+	})
+
+	It("passes the turn on when a woken waiter cannot connect", func() {
+		closed, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		closed.Close()
+		host, port, _ := net.SplitHostPort(closed.Addr().String())
+		portNumber, _ := strconv.Atoi(port)
+		pool.AddServer(host, portNumber)
+
+		p := pool
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				_, err := p.AcquireConnection(context.Background(), 0)
+				errs <- err
+			}()
+		}
+		Eventually(func() int { return pool.Stats().Waiting }).Should(Equal(2))
+
+		// A new connection may be opened in place of the discarded one, but the server is down
+		pool.DiscardConnection(held)
+
+		Eventually(errs).Should(Receive(MatchError("no connections available")))
+		Eventually(errs).Should(Receive(MatchError("no connections available")))
+	})
+
+	It("serves every waiter when the limit is raised", func() {
+		server := newHandshakeServer()
+		defer server.listener.Close()
+		host, port, _ := net.SplitHostPort(server.address())
+		portNumber, _ := strconv.Atoi(port)
+		pool.AddServer(host, portNumber)
+
+		p := pool
+		acquired := make(chan *connector.GeodeConnection, 3)
+		for i := 0; i < 3; i++ {
+			go func() {
+				c, _ := p.AcquireConnection(context.Background(), 0)
+				acquired <- c
+			}()
+		}
+		Eventually(func() int { return pool.Stats().Waiting }).Should(Equal(3))
+
+		pool.SetMaxConnections(4)
+
+		for i := 0; i < 3; i++ {
+			Eventually(acquired).Should(Receive(Not(BeNil())))
+		}
+		Expect(pool.Stats().InUse).To(Equal(4))
+		Expect(server.accepted).To(HaveLen(3))
+	})
+})
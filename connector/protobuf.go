@@ -1,15 +1,19 @@
 package connector
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"github.com/gemfire/geode-go-client/codec"
 	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
 	"github.com/gemfire/geode-go-client/query"
 	"github.com/golang/protobuf/proto"
-	"io"
 	"net"
 	"reflect"
+	"sort"
+	"sync"
+	"time"
 )
 
 //go:generate protoc --proto_path=$GEODE_CHECKOUT/geode-protobuf-messages/src/main/proto --go_out=../protobuf protocolVersion.proto
@@ -18,7 +22,29 @@ import (
 // A Protobuf connector provides the low-level interface between a Client and the backend Geode servers.
 // It should not be used directly; rather the Client API should be used.
 type Protobuf struct {
-	pool *Pool
+	pool          *Pool
+	keyProvider   KeyProvider
+	checksums     bool
+	schemas       *SchemaRegistry
+	deadLetters   *DeadLetterQueue
+	retryBudget   *RetryBudget
+	chunkSize     int
+	ctx           context.Context
+	priority      int
+	timeouts      *timeoutPolicy
+	timeout       time.Duration
+	queries       *queryGate
+	jsonLimits    *JSONLimits
+	throttle      *ThrottlePolicy
+	pinned        *pinnedConnection
+	dedup         *deduplicator
+	resultRef     func() interface{}
+	decodeWorkers int
+	failover      *FailoverPolicy
+	timing        *operationTiming
+	timeValues    bool
+	nearCaches    *nearCaches
+	staleRead     *staleRead
 }
 
 const MAJOR_VERSION uint32 = 1
@@ -32,23 +58,94 @@ func (e *RetryableError) Error() string {
 	return e.Err.Error()
 }
 
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
 func NewConnector(pool *Pool) *Protobuf {
 	return &Protobuf{
-		pool: pool,
+		pool:       pool,
+		timeouts:   &timeoutPolicy{},
+		queries:    &queryGate{},
+		dedup:      &deduplicator{},
+		nearCaches: &nearCaches{},
 	}
 }
 
-func (this *Protobuf) Put(region string, k, v interface{}) (err error) {
+// GetPool returns the Pool used by this connector.
+func (this *Protobuf) GetPool() *Pool {
+	return this.pool
+}
+
+// WithPriority returns a copy of this connector whose operations wait for a connection with
+// the given priority when the pool is exhausted. Higher priorities are served first; the
+// default is 0.
+func (this *Protobuf) WithPriority(priority int) *Protobuf {
+	c := *this
+	c.priority = priority
+	return &c
+}
+
+// SetKeyProvider enables encryption of struct fields tagged with `geode:",encrypt"`. Tagged
+// fields are encrypted before values are written to a region and decrypted when values are
+// read back into a reference struct. Tagged fields of embedded and nested structs are
+// encrypted too; writing a value with tagged fields inside a slice or map is an error.
+func (this *Protobuf) SetKeyProvider(keyProvider KeyProvider) {
+	this.keyProvider = keyProvider
+}
+
+// SetChecksumVerification enables storing a CRC32 checksum with every value written to a
+// region and verifying it when the value is read. A value which fails verification produces
+// an IntegrityError. Checksummed values are stored as byte arrays, so all clients reading
+// them must also have verification enabled and they cannot be used in server-side queries.
+func (this *Protobuf) SetChecksumVerification(enabled bool) {
+	this.checksums = enabled
+}
+
+// SetSchemaRegistry enables schema versioning of struct values. Values of registered types
+// are written with a SchemaVersionField and, when read, are upgraded to the current version
+// by the registry's migrations before being unmarshalled.
+//
+// Encrypted fields are decrypted before migrations run, using the field names of the current
+// version, so migrations see plaintext but cannot rename or move an encrypted field. Reading
+// a value whose encrypted field could not be located this way returns an error rather than
+// the ciphertext.
+func (this *Protobuf) SetSchemaRegistry(registry *SchemaRegistry) {
+	this.schemas = registry
+}
+
+// SetDeadLetterQueue changes the handling of entries which cannot be decoded in GetAll, query
+// and function results. Instead of failing the operation (or, for GetAll, reporting a
+// failure for the key) such entries are added to the queue and omitted from the results.
+// Undecodable cells in table results are replaced by nil so that rows remain aligned.
+func (this *Protobuf) SetDeadLetterQueue(queue *DeadLetterQueue) {
+	this.deadLetters = queue
+}
+
+func (this *Protobuf) Put(region string, k, v interface{}) error {
+	timed, finish := this.timed("Put")
+	defer finish()
+
+	return timed.put(region, k, v)
+}
+
+func (this *Protobuf) put(region string, k, v interface{}) (err error) {
 	key, err := EncodeValue(k)
 	if err != nil {
 		return err
 	}
 
-	value, err := EncodeValue(v)
+	value, err := this.encodeValue(v)
 	if err != nil {
 		return err
 	}
 
+	return this.PutRaw(region, key, value)
+}
+
+// PutRaw stores an already encoded key and value, bypassing all value encoding options. It
+// is intended for proxies and tools which move data without needing to understand it.
+func (this *Protobuf) PutRaw(region string, key, value *v1.EncodedValue) error {
 	put := &v1.Message{
 		MessageType: &v1.Message_PutRequest{
 			PutRequest: &v1.PutRequest{
@@ -61,7 +158,7 @@ func (this *Protobuf) Put(region string, k, v interface{}) (err error) {
 		},
 	}
 
-	_, err = this.doOperation(put)
+	_, err := this.doOperation(put)
 	if err != nil {
 		return err
 	}
@@ -69,13 +166,20 @@ func (this *Protobuf) Put(region string, k, v interface{}) (err error) {
 	return nil
 }
 
-func (this *Protobuf) PutIfAbsent(region string, k, v interface{}) (err error) {
+func (this *Protobuf) PutIfAbsent(region string, k, v interface{}) error {
+	timed, finish := this.timed("PutIfAbsent")
+	defer finish()
+
+	return timed.putIfAbsent(region, k, v)
+}
+
+func (this *Protobuf) putIfAbsent(region string, k, v interface{}) (err error) {
 	key, err := EncodeValue(k)
 	if err != nil {
 		return err
 	}
 
-	value, err := EncodeValue(v)
+	value, err := this.encodeValue(v)
 	if err != nil {
 		return err
 	}
@@ -101,11 +205,34 @@ func (this *Protobuf) PutIfAbsent(region string, k, v interface{}) (err error) {
 }
 
 func (this *Protobuf) Get(region string, k interface{}, value interface{}) (interface{}, error) {
+	timed, finish := this.timed("Get")
+	defer finish()
+
+	return timed.get(region, k, value)
+}
+
+func (this *Protobuf) get(region string, k interface{}, value interface{}) (interface{}, error) {
 	key, err := EncodeValue(k)
 	if err != nil {
 		return nil, err
 	}
 
+	v, err := this.GetRaw(region, key)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := this.decodeValue(v, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return decoded, nil
+}
+
+// GetRaw returns the encoded value for an encoded key without decoding it. No value decoding
+// options, such as checksum verification or decryption, are applied.
+func (this *Protobuf) GetRaw(region string, key *v1.EncodedValue) (*v1.EncodedValue, error) {
 	get := &v1.Message{
 		MessageType: &v1.Message_GetRequest{
 			GetRequest: &v1.GetRequest{
@@ -120,82 +247,221 @@ func (this *Protobuf) Get(region string, k interface{}, value interface{}) (inte
 		return nil, err
 	}
 
-	v := response.GetGetResponse().GetResult()
+	return response.GetGetResponse().GetResult(), nil
+}
+
+func (this *Protobuf) GetAll(region string, keys interface{}) (map[interface{}]interface{}, map[interface{}]error, error) {
+	timed, finish := this.timed("GetAll")
+	defer finish()
+
+	return timed.getAll(region, keys)
+}
 
-	decoded, err := DecodeValue(v, value)
+func (this *Protobuf) getAll(region string, keys interface{}) (map[interface{}]interface{}, map[interface{}]error, error) {
+	keySlice, encodedKeys, err := encodeKeys(keys)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return decoded, nil
+	chunks := this.chunks(len(encodedKeys))
+	if len(chunks) == 1 {
+		response, err := this.doOperation(getAllRequest(region, encodedKeys))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		decodedEntries, decodedFailures, err := this.decodeGetAllResponse(region, response)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(decodedFailures) == 0 {
+			return decodedEntries, nil, nil
+		}
+
+		return decodedEntries, decodedFailures, nil
+	}
+
+	decodedEntries := make(map[interface{}]interface{})
+	decodedFailures := make(map[interface{}]error)
+	multi := &MultiError{Operation: "GetAll", Region: region, Total: len(chunks)}
+
+	for i, bounds := range chunks {
+		response, server, err := this.doTrackedOperation(getAllRequest(region, encodedKeys[bounds[0]:bounds[1]]))
+
+		var entries map[interface{}]interface{}
+		var failures map[interface{}]error
+		if err == nil {
+			entries, failures, err = this.decodeGetAllResponse(region, response)
+		}
+
+		chunkKeys := make([]interface{}, 0, bounds[1]-bounds[0])
+		for j := bounds[0]; j < bounds[1]; j++ {
+			chunkKeys = append(chunkKeys, keySlice.Index(j).Interface())
+		}
+
+		if err != nil {
+			for _, k := range chunkKeys {
+				decodedFailures[k] = err
+			}
+			multi.Chunks = append(multi.Chunks, &ChunkError{Index: i, Server: server, Keys: chunkKeys, Err: err})
+			continue
+		}
+
+		for k, v := range entries {
+			decodedEntries[k] = v
+		}
+		for k, failure := range failures {
+			decodedFailures[k] = failure
+		}
+	}
+
+	if len(decodedFailures) == 0 {
+		decodedFailures = nil
+	}
+
+	if len(multi.Chunks) > 0 {
+		return decodedEntries, decodedFailures, multi
+	}
+
+	return decodedEntries, decodedFailures, nil
 }
 
-func (this *Protobuf) GetAll(region string, keys interface{}) (map[interface{}]interface{}, map[interface{}]error, error) {
+// Encode a slice or array of keys
+func encodeKeys(keys interface{}) (reflect.Value, []*v1.EncodedValue, error) {
 	keySlice := reflect.ValueOf(keys)
 	if keySlice.Kind() != reflect.Slice && keySlice.Kind() != reflect.Array {
-		return nil, nil, errors.New("keys must be a slice or array")
+		return keySlice, nil, errors.New("keys must be a slice or array")
 	}
 
 	encodedKeys := make([]*v1.EncodedValue, 0, keySlice.Len())
 	for i := 0; i < keySlice.Len(); i++ {
 		key, err := EncodeValue(keySlice.Index(i).Interface())
 		if err != nil {
-			return nil, nil, err
+			return keySlice, nil, err
 		}
 
 		encodedKeys = append(encodedKeys, key)
 	}
 
-	getAll := &v1.Message{
+	return keySlice, encodedKeys, nil
+}
+
+func getAllRequest(region string, keys []*v1.EncodedValue) *v1.Message {
+	return &v1.Message{
 		MessageType: &v1.Message_GetAllRequest{
 			GetAllRequest: &v1.GetAllRequest{
 				RegionName:  region,
-				Key:         encodedKeys,
+				Key:         keys,
 				CallbackArg: nil,
 			},
 		},
 	}
+}
+
+func (this *Protobuf) decodeGetAllResponse(region string, response *v1.Message) (map[interface{}]interface{}, map[interface{}]error, error) {
+	decodedEntries := make(map[interface{}]interface{})
+	decodedFailures := make(map[interface{}]error)
 
-	response, err := this.doOperation(getAll)
+	_, err := this.eachGetAllEntry(region, response, this.valueDecoder(nil), func(key, value interface{}, err error) bool {
+		if err != nil {
+			decodedFailures[key] = err
+		} else {
+			decodedEntries[key] = value
+		}
+		return true
+	})
 	if err != nil {
 		return nil, nil, err
 	}
 
-	decodedEntries := make(map[interface{}]interface{})
-	decodedFailures := make(map[interface{}]error)
+	return decodedEntries, decodedFailures, nil
+}
 
-	for _, entry := range response.GetGetAllResponse().Entries {
-		key, err := DecodeValue(entry.Key, nil)
-		if err != nil {
-			return nil, nil, errors.New(fmt.Sprintf("unable to decode GetAll response key: %s", err.Error()))
+// Return a function decoding values, JSON values into a new reference from newRef if given
+func (this *Protobuf) valueDecoder(newRef func() interface{}) func(*v1.EncodedValue) (interface{}, error) {
+	return func(ev *v1.EncodedValue) (interface{}, error) {
+		var ref interface{}
+		if newRef != nil {
+			ref = newRef()
 		}
+		return this.decodeValue(ev, ref)
+	}
+}
 
-		value, err := DecodeValue(entry.Value, nil)
-		if err != nil {
-			decodedFailures[key] = errors.New(fmt.Sprintf("unable to decode GetAll value for key: %v: %s", key, err.Error()))
+// Decode the entries and failures of a GetAll response, passing each to fn until it returns
+// false. Values are decoded with decode. Each entry is released from the response once
+// decoded, so that the response and the decoded values are not both held in full. Returns
+// whether fn asked to continue.
+func (this *Protobuf) eachGetAllEntry(region string, response *v1.Message, decode func(*v1.EncodedValue) (interface{}, error), fn func(key, value interface{}, err error) bool) (bool, error) {
+	entries := response.GetGetAllResponse().GetEntries()
+	var decoded []decodedValue
+	if this.decodeWorkers > 1 {
+		values := make([]*v1.EncodedValue, len(entries))
+		for i, entry := range entries {
+			values[i] = entry.Value
+		}
+		decoded = this.decodeParallel(values, decode)
+	}
+
+	for i, entry := range entries {
+		entries[i] = nil
+
+		key, err := decodeKey(entry.GetKey())
+		if err != nil && this.deadLetters != nil {
+			this.deadLetters.add(&DeadLetter{Operation: "GetAll", Region: region, Key: entry.Key, Value: entry.Value, Err: err})
 			continue
+		} else if err != nil {
+			return false, errors.New(fmt.Sprintf("unable to decode GetAll response key: %s", err.Error()))
 		}
 
-		decodedEntries[key] = value
-	}
+		var value interface{}
+		if decoded != nil {
+			value, err = decoded[i].value, decoded[i].err
+			decoded[i] = decodedValue{}
+		} else {
+			value, err = decode(entry.Value)
+		}
+		if err != nil && this.deadLetters != nil {
+			this.deadLetters.add(&DeadLetter{Operation: "GetAll", Region: region, Key: entry.Key, Value: entry.Value, Err: err})
+			continue
+		} else if _, ok := err.(*IntegrityError); !ok && err != nil {
+			err = errors.New(fmt.Sprintf("unable to decode GetAll value for key: %v: %s", key, err.Error()))
+		}
 
-	for _, failure := range response.GetGetAllResponse().Failures {
-		key, err := DecodeValue(failure.Key, nil)
 		if err != nil {
-			return nil, nil, errors.New(fmt.Sprintf("unable to decode GetAll failure response for key: %v: %s", failure.Key, err.Error()))
+			value = nil
+		}
+		if !fn(key, value, err) {
+			return false, nil
 		}
-
-		decodedFailures[key] = errors.New(fmt.Sprintf("%s (%d)", failure.Error.Message, failure.Error.ErrorCode))
 	}
 
-	if len(decodedFailures) == 0 {
-		return decodedEntries, nil, nil
+	failures := response.GetGetAllResponse().GetFailures()
+	for i, failure := range failures {
+		failures[i] = nil
+
+		key, err := decodeKey(failure.GetKey())
+		if err != nil {
+			return false, errors.New(fmt.Sprintf("unable to decode GetAll failure response for key: %v: %s", failure.GetKey(), err.Error()))
+		}
+
+		if !fn(key, nil, errors.New(fmt.Sprintf("%s (%d)", failure.GetError().GetMessage(), failure.GetError().GetErrorCode()))) {
+			return false, nil
+		}
 	}
 
-	return decodedEntries, decodedFailures, nil
+	return true, nil
 }
 
 func (this *Protobuf) PutAll(region string, entries interface{}) (map[interface{}]error, error) {
+	timed, finish := this.timed("PutAll")
+	defer finish()
+
+	return timed.putAll(region, entries)
+}
+
+func (this *Protobuf) putAll(region string, entries interface{}) (map[interface{}]error, error) {
 	// Check if we have a map
 	entriesMap := reflect.ValueOf(entries)
 	if entriesMap.Kind() != reflect.Map {
@@ -203,14 +469,20 @@ func (this *Protobuf) PutAll(region string, entries interface{}) (map[interface{
 	}
 
 	encodedEntries := make([]*v1.Entry, 0)
+	keys := entriesMap.MapKeys()
+	if codec.Deterministic() {
+		if err := sortKeys(keys); err != nil {
+			return nil, err
+		}
+	}
 
-	for _, k := range entriesMap.MapKeys() {
+	for _, k := range keys {
 		key, err := EncodeValue(k.Interface())
 		if err != nil {
 			return nil, err
 		}
 
-		value, err := EncodeValue(entriesMap.MapIndex(k).Interface())
+		value, err := this.encodeValue(entriesMap.MapIndex(k).Interface())
 		if err != nil {
 			return nil, err
 		}
@@ -223,36 +495,133 @@ func (this *Protobuf) PutAll(region string, entries interface{}) (map[interface{
 		encodedEntries = append(encodedEntries, e)
 	}
 
-	putAll := &v1.Message{
+	chunks := this.chunks(len(encodedEntries))
+	if len(chunks) == 1 {
+		r, err := this.doOperation(putAllRequest(region, encodedEntries))
+		if err != nil {
+			return nil, err
+		}
+
+		failures, err := decodePutAllResponse(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(failures) == 0 {
+			return nil, nil
+		}
+
+		return failures, nil
+	}
+
+	allFailures := make(map[interface{}]error)
+	multi := &MultiError{Operation: "PutAll", Region: region, Total: len(chunks)}
+
+	for i, bounds := range chunks {
+		r, server, err := this.doTrackedOperation(putAllRequest(region, encodedEntries[bounds[0]:bounds[1]]))
+
+		var failures map[interface{}]error
+		if err == nil {
+			failures, err = decodePutAllResponse(r)
+		}
+
+		chunkKeys := make([]interface{}, 0, bounds[1]-bounds[0])
+		for _, k := range keys[bounds[0]:bounds[1]] {
+			chunkKeys = append(chunkKeys, k.Interface())
+		}
+
+		if err != nil {
+			for _, k := range chunkKeys {
+				allFailures[k] = err
+			}
+			multi.Chunks = append(multi.Chunks, &ChunkError{Index: i, Server: server, Keys: chunkKeys, Err: err})
+			continue
+		}
+
+		for k, failure := range failures {
+			allFailures[k] = failure
+		}
+	}
+
+	if len(allFailures) == 0 {
+		return nil, nil
+	}
+
+	if len(multi.Chunks) > 0 {
+		return allFailures, multi
+	}
+
+	return allFailures, nil
+}
+
+// Sort the keys of a map by their encoding, for deterministic encoding
+func sortKeys(keys []reflect.Value) error {
+	type encodedKey struct {
+		key  reflect.Value
+		data []byte
+	}
+
+	encoded := make([]encodedKey, len(keys))
+	for i, k := range keys {
+		ev, err := EncodeValue(k.Interface())
+		if err != nil {
+			return err
+		}
+		data, err := proto.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		encoded[i] = encodedKey{key: k, data: data}
+	}
+
+	sort.Slice(encoded, func(i, j int) bool {
+		return bytes.Compare(encoded[i].data, encoded[j].data) < 0
+	})
+	for i := range encoded {
+		keys[i] = encoded[i].key
+	}
+
+	return nil
+}
+
+func putAllRequest(region string, entries []*v1.Entry) *v1.Message {
+	return &v1.Message{
 		MessageType: &v1.Message_PutAllRequest{
 			PutAllRequest: &v1.PutAllRequest{
 				RegionName: region,
-				Entry:      encodedEntries,
+				Entry:      entries,
 			},
 		},
 	}
+}
 
-	r, err := this.doOperation(putAll)
-	if err != nil {
-		return nil, err
-	}
-
+func decodePutAllResponse(r *v1.Message) (map[interface{}]error, error) {
 	response := r.GetPutAllResponse()
 	failures := make(map[interface{}]error)
 	for _, k := range response.GetFailedKeys() {
-		key, err := DecodeValue(k.Key, nil)
+		key, err := decodeKey(k.GetKey())
 		if err != nil {
 			return nil, errors.New(fmt.Sprintf("unable to decode failed PutAll response key: %s", err.Error()))
 		}
 
-		failures[key] = errors.New(fmt.Sprintf("%s (%d)", k.GetError().Message, k.GetError().ErrorCode))
+		failures[key] = errors.New(fmt.Sprintf("%s (%d)", k.GetError().GetMessage(), k.GetError().GetErrorCode()))
 	}
 
-	if len(failures) == 0 {
-		return nil, nil
+	return failures, nil
+}
+
+// Decode a key which is to be used in a map of results
+func decodeKey(ev *v1.EncodedValue) (interface{}, error) {
+	key, err := DecodeValue(ev, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	return failures, nil
+	if key != nil && !reflect.TypeOf(key).Comparable() {
+		return nil, errors.New(fmt.Sprintf("unable to use a key of type %T", key))
+	}
+
+	return key, nil
 }
 
 func (this *Protobuf) Remove(region string, k interface{}) error {
@@ -275,6 +644,71 @@ func (this *Protobuf) Remove(region string, k interface{}) error {
 	return err
 }
 
+// The number of keys RemoveAll removes at once
+const removeAllConcurrency = 8
+
+// RemoveAll removes many entries from a region. Keys must be passed as an array or slice. The
+// protocol has no bulk remove message, so each key is removed by its own request, up to
+// removeAllConcurrency at once over the pool's connections (one at a time on a pinned
+// connector). Every key is attempted, and those which could not be removed are returned with
+// their errors. The single error is only returned when the keys cannot be encoded, in which
+// case nothing is removed.
+func (this *Protobuf) RemoveAll(region string, keys interface{}) (map[interface{}]error, error) {
+	keySlice := reflect.ValueOf(keys)
+	if keySlice.Kind() != reflect.Slice && keySlice.Kind() != reflect.Array {
+		return nil, errors.New("keys must be a slice or array")
+	}
+
+	requests := make([]*v1.Message, keySlice.Len())
+	for i := 0; i < keySlice.Len(); i++ {
+		key, err := EncodeValue(keySlice.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		requests[i] = &v1.Message{
+			MessageType: &v1.Message_RemoveRequest{
+				RemoveRequest: &v1.RemoveRequest{
+					RegionName: region,
+					Key:        key,
+				},
+			},
+		}
+	}
+
+	concurrency := removeAllConcurrency
+	if this.pinned != nil {
+		// The operations of a pinned connector must not run concurrently
+		concurrency = 1
+	}
+
+	errs := make([]error, len(requests))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, request := range requests {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(i int, request *v1.Message) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			_, errs[i] = this.doOperation(request)
+		}(i, request)
+	}
+	wg.Wait()
+
+	var failures map[interface{}]error
+	for i, err := range errs {
+		if err != nil {
+			if failures == nil {
+				failures = make(map[interface{}]error)
+			}
+			failures[keySlice.Index(i).Interface()] = err
+		}
+	}
+
+	return failures, nil
+}
+
 func (this *Protobuf) Size(r string) (int32, error) {
 	request := &v1.Message{
 		MessageType: &v1.Message_GetSizeRequest{
@@ -294,37 +728,116 @@ func (this *Protobuf) Size(r string) (int32, error) {
 	return size, nil
 }
 
+// RegionNames returns the names of the regions hosted by the server handling the request.
+func (this *Protobuf) RegionNames() ([]string, error) {
+	request := &v1.Message{
+		MessageType: &v1.Message_GetRegionNamesRequest{
+			GetRegionNamesRequest: &v1.GetRegionNamesRequest{},
+		},
+	}
+
+	response, err := this.doOperation(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetGetRegionNamesResponse().GetRegions(), nil
+}
+
+// Clear removes every entry of a region. Servers which cannot clear a region, such as older
+// servers with a partitioned region, return an error.
+func (this *Protobuf) Clear(region string) error {
+	request := &v1.Message{
+		MessageType: &v1.Message_ClearRequest{
+			ClearRequest: &v1.ClearRequest{
+				RegionName: region,
+			},
+		},
+	}
+
+	_, err := this.doOperation(request)
+	return err
+}
+
 func (this *Protobuf) ExecuteOnRegion(functionId, region string, functionArgs interface{}, keyFilter []interface{}) ([]interface{}, error) {
-	args, err := EncodeValue(functionArgs)
+	results := make([]interface{}, 0)
+	err := this.ExecuteOnRegionFunc(functionId, region, functionArgs, keyFilter, collectFunctionResults(&results))
 	if err != nil {
 		return nil, err
 	}
 
+	return results, nil
+}
+
+// ExecuteOnRegionFunc executes a function as ExecuteOnRegion, passing each result to fn as it
+// is decoded, until fn returns false. The server sends every result in one response, which
+// the protocol cannot split into chunks, but each result is released from the response once
+// decoded so that large result sets are not held twice.
+func (this *Protobuf) ExecuteOnRegionFunc(functionId, region string, functionArgs interface{}, keyFilter []interface{}, fn func(result interface{}) bool) error {
+	timed, finish := this.timed("ExecuteFunctionOnRegion")
+	defer finish()
+
+	return timed.executeOnRegionFunc(functionId, region, functionArgs, keyFilter, fn)
+}
+
+func (this *Protobuf) executeOnRegionFunc(functionId, region string, functionArgs interface{}, keyFilter []interface{}, fn func(result interface{}) bool) error {
+	args, err := EncodeValue(functionArgs)
+	if err != nil {
+		return err
+	}
+
+	var filter []*v1.EncodedValue
+	if len(keyFilter) > 0 {
+		if filter, err = EncodeList(keyFilter); err != nil {
+			return err
+		}
+	}
+
 	request := &v1.Message{
 		MessageType: &v1.Message_ExecuteFunctionOnRegionRequest{
 			ExecuteFunctionOnRegionRequest: &v1.ExecuteFunctionOnRegionRequest{
 				FunctionID: functionId,
 				Region:     region,
 				Arguments:  args,
+				KeyFilter:  filter,
 			},
 		},
 	}
 
 	response, err := this.doOperation(request)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	results := response.GetExecuteFunctionOnRegionResponse().GetResults()
-	return decodedFunctionResults(results)
+	return this.eachFunctionResult(region, results, fn)
 }
 
 func (this *Protobuf) ExecuteOnMembers(functionId string, members []string, functionArgs interface{}) ([]interface{}, error) {
-	args, err := EncodeValue(functionArgs)
+	results := make([]interface{}, 0)
+	err := this.ExecuteOnMembersFunc(functionId, members, functionArgs, collectFunctionResults(&results))
 	if err != nil {
 		return nil, err
 	}
 
+	return results, nil
+}
+
+// ExecuteOnMembersFunc executes a function as ExecuteOnMembers, passing each result to fn as
+// ExecuteOnRegionFunc does.
+func (this *Protobuf) ExecuteOnMembersFunc(functionId string, members []string, functionArgs interface{}, fn func(result interface{}) bool) error {
+	timed, finish := this.timed("ExecuteFunctionOnMember")
+	defer finish()
+
+	return timed.executeOnMembersFunc(functionId, members, functionArgs, fn)
+}
+
+func (this *Protobuf) executeOnMembersFunc(functionId string, members []string, functionArgs interface{}, fn func(result interface{}) bool) error {
+	args, err := EncodeValue(functionArgs)
+	if err != nil {
+		return err
+	}
+
 	request := &v1.Message{
 		MessageType: &v1.Message_ExecuteFunctionOnMemberRequest{
 			ExecuteFunctionOnMemberRequest: &v1.ExecuteFunctionOnMemberRequest{
@@ -337,19 +850,38 @@ func (this *Protobuf) ExecuteOnMembers(functionId string, members []string, func
 
 	response, err := this.doOperation(request)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	results := response.GetExecuteFunctionOnMemberResponse().GetResults()
-	return decodedFunctionResults(results)
+	return this.eachFunctionResult("", results, fn)
 }
 
 func (this *Protobuf) ExecuteOnGroups(functionId string, groups []string, functionArgs interface{}) ([]interface{}, error) {
-	args, err := EncodeValue(functionArgs)
+	results := make([]interface{}, 0)
+	err := this.ExecuteOnGroupsFunc(functionId, groups, functionArgs, collectFunctionResults(&results))
 	if err != nil {
 		return nil, err
 	}
 
+	return results, nil
+}
+
+// ExecuteOnGroupsFunc executes a function as ExecuteOnGroups, passing each result to fn as
+// ExecuteOnRegionFunc does.
+func (this *Protobuf) ExecuteOnGroupsFunc(functionId string, groups []string, functionArgs interface{}, fn func(result interface{}) bool) error {
+	timed, finish := this.timed("ExecuteFunctionOnGroup")
+	defer finish()
+
+	return timed.executeOnGroupsFunc(functionId, groups, functionArgs, fn)
+}
+
+func (this *Protobuf) executeOnGroupsFunc(functionId string, groups []string, functionArgs interface{}, fn func(result interface{}) bool) error {
+	args, err := EncodeValue(functionArgs)
+	if err != nil {
+		return err
+	}
+
 	request := &v1.Message{
 		MessageType: &v1.Message_ExecuteFunctionOnGroupRequest{
 			ExecuteFunctionOnGroupRequest: &v1.ExecuteFunctionOnGroupRequest{
@@ -362,69 +894,117 @@ func (this *Protobuf) ExecuteOnGroups(functionId string, groups []string, functi
 
 	response, err := this.doOperation(request)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	results := response.GetExecuteFunctionOnGroupResponse().GetResults()
-	return decodedFunctionResults(results)
+	return this.eachFunctionResult("", results, fn)
+}
+
+func collectFunctionResults(results *[]interface{}) func(result interface{}) bool {
+	return func(result interface{}) bool {
+		*results = append(*results, result)
+		return true
+	}
 }
 
 func (this *Protobuf) QuerySingleResult(query *query.Query) (interface{}, error) {
-	response, err := this.doQuery(query.QueryString, query.BindParameters)
+	timed, finish := this.timed("OqlQuery")
+	defer finish()
+
+	return timed.querySingleResult(query)
+}
+
+func (this *Protobuf) querySingleResult(query *query.Query) (interface{}, error) {
+	response, err := this.doQuery(query)
 	if err != nil {
 		return nil, err
 	}
 
 	ref := cloneStruct(query.Reference)
-	result, err := DecodeValue(response.GetOqlQueryResponse().GetSingleResult(), ref)
+	result, err := this.decodeValue(response.GetOqlQueryResponse().GetSingleResult(), ref)
 	if err != nil {
 		return nil, errors.New(fmt.Sprintf("unable to decode query result: %s", err.Error()))
 	}
 
+	if query.LastTrace != nil {
+		query.LastTrace.Results = 1
+	}
+
 	return result, nil
 }
 
 func (this *Protobuf) QueryListResult(query *query.Query) ([]interface{}, error) {
-	response, err := this.doQuery(query.QueryString, query.BindParameters)
+	timed, finish := this.timed("OqlQuery")
+	defer finish()
+
+	return timed.queryListResult(query)
+}
+
+func (this *Protobuf) queryListResult(query *query.Query) ([]interface{}, error) {
+	response, err := this.doQuery(query)
 	if err != nil {
 		return nil, err
 	}
 
 	// Build up a slice of results
 	encodedResultList := response.GetOqlQueryResponse().GetListResult().GetElement()
-	results := make([]interface{}, len(encodedResultList))
+	results := make([]interface{}, 0, len(encodedResultList))
+	if query.LastTrace != nil {
+		query.LastTrace.Results = len(encodedResultList)
+	}
+
+	decode := func(v *v1.EncodedValue) (interface{}, error) {
+		return this.decodeValue(v, cloneStruct(query.Reference))
+	}
+	decoded := this.decodeParallel(encodedResultList, decode)
 
 	for i, v := range encodedResultList {
-		ref := cloneStruct(query.Reference)
-		val, err := DecodeValue(v, ref)
-		if err != nil {
+		var val interface{}
+		if decoded != nil {
+			val, err = decoded[i].value, decoded[i].err
+		} else {
+			val, err = decode(v)
+		}
+		if err != nil && this.deadLetters != nil {
+			this.deadLetters.add(&DeadLetter{Operation: "Query", Value: v, Err: err})
+			continue
+		} else if err != nil {
 			return nil, errors.New(fmt.Sprintf("unable to decode query result: %s", err.Error()))
 		}
-		results[i] = val
+		results = append(results, val)
 	}
 
 	return results, nil
 }
 
 func (this *Protobuf) QueryTableResult(query *query.Query) (map[string][]interface{}, error) {
-	response, err := this.doQuery(query.QueryString, query.BindParameters)
+	timed, finish := this.timed("OqlQuery")
+	defer finish()
+
+	return timed.queryTableResult(query)
+}
+
+func (this *Protobuf) queryTableResult(query *query.Query) (map[string][]interface{}, error) {
+	response, err := this.doQuery(query)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build up a slice of results
 	table := response.GetOqlQueryResponse().GetTableResult()
-	columns := table.GetFieldName()
-	valueList := table.GetRow()
-	results := make(map[string][]interface{}, len(columns))
-
-	for i, columnName := range columns {
-		ref := cloneStruct(query.Reference)
-		val, err := DecodeValueList(valueList[i], ref)
-		if err != nil {
-			return nil, errors.New(fmt.Sprintf("unable to decode query result: %s", err.Error()))
+	results, err := codec.DecodeTableWith(table, func(value *v1.EncodedValue) (interface{}, error) {
+		decoded, err := this.decodeValue(value, cloneStruct(query.Reference))
+		if err != nil && this.deadLetters != nil {
+			this.deadLetters.add(&DeadLetter{Operation: "Query", Value: value, Err: err})
+			return nil, nil
 		}
-		results[columnName] = val
+		return decoded, err
+	})
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to decode query result: %s", err.Error()))
+	}
+	if query.LastTrace != nil && len(table.GetRow()) > 0 {
+		query.LastTrace.Results = len(table.GetRow()[0].GetElement())
 	}
 
 	return results, nil
@@ -439,7 +1019,9 @@ func cloneStruct(i interface{}) interface{} {
 	return reflect.New(reflect.Indirect(reflect.ValueOf(i)).Type()).Interface()
 }
 
-func (this *Protobuf) doQuery(query string, bindParameters []interface{}) (*v1.Message, error) {
+// Execute a query, setting its LastTrace if it is traced
+func (this *Protobuf) doQuery(q *query.Query) (*v1.Message, error) {
+	bindParameters := q.BindParameters
 	encodedKeys := make([]*v1.EncodedValue, 0, len(bindParameters))
 	for i := 0; i < len(bindParameters); i++ {
 		key, err := EncodeValue(bindParameters[i])
@@ -453,288 +1035,500 @@ func (this *Protobuf) doQuery(query string, bindParameters []interface{}) (*v1.M
 	request := &v1.Message{
 		MessageType: &v1.Message_OqlQueryRequest{
 			OqlQueryRequest: &v1.OQLQueryRequest{
-				Query: query,
+				Query: q.Statement(),
 				BindParameter: encodedKeys,
 			},
 		},
 	}
 
-	response, err := this.doOperation(request)
+	leave, err := this.queries.enter(this.context())
 	if err != nil {
 		return nil, err
 	}
+	defer leave()
 
-	return response, nil
-}
-
-func decodedFunctionResults(results []*v1.EncodedValue) ([]interface{}, error) {
-	decodedEntries := make([]interface{}, len(results))
-
-	for i, entry := range results {
-		value, err := DecodeValue(entry, nil)
-		if err != nil {
-			return nil, errors.New(fmt.Sprintf("unable to decode function result value: %s", err.Error()))
-		}
+	if !q.Trace {
+		return this.doOperation(request)
+	}
 
-		decodedEntries[i] = value
+	q.LastTrace = nil
+	clock := this.pool.GetClock()
+	start := clock.Now()
+	response, server, err := this.doTrackedOperation(request)
+	if err != nil {
+		return nil, err
 	}
+	q.LastTrace = &query.Trace{Server: server, Elapsed: clock.Now() - start}
 
-	return decodedEntries, nil
+	return response, nil
 }
 
-func (this *Protobuf) doOperation(request *v1.Message) (*v1.Message, error) {
-	gConn, err := this.pool.GetConnection()
+// Encode a value which is to be stored in a region, applying any value level options, such as
+// field encryption, configured on the connector. Keys, bind parameters and function arguments
+// must be encoded with EncodeValue directly.
+func (this *Protobuf) encodeValue(v interface{}) (*v1.EncodedValue, error) {
+	defer this.timing.addSerialization(this.timing.now())
+
+	ev, err := EncodeValue(v)
 	if err != nil {
 		return nil, err
 	}
-	defer this.pool.ReturnConnection(gConn)
 
-	message, err := doOperationWithConnection(gConn.rawConn, request)
-	if err != nil {
-		this.pool.DiscardConnection(gConn)
+	if j, ok := ev.Value.(*v1.EncodedValue_JsonObjectResult); ok {
+		document := j.JsonObjectResult
+
+		if this.schemas != nil {
+			if document, err = this.schemas.stamp(v, document); err != nil {
+				return nil, err
+			}
+		}
+
+		if this.keyProvider != nil {
+			if document, err = encryptFields(reflect.TypeOf(v), document, this.keyProvider); err != nil {
+				return nil, err
+			}
+		}
+
+		ev.Value = &v1.EncodedValue_JsonObjectResult{JsonObjectResult: document}
 	}
 
-	if _, ok := err.(*RetryableError); ok {
-		return this.doOperation(request)
-	} else if err != nil {
-		return nil, err
+	if this.checksums {
+		return wrapChecksum(ev)
 	}
 
-	return message, nil
+	return ev, nil
 }
 
-func doOperationWithConnection(connection net.Conn, request *v1.Message) (*v1.Message, error) {
-	err := writeMessage(connection, request)
-	if err != nil {
-		return nil, err
+// Decode a value read from a region; the inverse of encodeValue.
+func (this *Protobuf) decodeValue(ev *v1.EncodedValue, ref interface{}) (interface{}, error) {
+	defer this.timing.addSerialization(this.timing.now())
+
+	if this.checksums {
+		var err error
+		if ev, err = unwrapChecksum(ev); err != nil {
+			return nil, err
+		}
 	}
 
-	// In the event that the client connection has timed out, it would have been closed by the server.
-	// This results in a FIN being sent to the client, however the prior write may appear to have succeeded
-	// even in light of the server side of the connection being closed. It is only on a subsequent read
-	// that an error will be detected. See Stevens pg 132, Section 5.13 SIGPIPE signal.
-	response, err := readResponse(connection)
-	if err != nil {
-		if err.Error() == "EOF" {
-			return nil, &RetryableError{err}
+	if j, ok := ev.GetValue().(*v1.EncodedValue_JsonObjectResult); ok && this.jsonLimits != nil {
+		if err := this.jsonLimits.check(j.JsonObjectResult); err != nil {
+			return nil, err
 		}
-		return nil, err
 	}
 
-	if x := response.GetErrorResponse(); x != nil {
-		return nil, errors.New(fmt.Sprintf("%s (%d)", x.GetError().Message, x.GetError().ErrorCode))
+	if j, ok := ev.GetValue().(*v1.EncodedValue_JsonObjectResult); ok && ref != nil {
+		document := j.JsonObjectResult
+
+		var err error
+		if this.keyProvider != nil {
+			if document, err = decryptFields(reflect.TypeOf(ref), document, this.keyProvider); err != nil {
+				return nil, err
+			}
+		}
+
+		if this.schemas != nil {
+			if document, err = this.schemas.migrate(ref, document); err != nil {
+				return nil, err
+			}
+		}
+
+		if this.keyProvider != nil {
+			if err = checkDecrypted(document); err != nil {
+				return nil, err
+			}
+		}
+
+		ev = &v1.EncodedValue{Value: &v1.EncodedValue_JsonObjectResult{JsonObjectResult: document}}
 	}
 
-	return response, nil
+	return DecodeValue(ev, ref)
 }
 
-func writeMessage(connection net.Conn, message proto.Message) (err error) {
-	p := proto.NewBuffer(nil)
-	err = p.EncodeMessage(message)
-	if err != nil {
-		return err
+// WithResultRef returns a copy of this connector which decodes each JSON function result
+// into a new reference returned by newRef, such as a pointer to a new struct, as GetAllInto
+// does for values. Without one, JSON results cannot be decoded.
+func (this *Protobuf) WithResultRef(newRef func() interface{}) *Protobuf {
+	c := *this
+	c.resultRef = newRef
+	return &c
+}
+
+func (this *Protobuf) eachFunctionResult(region string, results []*v1.EncodedValue, fn func(result interface{}) bool) error {
+	decode := func(ev *v1.EncodedValue) (interface{}, error) {
+		defer this.timing.addSerialization(this.timing.now())
+		return DecodeValue(ev, nil)
+	}
+	if this.resultRef != nil {
+		decode = this.valueDecoder(this.resultRef)
 	}
 
-	_, err = connection.Write(p.Bytes())
-	if err != nil {
-		switch nerr := err.(type) {
-		case *net.OpError:
-			if nerr.Op == "write" {
-				return &RetryableError{err}
-			}
+	for i, entry := range results {
+		results[i] = nil
+
+		value, err := decode(entry)
+		if err != nil && this.deadLetters != nil {
+			this.deadLetters.add(&DeadLetter{Operation: "Function", Region: region, Value: entry, Err: err})
+			continue
+		} else if err != nil {
+			return errors.New(fmt.Sprintf("unable to decode function result value: %s", err.Error()))
+		}
+
+		if !fn(value) {
+			return nil
 		}
-		return err
 	}
 
 	return nil
 }
 
-func readResponse(connection net.Conn) (*v1.Message, error) {
-	data, err := readRawMessage(connection)
+func (this *Protobuf) doOperation(request *v1.Message) (*v1.Message, error) {
+	message, _, err := this.doTrackedOperation(request)
+	return message, err
+}
+
+// Perform an operation, also returning the address of the server which handled the final
+// attempt, if known. Reads answered by a near cache return no server.
+func (this *Protobuf) doTrackedOperation(request *v1.Message) (*v1.Message, string, error) {
+	if this.pinned != nil {
+		return this.doPinnedOperation(request)
+	}
+	if cache := this.nearCache(requestRegion(request)); cache != nil {
+		return this.doNearCachedOperation(cache, request)
+	}
+	return this.doRemoteOperation(request)
+}
+
+// Perform an operation on the cluster
+func (this *Protobuf) doRemoteOperation(request *v1.Message) (*v1.Message, string, error) {
+	fingerprint := this.dedup.fingerprint(this.context(), request)
+	if fingerprint != "" && this.dedup.acknowledged(fingerprint) {
+		guardedPublisher{this.pool.GetMetricsPublisher()}.AddKeyed(MetricDeduplicatedWrites, operationName(request), 1)
+		return deduplicatedResponse(request), "", nil
+	}
+
+	timed := this
+	if this.timing == nil && this.timeValues {
+		// An operation which serializes no values, whose exchanges are timed alone
+		c := *this
+		c.timing = &operationTiming{operation: operationName(request), clock: this.pool.GetClock()}
+		defer c.timing.finish(this.pool.GetMetricsPublisher())
+		timed = &c
+	}
+
+	span := startSpan(this.pool.GetTracer(), this.context(), request)
+	clock := this.pool.GetClock()
+	start := clock.Now()
+	message, server, retries, err := timed.attemptOperation(request)
+	timed.timing.deferSpan(func(timing *operationTiming) {
+		setTimingAttributes(span, timing)
+		endSpan(span, server, retries, err)
+	})
+
+	end := clock.Now()
+	latency := end - start
+	if latency < 0 {
+		latency = 0
+	}
+	this.pool.debug.record(request, message, end, latency, err)
+	if err == nil && fingerprint != "" {
+		this.dedup.acknowledge(fingerprint)
+	}
 	if err != nil {
-		return nil, err
+		logTo(this.pool.GetLogger(), LogWarn, "operation failed", "operation", operationName(request),
+			"region", requestRegion(request), "server", server, "error", err)
+	}
+
+	publisher := this.pool.GetMetricsPublisher()
+	guardedPublisher{publisher}.AddKeyed(MetricOperations, operationName(request), 1)
+	countOperationErrors(publisher, operationName(request), retries, err)
+	if latencyPublisher, ok := publisher.(LatencyPublisher); ok {
+		guardedPublisher{latencyPublisher}.ObserveLatency(MetricOperationLatency, operationName(request), latency)
 	}
 
-	p := proto.NewBuffer(data)
-	response := &v1.Message{}
+	return message, server, err
+}
 
-	if err := p.DecodeMessage(response); err != nil {
-		return nil, err
+// Attempt an operation until it succeeds or may no longer be retried, returning the server of
+// the final attempt and the number of retries
+func (this *Protobuf) attemptOperation(request *v1.Message) (*v1.Message, string, int, error) {
+	region := requestRegion(request)
+	if region != "" && this.pinned == nil && this.IsOptionalRegion(region) {
+		return this.attemptOptional(request, region)
 	}
 
-	return response, nil
+	ctx := this.context()
+	deadline := this.operationDeadline(ctx, region, time.Now())
+
+	throttles := 0
+	failovers := 0
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, "", attempt - 1, err
+		}
+
+		message, server, err := this.attemptOnce(request, deadline)
+		if err != nil && this.pinned != nil {
+			// Retrying could only use the same connection, which has been discarded
+			return nil, server, attempt - 1, err
+		}
+		if this.throttle != nil {
+			if serverErr, ok := this.throttle.throttled(err); ok {
+				throttles++
+				guardedPublisher{this.pool.GetMetricsPublisher()}.AddKeyed(MetricThrottledAttempts, server, 1)
+				logTo(this.pool.GetLogger(), LogInfo, "operation throttled", "operation", operationName(request),
+					"server", server, "throttles", throttles)
+				if err := this.waitThrottled(ctx, deadline, throttles, serverErr); err != nil {
+					return nil, server, attempt - 1, err
+				}
+				continue
+			}
+			throttles = 0
+		}
+
+		if err == nil {
+			return message, server, attempt - 1, nil
+		}
+		retry, callbackErr := this.retryBudget.retryable(err)
+		if callbackErr != nil {
+			return nil, server, attempt - 1, callbackErr
+		}
+		if !retry && this.failover.allows(ctx, request, err, failovers) {
+			failovers++
+			retry = true
+			guardedPublisher{this.pool.GetMetricsPublisher()}.AddKeyed(MetricFailovers, operationName(request), 1)
+		}
+		if !retry {
+			return message, server, attempt - 1, err
+		}
+
+		if this.retryBudget == nil {
+			// Retrying cannot succeed once the deadline has passed
+			if !deadline.IsZero() && !time.Now().Before(deadline) {
+				return nil, server, attempt - 1, err
+			}
+			this.logRetry(request, attempt, server, err)
+			continue
+		}
+
+		if this.retryBudget.MaxRetries > 0 && attempt > this.retryBudget.MaxRetries {
+			return nil, "", attempt - 1, &RetryBudgetError{Attempts: attempt, Err: err}
+		}
+
+		backoff := this.retryBudget.Delay(attempt)
+		if !deadline.IsZero() && time.Until(deadline) <= backoff {
+			return nil, "", attempt - 1, &RetryBudgetError{Attempts: attempt, Err: err}
+		}
+		this.logRetry(request, attempt, server, err)
+
+		if backoff > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, "", attempt - 1, ctx.Err()
+			}
+		}
+	}
+}
+
+func (this *Protobuf) logRetry(request *v1.Message, attempt int, server string, err error) {
+	logTo(this.pool.GetLogger(), LogWarn, "retrying operation", "operation", operationName(request),
+		"region", requestRegion(request), "attempt", attempt, "server", server, "error", err)
 }
 
-func readRawMessage(connection net.Conn) ([]byte, error) {
-	data := make([]byte, 4096)
-	bytesRead, err := connection.Read(data)
+func (this *Protobuf) attemptOnce(request *v1.Message, deadline time.Time) (*v1.Message, string, error) {
+	if this.pinned == nil && this.pool.isMultiplexing() && sameServerOf(this.context()) == nil {
+		return this.attemptShared(request, deadline)
+	}
+
+	gConn, err := this.acquireConnection()
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+	defer this.returnConnection(gConn)
+
+	server := ""
+	if addr := gConn.rawConn.RemoteAddr(); addr != nil {
+		server = addr.String()
 	}
 
-	// Get the length of the message
-	m, n := proto.DecodeVarint(data)
-	messageLength := int(m) + n
+	ctx := this.context()
+	limits := this.pool.ioLimits(ctx, deadline)
 
-	if messageLength > len(data) {
-		t := make([]byte, len(data), messageLength)
-		copy(t, data)
-		data = t
+	if !deadline.IsZero() {
+		gConn.rawConn.SetDeadline(deadline)
+	}
+	if !deadline.IsZero() || limits.readTimeout > 0 || limits.writeTimeout > 0 {
+		defer gConn.rawConn.SetDeadline(time.Time{})
 	}
 
-	for bytesRead < messageLength {
-		n, err := io.ReadFull(connection, data[bytesRead:messageLength])
-		if err != nil {
-			return nil, err
-		}
+	// Interrupt the exchange if ctx is done before it completes
+	stop := context.AfterFunc(ctx, func() {
+		gConn.rawConn.SetDeadline(time.Unix(1, 0))
+	})
 
-		bytesRead += n
+	response, err := this.exchange(gConn, request, limits)
+	// If interrupted, the connection may have been left part way through a message
+	interrupted := !stop()
+	if err != nil && !interrupted && this.failover != nil && this.pinned == nil && isConnectionFailure(err) {
+		this.pool.quarantineServer(gConn, this.failover.quarantine())
+	}
+	if err != nil && interrupted {
+		err = ctx.Err()
+	} else if err == nil {
+		err = responseError(request, response)
+	}
+	this.pool.responseMismatched(gConn, err)
+	if err != nil || interrupted {
+		this.discardConnection(gConn)
+	}
+	if err != nil {
+		return nil, server, err
 	}
 
-	return data[0:bytesRead], nil
+	return response, server, nil
 }
 
-func EncodeValue(val interface{}) (*v1.EncodedValue, error) {
-	ev := &v1.EncodedValue{}
-
-	switch k := val.(type) {
-	case int:
-		ev.Value = &v1.EncodedValue_IntResult{int32(k)}
-	case int16:
-		ev.Value = &v1.EncodedValue_ShortResult{int32(k)}
-	case int32:
-		ev.Value = &v1.EncodedValue_IntResult{k}
-	case int64:
-		ev.Value = &v1.EncodedValue_LongResult{k}
-	case byte:
-		ev.Value = &v1.EncodedValue_ByteResult{int32(k)}
-	case bool:
-		ev.Value = &v1.EncodedValue_BooleanResult{k}
-	case float64:
-		ev.Value = &v1.EncodedValue_DoubleResult{k}
-	case float32:
-		ev.Value = &v1.EncodedValue_FloatResult{k}
-	case []byte:
-		ev.Value = &v1.EncodedValue_BinaryResult{k}
-	case string:
-		ev.Value = &v1.EncodedValue_StringResult{k}
-	default:
-		// <nil> is not a type
-		if k == nil {
-			ev.Value = &v1.EncodedValue_NullResult{}
-		} else {
-			// Assume we have some struct and want to turn it into JSON
-			j, err := json.Marshal(k)
-			if err != nil {
-				return nil, err
-			}
-			ev.Value = &v1.EncodedValue_JsonObjectResult{string(j)}
-		}
+// Exchange a request on a pooled connection, reporting the outcome to the pool's
+// FailureDetector. Only failures to communicate count against the server.
+func (this *Protobuf) exchange(gConn *GeodeConnection, request *v1.Message, limits ioLimits) (*v1.Message, error) {
+	defer this.timing.addNetwork(this.timing.now())
+
+	if gConn.provider == nil {
+		response, err := exchange(gConn.rawConn, request, limits)
+		this.countBytes(request, response)
+		return response, err
 	}
 
-	return ev, nil
+	clock := this.pool.GetClock()
+	start := clock.Now()
+	response, err := exchange(gConn.rawConn, request, limits)
+	// An exchange interrupted because ctx is done says nothing about the server
+	if err == nil || this.context().Err() == nil {
+		this.pool.reportOutcome(gConn, clock.Now()-start, err)
+	}
+	this.countBytes(request, response)
+
+	return response, err
 }
 
-func EncodeList(list interface{}) ([]*v1.EncodedValue, error) {
-	listSlice := reflect.ValueOf(list)
-	if listSlice.Kind() != reflect.Slice && listSlice.Kind() != reflect.Array {
-		return nil, errors.New("argument must be a slice or array")
+// Count the bytes of an exchange. A request whose response was not read may still have
+// been sent, and is counted.
+func (this *Protobuf) countBytes(request, response *v1.Message) {
+	publisher := guardedPublisher{this.pool.GetMetricsPublisher()}
+	publisher.Add(MetricBytesSent, int64(messageSize(request)))
+	if response != nil {
+		publisher.Add(MetricBytesReceived, int64(messageSize(response)))
 	}
+}
 
-	encodedEntries := make([]*v1.EncodedValue, 0, listSlice.Len())
-	for i := 0; i < listSlice.Len(); i++ {
-		key, err := EncodeValue(listSlice.Index(i).Interface())
-		if err != nil {
-			return nil, err
-		}
+// Return the size of a message on the wire, with its length prefix
+func messageSize(message *v1.Message) int {
+	n := proto.Size(message)
+	return proto.SizeVarint(uint64(n)) + n
+}
 
-		encodedEntries = append(encodedEntries, key)
+func doOperationWithConnection(connection net.Conn, request *v1.Message) (*v1.Message, error) {
+	response, err := exchange(connection, request, ioLimits{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := responseError(request, response); err != nil {
+		return nil, err
 	}
 
-	return encodedEntries, nil
+	return response, nil
 }
 
-func EncodeValueList(list interface{}) (*v1.EncodedValueList, error) {
-	encodedList, err := EncodeList(list)
+// Write a request and read its response
+func exchange(connection net.Conn, request *v1.Message, limits ioLimits) (*v1.Message, error) {
+	limited, err := limits.limit(connection.SetWriteDeadline, limits.writeTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	return &v1.EncodedValueList{Element: encodedList}, nil
-}
+	err = writeMessage(connection, request)
+	if err != nil {
+		return nil, timeoutError(err, limited)
+	}
 
-func EncodeTable(table map[string][]interface{}) (*v1.Table, error) {
-	columnNames := make([]string, len(table))
-	columns := make([]*v1.EncodedValueList, len(table))
+	limited, err = limits.limit(connection.SetReadDeadline, limits.readTimeout)
+	if err != nil {
+		return nil, err
+	}
 
-	idx := 0
-	for k, v := range table {
-		columnNames[idx] = k
-		list, err := EncodeValueList(v)
-		if err != nil {
-			return nil, err
+	// In the event that the client connection has timed out, it would have been closed by the server.
+	// This results in a FIN being sent to the client, however the prior write may appear to have succeeded
+	// even in light of the server side of the connection being closed. It is only on a subsequent read
+	// that an error will be detected. See Stevens pg 132, Section 5.13 SIGPIPE signal.
+	response, err := readResponse(connection)
+	if err != nil {
+		if err.Error() == "EOF" {
+			return nil, &RetryableError{err}
 		}
-		columns[idx] = list
-		idx += 1
+		return nil, timeoutError(mismatchedRead(request, err), limited)
 	}
 
-	result := &v1.Table{
-		FieldName: columnNames,
-		Row:       columns,
+	return response, nil
+}
+
+// Return the error carried by an error response from the server, or a ResponseMismatchError
+// if the response cannot answer the request.
+func responseError(request, response *v1.Message) error {
+	if x := response.GetErrorResponse(); x != nil {
+		return &ServerError{Code: x.GetError().GetErrorCode(), Message: x.GetError().GetMessage()}
 	}
 
-	return result, nil
+	return checkCorrelation(request, response)
 }
 
-func DecodeValue(value *v1.EncodedValue, ref interface{}) (interface{}, error) {
-	var decodedValue interface{}
-
-	switch v := value.GetValue().(type) {
-	case *v1.EncodedValue_IntResult:
-		decodedValue = v.IntResult
-	case *v1.EncodedValue_ShortResult:
-		decodedValue = v.ShortResult
-	case *v1.EncodedValue_LongResult:
-		decodedValue = v.LongResult
-	case *v1.EncodedValue_ByteResult:
-		// Protobuf seems to transmit bytes as int32
-		decodedValue = uint8(v.ByteResult)
-	case *v1.EncodedValue_BooleanResult:
-		decodedValue = v.BooleanResult
-	case *v1.EncodedValue_DoubleResult:
-		decodedValue = v.DoubleResult
-	case *v1.EncodedValue_FloatResult:
-		decodedValue = v.FloatResult
-	case *v1.EncodedValue_BinaryResult:
-		decodedValue = v.BinaryResult
-	case *v1.EncodedValue_StringResult:
-		decodedValue = v.StringResult
-	case *v1.EncodedValue_JsonObjectResult:
-		err := json.Unmarshal([]byte(v.JsonObjectResult), ref)
-		if err != nil {
-			return nil, err
+func writeMessage(connection net.Conn, message proto.Message) (err error) {
+	err = codec.WriteMessage(connection, message)
+	if err != nil {
+		switch nerr := err.(type) {
+		case *net.OpError:
+			if nerr.Op == "write" {
+				return &RetryableError{err}
+			}
 		}
-		decodedValue = ref
-	case *v1.EncodedValue_NullResult, nil:
-		decodedValue = nil
-	default:
-		return nil, errors.New(fmt.Sprintf("unable to decode type: %T", v))
+		return err
 	}
 
-	return decodedValue, nil
+	return nil
 }
 
-func DecodeValueList(list *v1.EncodedValueList, ref interface{}) ([]interface{}, error) {
-	decodedValueList := make([]interface{}, len(list.GetElement()))
+func readResponse(connection net.Conn) (*v1.Message, error) {
+	return codec.ReadMessage(connection)
+}
 
-	for i, v := range list.GetElement() {
-		val, err := DecodeValue(v, ref)
-		if err != nil {
-			return nil, err
-		}
+// EncodeValue is codec.EncodeValue, kept for existing callers.
+func EncodeValue(val interface{}) (*v1.EncodedValue, error) {
+	return codec.EncodeValue(val)
+}
 
-		decodedValueList[i] = val
-	}
+// EncodeList is codec.EncodeList, kept for existing callers.
+func EncodeList(list interface{}) ([]*v1.EncodedValue, error) {
+	return codec.EncodeList(list)
+}
+
+// EncodeValueList is codec.EncodeValueList, kept for existing callers.
+func EncodeValueList(list interface{}) (*v1.EncodedValueList, error) {
+	return codec.EncodeValueList(list)
+}
 
-	return decodedValueList, nil
+// EncodeTable is codec.EncodeTable, kept for existing callers.
+func EncodeTable(table map[string][]interface{}) (*v1.Table, error) {
+	return codec.EncodeTable(table)
+}
+
+// DecodeValue is codec.DecodeValue, kept for existing callers.
+func DecodeValue(value *v1.EncodedValue, ref interface{}) (interface{}, error) {
+	return codec.DecodeValue(value, ref)
+}
+
+// DecodeValueList is codec.DecodeValueList, kept for existing callers.
+func DecodeValueList(list *v1.EncodedValueList, ref interface{}) ([]interface{}, error) {
+	return codec.DecodeValueList(list, ref)
 }
@@ -1,6 +1,7 @@
 package connector_test
 
 import (
+	"github.com/gemfire/geode-go-client/codec"
 	"github.com/gemfire/geode-go-client/connector"
 	"github.com/gemfire/geode-go-client/connector/connectorfakes"
 	"github.com/gemfire/geode-go-client/protobuf"
@@ -12,6 +13,7 @@ import (
 	"strconv"
 	"github.com/gemfire/geode-go-client/query"
 	"errors"
+	"sync/atomic"
 )
 
 //go:generate counterfeiter net.Conn
@@ -344,6 +346,24 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Context("Raw values", func() {
+		It("passes encoded values through untouched", func() {
+			store := newFakeStore(fakeConn)
+
+			key, _ := connector.EncodeValue("A")
+			raw := &v1.EncodedValue{
+				Value: &v1.EncodedValue_CustomObjectResult{CustomObjectResult: []byte{1, 2, 3}},
+			}
+
+			Expect(connection.PutRaw("foo", key, raw)).To(BeNil())
+			Expect(store.value.GetCustomObjectResult()).To(Equal([]byte{1, 2, 3}))
+
+			result, err := connection.GetRaw("foo", key)
+			Expect(err).To(BeNil())
+			Expect(result.GetCustomObjectResult()).To(Equal([]byte{1, 2, 3}))
+		})
+	})
+
 	Context("PutAll", func() {
 		It("encodes values correctly", func() {
 			fakeConn.ReadStub = func(b []byte) (int, error) {
@@ -399,6 +419,34 @@ var _ = Describe("Client", func() {
 			Expect(failures[int32(77)]).NotTo(BeNil())
 			Expect(failures[int32(77)].Error()).To(Equal("test error (1)"))
 		})
+
+		It("writes entries in order of their keys when encoding is deterministic", func() {
+			codec.SetDeterministic(true)
+			defer codec.SetDeterministic(false)
+
+			fakeConn.ReadStub = func(b []byte) (int, error) {
+				return writeFakeMessage(&v1.Message{
+					MessageType: &v1.Message_PutAllResponse{PutAllResponse: &v1.PutAllResponse{}},
+				}, b)
+			}
+
+			entries := make(map[string]int)
+			for i := 9; i >= 0; i-- {
+				entries["k"+strconv.Itoa(i)] = i
+			}
+
+			_, err := connection.PutAll("foo", entries)
+			Expect(err).To(BeNil())
+			_, err = connection.PutAll("foo", entries)
+			Expect(err).To(BeNil())
+
+			Expect(fakeConn.WriteArgsForCall(1)).To(Equal(fakeConn.WriteArgsForCall(0)))
+			request, err := codec.UnmarshalMessage(fakeConn.WriteArgsForCall(0))
+			Expect(err).To(BeNil())
+			for i, entry := range request.GetPutAllRequest().GetEntry() {
+				Expect(entry.GetKey().GetStringResult()).To(Equal("k" + strconv.Itoa(i)))
+			}
+		})
 	})
 
 	Context("GetAll", func() {
@@ -501,6 +549,100 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Context("RemoveAll", func() {
+		It("removes each key", func() {
+			// Removals wait for the one connection rather than failing to open others
+			pool.SetMaxConnections(1)
+			var removed []interface{}
+			fakeConn.WriteStub = func(b []byte) (int, error) {
+				message := &v1.Message{}
+				if err := proto.NewBuffer(b).DecodeMessage(message); err != nil {
+					return 0, err
+				}
+				key, err := connector.DecodeValue(message.GetRemoveRequest().Key, nil)
+				if err != nil {
+					return 0, err
+				}
+				removed = append(removed, key)
+				return len(b), nil
+			}
+			fakeConn.ReadStub = func(b []byte) (int, error) {
+				return writeFakeMessage(&v1.Message{
+					MessageType: &v1.Message_RemoveResponse{RemoveResponse: &v1.RemoveResponse{}},
+				}, b)
+			}
+
+			failures, err := connection.RemoveAll("foo", []string{"A", "B"})
+			Expect(err).To(BeNil())
+			Expect(failures).To(BeNil())
+			Expect(removed).To(ConsistOf("A", "B"))
+		})
+
+		It("reports the keys which could not be removed", func() {
+			fakeConn.ReadStub = func(b []byte) (int, error) {
+				return writeFakeMessage(&v1.Message{
+					MessageType: &v1.Message_ErrorResponse{
+						ErrorResponse: &v1.ErrorResponse{
+							Error: &v1.Error{ErrorCode: 1, Message: "error from fake"},
+						},
+					},
+				}, b)
+			}
+
+			failures, err := connection.RemoveAll("foo", []string{"A"})
+			Expect(err).To(BeNil())
+			Expect(failures).To(HaveLen(1))
+			Expect(failures["A"]).To(MatchError("error from fake (1)"))
+		})
+
+		It("removes the other keys when some fail", func() {
+			// A failed removal discards its connection, so give each removal one of its own
+			var written int32
+			for i := 0; i < 12; i++ {
+				conn := new(connectorfakes.FakeConn)
+				var key interface{}
+				conn.WriteStub = func(b []byte) (int, error) {
+					atomic.AddInt32(&written, 1)
+					message := &v1.Message{}
+					if err := proto.NewBuffer(b).DecodeMessage(message); err != nil {
+						return 0, err
+					}
+					key, _ = connector.DecodeValue(message.GetRemoveRequest().Key, nil)
+					return len(b), nil
+				}
+				conn.ReadStub = func(b []byte) (int, error) {
+					if key == "B" || key == "D" {
+						return writeFakeMessage(&v1.Message{
+							MessageType: &v1.Message_ErrorResponse{
+								ErrorResponse: &v1.ErrorResponse{
+									Error: &v1.Error{ErrorCode: 1, Message: "error from fake"},
+								},
+							},
+						}, b)
+					}
+					return writeFakeMessage(&v1.Message{
+						MessageType: &v1.Message_RemoveResponse{RemoveResponse: &v1.RemoveResponse{}},
+					}, b)
+				}
+				pool.AddConnection(conn, true)
+			}
+
+			keys := []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J"}
+			failures, err := connection.RemoveAll("foo", keys)
+			Expect(err).To(BeNil())
+			Expect(failures).To(HaveLen(2))
+			Expect(failures["B"]).To(MatchError("error from fake (1)"))
+			Expect(failures["D"]).To(MatchError("error from fake (1)"))
+			Expect(atomic.LoadInt32(&written)).To(Equal(int32(len(keys))))
+		})
+
+		It("rejects keys which are not a slice or array", func() {
+			_, err := connection.RemoveAll("foo", "A")
+			Expect(err).To(MatchError("keys must be a slice or array"))
+			Expect(fakeConn.WriteCallCount()).To(Equal(0))
+		})
+	})
+
 	Context("Size", func() {
 		It("returns the correct region size", func() {
 			fakeConn.ReadStub = func(b []byte) (int, error) {
@@ -522,6 +664,44 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Context("RegionNames", func() {
+		It("returns the region names", func() {
+			fakeConn.ReadStub = func(b []byte) (int, error) {
+				return writeFakeMessage(&v1.Message{
+					MessageType: &v1.Message_GetRegionNamesResponse{
+						GetRegionNamesResponse: &v1.GetRegionNamesResponse{Regions: []string{"foo", "bar"}},
+					},
+				}, b)
+			}
+
+			names, err := connection.RegionNames()
+			Expect(err).To(BeNil())
+			Expect(names).To(Equal([]string{"foo", "bar"}))
+		})
+	})
+
+	Context("Clear", func() {
+		It("clears the region", func() {
+			var request *v1.ClearRequest
+			fakeConn.WriteStub = func(b []byte) (int, error) {
+				message := &v1.Message{}
+				if err := proto.NewBuffer(b).DecodeMessage(message); err != nil {
+					return 0, err
+				}
+				request = message.GetClearRequest()
+				return len(b), nil
+			}
+			fakeConn.ReadStub = func(b []byte) (int, error) {
+				return writeFakeMessage(&v1.Message{
+					MessageType: &v1.Message_ClearResponse{ClearResponse: &v1.ClearResponse{}},
+				}, b)
+			}
+
+			Expect(connection.Clear("foo")).To(BeNil())
+			Expect(request.RegionName).To(Equal("foo"))
+		})
+	})
+
 	Context("Function", func() {
 		It("processes onRegion function arguments correctly", func() {
 			fakeConn.ReadStub = func(b []byte) (int, error) {
@@ -548,6 +728,78 @@ var _ = Describe("Client", func() {
 			Expect(result[1]).To(Equal("Hello World"))
 		})
 
+		It("passes onRegion function results to a function until it returns false", func() {
+			fakeConn.ReadStub = func(b []byte) (int, error) {
+				v_1, _ := connector.EncodeValue(777)
+				v_2, _ := connector.EncodeValue("Hello World")
+				return writeFakeMessage(&v1.Message{
+					MessageType: &v1.Message_ExecuteFunctionOnRegionResponse{
+						ExecuteFunctionOnRegionResponse: &v1.ExecuteFunctionOnRegionResponse{
+							Results: []*v1.EncodedValue{v_1, v_2},
+						},
+					},
+				}, b)
+			}
+
+			var results []interface{}
+			err := connection.ExecuteOnRegionFunc("foo", "bar", nil, nil, func(result interface{}) bool {
+				results = append(results, result)
+				return false
+			})
+
+			Expect(err).To(BeNil())
+			Expect(results).To(Equal([]interface{}{int32(777)}))
+		})
+
+		It("passes onMember function results to a function", func() {
+			fakeConn.ReadStub = func(b []byte) (int, error) {
+				v_1, _ := connector.EncodeValue("A")
+				v_2, _ := connector.EncodeValue("B")
+				return writeFakeMessage(&v1.Message{
+					MessageType: &v1.Message_ExecuteFunctionOnMemberResponse{
+						ExecuteFunctionOnMemberResponse: &v1.ExecuteFunctionOnMemberResponse{
+							Results: []*v1.EncodedValue{v_1, v_2},
+						},
+					},
+				}, b)
+			}
+
+			var results []interface{}
+			err := connection.ExecuteOnMembersFunc("foo", []string{"server1"}, nil, func(result interface{}) bool {
+				results = append(results, result)
+				return true
+			})
+
+			Expect(err).To(BeNil())
+			Expect(results).To(Equal([]interface{}{"A", "B"}))
+		})
+
+		It("sends the key filter of onRegion functions", func() {
+			var request *v1.ExecuteFunctionOnRegionRequest
+			fakeConn.WriteStub = func(b []byte) (int, error) {
+				message := &v1.Message{}
+				if err := proto.NewBuffer(b).DecodeMessage(message); err != nil {
+					return 0, err
+				}
+				request = message.GetExecuteFunctionOnRegionRequest()
+				return len(b), nil
+			}
+			fakeConn.ReadStub = func(b []byte) (int, error) {
+				return writeFakeMessage(&v1.Message{
+					MessageType: &v1.Message_ExecuteFunctionOnRegionResponse{
+						ExecuteFunctionOnRegionResponse: &v1.ExecuteFunctionOnRegionResponse{},
+					},
+				}, b)
+			}
+
+			_, err := connection.ExecuteOnRegion("foo", "bar", nil, []interface{}{"A", codec.RoutedKey{Routing: "customer-7", Key: "order-12"}})
+
+			Expect(err).To(BeNil())
+			Expect(request.KeyFilter).To(HaveLen(2))
+			Expect(request.KeyFilter[0].GetStringResult()).To(Equal("A"))
+			Expect(request.KeyFilter[1].GetStringResult()).To(Equal("customer-7|order-12"))
+		})
+
 		It("processes onMember function arguments correctly", func() {
 			fakeConn.ReadStub = func(b []byte) (int, error) {
 				v_1, _ := connector.EncodeValue(777)
@@ -690,6 +942,46 @@ var _ = Describe("Client", func() {
 			Expect(result[0]).To(Equal(one))
 			Expect(result[1]).To(Equal("hey"))
 		})
+
+		It("traces the query when asked to", func() {
+			var sent []string
+
+			fakeConn.ReadStub = func(b []byte) (int, error) {
+				v, _ := connector.EncodeValueList([]interface{}{"a", "b"})
+				response := &v1.Message{
+					MessageType: &v1.Message_OqlQueryResponse{
+						OqlQueryResponse: &v1.OQLQueryResponse{
+							Result: &v1.OQLQueryResponse_ListResult{
+								ListResult: v,
+							},
+						},
+					},
+				}
+				return writeFakeMessage(response, b)
+			}
+
+			fakeConn.WriteStub = func(b []byte) (int, error) {
+				request := &v1.Message{}
+				if err := proto.NewBuffer(b).DecodeMessage(request); err != nil {
+					return 0, err
+				}
+				sent = append(sent, request.GetOqlQueryRequest().GetQuery())
+				return len(b), nil
+			}
+
+			q := query.NewQuery("select * from /foo")
+			_, err := connection.QueryListResult(q)
+			Expect(err).To(BeNil())
+			Expect(q.LastTrace).To(BeNil())
+
+			q.Trace = true
+			_, err = connection.QueryListResult(q)
+			Expect(err).To(BeNil())
+			Expect(sent).To(Equal([]string{"select * from /foo", "<trace> select * from /foo"}))
+			Expect(q.LastTrace).ToNot(BeNil())
+			Expect(q.LastTrace.Results).To(Equal(2))
+			Expect(q.LastTrace.Elapsed).To(BeNumerically(">=", 0))
+		})
 	})
 
 	Context("Query for a list result", func() {
@@ -786,6 +1078,28 @@ var _ = Describe("Client", func() {
 			Expect(result["0"][0]).To(Equal(one))
 			Expect(result["1"][0]).To(Equal("hey"))
 		})
+
+		It("decodes each JSON value of a table into its own reference", func() {
+			fakeConn.ReadStub = func(b []byte) (int, error) {
+				table, _ := connector.EncodeTable(map[string][]interface{}{
+					"t": {&TestStruct{Value: 1}, &TestStruct{Value: 2}},
+				})
+				return writeFakeMessage(&v1.Message{
+					MessageType: &v1.Message_OqlQueryResponse{
+						OqlQueryResponse: &v1.OQLQueryResponse{
+							Result: &v1.OQLQueryResponse_TableResult{TableResult: table},
+						},
+					},
+				}, b)
+			}
+
+			q := query.NewQuery("select t from /foo t")
+			q.Reference = &TestStruct{}
+			result, err := connection.QueryTableResult(q)
+
+			Expect(err).To(BeNil())
+			Expect(result["t"]).To(Equal([]interface{}{&TestStruct{Value: 1}, &TestStruct{Value: 2}}))
+		})
 	})
 })
 
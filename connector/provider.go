@@ -0,0 +1,68 @@
+package connector
+
+import (
+	"net"
+)
+
+// NewGeodeConnection wraps a connection opened by a ConnectionProvider for its pool. The pool
+// handshakes it before it is used.
+func NewGeodeConnection(conn net.Conn) *GeodeConnection {
+	return &GeodeConnection{
+		rawConn:            conn,
+		handshakeDone:      false,
+		authenticationDone: false,
+		inUse:              false,
+	}
+}
+
+// AddProvider adds a provider of connections to the pool, alongside any servers. As with
+// AddServer, providers added later are tried first, and partitions share the pool's
+// providers.
+func (this *Pool) AddProvider(provider ConnectionProvider) {
+	this.Lock()
+	defer this.Unlock()
+
+	before := this.providers
+	this.providers = append(this.providers, provider)
+	this.publishServerChanges(before, this.providers)
+	this.syncPartitions()
+}
+
+// RemoveProvider removes a provider added with AddProvider, closing the idle connections it
+// opened, and then closes the provider. Connections in use are closed when they are
+// returned. The provider's Close error is returned.
+func (this *Pool) RemoveProvider(provider ConnectionProvider) (err error) {
+	this.Lock()
+	if !this.hasProvider(provider) {
+		this.Unlock()
+		return nil
+	}
+
+	before := this.providers
+	this.providers = make([]ConnectionProvider, 0, len(before)-1)
+	for _, p := range before {
+		if p != provider {
+			this.providers = append(this.providers, p)
+		}
+	}
+	this.publishServerChanges(before, this.providers)
+	this.discardRemovedConnections()
+	this.syncPartitions()
+	this.Unlock()
+
+	defer RecoverCallback("ConnectionProvider", &err)
+	return provider.Close()
+}
+
+// Ask a provider whether it should be used, treating a panic as unhealthy
+func providerHealthy(provider ConnectionProvider) (healthy bool) {
+	var err error
+	defer func() {
+		if err != nil {
+			healthy = false
+		}
+	}()
+	defer RecoverCallback("ConnectionProvider", &err)
+
+	return provider.Healthy()
+}
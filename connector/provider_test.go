@@ -0,0 +1,142 @@
+package connector_test
+
+import (
+	"errors"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	"github.com/gemfire/geode-go-client/protobuf"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// A ConnectionProvider whose connections accept the handshake
+type fakeProvider struct {
+	name     string
+	healthy  bool
+	closeErr error
+	opened   []*connectorfakes.FakeConn
+	closed   int
+}
+
+func (this *fakeProvider) GetGeodeConnection() *connector.GeodeConnection {
+	conn := new(connectorfakes.FakeConn)
+	conn.ReadStub = func(b []byte) (int, error) {
+		return writeFakeMessage(&org_apache_geode_internal_protocol_protobuf.VersionAcknowledgement{
+			VersionAccepted: true,
+		}, b)
+	}
+	this.opened = append(this.opened, conn)
+
+	return connector.NewGeodeConnection(conn)
+}
+
+func (this *fakeProvider) Healthy() bool {
+	return this.healthy
+}
+
+func (this *fakeProvider) Close() error {
+	this.closed++
+	return this.closeErr
+}
+
+func (this *fakeProvider) String() string {
+	return this.name
+}
+
+var _ = Describe("ConnectionProvider", func() {
+
+	var pool *connector.Pool
+	var provider *fakeProvider
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+		provider = &fakeProvider{name: "mesh:orders", healthy: true}
+	})
+
+	It("opens and handshakes connections from an added provider", func() {
+		pool.AddProvider(provider)
+
+		gConn, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		Expect(provider.opened).To(HaveLen(1))
+		Expect(gConn.GetRawConnection()).To(Equal(provider.opened[0]))
+		Expect(provider.opened[0].WriteCallCount()).To(Equal(1))
+	})
+
+	It("skips unhealthy providers", func() {
+		unhealthy := &fakeProvider{name: "mesh:draining"}
+		pool.AddProvider(provider)
+		pool.AddProvider(unhealthy)
+
+		_, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		Expect(unhealthy.opened).To(BeEmpty())
+		Expect(provider.opened).To(HaveLen(1))
+
+		pool.CheckHealth()
+		Expect(unhealthy.opened).To(BeEmpty())
+	})
+
+	It("treats a panic in Healthy as unhealthy", func() {
+		pool.AddProvider(&panickingProvider{})
+
+		_, err := pool.GetConnection()
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("identifies providers by String", func() {
+		events, cancel := pool.ClusterEvents(10)
+		defer cancel()
+
+		pool.AddProvider(provider)
+		Expect(<-events).To(Equal(connector.ClusterEvent{Type: connector.ServerAdded, Server: "mesh:orders"}))
+
+		Expect(pool.RemoveProvider(provider)).To(Succeed())
+		Expect(<-events).To(Equal(connector.ClusterEvent{Type: connector.ServerRemoved, Server: "mesh:orders"}))
+	})
+
+	It("closes a removed provider and its idle connections", func() {
+		pool.AddProvider(provider)
+		gConn, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		pool.ReturnConnection(gConn)
+
+		provider.closeErr = errors.New("watch already stopped")
+		Expect(pool.RemoveProvider(provider)).To(MatchError("watch already stopped"))
+		Expect(provider.closed).To(Equal(1))
+		Expect(provider.opened[0].CloseCallCount()).To(Equal(1))
+
+		_, err = pool.GetConnection()
+		Expect(err).ToNot(BeNil())
+		Expect(provider.opened).To(HaveLen(1))
+
+		Expect(pool.RemoveProvider(provider)).To(Succeed())
+		Expect(provider.closed).To(Equal(1))
+	})
+
+	It("keeps added providers when the servers are configured", func() {
+		pool.AddProvider(provider)
+		Expect(pool.Configure(&connector.Config{Servers: []string{"localhost:1"}})).To(BeNil())
+
+		_, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		Expect(provider.opened).To(HaveLen(1))
+	})
+
+	It("is shared with partitions", func() {
+		pool.AddProvider(provider)
+
+		_, err := pool.Partition("reports").GetConnection()
+		Expect(err).To(BeNil())
+		Expect(provider.opened).To(HaveLen(1))
+	})
+})
+
+type panickingProvider struct {
+	fakeProvider
+}
+
+func (this *panickingProvider) Healthy() bool {
+	panic("registry unreachable")
+}
@@ -0,0 +1,47 @@
+package connector
+
+import (
+	"context"
+	"sync"
+)
+
+// Limits the number of queries in progress, shared by the copies of a connector
+type queryGate struct {
+	sync.Mutex
+	// Holds a token for each query in progress, nil when queries are not limited
+	slots chan struct{}
+}
+
+// SetQueryConcurrency limits the number of OQL queries this connector, and the copies made
+// with WithContext and similar, runs at once. Further queries wait for one to finish, or for
+// their context to be done, without holding a connection, so that a burst of expensive
+// queries cannot take every connection from the pool and starve other operations. A limit of
+// 0, the default, is unlimited. Queries already in progress are not affected by a change.
+func (this *Protobuf) SetQueryConcurrency(max int) {
+	this.queries.Lock()
+	defer this.queries.Unlock()
+
+	if max <= 0 {
+		this.queries.slots = nil
+		return
+	}
+	this.queries.slots = make(chan struct{}, max)
+}
+
+// Wait for a query to be allowed to run, returning the function to call when it finishes
+func (this *queryGate) enter(ctx context.Context) (func(), error) {
+	this.Lock()
+	slots := this.slots
+	this.Unlock()
+
+	if slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,123 @@
+package connector_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/gemfire/geode-go-client/query"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Query concurrency", func() {
+
+	var connection *connector.Protobuf
+	var release chan struct{}
+	var queries chan string
+
+	// A connection which answers Puts at once and holds queries until released
+	addConnection := func(pool *connector.Pool) {
+		fakeConn := new(connectorfakes.FakeConn)
+		requests := make(chan *v1.Message, 1)
+
+		fakeConn.WriteStub = func(b []byte) (int, error) {
+			request := &v1.Message{}
+			if err := proto.NewBuffer(b).DecodeMessage(request); err != nil {
+				return 0, err
+			}
+			requests <- request
+			return len(b), nil
+		}
+
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			request := <-requests
+			if q := request.GetOqlQueryRequest(); q != nil {
+				queries <- q.GetQuery()
+				<-release
+				v, _ := connector.EncodeValue(int32(1))
+				return writeFakeMessage(&v1.Message{
+					MessageType: &v1.Message_OqlQueryResponse{
+						OqlQueryResponse: &v1.OQLQueryResponse{
+							Result: &v1.OQLQueryResponse_SingleResult{SingleResult: v},
+						},
+					},
+				}, b)
+			}
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}},
+			}, b)
+		}
+
+		pool.AddConnection(fakeConn, true)
+	}
+
+	BeforeEach(func() {
+		release = make(chan struct{})
+		queries = make(chan string, 10)
+
+		pool := connector.NewPool()
+		for i := 0; i < 3; i++ {
+			addConnection(pool)
+		}
+		connection = connector.NewConnector(pool)
+		connection.SetQueryConcurrency(1)
+	})
+
+	It("holds queries beyond the limit without holding other operations", func() {
+		done := make(chan error, 1)
+		go func() {
+			_, err := connection.QuerySingleResult(query.NewQuery("select 1"))
+			done <- err
+		}()
+		Eventually(queries).Should(Receive(Equal("select 1")))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := connection.WithContext(ctx).QuerySingleResult(query.NewQuery("select 2"))
+		Expect(err).To(Equal(context.DeadlineExceeded))
+		Expect(queries).ToNot(Receive())
+
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+
+		close(release)
+		Eventually(done).Should(Receive(BeNil()))
+
+		_, err = connection.QuerySingleResult(query.NewQuery("select 3"))
+		Expect(err).To(BeNil())
+		Expect(queries).To(Receive(Equal("select 3")))
+	})
+
+	It("runs queries waiting for the limit once one finishes", func() {
+		done := make(chan error, 2)
+		for _, q := range []string{"select 1", "select 2"} {
+			go func(q string) {
+				_, err := connection.QuerySingleResult(query.NewQuery(q))
+				done <- err
+			}(q)
+		}
+
+		Eventually(queries).Should(Receive())
+		Consistently(queries, 20*time.Millisecond).ShouldNot(Receive())
+
+		close(release)
+		Eventually(queries).Should(Receive())
+		Eventually(done).Should(Receive(BeNil()))
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	It("does not limit queries when the limit is removed", func() {
+		connection.SetQueryConcurrency(0)
+
+		for _, q := range []string{"select 1", "select 2"} {
+			go connection.QuerySingleResult(query.NewQuery(q))
+		}
+
+		Eventually(queries).Should(Receive())
+		Eventually(queries).Should(Receive())
+		close(release)
+	})
+})
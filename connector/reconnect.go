@@ -0,0 +1,154 @@
+package connector
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// A ReconnectPolicy limits the rate at which a Pool opens connections, so that when a cluster
+// restarts, many clients reconnecting at once do not overwhelm it. It applies to a pool and
+// its partitions together.
+type ReconnectPolicy struct {
+	// Connections opened per second, allowing bursts of up to Burst. 0 is unlimited.
+	Rate  float64
+	Burst int
+	// Once the pool has lost all its connections, the first attempt to reconnect waits for a
+	// random time of up to Jitter, so that clients which lost their connections together
+	// spread out.
+	Jitter time.Duration
+	// After reconnecting, Rate grows from a tenth of its value to its full value over RampUp,
+	// so that the cluster is not immediately asked for every connection it had before.
+	RampUp time.Duration
+}
+
+// The state of a ReconnectPolicy, shared by a pool and its partitions
+type reconnectGate struct {
+	sync.Mutex
+	policy ReconnectPolicy
+	tokens float64
+	// Clock reading when the tokens were last topped up
+	filled time.Duration
+	// Whether the pool has ever had a connection, and so is reconnecting when it has none
+	connected    bool
+	reconnecting bool
+	reconnectAt  time.Duration
+	rampStart    time.Duration
+	ramping      bool
+}
+
+// SetReconnectPolicy limits the rate at which connections are opened. A nil policy, the
+// default, opens connections whenever they are needed. Callers needing a new connection wait
+// for the policy to allow one, or until their context is done; idle connections are used
+// without waiting.
+func (this *Pool) SetReconnectPolicy(policy *ReconnectPolicy) {
+	this.Lock()
+	defer this.Unlock()
+
+	if policy == nil {
+		this.reconnect = nil
+	} else {
+		burst := policy.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		this.reconnect = &reconnectGate{policy: *policy, tokens: float64(burst), filled: this.currentClock().Now()}
+	}
+	this.syncPartitions()
+}
+
+// Return how long to wait before opening a connection, or 0 if one may be opened now or none
+// is needed. A connection which may be opened is counted against the rate.
+// MUST hold the pool lock when calling
+func (this *Pool) reconnectDelay() time.Duration {
+	if this.reconnect == nil {
+		return 0
+	}
+	for _, c := range this.recentConnections {
+		if !c.inUse {
+			return 0
+		}
+	}
+	if this.maxConnections > 0 && len(this.recentConnections) >= this.maxConnections {
+		return 0
+	}
+
+	return this.reconnect.delay(this.currentClock().Now(), len(this.recentConnections) == 0)
+}
+
+// Record that a connection was opened
+// MUST hold the pool lock when calling
+func (this *Pool) connectionOpened() {
+	if this.reconnect != nil {
+		this.reconnect.opened(this.currentClock().Now())
+	}
+}
+
+func (this *reconnectGate) delay(now time.Duration, disconnected bool) time.Duration {
+	this.Lock()
+	defer this.Unlock()
+
+	if disconnected && this.connected && !this.reconnecting {
+		this.reconnecting = true
+		this.reconnectAt = now
+		if this.policy.Jitter > 0 {
+			this.reconnectAt += time.Duration(rand.Int63n(int64(this.policy.Jitter)))
+		}
+	}
+	if this.reconnecting && now < this.reconnectAt {
+		return this.reconnectAt - now
+	}
+
+	if this.policy.Rate <= 0 {
+		return 0
+	}
+
+	rate := this.rate(now)
+	burst := float64(this.policy.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	if elapsed := now - this.filled; elapsed > 0 {
+		this.tokens += rate * elapsed.Seconds()
+		if this.tokens > burst {
+			this.tokens = burst
+		}
+	}
+	this.filled = now
+
+	if this.tokens >= 1 {
+		this.tokens--
+		return 0
+	}
+	return time.Duration((1 - this.tokens) / rate * float64(time.Second))
+}
+
+// Return the rate at which connections may be opened, ramping up after reconnecting
+// MUST hold the gate lock when calling
+func (this *reconnectGate) rate(now time.Duration) float64 {
+	if !this.ramping || this.policy.RampUp <= 0 {
+		return this.policy.Rate
+	}
+
+	fraction := float64(now-this.rampStart) / float64(this.policy.RampUp)
+	if fraction >= 1 {
+		this.ramping = false
+		return this.policy.Rate
+	}
+	if fraction < 0.1 {
+		fraction = 0.1
+	}
+	return this.policy.Rate * fraction
+}
+
+func (this *reconnectGate) opened(now time.Duration) {
+	this.Lock()
+	defer this.Unlock()
+
+	if this.reconnecting {
+		this.reconnecting = false
+		this.ramping = true
+		this.rampStart = now
+	}
+	this.connected = true
+}
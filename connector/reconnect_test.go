@@ -0,0 +1,144 @@
+package connector_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reconnect policy", func() {
+
+	var pool *connector.Pool
+	var clock *manualClock
+	var server *handshakeServer
+
+	acquire := func() (*connector.GeodeConnection, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		return pool.AcquireConnection(ctx, 0)
+	}
+
+	BeforeEach(func() {
+		server = newHandshakeServer()
+		pool = connector.NewPool()
+		clock = &manualClock{}
+		pool.SetClock(clock)
+		Expect(pool.Configure(&connector.Config{Servers: []string{server.address()}})).To(BeNil())
+	})
+
+	AfterEach(func() {
+		server.listener.Close()
+	})
+
+	It("limits the rate at which connections are opened", func() {
+		pool.SetReconnectPolicy(&connector.ReconnectPolicy{Rate: 1})
+
+		first, err := acquire()
+		Expect(err).To(BeNil())
+
+		_, err = acquire()
+		Expect(err).To(Equal(context.DeadlineExceeded))
+
+		clock.now += time.Second
+		second, err := acquire()
+		Expect(err).To(BeNil())
+
+		pool.ReturnConnection(first)
+		pool.ReturnConnection(second)
+	})
+
+	It("uses idle connections without waiting", func() {
+		pool.SetReconnectPolicy(&connector.ReconnectPolicy{Rate: 1})
+
+		c, err := acquire()
+		Expect(err).To(BeNil())
+		pool.ReturnConnection(c)
+
+		c, err = acquire()
+		Expect(err).To(BeNil())
+		pool.ReturnConnection(c)
+	})
+
+	It("allows bursts", func() {
+		pool.SetReconnectPolicy(&connector.ReconnectPolicy{Rate: 1, Burst: 2})
+
+		first, err := acquire()
+		Expect(err).To(BeNil())
+		second, err := acquire()
+		Expect(err).To(BeNil())
+
+		_, err = acquire()
+		Expect(err).To(Equal(context.DeadlineExceeded))
+
+		pool.ReturnConnection(first)
+		pool.ReturnConnection(second)
+	})
+
+	It("waits for a random time before reconnecting after losing every connection", func() {
+		pool.SetReconnectPolicy(&connector.ReconnectPolicy{Jitter: time.Minute})
+
+		c, err := acquire()
+		Expect(err).To(BeNil())
+		pool.DiscardConnection(c)
+
+		_, err = acquire()
+		Expect(err).To(Equal(context.DeadlineExceeded))
+
+		clock.now += time.Minute
+		c, err = acquire()
+		Expect(err).To(BeNil())
+		pool.ReturnConnection(c)
+	})
+
+	It("ramps up the rate after reconnecting", func() {
+		pool.SetReconnectPolicy(&connector.ReconnectPolicy{Rate: 10, RampUp: 10 * time.Second})
+
+		c, err := acquire()
+		Expect(err).To(BeNil())
+		pool.DiscardConnection(c)
+
+		clock.now = 10 * time.Second
+		first, err := acquire()
+		Expect(err).To(BeNil())
+
+		// A tenth of the rate, so a connection every second
+		clock.now += 100 * time.Millisecond
+		_, err = acquire()
+		Expect(err).To(Equal(context.DeadlineExceeded))
+
+		clock.now += time.Second
+		second, err := acquire()
+		Expect(err).To(BeNil())
+
+		pool.ReturnConnection(first)
+		pool.ReturnConnection(second)
+	})
+
+	It("is shared with partitions", func() {
+		pool.SetReconnectPolicy(&connector.ReconnectPolicy{Rate: 1})
+
+		c, err := pool.Partition("bulk").GetConnection()
+		Expect(err).To(BeNil())
+
+		_, err = acquire()
+		Expect(err).To(Equal(context.DeadlineExceeded))
+
+		pool.Partition("bulk").ReturnConnection(c)
+	})
+
+	It("can be removed", func() {
+		pool.SetReconnectPolicy(&connector.ReconnectPolicy{Rate: 1})
+		pool.SetReconnectPolicy(nil)
+
+		first, err := acquire()
+		Expect(err).To(BeNil())
+		second, err := acquire()
+		Expect(err).To(BeNil())
+
+		pool.ReturnConnection(first)
+		pool.ReturnConnection(second)
+	})
+})
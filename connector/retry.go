@@ -0,0 +1,169 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// A RetryBudget bounds the retries made when an operation fails with a RetryableError. The
+// original attempt, every retry and the backoff between them all share a single deadline:
+// the earlier of the operation's deadline (see ResolveDeadline) and Timeout from the start of
+// the operation. Each attempt is also bound by that deadline, so timeouts are not multiplied by
+// the number of retries.
+type RetryBudget struct {
+	// Maximum number of retries after the original attempt. 0 is unlimited.
+	MaxRetries int
+	// Overall time allowed for the operation. 0 leaves the deadline to the context.
+	Timeout time.Duration
+	// Delay before the first retry, doubled for each subsequent retry up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Fraction of each backoff, from 0 to 1, which is random. A backoff of 100ms with a
+	// Jitter of 0.5 waits between 50ms and 100ms, so that clients which failed together do
+	// not all retry together.
+	Jitter float64
+	// Source of the randomness of Jitter, returning numbers from 0 up to but not including 1.
+	// It is called by concurrent operations, so must be safe for concurrent use. The default
+	// is rand.Float64.
+	Random func() float64
+	// Classifies the errors of failed attempts, returning whether to retry. The default is
+	// IsRetryable. Errors from servers reporting overload are handled by the connector's
+	// ThrottlePolicy instead, if it has one.
+	Retryable func(err error) bool
+}
+
+// IsRetryable returns whether err is a RetryableError, such as a failure to write a request
+// or a read which timed out, or an error from a server whose data moved during the operation
+// (see IsRelocated), after which the operation may succeed on another connection.
+func IsRetryable(err error) bool {
+	_, ok := err.(*RetryableError)
+	return ok || IsRelocated(err)
+}
+
+// Exceptions thrown by servers when the bucket holding an entry moves, or loses its primary,
+// while an operation on it is in progress
+var relocationExceptions = []string{"PrimaryBucketException", "BucketMovedException", "ForceReattemptException"}
+
+// IsRelocated returns whether err is an error from a server which could not complete an
+// operation because the data it needed moved to another server, as happens while a cluster
+// rebalances or a server leaves it. The protocol has no error code for this, nor data
+// locations for the client to refresh, so such errors are recognized by the exception the
+// server names. Servers route each operation to the data themselves, so trying again, on
+// another connection, reaches its new location.
+func IsRelocated(err error) bool {
+	serverErr, ok := err.(*ServerError)
+	if !ok || serverErr.Code != v1.ErrorCode_SERVER_ERROR {
+		return false
+	}
+
+	for _, exception := range relocationExceptions {
+		if strings.Contains(serverErr.Message, exception) {
+			return true
+		}
+	}
+	return false
+}
+
+// A RetryBudgetError is returned when an operation is abandoned because its RetryBudget is
+// exhausted. Err is the error from the last attempt.
+type RetryBudgetError struct {
+	Attempts int
+	Err      error
+}
+
+func (this *RetryBudgetError) Error() string {
+	return fmt.Sprintf("retry budget exhausted after %d attempts: %s", this.Attempts, this.Err.Error())
+}
+
+func (this *RetryBudgetError) Unwrap() error {
+	return this.Err
+}
+
+// SetRetryBudget limits the retries made for each operation, and chooses which errors are
+// retried. Without a budget, operations failing with a RetryableError are retried immediately
+// until they succeed or the context is done, which may be indefinitely against a server
+// which keeps failing.
+func (this *Protobuf) SetRetryBudget(budget *RetryBudget) {
+	this.retryBudget = budget
+}
+
+// WithContext returns a copy of this connector whose operations are bound by ctx. An
+// operation is abandoned once ctx is done and ctx's deadline applies to every attempt.
+func (this *Protobuf) WithContext(ctx context.Context) *Protobuf {
+	c := *this
+	c.ctx = ctx
+	return &c
+}
+
+func (this *Protobuf) context() context.Context {
+	if this.ctx == nil {
+		return context.Background()
+	}
+	return this.ctx
+}
+
+// Return the deadline for an operation on region started at start, or the zero time if there
+// is none. The retry budget's timeout, if any, bounds the deadline resolved from the
+// timeouts.
+func (this *Protobuf) operationDeadline(ctx context.Context, region string, start time.Time) time.Time {
+	deadline := ResolveDeadline(ctx, start, this.timeoutLevels(region))
+
+	if this.retryBudget != nil && this.retryBudget.Timeout > 0 {
+		budgetDeadline := start.Add(this.retryBudget.Timeout)
+		if deadline.IsZero() || budgetDeadline.Before(deadline) {
+			deadline = budgetDeadline
+		}
+	}
+
+	return deadline
+}
+
+func (this *RetryBudget) backoff(retry int) time.Duration {
+	backoff := this.InitialBackoff
+	for i := 1; i < retry; i++ {
+		if (this.MaxBackoff > 0 && backoff >= this.MaxBackoff) || backoff > math.MaxInt64/2 {
+			break
+		}
+		backoff *= 2
+	}
+
+	if this.MaxBackoff > 0 && backoff > this.MaxBackoff {
+		return this.MaxBackoff
+	}
+	return backoff
+}
+
+// Delay returns the time waited before a retry, counted from 1, randomized by Jitter.
+func (this *RetryBudget) Delay(retry int) time.Duration {
+	backoff := this.backoff(retry)
+	if this.Jitter <= 0 || backoff <= 0 {
+		return backoff
+	}
+
+	jitter := this.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+	random := rand.Float64
+	if this.Random != nil {
+		random = this.Random
+	}
+	return backoff - time.Duration(random()*jitter*float64(backoff))
+}
+
+// Return whether the error of a failed attempt should be retried, or the error of a panic in
+// the Retryable callback. The budget may be nil.
+func (this *RetryBudget) retryable(err error) (retry bool, callbackErr error) {
+	if this == nil || this.Retryable == nil {
+		return IsRetryable(err), nil
+	}
+
+	defer RecoverCallback("RetryBudget.Retryable", &callbackErr)
+	return this.Retryable(err), nil
+}
@@ -0,0 +1,232 @@
+package connector_test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retry budget", func() {
+
+	var connection *connector.Protobuf
+	var pool *connector.Pool
+	var conns []*connectorfakes.FakeConn
+
+	// Add connections whose writes all fail with a retryable error
+	addFailingConnections := func(n int) {
+		for i := 0; i < n; i++ {
+			fakeConn := new(connectorfakes.FakeConn)
+			fakeConn.WriteStub = func(b []byte) (int, error) {
+				return 0, &net.OpError{Op: "write", Err: errors.New("fake retryable write error")}
+			}
+			pool.AddConnection(fakeConn, true)
+			conns = append(conns, fakeConn)
+		}
+	}
+
+	attempts := func() int {
+		n := 0
+		for _, c := range conns {
+			n += c.WriteCallCount()
+		}
+		return n
+	}
+
+	BeforeEach(func() {
+		conns = nil
+		pool = connector.NewPool()
+		connection = connector.NewConnector(pool)
+	})
+
+	It("stops after the maximum number of retries", func() {
+		addFailingConnections(5)
+		connection.SetRetryBudget(&connector.RetryBudget{MaxRetries: 2})
+
+		err := connection.Put("foo", "A", 1)
+		Expect(err).To(BeAssignableToTypeOf(&connector.RetryBudgetError{}))
+		Expect(err.(*connector.RetryBudgetError).Attempts).To(Equal(3))
+		Expect(attempts()).To(Equal(3))
+	})
+
+	It("does not retry when the backoff would exceed the overall timeout", func() {
+		addFailingConnections(5)
+		connection.SetRetryBudget(&connector.RetryBudget{
+			Timeout:        50 * time.Millisecond,
+			InitialBackoff: 30 * time.Millisecond,
+		})
+
+		// The first backoff fits in the timeout, the doubled second one does not
+		err := connection.Put("foo", "A", 1)
+		Expect(err).To(BeAssignableToTypeOf(&connector.RetryBudgetError{}))
+		Expect(err.(*connector.RetryBudgetError).Attempts).To(Equal(2))
+		Expect(attempts()).To(Equal(2))
+	})
+
+	It("applies the shared deadline to each attempt", func() {
+		// The pool uses the most recently added connection first
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}},
+			}, b)
+		}
+		pool.AddConnection(fakeConn, true)
+		addFailingConnections(1)
+		connection.SetRetryBudget(&connector.RetryBudget{Timeout: time.Minute})
+
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+
+		first := conns[0].SetDeadlineArgsForCall(0)
+		Expect(first).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+		Expect(fakeConn.SetDeadlineArgsForCall(0)).To(Equal(first))
+		Expect(fakeConn.SetDeadlineArgsForCall(1).IsZero()).To(BeTrue())
+	})
+
+	It("uses the context deadline when it is earlier", func() {
+		addFailingConnections(5)
+		connection.SetRetryBudget(&connector.RetryBudget{
+			Timeout:        time.Minute,
+			InitialBackoff: 30 * time.Millisecond,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := connection.WithContext(ctx).Put("foo", "A", 1)
+		Expect(err).To(BeAssignableToTypeOf(&connector.RetryBudgetError{}))
+		Expect(attempts()).To(Equal(2))
+	})
+
+	It("retries the errors chosen by the budget", func() {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}},
+			}, b)
+		}
+		pool.AddConnection(fakeConn, true)
+		failing := new(connectorfakes.FakeConn)
+		failing.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_ErrorResponse{
+					ErrorResponse: &v1.ErrorResponse{Error: &v1.Error{ErrorCode: v1.ErrorCode_SERVER_ERROR, Message: "flapping"}},
+				},
+			}, b)
+		}
+		pool.AddConnection(failing, true)
+
+		var classified []error
+		connection.SetRetryBudget(&connector.RetryBudget{
+			MaxRetries: 3,
+			Retryable: func(err error) bool {
+				classified = append(classified, err)
+				_, ok := err.(*connector.ServerError)
+				return ok || connector.IsRetryable(err)
+			},
+		})
+
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+		Expect(classified).To(HaveLen(1))
+		Expect(fakeConn.WriteCallCount()).To(Equal(1))
+	})
+
+	It("retries operations whose data moved to another server", func() {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}},
+			}, b)
+		}
+		pool.AddConnection(fakeConn, true)
+		moved := new(connectorfakes.FakeConn)
+		moved.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_ErrorResponse{
+					ErrorResponse: &v1.ErrorResponse{Error: &v1.Error{
+						ErrorCode: v1.ErrorCode_SERVER_ERROR,
+						Message:   "org.apache.geode.internal.cache.PrimaryBucketException: bucket 7 is not primary",
+					}},
+				},
+			}, b)
+		}
+		pool.AddConnection(moved, true)
+		connection.SetRetryBudget(&connector.RetryBudget{MaxRetries: 3})
+
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+		Expect(moved.WriteCallCount()).To(Equal(1))
+		Expect(fakeConn.WriteCallCount()).To(Equal(1))
+	})
+
+	It("recognizes relocation errors", func() {
+		Expect(connector.IsRelocated(&connector.ServerError{Code: v1.ErrorCode_SERVER_ERROR, Message: "BucketMovedException: moved"})).To(BeTrue())
+		Expect(connector.IsRelocated(&connector.ServerError{Code: v1.ErrorCode_SERVER_ERROR, Message: "flapping"})).To(BeFalse())
+		Expect(connector.IsRelocated(&connector.ServerError{Code: v1.ErrorCode_INVALID_REQUEST, Message: "BucketMovedException"})).To(BeFalse())
+		Expect(connector.IsRelocated(errors.New("BucketMovedException"))).To(BeFalse())
+	})
+
+	It("does not retry errors the budget rejects", func() {
+		addFailingConnections(2)
+		connection.SetRetryBudget(&connector.RetryBudget{
+			Retryable: func(err error) bool { return false },
+		})
+
+		err := connection.Put("foo", "A", 1)
+		Expect(err).To(BeAssignableToTypeOf(&connector.RetryableError{}))
+		Expect(attempts()).To(Equal(1))
+	})
+
+	It("returns an error when the classifier panics", func() {
+		addFailingConnections(2)
+		connection.SetRetryBudget(&connector.RetryBudget{
+			Retryable: func(err error) bool { panic("boom") },
+		})
+
+		err := connection.Put("foo", "A", 1)
+		Expect(err).To(BeAssignableToTypeOf(&connector.CallbackPanicError{}))
+		Expect(attempts()).To(Equal(1))
+	})
+
+	It("randomizes the backoff by the jitter", func() {
+		budget := &connector.RetryBudget{
+			InitialBackoff: 20 * time.Millisecond,
+			MaxBackoff:     20 * time.Millisecond,
+			Jitter:         1,
+			Random:         rand.New(rand.NewSource(1)).Float64,
+		}
+
+		delays := make([]time.Duration, 10)
+		for i := range delays {
+			delays[i] = budget.Delay(i + 1)
+			Expect(delays[i]).To(BeNumerically(">=", 0))
+			Expect(delays[i]).To(BeNumerically("<=", 20*time.Millisecond))
+		}
+		Expect(delays).ToNot(HaveEach(delays[0]))
+	})
+
+	It("backs off exponentially up to the maximum", func() {
+		budget := &connector.RetryBudget{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+
+		Expect(budget.Delay(1)).To(Equal(10 * time.Millisecond))
+		Expect(budget.Delay(2)).To(Equal(20 * time.Millisecond))
+		Expect(budget.Delay(3)).To(Equal(40 * time.Millisecond))
+		Expect(budget.Delay(4)).To(Equal(50 * time.Millisecond))
+	})
+
+	It("does not start an operation when the context is done", func() {
+		addFailingConnections(1)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := connection.WithContext(ctx).Put("foo", "A", 1)
+		Expect(err).To(Equal(context.Canceled))
+		Expect(attempts()).To(Equal(0))
+	})
+})
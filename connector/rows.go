@@ -0,0 +1,158 @@
+package connector
+
+import (
+	"errors"
+	"fmt"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/gemfire/geode-go-client/query"
+)
+
+// Rows iterates over the results of a query, decoding one row at a time. A list or single
+// result has one value per row; a table result has one value per column. The protocol returns
+// the whole result in a single response, so it is read at once, but only the row being
+// scanned is decoded and each row's encoded values are released once the iterator moves
+// past it, so large results need not be held both encoded and decoded. Rows is not safe for
+// concurrent use.
+//
+//	rows, err := connection.QueryRows(q)
+//	for rows.Next() {
+//	    var person Person
+//	    if err := rows.Scan(&person); err != nil { ... }
+//	}
+type Rows struct {
+	connector *Protobuf
+	columns   []string
+	// Values of each column, nil for a list result
+	table []*v1.EncodedValueList
+	// Values of a list or single result
+	list  []*v1.EncodedValue
+	count int
+	// Index of the current row, -1 before the first call to Next
+	row int
+}
+
+// QueryRows runs a query, returning an iterator over its results which decodes them as they
+// are scanned. Columns is empty unless the result is a table. The query's Reference is not
+// used; pass a reference to Scan instead.
+func (this *Protobuf) QueryRows(query *query.Query) (*Rows, error) {
+	response, err := this.doQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := &Rows{connector: this, row: -1}
+	result := response.GetOqlQueryResponse()
+	switch {
+	case result.GetTableResult() != nil:
+		table := result.GetTableResult()
+		rows.columns = table.GetFieldName()
+		rows.table = table.GetRow()
+		if len(rows.table) != len(rows.columns) {
+			return nil, errors.New(fmt.Sprintf("unable to decode query result: %d columns named but %d received", len(rows.columns), len(rows.table)))
+		}
+		if len(rows.table) > 0 {
+			rows.count = len(rows.table[0].GetElement())
+		}
+		for _, column := range rows.table {
+			if len(column.GetElement()) != rows.count {
+				return nil, errors.New("unable to decode query result: columns have different lengths")
+			}
+		}
+	case result.GetSingleResult() != nil:
+		rows.list = []*v1.EncodedValue{result.GetSingleResult()}
+		rows.count = 1
+	default:
+		rows.list = result.GetListResult().GetElement()
+		rows.count = len(rows.list)
+	}
+
+	if query.LastTrace != nil {
+		query.LastTrace.Results = rows.count
+	}
+
+	return rows, nil
+}
+
+// Columns returns the names of the columns of a table result, or nil for other results.
+func (this *Rows) Columns() []string {
+	return this.columns
+}
+
+// Len returns the number of rows.
+func (this *Rows) Len() int {
+	return this.count
+}
+
+// Next advances to the next row, returning false once there are no more.
+func (this *Rows) Next() bool {
+	if this.row >= 0 && this.row < this.count {
+		this.release(this.row)
+	}
+	if this.row < this.count {
+		this.row++
+	}
+	return this.row < this.count
+}
+
+// Scan decodes the values of the current row into dest, one for each column of a table or
+// a single one otherwise. Values are decoded as LazyValue.Decode does, so JSON documents
+// are unmarshalled into the structs dest points to.
+func (this *Rows) Scan(dest ...interface{}) error {
+	if this.row < 0 || this.row >= this.count {
+		return errors.New("Scan called without a row; call Next first")
+	}
+
+	values := this.values()
+	if len(dest) != len(values) {
+		return errors.New(fmt.Sprintf("expected %d destinations for Scan but received %d", len(values), len(dest)))
+	}
+
+	for i, value := range values {
+		lazy := &LazyValue{connector: this.connector, encoded: value}
+		if err := lazy.Decode(dest[i]); err != nil {
+			if this.columns != nil {
+				return errors.New(fmt.Sprintf("unable to decode column %s: %s", this.columns[i], err.Error()))
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close releases the remaining rows. Iterating to the end has the same effect.
+func (this *Rows) Close() error {
+	this.table = nil
+	this.list = nil
+	this.row = this.count
+	return nil
+}
+
+// Return the encoded values of the current row
+func (this *Rows) values() []*v1.EncodedValue {
+	if this.table == nil {
+		return []*v1.EncodedValue{this.list[this.row]}
+	}
+
+	values := make([]*v1.EncodedValue, len(this.table))
+	for i, column := range this.table {
+		values[i] = column.GetElement()[this.row]
+	}
+	return values
+}
+
+// Drop the references to a row's encoded values, which are shared with the response, so that
+// they can be collected
+func (this *Rows) release(row int) {
+	if this.table == nil {
+		if this.list != nil {
+			this.list[row] = nil
+		}
+		return
+	}
+
+	for _, column := range this.table {
+		column.GetElement()[row] = nil
+	}
+}
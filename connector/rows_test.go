@@ -0,0 +1,105 @@
+package connector_test
+
+import (
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/gemfire/geode-go-client/query"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rows", func() {
+
+	var connection *connector.Protobuf
+	var fakeConn *connectorfakes.FakeConn
+
+	respond := func(result interface{}) {
+		response := &v1.OQLQueryResponse{}
+		switch r := result.(type) {
+		case *v1.Table:
+			response.Result = &v1.OQLQueryResponse_TableResult{TableResult: r}
+		case *v1.EncodedValueList:
+			response.Result = &v1.OQLQueryResponse_ListResult{ListResult: r}
+		case *v1.EncodedValue:
+			response.Result = &v1.OQLQueryResponse_SingleResult{SingleResult: r}
+		}
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{MessageType: &v1.Message_OqlQueryResponse{OqlQueryResponse: response}}, b)
+		}
+	}
+
+	BeforeEach(func() {
+		fakeConn = new(connectorfakes.FakeConn)
+		pool := connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+	})
+
+	It("decodes list results a row at a time", func() {
+		list, _ := connector.EncodeValueList([]interface{}{&TestStruct{Value: 1, Message: "a"}, &TestStruct{Value: 2, Message: "b"}})
+		respond(list)
+
+		rows, err := connection.QueryRows(query.NewQuery("select * from /foo"))
+		Expect(err).To(BeNil())
+		Expect(rows.Len()).To(Equal(2))
+		Expect(rows.Columns()).To(BeNil())
+
+		var results []TestStruct
+		for rows.Next() {
+			var result TestStruct
+			Expect(rows.Scan(&result)).To(BeNil())
+			results = append(results, result)
+		}
+		Expect(results).To(Equal([]TestStruct{{Value: 1, Message: "a"}, {Value: 2, Message: "b"}}))
+		Expect(rows.Next()).To(BeFalse())
+	})
+
+	It("scans a value per column of a table result", func() {
+		names, _ := connector.EncodeValueList([]interface{}{"a", "b"})
+		ages, _ := connector.EncodeValueList([]interface{}{int32(1), int32(2)})
+		respond(&v1.Table{FieldName: []string{"name", "age"}, Row: []*v1.EncodedValueList{names, ages}})
+
+		rows, err := connection.QueryRows(query.NewQuery("select name, age from /foo"))
+		Expect(err).To(BeNil())
+		Expect(rows.Columns()).To(Equal([]string{"name", "age"}))
+
+		Expect(rows.Next()).To(BeTrue())
+		Expect(rows.Next()).To(BeTrue())
+		var name string
+		var age int32
+		Expect(rows.Scan(&name, &age)).To(BeNil())
+		Expect(name).To(Equal("b"))
+		Expect(age).To(Equal(int32(2)))
+
+		Expect(rows.Scan(&name)).To(MatchError("expected 2 destinations for Scan but received 1"))
+	})
+
+	It("returns a single result as one row", func() {
+		count, _ := connector.EncodeValue(int32(42))
+		respond(count)
+
+		rows, err := connection.QueryRows(query.NewQuery("select count(*) from /foo"))
+		Expect(err).To(BeNil())
+		Expect(rows.Len()).To(Equal(1))
+
+		var result int32
+		Expect(rows.Next()).To(BeTrue())
+		Expect(rows.Scan(&result)).To(BeNil())
+		Expect(result).To(Equal(int32(42)))
+	})
+
+	It("requires Next before Scan", func() {
+		list, _ := connector.EncodeValueList([]interface{}{"a"})
+		respond(list)
+
+		rows, err := connection.QueryRows(query.NewQuery("select * from /foo"))
+		Expect(err).To(BeNil())
+
+		var value string
+		Expect(rows.Scan(&value)).ToNot(BeNil())
+
+		Expect(rows.Close()).To(BeNil())
+		Expect(rows.Next()).To(BeFalse())
+	})
+})
@@ -0,0 +1,70 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrSameServerUnavailable is returned by operations made with a context from WithSameServer
+// once the server they are tied to has been removed from the pool or cannot be connected to.
+var ErrSameServerUnavailable = errors.New("the server of related operations is unavailable")
+
+type sameServerContextKey struct{}
+
+// The server which the operations made with a context are tied to, once the first of them
+// has chosen one
+type serverAffinity struct {
+	sync.Mutex
+	provider ConnectionProvider
+}
+
+// WithSameServer returns a copy of ctx whose operations, made with WithContext or the Ctx
+// methods, all use connections to the same server: whichever the first of them used. This
+// suits related operations which rely on state a server keeps, such as a query followed by a
+// function reading its results, without holding a single connection as Pin does. Operations
+// are still retried, on other connections to the server, but fail with
+// ErrSameServerUnavailable if it has been removed or cannot be connected to. Connections
+// added with Pool.AddConnection belong to no server, so operations using one are not tied.
+// Related operations should run one after another, as concurrent ones may not agree on a
+// server until one of them has finished.
+func WithSameServer(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sameServerContextKey{}, &serverAffinity{})
+}
+
+// SameServer returns the server which the operations made with ctx are tied to, or "" if
+// ctx is not from WithSameServer or none of them has been made yet.
+func SameServer(ctx context.Context) string {
+	affinity := sameServerOf(ctx)
+	if affinity == nil {
+		return ""
+	}
+
+	provider := affinity.current()
+	if provider == nil {
+		return ""
+	}
+	return providerAddress(provider)
+}
+
+func sameServerOf(ctx context.Context) *serverAffinity {
+	affinity, _ := ctx.Value(sameServerContextKey{}).(*serverAffinity)
+	return affinity
+}
+
+func (this *serverAffinity) current() ConnectionProvider {
+	this.Lock()
+	defer this.Unlock()
+
+	return this.provider
+}
+
+// Tie the operations to a server, unless an earlier operation has already
+func (this *serverAffinity) choose(provider ConnectionProvider) {
+	this.Lock()
+	defer this.Unlock()
+
+	if this.provider == nil {
+		this.provider = provider
+	}
+}
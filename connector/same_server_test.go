@@ -0,0 +1,71 @@
+package connector_test
+
+import (
+	"context"
+
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Same server operations", func() {
+
+	var pool *connector.Pool
+	var first, second *fakeProvider
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+		first = &fakeProvider{name: "server-1", healthy: true}
+		second = &fakeProvider{name: "server-2", healthy: true}
+		pool.AddProvider(first)
+	})
+
+	It("ties operations to the server the first of them used", func() {
+		ctx := connector.WithSameServer(context.Background())
+		Expect(connector.SameServer(ctx)).To(Equal(""))
+
+		gConn, err := pool.AcquireConnection(ctx, 0)
+		Expect(err).To(BeNil())
+		pool.ReturnConnection(gConn)
+		Expect(connector.SameServer(ctx)).To(Equal("server-1"))
+
+		pool.AddProvider(second)
+		busy, err := pool.AcquireConnection(ctx, 0)
+		Expect(err).To(BeNil())
+		defer pool.ReturnConnection(busy)
+		gConn, err = pool.AcquireConnection(ctx, 0)
+		Expect(err).To(BeNil())
+		defer pool.ReturnConnection(gConn)
+
+		Expect(first.opened).To(HaveLen(2))
+		Expect(second.opened).To(BeEmpty())
+	})
+
+	It("does not tie operations made with other contexts", func() {
+		Expect(connector.SameServer(context.Background())).To(Equal(""))
+
+		ctx := connector.WithSameServer(context.Background())
+		gConn, err := pool.AcquireConnection(ctx, 0)
+		Expect(err).To(BeNil())
+		defer pool.ReturnConnection(gConn)
+
+		pool.AddProvider(second)
+		other, err := pool.AcquireConnection(context.Background(), 0)
+		Expect(err).To(BeNil())
+		defer pool.ReturnConnection(other)
+		Expect(second.opened).To(HaveLen(1))
+	})
+
+	It("fails once the server has been removed", func() {
+		ctx := connector.WithSameServer(context.Background())
+		gConn, err := pool.AcquireConnection(ctx, 0)
+		Expect(err).To(BeNil())
+		pool.ReturnConnection(gConn)
+
+		pool.AddProvider(second)
+		Expect(pool.RemoveProvider(first)).To(BeNil())
+
+		_, err = pool.AcquireConnection(ctx, 0)
+		Expect(err).To(Equal(connector.ErrSameServerUnavailable))
+	})
+})
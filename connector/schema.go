@@ -0,0 +1,174 @@
+package connector
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Name of the field added to JSON documents to record the schema version of the struct
+// they were written from.
+const SchemaVersionField = "@schemaVersion"
+
+// A SchemaMigration upgrades the fields of a JSON document from one schema version to the
+// next. The map is modified in place.
+type SchemaMigration func(fields map[string]interface{}) error
+
+type schema struct {
+	version    int
+	migrations map[int]SchemaMigration
+}
+
+// A SchemaRegistry records the current schema version of struct types stored in regions,
+// along with the migrations needed to upgrade documents written by older versions. Documents
+// without a version field are treated as version 1.
+type SchemaRegistry struct {
+	sync.RWMutex
+	schemas map[reflect.Type]*schema
+}
+
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		schemas: make(map[reflect.Type]*schema),
+	}
+}
+
+func structType(value interface{}) reflect.Type {
+	t := reflect.TypeOf(value)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func (this *SchemaRegistry) schemaFor(t reflect.Type) *schema {
+	s, ok := this.schemas[t]
+	if !ok {
+		s = &schema{
+			version:    1,
+			migrations: make(map[int]SchemaMigration),
+		}
+		this.schemas[t] = s
+	}
+	return s
+}
+
+// Register declares the current schema version of the struct type of value, which may be
+// either a struct or a pointer to one.
+func (this *SchemaRegistry) Register(value interface{}, version int) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.schemaFor(structType(value)).version = version
+}
+
+// AddMigration adds the migration which upgrades documents of the struct type of value
+// from version from to version from+1.
+func (this *SchemaRegistry) AddMigration(value interface{}, from int, migration SchemaMigration) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.schemaFor(structType(value)).migrations[from] = migration
+}
+
+// Returns a copy of the schema registered for the struct type of value, or nil.
+func (this *SchemaRegistry) lookup(value interface{}) *schema {
+	this.RLock()
+	defer this.RUnlock()
+
+	s, ok := this.schemas[structType(value)]
+	if !ok {
+		return nil
+	}
+
+	migrations := make(map[int]SchemaMigration, len(s.migrations))
+	for from, migration := range s.migrations {
+		migrations[from] = migration
+	}
+
+	return &schema{
+		version:    s.version,
+		migrations: migrations,
+	}
+}
+
+// Add the schema version field to a JSON document written from value.
+func (this *SchemaRegistry) stamp(value interface{}, document string) (string, error) {
+	s := this.lookup(value)
+	if s == nil {
+		return document, nil
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(document), &fields); err != nil {
+		// Not a JSON object, so there is nowhere to record the version
+		return document, nil
+	}
+
+	fields[SchemaVersionField] = json.RawMessage(fmt.Sprintf("%d", s.version))
+	result, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+
+	return string(result), nil
+}
+
+// Upgrade a JSON document to the current schema version of ref, applying each migration in
+// turn. Documents written by a newer schema version are decoded as-is.
+func (this *SchemaRegistry) migrate(ref interface{}, document string) (string, error) {
+	s := this.lookup(ref)
+	if s == nil {
+		return document, nil
+	}
+
+	fields := make(map[string]interface{})
+	decoder := json.NewDecoder(bytes.NewReader([]byte(document)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return document, nil
+	}
+
+	version := 1
+	if v, ok := fields[SchemaVersionField]; ok {
+		n, ok := v.(json.Number)
+		if !ok {
+			return "", errors.New(fmt.Sprintf("invalid schema version: %v", v))
+		}
+		parsed, err := n.Int64()
+		if err != nil {
+			return "", errors.New(fmt.Sprintf("invalid schema version: %v", v))
+		}
+		version = int(parsed)
+		delete(fields, SchemaVersionField)
+	}
+
+	for ; version < s.version; version++ {
+		migration, ok := s.migrations[version]
+		if !ok {
+			return "", errors.New(fmt.Sprintf("no schema migration registered for %s from version %d", structType(ref), version))
+		}
+
+		if err := runMigration(migration, fields); err != nil {
+			if _, ok := err.(*CallbackPanicError); ok {
+				return "", err
+			}
+			return "", errors.New(fmt.Sprintf("schema migration for %s from version %d failed: %s", structType(ref), version, err.Error()))
+		}
+	}
+
+	result, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+
+	return string(result), nil
+}
+
+func runMigration(migration SchemaMigration, fields map[string]interface{}) (err error) {
+	defer RecoverCallback("SchemaMigration", &err)
+	return migration(fields)
+}
@@ -0,0 +1,91 @@
+package connector_test
+
+import (
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type PersonV3 struct {
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Age       int    `json:"age"`
+}
+
+var _ = Describe("Schema versioning", func() {
+
+	var connection *connector.Protobuf
+	var fakeConn *connectorfakes.FakeConn
+	var registry *connector.SchemaRegistry
+	var store *fakeStore
+
+	BeforeEach(func() {
+		fakeConn = new(connectorfakes.FakeConn)
+		pool := connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		connection = connector.NewConnector(pool)
+
+		registry = connector.NewSchemaRegistry()
+		registry.Register(&PersonV3{}, 3)
+		registry.AddMigration(&PersonV3{}, 1, func(fields map[string]interface{}) error {
+			fields["firstName"] = fields["name"]
+			delete(fields, "name")
+			return nil
+		})
+		registry.AddMigration(&PersonV3{}, 2, func(fields map[string]interface{}) error {
+			fields["lastName"] = "Unknown"
+			return nil
+		})
+		connection.SetSchemaRegistry(registry)
+
+		store = newFakeStore(fakeConn)
+	})
+
+	It("records the schema version on write", func() {
+		Expect(connection.Put("foo", "A", &PersonV3{FirstName: "Joe"})).To(BeNil())
+		Expect(store.value.GetJsonObjectResult()).To(ContainSubstring(`"@schemaVersion":3`))
+	})
+
+	It("migrates unversioned documents through every version", func() {
+		store.value = &v1.EncodedValue{
+			Value: &v1.EncodedValue_JsonObjectResult{JsonObjectResult: `{"name":"Joe","age":42}`},
+		}
+
+		v, err := connection.Get("foo", "A", &PersonV3{})
+		Expect(err).To(BeNil())
+		Expect(v).To(Equal(&PersonV3{FirstName: "Joe", LastName: "Unknown", Age: 42}))
+	})
+
+	It("does not migrate documents already at the current version", func() {
+		original := &PersonV3{FirstName: "Joe", LastName: "Bloggs", Age: 42}
+		Expect(connection.Put("foo", "A", original)).To(BeNil())
+
+		v, err := connection.Get("foo", "A", &PersonV3{})
+		Expect(err).To(BeNil())
+		Expect(v).To(Equal(original))
+	})
+
+	It("returns an error when a migration is missing", func() {
+		registry.Register(&PersonV3{}, 4)
+		store.value = &v1.EncodedValue{
+			Value: &v1.EncodedValue_JsonObjectResult{JsonObjectResult: `{"@schemaVersion":3,"firstName":"Joe"}`},
+		}
+
+		_, err := connection.Get("foo", "A", &PersonV3{})
+		Expect(err).To(MatchError(ContainSubstring("from version 3")))
+	})
+
+	It("returns an error when a migration moves an encrypted field", func() {
+		type PersonV1 struct {
+			Name string `json:"name" geode:",encrypt"`
+		}
+
+		connection.SetKeyProvider(connector.StaticKeyProvider("0123456789abcdef"))
+		Expect(connection.Put("foo", "A", &PersonV1{Name: "Joe"})).To(BeNil())
+
+		_, err := connection.Get("foo", "A", &PersonV3{})
+		Expect(err).To(MatchError(ContainSubstring("field firstName is encrypted")))
+	})
+})
@@ -1,19 +1,31 @@
 package connector
 
 import (
+	"crypto/tls"
 	"net"
-	"fmt"
+	"strconv"
+	"time"
 )
 
 type serverConnectionProvider struct {
-	host string
-	port int
+	host    string
+	port    int
+	timeout time.Duration
+	// Connect with TLS if set
+	tlsConfig *tls.Config
 }
 
 var _ ConnectionProvider = (*serverConnectionProvider)(nil)
 
 func (this *serverConnectionProvider) GetGeodeConnection() *GeodeConnection {
-	c, err := net.Dial("tcp", fmt.Sprintf("%s:%d", this.host, this.port))
+	var c net.Conn
+	var err error
+	address := net.JoinHostPort(this.host, strconv.Itoa(this.port))
+	if this.tlsConfig != nil {
+		c, err = tls.DialWithDialer(&net.Dialer{Timeout: this.timeout}, "tcp", address, this.tlsConfig)
+	} else {
+		c, err = net.DialTimeout("tcp", address, this.timeout)
+	}
 	if err != nil {
 		return nil
 	}
@@ -25,3 +37,12 @@ func (this *serverConnectionProvider) GetGeodeConnection() *GeodeConnection {
 		authenticationDone: false,
 	}
 }
+
+// Servers are judged by the FailureDetector instead
+func (this *serverConnectionProvider) Healthy() bool {
+	return true
+}
+
+func (this *serverConnectionProvider) Close() error {
+	return nil
+}
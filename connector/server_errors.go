@@ -0,0 +1,100 @@
+package connector
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// GeodeError is another name for ServerError, the error response from a server, which carries
+// the response's error code and message.
+type GeodeError = ServerError
+
+// Messages of the servers' errors for operations on regions which do not exist, or have been
+// destroyed, which the protocol reports as SERVER_ERROR or INVALID_REQUEST
+var regionNotFound = regexp.MustCompile(`(?i)region .*not found|nonexistent region|RegionDestroyedException`)
+
+// Messages of the servers' errors for operations refused because a server is short of heap
+var lowMemory = []string{"LowMemoryException", "low on memory"}
+
+// AsServerError returns the error response from a server which caused err, unwrapping the
+// errors of retries, chunks and optional regions which wrap it, and whether there is one.
+// Of the chunks of a MultiError, the first to fail with an error response is returned.
+func AsServerError(err error) (*ServerError, bool) {
+	var serverErr *ServerError
+	if errors.As(err, &serverErr) {
+		return serverErr, true
+	}
+	return nil, false
+}
+
+// IsServerErrorCode returns whether err is caused by an error response from a server with the
+// given code.
+func IsServerErrorCode(err error, code v1.ErrorCode) bool {
+	serverErr, ok := AsServerError(err)
+	return ok && serverErr.Code == code
+}
+
+// IsAuthenticationFailed returns whether err is caused by credentials being missing or rejected,
+// either in a server's error response or when a connection was authenticated.
+func IsAuthenticationFailed(err error) bool {
+	var authErr AuthenticationError
+	if errors.As(err, &authErr) {
+		return true
+	}
+	return IsServerErrorCode(err, v1.ErrorCode_AUTHENTICATION_FAILED) ||
+		IsServerErrorCode(err, v1.ErrorCode_AUTHENTICATION_REQUIRED)
+}
+
+// IsAuthorizationFailed returns whether err is caused by the client's user not being permitted
+// to perform an operation.
+func IsAuthorizationFailed(err error) bool {
+	return IsServerErrorCode(err, v1.ErrorCode_AUTHORIZATION_FAILED)
+}
+
+// IsInvalidRequest returns whether err is caused by a server rejecting a request as invalid.
+func IsInvalidRequest(err error) bool {
+	return IsServerErrorCode(err, v1.ErrorCode_INVALID_REQUEST)
+}
+
+// IsUnsupportedOperation returns whether err is caused by a server which does not support an
+// operation.
+func IsUnsupportedOperation(err error) bool {
+	return IsServerErrorCode(err, v1.ErrorCode_UNSUPPORTED_OPERATION)
+}
+
+// IsNoAvailableServer returns whether err is caused by a server reporting that it, or the
+// cluster, cannot serve requests, as when it is overloaded or shutting down.
+func IsNoAvailableServer(err error) bool {
+	return IsServerErrorCode(err, v1.ErrorCode_NO_AVAILABLE_SERVER)
+}
+
+// IsRegionNotFound returns whether err is caused by an operation on a region which does not
+// exist on the server. The protocol has no error code for this, so such errors are
+// recognized by the server's message, as IsRelocated recognizes its exceptions.
+func IsRegionNotFound(err error) bool {
+	serverErr, ok := AsServerError(err)
+	if !ok || (serverErr.Code != v1.ErrorCode_SERVER_ERROR && serverErr.Code != v1.ErrorCode_INVALID_REQUEST) {
+		return false
+	}
+	return regionNotFound.MatchString(serverErr.Message)
+}
+
+// IsLowMemory returns whether err is caused by a server refusing an operation because it is
+// short of heap, which it reports as a SERVER_ERROR naming the LowMemoryException. Retrying
+// at once is likely to fail again; such servers recover as they evict or expire entries.
+func IsLowMemory(err error) bool {
+	serverErr, ok := AsServerError(err)
+	if !ok || serverErr.Code != v1.ErrorCode_SERVER_ERROR {
+		return false
+	}
+
+	for _, message := range lowMemory {
+		if strings.Contains(serverErr.Message, message) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package connector_test
+
+import (
+	"errors"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server errors", func() {
+
+	serverError := func(code v1.ErrorCode, message string) error {
+		return &connector.GeodeError{Code: code, Message: message}
+	}
+
+	It("returns error responses with their code and message", func() {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(&v1.Message{
+				MessageType: &v1.Message_ErrorResponse{
+					ErrorResponse: &v1.ErrorResponse{Error: &v1.Error{
+						ErrorCode: v1.ErrorCode_SERVER_ERROR,
+						Message:   `Region "orders" not found`,
+					}},
+				},
+			}, b)
+		}
+		pool := connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+
+		_, err := connector.NewConnector(pool).Get("orders", "A", nil)
+
+		geodeErr, ok := connector.AsServerError(err)
+		Expect(ok).To(BeTrue())
+		Expect(geodeErr.Code).To(Equal(v1.ErrorCode_SERVER_ERROR))
+		Expect(geodeErr.Message).To(Equal(`Region "orders" not found`))
+		Expect(connector.IsRegionNotFound(err)).To(BeTrue())
+		Expect(connector.IsLowMemory(err)).To(BeFalse())
+	})
+
+	It("classifies errors by code", func() {
+		Expect(connector.IsAuthenticationFailed(serverError(v1.ErrorCode_AUTHENTICATION_FAILED, "bad password"))).To(BeTrue())
+		Expect(connector.IsAuthenticationFailed(serverError(v1.ErrorCode_AUTHENTICATION_REQUIRED, "no credentials"))).To(BeTrue())
+		Expect(connector.IsAuthenticationFailed(connector.AuthenticationError("connection not authenticated"))).To(BeTrue())
+		Expect(connector.IsAuthorizationFailed(serverError(v1.ErrorCode_AUTHORIZATION_FAILED, "DATA:WRITE"))).To(BeTrue())
+		Expect(connector.IsInvalidRequest(serverError(v1.ErrorCode_INVALID_REQUEST, "bad key"))).To(BeTrue())
+		Expect(connector.IsUnsupportedOperation(serverError(v1.ErrorCode_UNSUPPORTED_OPERATION, "no"))).To(BeTrue())
+		Expect(connector.IsNoAvailableServer(serverError(v1.ErrorCode_NO_AVAILABLE_SERVER, "busy"))).To(BeTrue())
+
+		Expect(connector.IsAuthenticationFailed(serverError(v1.ErrorCode_AUTHORIZATION_FAILED, "DATA:WRITE"))).To(BeFalse())
+		Expect(connector.IsNoAvailableServer(errors.New("busy"))).To(BeFalse())
+		Expect(connector.IsServerErrorCode(nil, v1.ErrorCode_SERVER_ERROR)).To(BeFalse())
+	})
+
+	It("recognizes missing regions and low memory by the server's message", func() {
+		Expect(connector.IsRegionNotFound(serverError(v1.ErrorCode_SERVER_ERROR, "Received get request for nonexistent region: orders"))).To(BeTrue())
+		Expect(connector.IsRegionNotFound(serverError(v1.ErrorCode_SERVER_ERROR, "org.apache.geode.cache.RegionDestroyedException: /orders"))).To(BeTrue())
+		Expect(connector.IsRegionNotFound(serverError(v1.ErrorCode_AUTHORIZATION_FAILED, `Region "orders" not found`))).To(BeFalse())
+		Expect(connector.IsRegionNotFound(serverError(v1.ErrorCode_SERVER_ERROR, "Key not found"))).To(BeFalse())
+
+		Expect(connector.IsLowMemory(serverError(v1.ErrorCode_SERVER_ERROR, "org.apache.geode.cache.LowMemoryException: member is running low on memory"))).To(BeTrue())
+		Expect(connector.IsLowMemory(serverError(v1.ErrorCode_SERVER_ERROR, "failed"))).To(BeFalse())
+	})
+
+	It("finds the server's error through the errors which wrap it", func() {
+		cause := serverError(v1.ErrorCode_NO_AVAILABLE_SERVER, "busy").(*connector.ServerError)
+
+		Expect(connector.IsNoAvailableServer(&connector.ThrottledError{Attempts: 3, Err: cause})).To(BeTrue())
+		Expect(connector.IsNoAvailableServer(&connector.RetryBudgetError{Attempts: 2, Err: &connector.RetryableError{Err: cause}})).To(BeTrue())
+		Expect(connector.IsNoAvailableServer(&connector.MultiError{Chunks: []*connector.ChunkError{{Index: 1, Err: cause}}})).To(BeTrue())
+		Expect(connector.IsNoAvailableServer(&connector.UnavailableError{Region: "orders", Err: cause})).To(BeTrue())
+
+		var geodeErr *connector.GeodeError
+		Expect(errors.As(&connector.ThrottledError{Err: cause}, &geodeErr)).To(BeTrue())
+		Expect(geodeErr).To(BeIdenticalTo(cause))
+	})
+})
@@ -0,0 +1,198 @@
+package connector
+
+import (
+	"hash/fnv"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// The servers a pool connects to when subsetting is enabled, see SetSubset
+type endpointSubset struct {
+	size     int
+	clientID string
+	// The providers the subset was chosen from, to tell when it must be chosen again
+	from    []ConnectionProvider
+	members map[ConnectionProvider]bool
+}
+
+// SetSubset limits the servers the pool connects to to size of them, so that a client of a
+// cluster with hundreds of servers does not hold connections to them all. Each client's
+// subset is chosen by rendezvous hashing of clientID with the servers' addresses, so it is
+// the same every time for a given client and set of servers, clients with different IDs are
+// spread evenly over the servers, and adding or removing a server only changes the subsets
+// which include it. clientID should differ between client processes; "" uses the hostname and
+// process ID.
+//
+// Servers outside the subset are only connected to when none in it can be. The subset is
+// chosen again as servers are added and removed, and when the pool is rebalanced (see
+// RebalanceSubset). A size of 0, the default, connects to every server.
+func (this *Pool) SetSubset(size int, clientID string) {
+	this.Lock()
+	defer this.Unlock()
+
+	if size <= 0 {
+		this.subset = nil
+		this.syncPartitions()
+		return
+	}
+
+	if clientID == "" {
+		hostname, _ := os.Hostname()
+		clientID = hostname + ":" + strconv.Itoa(os.Getpid())
+	}
+	this.subset = &endpointSubset{size: size, clientID: clientID}
+	this.chooseSubset(false)
+	this.syncPartitions()
+}
+
+// Subset returns the addresses of the servers in the pool's subset, or nil if subsetting is
+// not enabled.
+func (this *Pool) Subset() []string {
+	this.Lock()
+	defer this.Unlock()
+
+	if this.currentSubset() == nil {
+		return nil
+	}
+
+	servers := make([]string, 0, len(this.subset.members))
+	for _, provider := range this.providers {
+		if this.subset.members[provider] {
+			servers = append(servers, providerAddress(provider))
+		}
+	}
+	return servers
+}
+
+// RebalanceSubset chooses the pool's subset again, replacing the servers which the
+// FailureDetector considers unavailable with the next servers in the client's order, and
+// closes the idle connections to servers which have left the subset. Without subsetting it
+// does nothing.
+func (this *Pool) RebalanceSubset() {
+	this.Lock()
+	defer this.Unlock()
+
+	if this.subset == nil {
+		return
+	}
+	this.chooseSubset(true)
+	for _, partition := range this.partitions {
+		partition.Lock()
+		if partition.subset != nil {
+			partition.chooseSubset(true)
+		}
+		partition.Unlock()
+	}
+}
+
+// StartSubsetRebalancing calls RebalanceSubset at the given interval until the returned
+// function is called.
+func (this *Pool) StartSubsetRebalancing(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				this.RebalanceSubset()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+// Return the subset, chosen again if the providers have changed since it was chosen, or nil
+// if subsetting is not enabled
+// MUST hold the pool lock when calling
+func (this *Pool) currentSubset() *endpointSubset {
+	if this.subset == nil {
+		return nil
+	}
+
+	if !sameProviders(this.subset.from, this.providers) {
+		this.chooseSubset(false)
+	}
+	return this.subset
+}
+
+// Choose the subset from the current providers, preferring available servers if
+// availability is true, and close the idle connections to servers outside it
+// MUST hold the pool lock when calling
+func (this *Pool) chooseSubset(availability bool) {
+	type ranked struct {
+		provider  ConnectionProvider
+		weight    uint64
+		available bool
+	}
+
+	candidates := make([]ranked, 0, len(this.providers))
+	for _, provider := range this.providers {
+		address := providerAddress(provider)
+		h := fnv.New64a()
+		h.Write([]byte(this.subset.clientID))
+		h.Write([]byte{0})
+		h.Write([]byte(address))
+		available := !availability || this.failureDetector().Available(address)
+		candidates = append(candidates, ranked{provider, mix(h.Sum64()), available})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].available != candidates[j].available {
+			return candidates[i].available
+		}
+		return candidates[i].weight > candidates[j].weight
+	})
+
+	members := make(map[ConnectionProvider]bool, this.subset.size)
+	for i := 0; i < len(candidates) && i < this.subset.size; i++ {
+		members[candidates[i].provider] = true
+	}
+	this.subset.members = members
+	this.subset.from = append([]ConnectionProvider{}, this.providers...)
+
+	for i := len(this.recentConnections) - 1; i >= 0; i-- {
+		c := this.recentConnections[i]
+		if !c.inUse && c.provider != nil && !members[c.provider] {
+			this.discardConnection(c)
+			this.metricsPublisher().Add(MetricDiscardedConnections, 1)
+		}
+	}
+}
+
+// Return whether provider is in the subset, which every provider is without subsetting
+// MUST hold the pool lock when calling
+func (this *Pool) inSubset(provider ConnectionProvider) bool {
+	subset := this.currentSubset()
+	return subset == nil || subset.members[provider]
+}
+
+// Finish a hash with the splitmix64 finalizer, so that servers whose addresses differ only
+// slightly are ranked independently
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func sameProviders(a, b []ConnectionProvider) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
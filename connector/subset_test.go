@@ -0,0 +1,97 @@
+package connector_test
+
+import (
+	"fmt"
+
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Endpoint subsetting", func() {
+
+	var pool *connector.Pool
+	var providers []*fakeProvider
+
+	newPool := func(servers int) *connector.Pool {
+		p := connector.NewPool()
+		for i := 0; i < servers; i++ {
+			provider := &fakeProvider{name: fmt.Sprintf("server-%d:40404", i), healthy: true}
+			providers = append(providers, provider)
+			p.AddProvider(provider)
+		}
+		return p
+	}
+
+	BeforeEach(func() {
+		providers = nil
+		pool = newPool(10)
+		pool.SetSubset(3, "client-a")
+	})
+
+	It("connects only to the servers in the subset", func() {
+		Expect(pool.Subset()).To(HaveLen(3))
+
+		for i := 0; i < 6; i++ {
+			gConn, err := pool.GetConnection()
+			Expect(err).To(BeNil())
+			defer pool.ReturnConnection(gConn)
+		}
+
+		for _, provider := range providers {
+			if len(provider.opened) > 0 {
+				Expect(pool.Subset()).To(ContainElement(provider.name))
+			}
+		}
+	})
+
+	It("chooses the same subset for the same client and spreads clients over the servers", func() {
+		other := newPool(10)
+		other.SetSubset(3, "client-a")
+		Expect(other.Subset()).To(ConsistOf(pool.Subset()))
+
+		chosen := make(map[string]int)
+		for i := 0; i < 100; i++ {
+			other.SetSubset(3, fmt.Sprintf("client-%d", i))
+			for _, server := range other.Subset() {
+				chosen[server]++
+			}
+		}
+		Expect(chosen).To(HaveLen(10))
+		for _, count := range chosen {
+			Expect(count).To(BeNumerically("~", 30, 20))
+		}
+	})
+
+	It("changes at most one server of the subset when a server is added", func() {
+		before := pool.Subset()
+		pool.AddProvider(&fakeProvider{name: "server-new:40404", healthy: true})
+
+		after := pool.Subset()
+		Expect(after).To(HaveLen(3))
+		kept := 0
+		for _, server := range after {
+			for _, previous := range before {
+				if server == previous {
+					kept++
+				}
+			}
+		}
+		Expect(kept).To(BeNumerically(">=", 2))
+	})
+
+	It("replaces unavailable servers when rebalanced", func() {
+		failed := pool.Subset()[0]
+		pool.GetFailureDetector().Failure(failed)
+
+		Expect(pool.Subset()).To(ContainElement(failed))
+		pool.RebalanceSubset()
+		Expect(pool.Subset()).To(HaveLen(3))
+		Expect(pool.Subset()).NotTo(ContainElement(failed))
+	})
+
+	It("connects to every server without a subset", func() {
+		pool.SetSubset(0, "")
+		Expect(pool.Subset()).To(BeNil())
+	})
+})
@@ -0,0 +1,116 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// A ServerError is an error response from a server.
+type ServerError struct {
+	Code    v1.ErrorCode
+	Message string
+}
+
+func (this *ServerError) Error() string {
+	return fmt.Sprintf("%s (%d)", this.Message, this.Code)
+}
+
+// A ThrottlePolicy makes operations wait and try again when a server reports that it is
+// overloaded, rather than failing at once and leaving callers to retry immediately. Error
+// responses carry no retry-after hint, so the delay is chosen by the policy: RetryAfter,
+// doubling for each consecutive throttled attempt up to MaxRetryAfter.
+type ThrottlePolicy struct {
+	// Error codes which mean that a server is overloaded. Default to NO_AVAILABLE_SERVER.
+	Codes []v1.ErrorCode
+	// Default to 100ms and 5s
+	RetryAfter    time.Duration
+	MaxRetryAfter time.Duration
+	// Maximum number of throttled attempts retried. 0 is unlimited, bounded only by the
+	// operation's deadline.
+	MaxRetries int
+}
+
+// A ThrottledError is returned when an operation is abandoned because a server is
+// overloaded and the policy's retries are exhausted, or the operation's deadline would pass
+// before the next attempt.
+type ThrottledError struct {
+	Attempts int
+	Err      *ServerError
+}
+
+func (this *ThrottledError) Error() string {
+	return fmt.Sprintf("server overloaded after %d attempts: %s", this.Attempts, this.Err.Error())
+}
+
+func (this *ThrottledError) Unwrap() error {
+	return this.Err
+}
+
+// SetThrottlePolicy enables waiting and retrying when a server reports that it is
+// overloaded. By default such error responses are returned at once, like any other.
+func (this *Protobuf) SetThrottlePolicy(policy *ThrottlePolicy) {
+	this.throttle = policy
+}
+
+// Return whether err reports that a server is overloaded
+func (this *ThrottlePolicy) throttled(err error) (*ServerError, bool) {
+	serverErr, ok := err.(*ServerError)
+	if !ok {
+		return nil, false
+	}
+
+	codes := this.Codes
+	if codes == nil {
+		codes = []v1.ErrorCode{v1.ErrorCode_NO_AVAILABLE_SERVER}
+	}
+	for _, code := range codes {
+		if serverErr.Code == code {
+			return serverErr, true
+		}
+	}
+	return nil, false
+}
+
+// Return the delay before retrying after the given number of consecutive throttled attempts
+func (this *ThrottlePolicy) retryAfter(throttles int) time.Duration {
+	delay, max := this.RetryAfter, this.MaxRetryAfter
+	if delay <= 0 {
+		delay = 100 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 5 * time.Second
+	}
+
+	for i := 1; i < throttles && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+// Wait before retrying an attempt which was throttled, or return the error which ends the
+// operation
+func (this *Protobuf) waitThrottled(ctx context.Context, deadline time.Time, throttles int, err *ServerError) error {
+	if this.throttle.MaxRetries > 0 && throttles > this.throttle.MaxRetries {
+		return &ThrottledError{Attempts: throttles, Err: err}
+	}
+
+	delay := this.throttle.retryAfter(throttles)
+	if !deadline.IsZero() && time.Until(deadline) <= delay {
+		return &ThrottledError{Attempts: throttles, Err: err}
+	}
+
+	timer := time.NewTimer(delay)
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,104 @@
+package connector_test
+
+import (
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Throttling", func() {
+
+	var connection *connector.Protobuf
+	var pool *connector.Pool
+	var publisher *recordingPublisher
+
+	errorResponse := func(code v1.ErrorCode) *v1.Message {
+		return &v1.Message{
+			MessageType: &v1.Message_ErrorResponse{
+				ErrorResponse: &v1.ErrorResponse{Error: &v1.Error{ErrorCode: code, Message: "busy"}},
+			},
+		}
+	}
+
+	// Add a connection answering every request with response. The pool uses the most
+	// recently added connection first.
+	addConnection := func(response *v1.Message) *connectorfakes.FakeConn {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(response, b)
+		}
+		pool.AddConnection(fakeConn, true)
+		return fakeConn
+	}
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+		publisher = &recordingPublisher{counters: make(map[string]int64)}
+		pool.SetMetricsPublisher(publisher)
+		connection = connector.NewConnector(pool)
+	})
+
+	It("returns error responses at once without a policy", func() {
+		addConnection(&v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}})
+		addConnection(errorResponse(v1.ErrorCode_NO_AVAILABLE_SERVER))
+
+		err := connection.Put("foo", "A", 1)
+		Expect(err).To(Equal(&connector.ServerError{Code: v1.ErrorCode_NO_AVAILABLE_SERVER, Message: "busy"}))
+		Expect(err.Error()).To(Equal("busy (101)"))
+	})
+
+	It("waits and retries when the server is overloaded", func() {
+		succeeding := addConnection(&v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}})
+		addConnection(errorResponse(v1.ErrorCode_NO_AVAILABLE_SERVER))
+		addConnection(errorResponse(v1.ErrorCode_NO_AVAILABLE_SERVER))
+		connection.SetThrottlePolicy(&connector.ThrottlePolicy{RetryAfter: 20 * time.Millisecond})
+
+		start := time.Now()
+		Expect(connection.Put("foo", "A", 1)).To(BeNil())
+		// 20ms, then 40ms
+		Expect(time.Since(start)).To(BeNumerically(">=", 60*time.Millisecond))
+		Expect(succeeding.WriteCallCount()).To(Equal(1))
+		Expect(publisher.counters[connector.MetricThrottledAttempts+"/"]).To(Equal(int64(2)))
+	})
+
+	It("does not retry other error responses", func() {
+		addConnection(&v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}})
+		addConnection(errorResponse(v1.ErrorCode_SERVER_ERROR))
+		connection.SetThrottlePolicy(&connector.ThrottlePolicy{})
+
+		err := connection.Put("foo", "A", 1)
+		Expect(err).To(BeAssignableToTypeOf(&connector.ServerError{}))
+		Expect(publisher.counters).ToNot(HaveKey(connector.MetricThrottledAttempts + "/"))
+	})
+
+	It("gives up after the maximum number of retries", func() {
+		for i := 0; i < 3; i++ {
+			addConnection(errorResponse(v1.ErrorCode_SERVER_ERROR))
+		}
+		connection.SetThrottlePolicy(&connector.ThrottlePolicy{
+			Codes:      []v1.ErrorCode{v1.ErrorCode_SERVER_ERROR},
+			RetryAfter: time.Millisecond,
+			MaxRetries: 1,
+		})
+
+		err := connection.Put("foo", "A", 1)
+		Expect(err).To(BeAssignableToTypeOf(&connector.ThrottledError{}))
+		Expect(err.(*connector.ThrottledError).Attempts).To(Equal(2))
+		Expect(pool.Stats().Connections).To(Equal(1))
+	})
+
+	It("gives up when the deadline would pass before the next attempt", func() {
+		addConnection(&v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}})
+		addConnection(errorResponse(v1.ErrorCode_NO_AVAILABLE_SERVER))
+		connection.SetThrottlePolicy(&connector.ThrottlePolicy{RetryAfter: time.Second})
+
+		start := time.Now()
+		err := connection.WithTimeout(100*time.Millisecond).Put("foo", "A", 1)
+		Expect(err).To(BeAssignableToTypeOf(&connector.ThrottledError{}))
+		Expect(time.Since(start)).To(BeNumerically("<", 100*time.Millisecond))
+	})
+})
@@ -0,0 +1,108 @@
+package connector
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// TimeoutLevels are the timeouts which may apply to an operation, from the least to the
+// most specific. A zero timeout is not set.
+type TimeoutLevels struct {
+	// Set with SetTimeout for every operation of a connector
+	Client time.Duration
+	// Set with SetRegionTimeout for operations on a region
+	Region time.Duration
+	// Set with WithTimeout for the operations of a single caller
+	Operation time.Duration
+}
+
+// ResolveDeadline returns the deadline of an operation started at start, or the zero time
+// if there is none. The most specific setting wins: the deadline of ctx if it has one, then
+// the operation, region and client timeouts, so that a caller can lengthen as well as
+// shorten a policy set for the whole client.
+func ResolveDeadline(ctx context.Context, start time.Time, levels TimeoutLevels) time.Time {
+	if deadline, ok := ctx.Deadline(); ok {
+		return deadline
+	}
+
+	for _, timeout := range []time.Duration{levels.Operation, levels.Region, levels.Client} {
+		if timeout > 0 {
+			return start.Add(timeout)
+		}
+	}
+
+	return time.Time{}
+}
+
+// The client and region timeouts, and the optional regions, shared by the copies of a
+// connector
+type timeoutPolicy struct {
+	sync.RWMutex
+	client   time.Duration
+	regions  map[string]time.Duration
+	optional map[string]bool
+}
+
+// SetTimeout sets the default timeout of every operation. 0 removes it.
+func (this *Protobuf) SetTimeout(timeout time.Duration) {
+	this.timeouts.Lock()
+	defer this.timeouts.Unlock()
+
+	this.timeouts.client = timeout
+}
+
+// SetRegionTimeout overrides the default timeout for operations on a region. 0 removes the
+// override.
+func (this *Protobuf) SetRegionTimeout(region string, timeout time.Duration) {
+	this.timeouts.Lock()
+	defer this.timeouts.Unlock()
+
+	if timeout == 0 {
+		delete(this.timeouts.regions, region)
+		return
+	}
+	if this.timeouts.regions == nil {
+		this.timeouts.regions = make(map[string]time.Duration)
+	}
+	this.timeouts.regions[region] = timeout
+}
+
+// WithTimeout returns a copy of this connector whose operations use timeout, overriding the
+// client and region timeouts. A context deadline (see WithContext) takes precedence.
+func (this *Protobuf) WithTimeout(timeout time.Duration) *Protobuf {
+	c := *this
+	c.timeout = timeout
+	return &c
+}
+
+// Return the timeouts which apply to an operation on region
+func (this *Protobuf) timeoutLevels(region string) TimeoutLevels {
+	this.timeouts.RLock()
+	defer this.timeouts.RUnlock()
+
+	return TimeoutLevels{
+		Client:    this.timeouts.client,
+		Region:    this.timeouts.regions[region],
+		Operation: this.timeout,
+	}
+}
+
+// Return the region a request operates on, or "" if it does not operate on one
+func requestRegion(request *v1.Message) string {
+	if request.MessageType == nil {
+		return ""
+	}
+
+	operation := reflect.ValueOf(request.MessageType).Elem().Field(0).Interface()
+	switch r := operation.(type) {
+	case interface{ GetRegionName() string }:
+		return r.GetRegionName()
+	case *v1.ExecuteFunctionOnRegionRequest:
+		return r.GetRegion()
+	}
+	return ""
+}
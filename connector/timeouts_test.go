@@ -0,0 +1,110 @@
+package connector_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Timeouts", func() {
+
+	Context("ResolveDeadline", func() {
+		start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+		var levels connector.TimeoutLevels
+
+		BeforeEach(func() {
+			levels = connector.TimeoutLevels{
+				Client:    time.Second,
+				Region:    2 * time.Second,
+				Operation: 3 * time.Second,
+			}
+		})
+
+		It("has no deadline when nothing is set", func() {
+			deadline := connector.ResolveDeadline(context.Background(), start, connector.TimeoutLevels{})
+			Expect(deadline.IsZero()).To(BeTrue())
+		})
+
+		It("uses the most specific timeout which is set", func() {
+			ctx := context.Background()
+			Expect(connector.ResolveDeadline(ctx, start, levels)).To(Equal(start.Add(3 * time.Second)))
+
+			levels.Operation = 0
+			Expect(connector.ResolveDeadline(ctx, start, levels)).To(Equal(start.Add(2 * time.Second)))
+
+			levels.Region = 0
+			Expect(connector.ResolveDeadline(ctx, start, levels)).To(Equal(start.Add(time.Second)))
+		})
+
+		It("prefers the context deadline, even when it is later", func() {
+			ctx, cancel := context.WithDeadline(context.Background(), start.Add(time.Hour))
+			defer cancel()
+
+			Expect(connector.ResolveDeadline(ctx, start, levels)).To(Equal(start.Add(time.Hour)))
+		})
+	})
+
+	Context("Operations", func() {
+		var connection *connector.Protobuf
+		var fakeConn *connectorfakes.FakeConn
+
+		BeforeEach(func() {
+			fakeConn = new(connectorfakes.FakeConn)
+			fakeConn.ReadStub = func(b []byte) (int, error) {
+				return writeFakeMessage(&v1.Message{
+					MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}},
+				}, b)
+			}
+			pool := connector.NewPool()
+			pool.AddConnection(fakeConn, true)
+			connection = connector.NewConnector(pool)
+
+			connection.SetTimeout(time.Minute)
+			connection.SetRegionTimeout("foo", time.Hour)
+		})
+
+		deadline := func() time.Time {
+			return fakeConn.SetDeadlineArgsForCall(fakeConn.SetDeadlineCallCount() - 2)
+		}
+
+		It("applies the client timeout", func() {
+			Expect(connection.Put("bar", "A", 1)).To(BeNil())
+			Expect(deadline()).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+		})
+
+		It("applies a region timeout to operations on that region", func() {
+			Expect(connection.Put("foo", "A", 1)).To(BeNil())
+			Expect(deadline()).To(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+
+			connection.SetRegionTimeout("foo", 0)
+			Expect(connection.Put("foo", "A", 1)).To(BeNil())
+			Expect(deadline()).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+		})
+
+		It("applies an operation timeout over the region timeout", func() {
+			Expect(connection.WithTimeout(time.Second).Put("foo", "A", 1)).To(BeNil())
+			Expect(deadline()).To(BeTemporally("~", time.Now().Add(time.Second), time.Second))
+		})
+
+		It("shares client and region timeouts with copies", func() {
+			copy := connection.WithTimeout(0)
+			connection.SetTimeout(2 * time.Minute)
+
+			Expect(copy.Put("bar", "A", 1)).To(BeNil())
+			Expect(deadline()).To(BeTemporally("~", time.Now().Add(2*time.Minute), time.Second))
+		})
+
+		It("applies the context deadline over every timeout", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+			defer cancel()
+
+			Expect(connection.WithTimeout(time.Second).WithContext(ctx).Put("foo", "A", 1)).To(BeNil())
+			Expect(deadline()).To(BeTemporally("~", time.Now().Add(2*time.Hour), time.Second))
+		})
+	})
+})
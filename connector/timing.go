@@ -0,0 +1,115 @@
+package connector
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// The time an operation spends serializing values, encoding those it sends and decoding
+// those it receives, and exchanging messages with servers. Times are summed over retries and
+// over the requests of operations split into chunks. Values decoded by several workers (see
+// SetDecodeWorkers) add the time taken by each.
+type operationTiming struct {
+	operation string
+	clock     Clock
+	// Whether the operation encodes or decodes values, rather than only exchanging messages
+	values        bool
+	serialization int64
+	network       int64
+	// Ends of the operation's spans, which wait until its results have been decoded
+	spans []func(timing *operationTiming)
+}
+
+// SetOperationTiming times the serialization of each operation's values apart from its
+// exchanges with servers, to tell a slow payload from a slow cluster. The times are set on the
+// operation's spans, as TraceAttributeSerializationTime and TraceAttributeNetworkTime, and
+// published as MetricSerializationLatency and MetricNetworkLatency if the pool's publisher is a
+// LatencyPublisher. Operations which encode and decode no values only record their network
+// time. MetricOperationLatency is unchanged. Timing is disabled by default, since it reads the
+// pool's clock around every value and every exchange.
+func (this *Protobuf) SetOperationTiming(enabled bool) {
+	this.timeValues = enabled
+}
+
+// Return a copy of this connector which times the serialization of an operation's values
+// apart from its exchanges with servers, and a function to call once the operation is done
+// to record the times. Within an operation which is already timed, or if timing is not
+// enabled, return this connector. See SetOperationTiming.
+func (this *Protobuf) timed(operation string) (*Protobuf, func()) {
+	if this.timing != nil || !this.timeValues {
+		return this, func() {}
+	}
+
+	c := *this
+	c.timing = &operationTiming{operation: operation, clock: this.pool.GetClock(), values: true}
+	return &c, func() {
+		c.timing.finish(c.pool.GetMetricsPublisher())
+	}
+}
+
+// Return the current time, or 0 if the operation is not timed
+func (this *operationTiming) now() time.Duration {
+	if this == nil {
+		return 0
+	}
+	return this.clock.Now()
+}
+
+// Add the time since start to the serialization time
+func (this *operationTiming) addSerialization(start time.Duration) {
+	if this != nil {
+		atomic.AddInt64(&this.serialization, int64(this.clock.Now()-start))
+	}
+}
+
+// Add the time since start to the network time
+func (this *operationTiming) addNetwork(start time.Duration) {
+	if this != nil {
+		atomic.AddInt64(&this.network, int64(this.clock.Now()-start))
+	}
+}
+
+func (this *operationTiming) serializationTime() time.Duration {
+	return time.Duration(atomic.LoadInt64(&this.serialization))
+}
+
+func (this *operationTiming) networkTime() time.Duration {
+	return time.Duration(atomic.LoadInt64(&this.network))
+}
+
+// Defer ending a span of the operation until its times are known, or end it now if the
+// operation is not timed
+func (this *operationTiming) deferSpan(end func(timing *operationTiming)) {
+	if this == nil {
+		end(nil)
+		return
+	}
+	this.spans = append(this.spans, end)
+}
+
+// Record the times of the operation on its spans and to publisher
+func (this *operationTiming) finish(publisher MetricsPublisher) {
+	for _, end := range this.spans {
+		end(this)
+	}
+	this.spans = nil
+
+	guarded := guardedPublisher{publisher}
+	if this.values {
+		guarded.ObserveLatency(MetricSerializationLatency, this.operation, this.serializationTime())
+	}
+	guarded.ObserveLatency(MetricNetworkLatency, this.operation, this.networkTime())
+}
+
+// Set the times of an operation on its span
+func setTimingAttributes(span Span, timing *operationTiming) {
+	if span == nil || timing == nil {
+		return
+	}
+
+	defer RecoverCallback("Tracer", nil)
+	if timing.values {
+		span.SetAttribute(TraceAttributeSerializationTime, timing.serializationTime())
+	}
+	span.SetAttribute(TraceAttributeNetworkTime, timing.networkTime())
+}
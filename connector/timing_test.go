@@ -0,0 +1,95 @@
+package connector_test
+
+import (
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Operation timing", func() {
+
+	var pool *connector.Pool
+	var fakeConn *connectorfakes.FakeConn
+	var connection *connector.Protobuf
+	var tracer *recordingTracer
+	var publisher *latencyPublisher
+
+	respond := func(response *v1.Message) {
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(response, b)
+		}
+	}
+
+	BeforeEach(func() {
+		fakeConn = new(connectorfakes.FakeConn)
+		pool = connector.NewPool()
+		pool.AddConnection(fakeConn, true)
+		tracer = &recordingTracer{}
+		pool.SetTracer(tracer)
+		publisher = &latencyPublisher{
+			recordingPublisher: recordingPublisher{counters: make(map[string]int64)},
+			latencies:          make(map[string][]time.Duration),
+		}
+		pool.SetMetricsPublisher(publisher)
+		pool.SetClock(&steppingClock{step: 7 * time.Millisecond})
+		connection = connector.NewConnector(pool)
+		connection.SetOperationTiming(true)
+	})
+
+	It("times serialization apart from the exchange", func() {
+		respond(&v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}})
+
+		Expect(connection.Put("foo", "A", 1)).To(Succeed())
+
+		Expect(tracer.spans).To(HaveLen(1))
+		span := tracer.spans[0]
+		Expect(span.ended).To(BeTrue())
+		Expect(span.attributes).To(HaveKeyWithValue(connector.TraceAttributeSerializationTime, 7*time.Millisecond))
+		Expect(span.attributes).To(HaveKeyWithValue(connector.TraceAttributeNetworkTime, 7*time.Millisecond))
+
+		Expect(publisher.latencies).To(HaveKeyWithValue(connector.MetricSerializationLatency+"/Put", []time.Duration{7 * time.Millisecond}))
+		Expect(publisher.latencies).To(HaveKeyWithValue(connector.MetricNetworkLatency+"/Put", []time.Duration{7 * time.Millisecond}))
+	})
+
+	It("is disabled by default", func() {
+		respond(&v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}})
+
+		Expect(connector.NewConnector(pool).Put("foo", "A", 1)).To(Succeed())
+
+		Expect(tracer.spans).To(HaveLen(1))
+		Expect(tracer.spans[0].ended).To(BeTrue())
+		Expect(tracer.spans[0].attributes).NotTo(HaveKey(connector.TraceAttributeNetworkTime))
+		Expect(publisher.latencies).To(Equal(map[string][]time.Duration{
+			connector.MetricOperationLatency + "/Put": {7 * time.Millisecond},
+		}))
+	})
+
+	It("includes decoding the response in the serialization time", func() {
+		value, _ := connector.EncodeValue("A")
+		respond(&v1.Message{MessageType: &v1.Message_GetResponse{GetResponse: &v1.GetResponse{Result: value}}})
+
+		Expect(connection.Get("foo", "A", nil)).To(Equal("A"))
+
+		Expect(tracer.spans).To(HaveLen(1))
+		Expect(tracer.spans[0].attributes).To(HaveKeyWithValue(connector.TraceAttributeSerializationTime, 7*time.Millisecond))
+		Expect(publisher.latencies).To(HaveKey(connector.MetricSerializationLatency + "/Get"))
+	})
+
+	It("only times the exchange of operations without values", func() {
+		respond(&v1.Message{MessageType: &v1.Message_RemoveResponse{RemoveResponse: &v1.RemoveResponse{}}})
+
+		Expect(connection.Remove("foo", "A")).To(Succeed())
+
+		Expect(tracer.spans).To(HaveLen(1))
+		span := tracer.spans[0]
+		Expect(span.attributes).NotTo(HaveKey(connector.TraceAttributeSerializationTime))
+		Expect(span.attributes).To(HaveKeyWithValue(connector.TraceAttributeNetworkTime, 7*time.Millisecond))
+
+		Expect(publisher.latencies).NotTo(HaveKey(connector.MetricSerializationLatency + "/Remove"))
+		Expect(publisher.latencies).To(HaveKey(connector.MetricNetworkLatency + "/Remove"))
+	})
+})
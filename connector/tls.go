@@ -0,0 +1,60 @@
+package connector
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// SetTLSConfig makes the pool connect to servers with TLS, using a copy of config. It
+// applies to new connections, including those to servers already added. A nil config
+// connects without TLS.
+//
+// If config does not set a ServerName, the host name of each server is verified.
+func (this *Pool) SetTLSConfig(config *tls.Config) {
+	this.Lock()
+	defer this.Unlock()
+
+	if config != nil {
+		config = config.Clone()
+	}
+	this.tlsConfig = config
+
+	for _, p := range this.providers {
+		if server, ok := p.(*serverConnectionProvider); ok {
+			server.tlsConfig = config
+		}
+	}
+	this.syncPartitions()
+}
+
+// LoadTLSConfig creates a TLS configuration from PEM encoded files. Servers are verified
+// against the certificates in caFile, or the system's if caFile is "". certFile and keyFile
+// hold a client certificate for clusters which require one, and may both be "".
+func LoadTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	config := &tls.Config{}
+
+	if caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New(fmt.Sprintf("no certificates found in %s", caFile))
+		}
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,161 @@
+package connector_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// A certificate and its key, signed by parent or self-signed if parent is nil
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCert(name string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).To(BeNil())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	Expect(err).To(BeNil())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).To(BeNil())
+
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+func (this *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{this.der}, PrivateKey: this.key}
+}
+
+// Write the certificate and key as PEM files in dir, returning their paths
+func (this *testCert) write(dir, name string) (string, string) {
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+
+	keyDer, err := x509.MarshalECPrivateKey(this.key)
+	Expect(err).To(BeNil())
+	Expect(ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: this.der}), 0600)).To(Succeed())
+	Expect(ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)).To(Succeed())
+
+	return certFile, keyFile
+}
+
+var _ = Describe("TLS", func() {
+
+	var dir string
+	var ca *testCert
+	var caFile, certFile, keyFile string
+	var server *handshakeServer
+	var pool *connector.Pool
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "tls")
+		Expect(err).To(BeNil())
+
+		ca = newTestCert("ca", nil)
+		caFile, _ = ca.write(dir, "ca")
+		certFile, keyFile = newTestCert("client", ca).write(dir, "client")
+
+		clientCAs := x509.NewCertPool()
+		clientCAs.AddCert(ca.cert)
+		listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+			Certificates: []tls.Certificate{newTestCert("server", ca).tlsCertificate()},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientCAs,
+		})
+		Expect(err).To(BeNil())
+		server = serveHandshakes(listener)
+
+		pool = connector.NewPool()
+	})
+
+	AfterEach(func() {
+		server.listener.Close()
+		os.RemoveAll(dir)
+	})
+
+	addServer := func() {
+		host, port, err := net.SplitHostPort(server.address())
+		Expect(err).To(BeNil())
+		p, err := net.LookupPort("tcp", port)
+		Expect(err).To(BeNil())
+		pool.AddServer(host, p)
+	}
+
+	It("connects with a client certificate and a custom CA", func() {
+		addServer()
+		config, err := connector.LoadTLSConfig(caFile, certFile, keyFile)
+		Expect(err).To(BeNil())
+		pool.SetTLSConfig(config)
+
+		c, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		_, ok := c.GetRawConnection().(*tls.Conn)
+		Expect(ok).To(BeTrue())
+		pool.ReturnConnection(c)
+	})
+
+	It("applies to servers added later", func() {
+		config, err := connector.LoadTLSConfig(caFile, certFile, keyFile)
+		Expect(err).To(BeNil())
+		pool.SetTLSConfig(config)
+		addServer()
+
+		c, err := pool.GetConnection()
+		Expect(err).To(BeNil())
+		pool.ReturnConnection(c)
+	})
+
+	It("does not connect to a server with an untrusted certificate", func() {
+		otherFile, _ := newTestCert("other", nil).write(dir, "other")
+		config, err := connector.LoadTLSConfig(otherFile, certFile, keyFile)
+		Expect(err).To(BeNil())
+		pool.SetTLSConfig(config)
+		addServer()
+
+		_, err = pool.GetConnection()
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("returns an error for a CA file without certificates", func() {
+		_, keyFile := ca.write(dir, "ca")
+
+		_, err := connector.LoadTLSConfig(keyFile, "", "")
+		Expect(err).To(MatchError(ContainSubstring("no certificates found")))
+	})
+})
@@ -0,0 +1,108 @@
+package connector
+
+import (
+	"context"
+
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+)
+
+// Names of the attributes set on the span of each operation, following OpenTelemetry's
+// conventions where they have one
+const (
+	TraceAttributeSystem    = "db.system"
+	TraceAttributeOperation = "db.operation"
+	TraceAttributeRegion    = "geode.region"
+	TraceAttributeKeyCount  = "geode.key_count"
+	TraceAttributeServer    = "server.address"
+	TraceAttributeRetries   = "geode.retries"
+	// Durations spent encoding and decoding values, and exchanging messages with
+	// servers, telling apart slow payloads from a slow cluster. See SetOperationTiming.
+	TraceAttributeSerializationTime = "geode.serialization_time"
+	TraceAttributeNetworkTime       = "geode.network_time"
+)
+
+// A Tracer starts a span for each operation, so that calls to the cluster appear in
+// distributed traces. It is typically an adapter for an OpenTelemetry trace.Tracer. The
+// span is started with the operation's context and named by the operation, such as "Put";
+// operations split into batches, such as GetAll, have a span per batch.
+type Tracer interface {
+	StartSpan(ctx context.Context, operation string) Span
+}
+
+// A Span records a single operation. Its attributes are set before End, which is passed the
+// operation's error, if any.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	End(err error)
+}
+
+// SetTracer sets the Tracer which records this pool's operations. nil, the default,
+// disables tracing.
+func (this *Pool) SetTracer(tracer Tracer) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.tracer = tracer
+	this.syncPartitions()
+}
+
+// GetTracer returns the Tracer set for this pool, or nil.
+func (this *Pool) GetTracer() Tracer {
+	this.RLock()
+	defer this.RUnlock()
+
+	return this.tracer
+}
+
+// Start a span for a request, returning nil if there is no tracer
+func startSpan(tracer Tracer, ctx context.Context, request *v1.Message) (span Span) {
+	if tracer == nil {
+		return nil
+	}
+
+	defer RecoverCallback("Tracer", nil)
+	span = tracer.StartSpan(ctx, operationName(request))
+	if span == nil {
+		return nil
+	}
+
+	span.SetAttribute(TraceAttributeSystem, "geode")
+	span.SetAttribute(TraceAttributeOperation, operationName(request))
+	if region := requestRegion(request); region != "" {
+		span.SetAttribute(TraceAttributeRegion, region)
+	}
+	if count, ok := requestKeyCount(request); ok {
+		span.SetAttribute(TraceAttributeKeyCount, count)
+	}
+
+	return span
+}
+
+// End the span of an operation, if any, with the outcome of its final attempt
+func endSpan(span Span, server string, retries int, err error) {
+	if span == nil {
+		return
+	}
+
+	defer RecoverCallback("Tracer", nil)
+	if server != "" {
+		span.SetAttribute(TraceAttributeServer, server)
+	}
+	span.SetAttribute(TraceAttributeRetries, retries)
+	span.End(err)
+}
+
+// Return the number of keys a request operates on, if it is keyed
+func requestKeyCount(request *v1.Message) (int, bool) {
+	switch r := request.MessageType.(type) {
+	case *v1.Message_PutRequest, *v1.Message_PutIfAbsentRequest, *v1.Message_GetRequest, *v1.Message_RemoveRequest:
+		return 1, true
+	case *v1.Message_GetAllRequest:
+		return len(r.GetAllRequest.Key), true
+	case *v1.Message_PutAllRequest:
+		return len(r.PutAllRequest.Entry), true
+	case *v1.Message_ExecuteFunctionOnRegionRequest:
+		return len(r.ExecuteFunctionOnRegionRequest.KeyFilter), true
+	}
+	return 0, false
+}
@@ -0,0 +1,139 @@
+package connector_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type recordedSpan struct {
+	operation  string
+	ctx        context.Context
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (this *recordedSpan) SetAttribute(key string, value interface{}) {
+	this.attributes[key] = value
+}
+
+func (this *recordedSpan) End(err error) {
+	this.err = err
+	this.ended = true
+}
+
+// A Tracer which records the spans it starts
+type recordingTracer struct {
+	sync.Mutex
+	spans []*recordedSpan
+}
+
+func (this *recordingTracer) StartSpan(ctx context.Context, operation string) connector.Span {
+	this.Lock()
+	defer this.Unlock()
+
+	span := &recordedSpan{operation: operation, ctx: ctx, attributes: make(map[string]interface{})}
+	this.spans = append(this.spans, span)
+	return span
+}
+
+type panickingTracer struct{}
+
+func (this panickingTracer) StartSpan(ctx context.Context, operation string) connector.Span {
+	panic("tracer failed")
+}
+
+var _ = Describe("Tracing", func() {
+
+	var pool *connector.Pool
+	var connection *connector.Protobuf
+	var tracer *recordingTracer
+
+	// Add a connection which answers every request with response
+	addConnection := func(pool *connector.Pool, response *v1.Message) *connectorfakes.FakeConn {
+		fakeConn := new(connectorfakes.FakeConn)
+		fakeConn.ReadStub = func(b []byte) (int, error) {
+			return writeFakeMessage(response, b)
+		}
+		pool.AddConnection(fakeConn, true)
+		return fakeConn
+	}
+
+	putResponse := &v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}}
+
+	BeforeEach(func() {
+		pool = connector.NewPool()
+		connection = connector.NewConnector(pool)
+		tracer = &recordingTracer{}
+		pool.SetTracer(tracer)
+	})
+
+	It("records a span for each operation", func() {
+		addConnection(pool, putResponse)
+		type key string
+		ctx := context.WithValue(context.Background(), key("trace"), "parent")
+
+		Expect(connection.PutCtx(ctx, "foo", "A", 1)).To(Succeed())
+
+		Expect(tracer.spans).To(HaveLen(1))
+		span := tracer.spans[0]
+		Expect(span.operation).To(Equal("Put"))
+		Expect(span.ctx.Value(key("trace"))).To(Equal("parent"))
+		Expect(span.attributes).To(HaveKeyWithValue(connector.TraceAttributeSystem, "geode"))
+		Expect(span.attributes).To(HaveKeyWithValue(connector.TraceAttributeOperation, "Put"))
+		Expect(span.attributes).To(HaveKeyWithValue(connector.TraceAttributeRegion, "foo"))
+		Expect(span.attributes).To(HaveKeyWithValue(connector.TraceAttributeKeyCount, 1))
+		Expect(span.attributes).To(HaveKeyWithValue(connector.TraceAttributeRetries, 0))
+		Expect(span.ended).To(BeTrue())
+		Expect(span.err).To(BeNil())
+	})
+
+	It("counts the keys of bulk operations", func() {
+		addConnection(pool, &v1.Message{MessageType: &v1.Message_GetAllResponse{GetAllResponse: &v1.GetAllResponse{}}})
+
+		_, _, err := connection.GetAll("foo", []string{"A", "B", "C"})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(tracer.spans[0].attributes).To(HaveKeyWithValue(connector.TraceAttributeKeyCount, 3))
+	})
+
+	It("records retries and the final error", func() {
+		for i := 0; i < 3; i++ {
+			fakeConn := new(connectorfakes.FakeConn)
+			fakeConn.WriteReturns(0, &net.OpError{Op: "write", Err: errors.New("fake retryable write error")})
+			pool.AddConnection(fakeConn, true)
+		}
+		connection.SetRetryBudget(&connector.RetryBudget{MaxRetries: 2})
+
+		err := connection.Put("foo", "A", 1)
+		Expect(err).To(HaveOccurred())
+
+		span := tracer.spans[0]
+		Expect(span.attributes).To(HaveKeyWithValue(connector.TraceAttributeRetries, 2))
+		Expect(span.err).To(Equal(err))
+	})
+
+	It("is shared with partitions", func() {
+		bulk := pool.Partition("bulk")
+		addConnection(bulk, putResponse)
+
+		Expect(connection.WithPartition("bulk").Put("foo", "A", 1)).To(Succeed())
+
+		Expect(tracer.spans).To(HaveLen(1))
+	})
+
+	It("survives a panicking tracer", func() {
+		pool.SetTracer(panickingTracer{})
+		addConnection(pool, putResponse)
+
+		Expect(connection.Put("foo", "A", 1)).To(Succeed())
+	})
+})
@@ -0,0 +1,146 @@
+package geode_go_client
+
+import (
+	"context"
+
+	"github.com/gemfire/geode-go-client/connector"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	. "github.com/gemfire/geode-go-client/query"
+)
+
+// The Ctx variants of each operation are bound by ctx: the operation is abandoned once ctx is
+// done, even while waiting for a server to respond, and returns ctx.Err(). The deadline of
+// ctx, if any, overrides the connector's timeouts.
+
+func (this *Client) PutCtx(ctx context.Context, region string, key, value interface{}) error {
+	return this.put(this.connector.BindContext(ctx), region, key, value)
+}
+
+func (this *Client) PutIfAbsentCtx(ctx context.Context, region string, key, value interface{}) error {
+	return this.putIfAbsent(this.connector.BindContext(ctx), region, key, value)
+}
+
+func (this *Client) GetCtx(ctx context.Context, region string, key interface{}, value ...interface{}) (interface{}, error) {
+	return this.get(this.connector.BindContext(ctx), region, key, value...)
+}
+
+func (this *Client) GetOptionalCtx(ctx context.Context, region string, key interface{}, value ...interface{}) (connector.Optional, error) {
+	return this.getOptional(this.connector.BindContext(ctx), region, key, value...)
+}
+
+func (this *Client) GetIfExistsCtx(ctx context.Context, region string, key interface{}, value ...interface{}) (interface{}, bool, error) {
+	result, err := this.getOptional(this.connector.BindContext(ctx), region, key, value...)
+	return result.Value, result.Present, err
+}
+
+func (this *Client) PutRawCtx(ctx context.Context, region string, key, value *v1.EncodedValue) error {
+	return this.putRaw(this.connector.BindContext(ctx), region, key, value)
+}
+
+func (this *Client) GetRawCtx(ctx context.Context, region string, key *v1.EncodedValue) (*v1.EncodedValue, error) {
+	return this.getRaw(this.connector.BindContext(ctx), region, key)
+}
+
+func (this *Client) PutAllCtx(ctx context.Context, region string, entries interface{}) (map[interface{}]error, error) {
+	return this.putAll(this.connector.BindContext(ctx), region, entries)
+}
+
+func (this *Client) GetAllCtx(ctx context.Context, region string, keys interface{}) (map[interface{}]interface{}, map[interface{}]error, error) {
+	return this.getAll(this.connector.BindContext(ctx), region, keys)
+}
+
+func (this *Client) GetAllFuncCtx(ctx context.Context, region string, keys interface{}, fn func(key, value interface{}, err error) bool) error {
+	return this.getAllInto(this.connector.BindContext(ctx), region, keys, nil, fn)
+}
+
+func (this *Client) GetAllIntoCtx(ctx context.Context, region string, keys interface{}, newRef func() interface{}, fn func(key, value interface{}, err error) bool) error {
+	return this.getAllInto(this.connector.BindContext(ctx), region, keys, newRef, fn)
+}
+
+func (this *Client) GetAllLazyCtx(ctx context.Context, region string, keys interface{}) (map[interface{}]*connector.LazyValue, map[interface{}]error, error) {
+	return this.getAllLazy(this.connector.BindContext(ctx), region, keys)
+}
+
+func (this *Client) KeysCtx(ctx context.Context, region string) ([]interface{}, error) {
+	return this.keys(this.connector.BindContext(ctx), region)
+}
+
+func (this *Client) KeysFuncCtx(ctx context.Context, region string, fn func(key interface{}) bool) error {
+	return this.keysFunc(this.connector.BindContext(ctx), region, fn)
+}
+
+func (this *Client) RemoveCtx(ctx context.Context, region string, key interface{}) error {
+	return this.remove(this.connector.BindContext(ctx), region, key)
+}
+
+func (this *Client) RemoveAllCtx(ctx context.Context, region string, keys interface{}) (map[interface{}]error, error) {
+	return this.removeAll(this.connector.BindContext(ctx), region, keys)
+}
+
+func (this *Client) PutNullCtx(ctx context.Context, region string, key interface{}) error {
+	return this.putNull(this.connector.BindContext(ctx), region, key)
+}
+
+func (this *Client) ClearCtx(ctx context.Context, region string) error {
+	return this.clear(this.connector.BindContext(ctx), region)
+}
+
+func (this *Client) SizeCtx(ctx context.Context, region string) (int32, error) {
+	return this.size(this.connector.BindContext(ctx), region)
+}
+
+func (this *Client) RegionNamesCtx(ctx context.Context) ([]string, error) {
+	return this.regionNames(this.connector.BindContext(ctx))
+}
+
+func (this *Client) GetRegionCtx(ctx context.Context, region string) (*RegionInfo, error) {
+	return this.getRegion(this.connector.BindContext(ctx), region)
+}
+
+func (this *Client) ExecuteOnRegionCtx(ctx context.Context, functionId, region string, functionArgs interface{}, keyFilter []interface{}) ([]interface{}, error) {
+	return this.executeOnRegion(this.connector.BindContext(ctx), functionId, region, functionArgs, keyFilter)
+}
+
+func (this *Client) ExecuteOnMembersCtx(ctx context.Context, functionId string, members []string, functionArgs interface{}) ([]interface{}, error) {
+	return this.executeOnMembers(this.connector.BindContext(ctx), functionId, members, functionArgs)
+}
+
+func (this *Client) ExecuteOnGroupsCtx(ctx context.Context, functionId string, groups []string, functionArgs interface{}) ([]interface{}, error) {
+	return this.executeOnGroups(this.connector.BindContext(ctx), functionId, groups, functionArgs)
+}
+
+func (this *Client) ExecuteOnRegionFuncCtx(ctx context.Context, functionId, region string, functionArgs interface{}, keyFilter []interface{}, fn func(result interface{}) bool) error {
+	return this.executeOnRegionFunc(this.connector.BindContext(ctx), functionId, region, functionArgs, keyFilter, fn)
+}
+
+func (this *Client) ExecuteOnMembersFuncCtx(ctx context.Context, functionId string, members []string, functionArgs interface{}, fn func(result interface{}) bool) error {
+	return this.executeOnMembersFunc(this.connector.BindContext(ctx), functionId, members, functionArgs, fn)
+}
+
+func (this *Client) ExecuteOnGroupsFuncCtx(ctx context.Context, functionId string, groups []string, functionArgs interface{}, fn func(result interface{}) bool) error {
+	return this.executeOnGroupsFunc(this.connector.BindContext(ctx), functionId, groups, functionArgs, fn)
+}
+
+func (this *Client) QueryForSingleResultCtx(ctx context.Context, query *Query) (interface{}, error) {
+	return this.queryForSingleResult(this.connector.BindContext(ctx), query)
+}
+
+func (this *Client) QueryForListResultCtx(ctx context.Context, query *Query) ([]interface{}, error) {
+	return this.queryForListResult(this.connector.BindContext(ctx), query)
+}
+
+func (this *Client) QueryForTableResultCtx(ctx context.Context, query *Query) (map[string][]interface{}, error) {
+	return this.queryForTableResult(this.connector.BindContext(ctx), query)
+}
+
+func (this *Client) QueryForListResultLazyCtx(ctx context.Context, query *Query) ([]*connector.LazyValue, error) {
+	return this.queryForListResultLazy(this.connector.BindContext(ctx), query)
+}
+
+func (this *Client) QueryForTableResultLazyCtx(ctx context.Context, query *Query) (map[string][]*connector.LazyValue, error) {
+	return this.queryForTableResultLazy(this.connector.BindContext(ctx), query)
+}
+
+func (this *Client) QueryRowsCtx(ctx context.Context, query *Query) (*connector.Rows, error) {
+	return this.queryRows(this.connector.BindContext(ctx), query)
+}
@@ -0,0 +1,50 @@
+package geode_go_client_test
+
+import (
+	"context"
+
+	geode "github.com/gemfire/geode-go-client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Context variants", func() {
+
+	var cluster *fakeCluster
+	var client *geode.Client
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		client = geode.NewGeodeClient(cluster.connector())
+	})
+
+	It("performs operations as usual", func() {
+		ctx := context.Background()
+		Expect(client.PutCtx(ctx, "foo", "A", "x")).To(BeNil())
+		Expect(client.GetCtx(ctx, "foo", "A")).To(Equal("x"))
+		Expect(client.SizeCtx(ctx, "foo")).To(Equal(int32(1)))
+		Expect(client.RemoveCtx(ctx, "foo", "A")).To(BeNil())
+		Expect(cluster.keys("foo")).To(BeEmpty())
+	})
+
+	It("does not send requests once the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		Expect(client.PutCtx(ctx, "foo", "A", "x")).To(Equal(context.Canceled))
+		_, err := client.GetCtx(ctx, "foo", "A")
+		Expect(err).To(Equal(context.Canceled))
+		_, _, err = client.GetAllCtx(ctx, "foo", []string{"A"})
+		Expect(err).To(Equal(context.Canceled))
+		Expect(cluster.requests).To(BeEmpty())
+
+		Expect(client.Put("foo", "A", "x")).To(BeNil())
+	})
+
+	It("still applies transforms and access control", func() {
+		client.SetReadOnly(true)
+
+		Expect(client.PutCtx(context.Background(), "foo", "A", "x")).ToNot(BeNil())
+		Expect(cluster.requests).To(BeEmpty())
+	})
+})
@@ -0,0 +1,32 @@
+// Package debug serves the state of a connection pool over HTTP, for a service's debug
+// endpoint. It is kept apart from the connector so that programs which do not use it do not
+// depend on net/http.
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+// Handler returns a handler which responds to every request with the pool's
+// connector.DebugSnapshot as JSON: its connection statistics, the operations on each region,
+// recent errors and its configuration, with the password redacted. For example:
+//
+//	http.Handle("/debug/geode", debug.Handler(pool))
+//
+// The snapshot reveals server addresses and the username, so the handler should only be
+// served where the pool's configuration may be seen.
+func Handler(pool *connector.Pool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := json.MarshalIndent(pool.DebugSnapshot(), "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	})
+}
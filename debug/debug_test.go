@@ -0,0 +1,36 @@
+package debug_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/debug"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Handler", func() {
+
+	It("serves a snapshot of the pool as JSON", func() {
+		pool := connector.NewPool()
+		pool.AddServer("localhost", 40404)
+		pool.AddCredentials("jbloggs", "t0p53cr3t")
+
+		recorder := httptest.NewRecorder()
+		debug.Handler(pool).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/geode", nil))
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(recorder.Body.String()).ToNot(ContainSubstring("t0p53cr3t"))
+
+		var body map[string]interface{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &body)).To(Succeed())
+		Expect(body).To(HaveKey("stats"))
+		Expect(body).To(HaveKey("regions"))
+		Expect(body).To(HaveKey("recentErrors"))
+		Expect(body["config"]).To(HaveKeyWithValue("servers", []interface{}{"localhost:40404"}))
+		Expect(body["config"]).To(HaveKeyWithValue("password", "REDACTED"))
+	})
+})
@@ -0,0 +1,186 @@
+// Package eventbridge forwards region events from a Geode cluster to another system, such as
+// Kafka, with at-least-once delivery.
+//
+// The client does not yet support registering interest or continuous queries, since the
+// protobuf protocol has no messages for server-pushed events, so events must be supplied by
+// an implementation of Source. Once the protocol supports subscriptions they will provide
+// one.
+//
+// A Sink for Kafka might look like this, using github.com/segmentio/kafka-go:
+//
+//	type kafkaSink struct {
+//	    writer *kafka.Writer
+//	}
+//
+//	func (this kafkaSink) Send(ctx context.Context, record *eventbridge.Record) error {
+//	    value, err := json.Marshal(record.Event)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    return this.writer.WriteMessages(ctx, kafka.Message{Key: []byte(record.Key), Value: value})
+//	}
+//
+//	bridge := eventbridge.NewBridge(source, kafkaSink{writer})
+//	err := bridge.Run(ctx)
+//
+// Using the record key as the Kafka message key keeps the events for each entry in order
+// within a partition.
+package eventbridge
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// An Operation is the kind of change recorded by an Event
+type Operation int
+
+const (
+	Create Operation = iota
+	Update
+	Destroy
+)
+
+func (o Operation) String() string {
+	switch o {
+	case Create:
+		return "create"
+	case Update:
+		return "update"
+	case Destroy:
+		return "destroy"
+	}
+	return fmt.Sprintf("Operation(%d)", int(o))
+}
+
+// An Event is a change to an entry of a region.
+type Event struct {
+	Region    string      `json:"region"`
+	Key       interface{} `json:"key"`
+	Value     interface{} `json:"value,omitempty"`
+	Operation Operation   `json:"operation"`
+	// Assigned by the Source, for example to identify the event when it is acknowledged
+	Sequence uint64 `json:"sequence"`
+}
+
+// A Source supplies region events in the order they occurred.
+type Source interface {
+	// Next blocks until an event is available or ctx is done.
+	Next(ctx context.Context) (*Event, error)
+	// Ack is called once an event has been accepted by the Sink. The Source may then
+	// forget it; unacknowledged events should be delivered again after a restart.
+	Ack(event *Event) error
+}
+
+// A Record is an event along with the key which orders it.
+type Record struct {
+	// Events with the same key are sent in the order they occurred
+	Key   string
+	Event *Event
+}
+
+// A Sink receives records. Send must return nil only once the record is durably accepted,
+// for example acknowledged by the Kafka brokers, since the event is then acknowledged to the
+// Source. A record may be sent again after an error or a restart, so Sinks should tolerate
+// duplicates.
+type Sink interface {
+	Send(ctx context.Context, record *Record) error
+}
+
+// A Bridge forwards events from a Source to a Sink one at a time, so that events are sent in
+// order and each is acknowledged only after it has been sent.
+type Bridge struct {
+	source Source
+	sink   Sink
+	// Returns the ordering key of an event. Defaults to the region and key of the entry.
+	OrderingKey func(event *Event) string
+	// Delay between attempts to send a record, doubling up to MaxBackoff. Default to 100ms
+	// and 5s.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Called with each error from the Sink before the record is sent again. Optional.
+	OnError func(record *Record, err error)
+}
+
+// NewBridge creates a Bridge from source to sink.
+func NewBridge(source Source, sink Sink) *Bridge {
+	return &Bridge{
+		source: source,
+		sink:   sink,
+	}
+}
+
+// DefaultOrderingKey orders the events of each entry, identified by its region and key.
+func DefaultOrderingKey(event *Event) string {
+	return fmt.Sprintf("%s/%v", event.Region, event.Key)
+}
+
+// Run forwards events until ctx is done or the Source or an acknowledgement fails. Records
+// which the Sink rejects are sent again, with backoff, until they are accepted. It returns
+// the error which stopped it, which is ctx.Err() if ctx is done.
+func (this *Bridge) Run(ctx context.Context) error {
+	orderingKey := this.OrderingKey
+	if orderingKey == nil {
+		orderingKey = DefaultOrderingKey
+	}
+
+	for {
+		event, err := this.source.Next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		record := &Record{Key: orderingKey(event), Event: event}
+		if err := this.send(ctx, record); err != nil {
+			return err
+		}
+
+		if err := this.source.Ack(event); err != nil {
+			return err
+		}
+	}
+}
+
+// Send a record until the Sink accepts it or ctx is done
+func (this *Bridge) send(ctx context.Context, record *Record) error {
+	backoff := this.InitialBackoff
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+	maxBackoff := this.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 5 * time.Second
+	}
+
+	for {
+		err := this.sink.Send(ctx, record)
+		if err == nil {
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if this.OnError != nil {
+			this.OnError(record, err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
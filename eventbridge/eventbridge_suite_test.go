@@ -0,0 +1,13 @@
+package eventbridge_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestEventbridge(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Event Bridge Suite")
+}
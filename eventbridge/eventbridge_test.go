@@ -0,0 +1,136 @@
+package eventbridge_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gemfire/geode-go-client/eventbridge"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// A Source which supplies a fixed list of events, then waits for ctx to be done
+type listSource struct {
+	events []*eventbridge.Event
+	acked  []uint64
+	ackErr error
+}
+
+func (this *listSource) Next(ctx context.Context) (*eventbridge.Event, error) {
+	if len(this.events) == 0 {
+		<-ctx.Done()
+		return nil, errors.New("closed")
+	}
+
+	event := this.events[0]
+	this.events = this.events[1:]
+	return event, nil
+}
+
+func (this *listSource) Ack(event *eventbridge.Event) error {
+	this.acked = append(this.acked, event.Sequence)
+	return this.ackErr
+}
+
+// A Sink which fails a number of times before accepting each record, cancelling ctx once it
+// has accepted them all
+type flakySink struct {
+	failures int
+	failed   int
+	records  []*eventbridge.Record
+	expected int
+	cancel   context.CancelFunc
+	source   *listSource
+	ackedAt  [][]uint64
+}
+
+func (this *flakySink) Send(ctx context.Context, record *eventbridge.Record) error {
+	this.ackedAt = append(this.ackedAt, append([]uint64(nil), this.source.acked...))
+	if this.failed < this.failures {
+		this.failed++
+		return errors.New("unavailable")
+	}
+
+	this.failed = 0
+	this.records = append(this.records, record)
+	if len(this.records) == this.expected {
+		this.cancel()
+	}
+	return nil
+}
+
+var _ = Describe("Bridge", func() {
+
+	var source *listSource
+	var sink *flakySink
+	var bridge *eventbridge.Bridge
+	var ctx context.Context
+
+	BeforeEach(func() {
+		source = &listSource{
+			events: []*eventbridge.Event{
+				{Region: "foo", Key: "A", Value: 1, Operation: eventbridge.Create, Sequence: 1},
+				{Region: "foo", Key: "A", Value: 2, Operation: eventbridge.Update, Sequence: 2},
+				{Region: "bar", Key: 7, Operation: eventbridge.Destroy, Sequence: 3},
+			},
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		sink = &flakySink{expected: 3, cancel: cancel, source: source}
+
+		bridge = eventbridge.NewBridge(source, sink)
+		bridge.InitialBackoff = time.Millisecond
+	})
+
+	It("sends events in order with their ordering keys", func() {
+		Expect(bridge.Run(ctx)).To(Equal(context.Canceled))
+
+		Expect(sink.records).To(HaveLen(3))
+		Expect(sink.records[0].Key).To(Equal("foo/A"))
+		Expect(sink.records[1].Event.Value).To(Equal(2))
+		Expect(sink.records[2].Key).To(Equal("bar/7"))
+		Expect(source.acked).To(Equal([]uint64{1, 2, 3}))
+	})
+
+	It("uses a custom ordering key", func() {
+		bridge.OrderingKey = func(event *eventbridge.Event) string {
+			return event.Region
+		}
+
+		bridge.Run(ctx)
+		Expect(sink.records[0].Key).To(Equal("foo"))
+	})
+
+	It("retries records and acknowledges them only once they are sent", func() {
+		sink.failures = 2
+		var errs []error
+		bridge.OnError = func(record *eventbridge.Record, err error) {
+			errs = append(errs, err)
+		}
+
+		Expect(bridge.Run(ctx)).To(Equal(context.Canceled))
+
+		Expect(errs).To(HaveLen(6))
+		Expect(source.acked).To(Equal([]uint64{1, 2, 3}))
+		// Every attempt at the second event happens before it is acknowledged
+		Expect(sink.ackedAt[3:6]).To(Equal([][]uint64{{1}, {1}, {1}}))
+	})
+
+	It("stops when an acknowledgement fails", func() {
+		source.ackErr = errors.New("ack failed")
+
+		Expect(bridge.Run(ctx)).To(MatchError("ack failed"))
+		Expect(sink.records).To(HaveLen(1))
+	})
+
+	It("stops retrying when ctx is done", func() {
+		sink.failures = 1000
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		Expect(bridge.Run(ctx)).To(Equal(context.DeadlineExceeded))
+		Expect(source.acked).To(BeEmpty())
+	})
+})
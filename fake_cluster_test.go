@@ -0,0 +1,196 @@
+package geode_go_client_test
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+)
+
+// A fakeCluster is a minimal in-memory implementation of the region operations, served over
+// a fake connection.
+type fakeCluster struct {
+	sync.Mutex
+	regions  map[string]map[string]*v1.Entry
+	requests []*v1.Message
+	response *v1.Message
+	// Answers every query, if set
+	queryResult *v1.OQLQueryResponse
+}
+
+func newFakeCluster() *fakeCluster {
+	return &fakeCluster{
+		regions: make(map[string]map[string]*v1.Entry),
+	}
+}
+
+// Return a connector which talks to this cluster.
+func (this *fakeCluster) connector() *connector.Protobuf {
+	fakeConn := new(connectorfakes.FakeConn)
+	fakeConn.WriteStub = this.write
+	fakeConn.ReadStub = this.read
+
+	// Error responses discard the connection, so keep some spares which share the same stubs
+	pool := connector.NewPool()
+	for i := 0; i < 8; i++ {
+		pool.AddConnection(fakeConn, true)
+	}
+
+	return connector.NewConnector(pool)
+}
+
+// Create an empty region.
+func (this *fakeCluster) createRegion(name string) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.region(name)
+}
+
+// Return the keys held in a region, decoded and formatted with %v.
+func (this *fakeCluster) keys(region string) []string {
+	this.Lock()
+	defer this.Unlock()
+
+	keys := make([]string, 0)
+	for _, entry := range this.regions[region] {
+		k, _ := connector.DecodeValue(entry.Key, nil)
+		keys = append(keys, fmt.Sprintf("%v", k))
+	}
+
+	return keys
+}
+
+func (this *fakeCluster) region(name string) map[string]*v1.Entry {
+	if this.regions[name] == nil {
+		this.regions[name] = make(map[string]*v1.Entry)
+	}
+	return this.regions[name]
+}
+
+func entryKey(key *v1.EncodedValue) string {
+	k, _ := connector.DecodeValue(key, nil)
+	return fmt.Sprintf("%T:%v", k, k)
+}
+
+func (this *fakeCluster) write(b []byte) (int, error) {
+	request := &v1.Message{}
+	if err := proto.NewBuffer(b).DecodeMessage(request); err != nil {
+		return 0, err
+	}
+
+	this.Lock()
+	defer this.Unlock()
+
+	this.requests = append(this.requests, request)
+	this.response = this.handle(request)
+
+	return len(b), nil
+}
+
+func (this *fakeCluster) read(b []byte) (int, error) {
+	this.Lock()
+	defer this.Unlock()
+
+	p := proto.NewBuffer(nil)
+	p.EncodeMessage(this.response)
+
+	return copy(b, p.Bytes()), nil
+}
+
+func (this *fakeCluster) handle(request *v1.Message) *v1.Message {
+	switch r := request.MessageType.(type) {
+	case *v1.Message_PutRequest:
+		this.region(r.PutRequest.RegionName)[entryKey(r.PutRequest.Entry.Key)] = r.PutRequest.Entry
+		return &v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}}
+	case *v1.Message_PutIfAbsentRequest:
+		region := this.region(r.PutIfAbsentRequest.RegionName)
+		response := &v1.PutIfAbsentResponse{}
+		if existing, ok := region[entryKey(r.PutIfAbsentRequest.Entry.Key)]; ok {
+			response.OldValue = existing.Value
+		} else {
+			region[entryKey(r.PutIfAbsentRequest.Entry.Key)] = r.PutIfAbsentRequest.Entry
+		}
+		return &v1.Message{MessageType: &v1.Message_PutIfAbsentResponse{PutIfAbsentResponse: response}}
+	case *v1.Message_GetRequest:
+		response := &v1.GetResponse{}
+		if entry, ok := this.region(r.GetRequest.RegionName)[entryKey(r.GetRequest.Key)]; ok {
+			response.Result = entry.Value
+		}
+		return &v1.Message{MessageType: &v1.Message_GetResponse{GetResponse: response}}
+	case *v1.Message_PutAllRequest:
+		region := this.region(r.PutAllRequest.RegionName)
+		for _, entry := range r.PutAllRequest.Entry {
+			region[entryKey(entry.Key)] = entry
+		}
+		return &v1.Message{MessageType: &v1.Message_PutAllResponse{PutAllResponse: &v1.PutAllResponse{}}}
+	case *v1.Message_GetAllRequest:
+		region := this.region(r.GetAllRequest.RegionName)
+		response := &v1.GetAllResponse{}
+		for _, key := range r.GetAllRequest.Key {
+			if entry, ok := region[entryKey(key)]; ok {
+				response.Entries = append(response.Entries, entry)
+			}
+		}
+		return &v1.Message{MessageType: &v1.Message_GetAllResponse{GetAllResponse: response}}
+	case *v1.Message_KeySetRequest:
+		response := &v1.KeySetResponse{}
+		for _, entry := range this.region(r.KeySetRequest.RegionName) {
+			response.Keys = append(response.Keys, entry.Key)
+		}
+		return &v1.Message{MessageType: &v1.Message_KeySetResponse{KeySetResponse: response}}
+	case *v1.Message_RemoveRequest:
+		delete(this.region(r.RemoveRequest.RegionName), entryKey(r.RemoveRequest.Key))
+		return &v1.Message{MessageType: &v1.Message_RemoveResponse{RemoveResponse: &v1.RemoveResponse{}}}
+	case *v1.Message_GetSizeRequest:
+		if _, ok := this.regions[r.GetSizeRequest.RegionName]; !ok {
+			break
+		}
+		size := int32(len(this.region(r.GetSizeRequest.RegionName)))
+		return &v1.Message{MessageType: &v1.Message_GetSizeResponse{GetSizeResponse: &v1.GetSizeResponse{Size: size}}}
+	case *v1.Message_GetRegionNamesRequest:
+		response := &v1.GetRegionNamesResponse{}
+		for name := range this.regions {
+			response.Regions = append(response.Regions, name)
+		}
+		return &v1.Message{MessageType: &v1.Message_GetRegionNamesResponse{GetRegionNamesResponse: response}}
+	case *v1.Message_ExecuteFunctionOnRegionRequest:
+		// Functions return their arguments followed by their key filter, and those named
+		// "fail" fail
+		if r.ExecuteFunctionOnRegionRequest.FunctionID == "fail" {
+			break
+		}
+		results := append([]*v1.EncodedValue{r.ExecuteFunctionOnRegionRequest.Arguments}, r.ExecuteFunctionOnRegionRequest.KeyFilter...)
+		return &v1.Message{MessageType: &v1.Message_ExecuteFunctionOnRegionResponse{
+			ExecuteFunctionOnRegionResponse: &v1.ExecuteFunctionOnRegionResponse{Results: results},
+		}}
+	case *v1.Message_ExecuteFunctionOnMemberRequest:
+		results := make([]*v1.EncodedValue, 0)
+		for _, member := range r.ExecuteFunctionOnMemberRequest.MemberName {
+			result, _ := connector.EncodeValue(member)
+			results = append(results, result)
+		}
+		return &v1.Message{MessageType: &v1.Message_ExecuteFunctionOnMemberResponse{
+			ExecuteFunctionOnMemberResponse: &v1.ExecuteFunctionOnMemberResponse{Results: results},
+		}}
+	case *v1.Message_OqlQueryRequest:
+		if this.queryResult == nil {
+			break
+		}
+		return &v1.Message{MessageType: &v1.Message_OqlQueryResponse{OqlQueryResponse: this.queryResult}}
+	case *v1.Message_ClearRequest:
+		delete(this.regions, r.ClearRequest.RegionName)
+		return &v1.Message{MessageType: &v1.Message_ClearResponse{ClearResponse: &v1.ClearResponse{}}}
+	}
+
+	return &v1.Message{
+		MessageType: &v1.Message_ErrorResponse{
+			ErrorResponse: &v1.ErrorResponse{
+				Error: &v1.Error{ErrorCode: v1.ErrorCode_INVALID_REQUEST, Message: "unsupported by fake"},
+			},
+		},
+	}
+}
@@ -0,0 +1,195 @@
+package geode_go_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+// An Execution holds the options for a single function execution, as the Java client's
+// Execution does. The zero value executes the function without arguments and waits for all
+// of its results.
+type Execution struct {
+	// Arguments passed to the function
+	Args interface{}
+	// Keys whose entries the function executes on, which may be codec.RoutedKeys. Only
+	// functions executed on a region take a filter.
+	Filter []interface{}
+	// Time allowed for the execution, overriding the connector's timeouts. 0 leaves them
+	// unchanged.
+	Timeout time.Duration
+	// Receives each result as it is decoded, returning false to ignore the rest, instead of
+	// the results being returned in a slice.
+	Collector func(result interface{}) bool
+	// Return as soon as the execution has started, without waiting for its results, which
+	// are discarded. The execution is not bound by the caller's context once started, but
+	// Timeout still applies.
+	NoWait bool
+	// With NoWait, called with the error of a failed execution
+	OnError func(err error)
+	// Returns a new reference, such as a pointer to a new struct, for each JSON result to be
+	// decoded into. JSON results cannot be decoded without one.
+	NewResult func() interface{}
+}
+
+// ExecuteOnRegionWith executes a function on a region, as ExecuteOnRegion, with the given
+// options. A nil execution uses the defaults. Results are returned unless the execution
+// has a Collector or is NoWait.
+func (this *Client) ExecuteOnRegionWith(ctx context.Context, functionId, region string, execution *Execution) ([]interface{}, error) {
+	options := Execution{}
+	if execution != nil {
+		options = *execution
+	}
+
+	return this.execute(ctx, functionId, region, &options, func(conn connector.Operations, fn func(result interface{}) bool) error {
+		return this.executeOnRegionFunc(conn, functionId, region, options.Args, options.Filter, fn)
+	})
+}
+
+// ExecuteOnMembersWith executes a function on members, as ExecuteOnMembers, with the given
+// options, which may not include a Filter.
+func (this *Client) ExecuteOnMembersWith(ctx context.Context, functionId string, members []string, execution *Execution) ([]interface{}, error) {
+	options := Execution{}
+	if execution != nil {
+		options = *execution
+	}
+
+	return this.execute(ctx, functionId, "", &options, func(conn connector.Operations, fn func(result interface{}) bool) error {
+		return this.executeOnMembersFunc(conn, functionId, members, options.Args, fn)
+	})
+}
+
+// ExecuteOnGroupsWith executes a function on groups, as ExecuteOnGroups, with the given
+// options, which may not include a Filter.
+func (this *Client) ExecuteOnGroupsWith(ctx context.Context, functionId string, groups []string, execution *Execution) ([]interface{}, error) {
+	options := Execution{}
+	if execution != nil {
+		options = *execution
+	}
+
+	return this.execute(ctx, functionId, "", &options, func(conn connector.Operations, fn func(result interface{}) bool) error {
+		return this.executeOnGroupsFunc(conn, functionId, groups, options.Args, fn)
+	})
+}
+
+// Run an execution, where run executes the function on conn, passing each result to fn.
+// region is empty for functions executed on members or groups.
+func (this *Client) execute(ctx context.Context, functionId, region string, execution *Execution, run func(conn connector.Operations, fn func(result interface{}) bool) error) ([]interface{}, error) {
+	if region == "" && len(execution.Filter) > 0 {
+		return nil, errors.New("a key filter can only be used when executing a function on a region")
+	}
+
+	if execution.NoWait {
+		// Report authorization errors to the caller rather than OnError
+		if err := this.authorize(OpFunction, region); err != nil {
+			return nil, err
+		}
+		if err := this.checkFunction(functionId); err != nil {
+			return nil, err
+		}
+
+		conn := this.connector.BindContext(context.WithoutCancel(ctx))
+		if execution.Timeout > 0 {
+			conn = conn.BindTimeout(execution.Timeout)
+		}
+		if execution.NewResult != nil {
+			conn = conn.BindResultRef(execution.NewResult)
+		}
+
+		go func() {
+			err := run(conn, func(interface{}) bool { return false })
+			if err != nil && execution.OnError != nil {
+				defer connector.RecoverCallback("Execution.OnError", nil)
+				execution.OnError(err)
+			}
+		}()
+
+		return nil, nil
+	}
+
+	conn := this.connector.BindContext(ctx)
+	if execution.Timeout > 0 {
+		conn = conn.BindTimeout(execution.Timeout)
+	}
+	if execution.NewResult != nil {
+		conn = conn.BindResultRef(execution.NewResult)
+	}
+
+	if execution.Collector != nil {
+		return nil, run(conn, execution.Collector)
+	}
+
+	results := make([]interface{}, 0)
+	if err := run(conn, collectResults(&results)); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ExecuteOnRegionAs executes a function on a region as Client.ExecuteOnRegionWith, returning
+// its results as T. As with Region, a T which is a struct, a map or a pointer to a struct is
+// decoded from JSON into a new value for each result, and other results are converted from
+// the types they are decoded as. The execution may not have a Collector or be NoWait.
+func ExecuteOnRegionAs[T any](ctx context.Context, client *Client, functionId, region string, execution *Execution) ([]T, error) {
+	return executeAs[T](execution, func(options *Execution) error {
+		_, err := client.ExecuteOnRegionWith(ctx, functionId, region, options)
+		return err
+	})
+}
+
+// ExecuteOnMembersAs executes a function on members as Client.ExecuteOnMembersWith,
+// returning its results as T, as ExecuteOnRegionAs.
+func ExecuteOnMembersAs[T any](ctx context.Context, client *Client, functionId string, members []string, execution *Execution) ([]T, error) {
+	return executeAs[T](execution, func(options *Execution) error {
+		_, err := client.ExecuteOnMembersWith(ctx, functionId, members, options)
+		return err
+	})
+}
+
+// ExecuteOnGroupsAs executes a function on groups as Client.ExecuteOnGroupsWith, returning
+// its results as T, as ExecuteOnRegionAs.
+func ExecuteOnGroupsAs[T any](ctx context.Context, client *Client, functionId string, groups []string, execution *Execution) ([]T, error) {
+	return executeAs[T](execution, func(options *Execution) error {
+		_, err := client.ExecuteOnGroupsWith(ctx, functionId, groups, options)
+		return err
+	})
+}
+
+// Run an execution, collecting its results as T
+func executeAs[T any](execution *Execution, run func(options *Execution) error) ([]T, error) {
+	options := Execution{}
+	if execution != nil {
+		options = *execution
+	}
+	if options.Collector != nil || options.NoWait {
+		return nil, errors.New("an execution with a Collector or NoWait has no results to return")
+	}
+	if options.NewResult == nil {
+		options.NewResult = newReference[T]
+	}
+
+	results := make([]T, 0)
+	var convertErr error
+	options.Collector = func(result interface{}) bool {
+		converted, err := convertTo[T](result)
+		if err != nil {
+			convertErr = errors.New(fmt.Sprintf("function result %d: %s", len(results), err.Error()))
+			return false
+		}
+		results = append(results, converted)
+		return true
+	}
+
+	if err := run(&options); err != nil {
+		return nil, err
+	}
+	if convertErr != nil {
+		return nil, convertErr
+	}
+
+	return results, nil
+}
@@ -0,0 +1,120 @@
+package geode_go_client_test
+
+import (
+	"context"
+	"sync"
+
+	geode "github.com/gemfire/geode-go-client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Function executions", func() {
+
+	var cluster *fakeCluster
+	var client *geode.Client
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		client = geode.NewGeodeClient(cluster.connector())
+	})
+
+	It("passes the arguments and filter", func() {
+		results, err := client.ExecuteOnRegionWith(context.Background(), "fn", "foo", &geode.Execution{
+			Args:   "x",
+			Filter: []interface{}{"A", "B"},
+		})
+		Expect(err).To(BeNil())
+		Expect(results).To(Equal([]interface{}{"x", "A", "B"}))
+	})
+
+	It("uses the defaults without an execution", func() {
+		results, err := client.ExecuteOnMembersWith(context.Background(), "fn", []string{"server1"}, nil)
+		Expect(err).To(BeNil())
+		Expect(results).To(Equal([]interface{}{"server1"}))
+	})
+
+	It("passes results to a collector", func() {
+		var collected []interface{}
+		results, err := client.ExecuteOnRegionWith(context.Background(), "fn", "foo", &geode.Execution{
+			Args:   "x",
+			Filter: []interface{}{"A", "B"},
+			Collector: func(result interface{}) bool {
+				collected = append(collected, result)
+				return len(collected) < 2
+			},
+		})
+		Expect(err).To(BeNil())
+		Expect(results).To(BeNil())
+		Expect(collected).To(Equal([]interface{}{"x", "A"}))
+	})
+
+	It("rejects a filter for functions not executed on a region", func() {
+		_, err := client.ExecuteOnGroupsWith(context.Background(), "fn", []string{"group1"}, &geode.Execution{
+			Filter: []interface{}{"A"},
+		})
+		Expect(err).To(MatchError("a key filter can only be used when executing a function on a region"))
+		Expect(cluster.requests).To(BeEmpty())
+	})
+
+	It("reports the errors of executions not waited for", func() {
+		var lock sync.Mutex
+		var failure error
+		results, err := client.ExecuteOnRegionWith(context.Background(), "fail", "foo", &geode.Execution{
+			NoWait: true,
+			OnError: func(err error) {
+				lock.Lock()
+				defer lock.Unlock()
+				failure = err
+			},
+		})
+		Expect(err).To(BeNil())
+		Expect(results).To(BeNil())
+
+		Eventually(func() error {
+			lock.Lock()
+			defer lock.Unlock()
+			return failure
+		}).ShouldNot(BeNil())
+	})
+
+	It("checks access before starting executions not waited for", func() {
+		client.SetReadOnly(true)
+
+		_, err := client.ExecuteOnRegionWith(context.Background(), "fn", "foo", &geode.Execution{NoWait: true})
+		Expect(err).To(Equal(geode.ErrReadOnly))
+		Expect(cluster.requests).To(BeEmpty())
+	})
+
+	It("decodes JSON results into new references", func() {
+		results, err := client.ExecuteOnRegionWith(context.Background(), "fn", "foo", &geode.Execution{
+			Args:      &Employee{Name: "Joe", Age: 42},
+			NewResult: func() interface{} { return &Employee{} },
+		})
+		Expect(err).To(BeNil())
+		Expect(results).To(Equal([]interface{}{&Employee{Name: "Joe", Age: 42}}))
+	})
+
+	It("returns results as a type", func() {
+		employees, err := geode.ExecuteOnRegionAs[Employee](context.Background(), client, "fn", "foo", &geode.Execution{
+			Args: &Employee{Name: "Joe", Age: 42},
+		})
+		Expect(err).To(BeNil())
+		Expect(employees).To(Equal([]Employee{{Name: "Joe", Age: 42}}))
+
+		keys, err := geode.ExecuteOnRegionAs[string](context.Background(), client, "fn", "foo", &geode.Execution{
+			Args:   "x",
+			Filter: []interface{}{"A", "B"},
+		})
+		Expect(err).To(BeNil())
+		Expect(keys).To(Equal([]string{"x", "A", "B"}))
+	})
+
+	It("reports results which cannot be returned as the type", func() {
+		_, err := geode.ExecuteOnMembersAs[int](context.Background(), client, "fn", []string{"server1"}, nil)
+		Expect(err).To(MatchError("function result 0: cannot convert string to int"))
+
+		_, err = geode.ExecuteOnGroupsAs[int](context.Background(), client, "fn", []string{"group1"}, &geode.Execution{NoWait: true})
+		Expect(err).To(MatchError("an execution with a Collector or NoWait has no results to return"))
+	})
+})
@@ -0,0 +1,13 @@
+package geode_go_client_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGeodeClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Geode Client Suite")
+}
@@ -0,0 +1,141 @@
+package geode_go_client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+// LazyState is the readiness of a Lazy client.
+type LazyState int
+
+const (
+	// Client has not been called
+	LazyPending LazyState = iota
+	// The client is being created
+	LazyInitializing
+	// The client was created and is returned by every later call
+	LazyReady
+	// The last attempt to create the client failed. The next call to Client tries again.
+	LazyFailed
+)
+
+func (s LazyState) String() string {
+	switch s {
+	case LazyPending:
+		return "pending"
+	case LazyInitializing:
+		return "initializing"
+	case LazyReady:
+		return "ready"
+	case LazyFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("LazyState(%d)", int(s))
+}
+
+// A Lazy defers creating a Client until it is first needed, so that libraries can declare a
+// client at package initialization without connecting to the cluster. For example:
+//
+//	var orders = geode.NewLazy(func(ctx context.Context) (*geode.Client, error) {
+//	    pool := connector.NewPool()
+//	    pool.AddServer("localhost", 40404)
+//	    client := geode.NewGeodeClient(connector.NewConnector(pool))
+//	    return client, client.WaitForCluster(ctx, geode.ClusterRequirements{Regions: []string{"Orders"}})
+//	})
+//
+// A Lazy is safe for concurrent use.
+type Lazy struct {
+	sync.Mutex
+	init  func(ctx context.Context) (*Client, error)
+	state LazyState
+	// Closed when the initialization in progress finishes
+	done   chan struct{}
+	client *Client
+	err    error
+}
+
+// NewLazy creates a Lazy which creates its client with init. init is not called until
+// Client is.
+func NewLazy(init func(ctx context.Context) (*Client, error)) *Lazy {
+	return &Lazy{init: init}
+}
+
+// Client returns the client, creating it first if necessary. Concurrent callers share a
+// single call to init, made with the context of the caller which started it, and all receive
+// its result. If init fails its error is returned and the next call tries again. A caller
+// whose ctx is done while waiting for another's initialization returns ctx.Err().
+func (this *Lazy) Client(ctx context.Context) (*Client, error) {
+	this.Lock()
+	switch this.state {
+	case LazyReady:
+		client := this.client
+		this.Unlock()
+		return client, nil
+	case LazyInitializing:
+		done := this.done
+		this.Unlock()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		this.Lock()
+		defer this.Unlock()
+		if this.state == LazyReady {
+			return this.client, nil
+		}
+		return nil, this.err
+	}
+
+	this.state = LazyInitializing
+	this.done = make(chan struct{})
+	this.Unlock()
+
+	client, err := this.initialize(ctx)
+
+	this.Lock()
+	defer this.Unlock()
+	if err != nil {
+		this.state, this.err = LazyFailed, err
+	} else {
+		this.state, this.client, this.err = LazyReady, client, nil
+	}
+	close(this.done)
+
+	return client, err
+}
+
+// Call init, reporting a panic as an error so that waiting callers are released
+func (this *Lazy) initialize(ctx context.Context) (client *Client, err error) {
+	defer connector.RecoverCallback("Lazy.init", &err)
+
+	if client, err = this.init(ctx); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// State returns the readiness of the client.
+func (this *Lazy) State() LazyState {
+	this.Lock()
+	defer this.Unlock()
+	return this.state
+}
+
+// Ready returns whether the client has been created. It does not create it.
+func (this *Lazy) Ready() bool {
+	return this.State() == LazyReady
+}
+
+// Err returns the error from the last attempt to create the client, or nil if it has not
+// failed.
+func (this *Lazy) Err() error {
+	this.Lock()
+	defer this.Unlock()
+	return this.err
+}
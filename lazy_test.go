@@ -0,0 +1,126 @@
+package geode_go_client_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	geode "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lazy", func() {
+
+	var cluster *fakeCluster
+	var calls int32
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		calls = 0
+	})
+
+	It("does not create the client until it is used", func() {
+		lazy := geode.NewLazy(func(ctx context.Context) (*geode.Client, error) {
+			atomic.AddInt32(&calls, 1)
+			return geode.NewGeodeClient(cluster.connector()), nil
+		})
+		Expect(lazy.State()).To(Equal(geode.LazyPending))
+		Expect(lazy.Ready()).To(BeFalse())
+		Expect(calls).To(Equal(int32(0)))
+
+		client, err := lazy.Client(context.Background())
+		Expect(err).To(BeNil())
+		Expect(client.Put("foo", "A", "x")).To(BeNil())
+		Expect(lazy.Ready()).To(BeTrue())
+
+		again, err := lazy.Client(context.Background())
+		Expect(err).To(BeNil())
+		Expect(again).To(BeIdenticalTo(client))
+		Expect(calls).To(Equal(int32(1)))
+	})
+
+	It("shares a single initialization between concurrent callers", func() {
+		release := make(chan struct{})
+		lazy := geode.NewLazy(func(ctx context.Context) (*geode.Client, error) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			return geode.NewGeodeClient(cluster.connector()), nil
+		})
+
+		var wg sync.WaitGroup
+		clients := make([]*geode.Client, 10)
+		for i := range clients {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				clients[i], _ = lazy.Client(context.Background())
+			}(i)
+		}
+
+		Eventually(lazy.State).Should(Equal(geode.LazyInitializing))
+		close(release)
+		wg.Wait()
+
+		Expect(calls).To(Equal(int32(1)))
+		for _, client := range clients {
+			Expect(client).ToNot(BeNil())
+			Expect(client).To(BeIdenticalTo(clients[0]))
+		}
+	})
+
+	It("reports a failure and tries again on the next call", func() {
+		failure := errors.New("cluster unavailable")
+		lazy := geode.NewLazy(func(ctx context.Context) (*geode.Client, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				return nil, failure
+			}
+			return geode.NewGeodeClient(cluster.connector()), nil
+		})
+
+		_, err := lazy.Client(context.Background())
+		Expect(err).To(Equal(failure))
+		Expect(lazy.State()).To(Equal(geode.LazyFailed))
+		Expect(lazy.Err()).To(Equal(failure))
+
+		client, err := lazy.Client(context.Background())
+		Expect(err).To(BeNil())
+		Expect(client).ToNot(BeNil())
+		Expect(lazy.State()).To(Equal(geode.LazyReady))
+		Expect(lazy.Err()).To(BeNil())
+	})
+
+	It("returns a waiting caller's context error", func() {
+		release := make(chan struct{})
+		lazy := geode.NewLazy(func(ctx context.Context) (*geode.Client, error) {
+			<-release
+			return nil, errors.New("cluster unavailable")
+		})
+		initialized := make(chan struct{})
+		go func() {
+			defer close(initialized)
+			lazy.Client(context.Background())
+		}()
+		Eventually(lazy.State).Should(Equal(geode.LazyInitializing))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := lazy.Client(ctx)
+		Expect(err).To(Equal(context.Canceled))
+
+		close(release)
+		<-initialized
+	})
+
+	It("returns an error when init panics", func() {
+		lazy := geode.NewLazy(func(ctx context.Context) (*geode.Client, error) {
+			panic("boom")
+		})
+
+		_, err := lazy.Client(context.Background())
+		Expect(err).To(BeAssignableToTypeOf(&connector.CallbackPanicError{}))
+		Expect(lazy.State()).To(Equal(geode.LazyFailed))
+	})
+})
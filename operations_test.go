@@ -0,0 +1,73 @@
+package geode_go_client_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	geode "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// A memoryOperations stores entries in memory, implementing only the operations the tests use.
+type memoryOperations struct {
+	connector.Operations
+	entries map[string]interface{}
+}
+
+func (this *memoryOperations) Put(region string, k, v interface{}) error {
+	this.entries[fmt.Sprintf("%s/%v", region, k)] = v
+	return nil
+}
+
+func (this *memoryOperations) Get(region string, k interface{}, value interface{}) (interface{}, error) {
+	return this.entries[fmt.Sprintf("%s/%v", region, k)], nil
+}
+
+func (this *memoryOperations) BindContext(ctx context.Context) connector.Operations {
+	return this
+}
+
+func (this *memoryOperations) BindTimeout(timeout time.Duration) connector.Operations {
+	return this
+}
+
+func (this *memoryOperations) BindResultRef(newRef func() interface{}) connector.Operations {
+	return this
+}
+
+var _ = Describe("Alternate connectors", func() {
+
+	var client *geode.Client
+
+	BeforeEach(func() {
+		client = geode.NewGeodeClient(&memoryOperations{entries: make(map[string]interface{})})
+	})
+
+	It("back the client", func() {
+		Expect(client.Put("foo", "A", "apple")).To(Succeed())
+
+		Expect(client.Get("foo", "A")).To(Equal("apple"))
+	})
+
+	It("apply the client's checks", func() {
+		client.SetReadOnly(true)
+
+		Expect(client.Put("foo", "A", "apple")).To(Equal(geode.ErrReadOnly))
+	})
+
+	It("support context variants", func() {
+		Expect(client.PutCtx(context.Background(), "foo", "A", "apple")).To(Succeed())
+
+		Expect(client.GetCtx(context.Background(), "foo", "A")).To(Equal("apple"))
+	})
+
+	It("do not support transactions", func() {
+		client.SetTransactionFunctions(&geode.TransactionFunctions{Region: "tx", Begin: "begin", Commit: "commit", Rollback: "rollback"})
+
+		_, err := client.Begin(context.Background())
+		Expect(err).To(MatchError("a protobuf connector is required for transactions"))
+	})
+})
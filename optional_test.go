@@ -0,0 +1,72 @@
+package geode_go_client_test
+
+import (
+	geode "github.com/gemfire/geode-go-client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Null and missing values", func() {
+
+	var cluster *fakeCluster
+	var client *geode.Client
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		client = geode.NewGeodeClient(cluster.connector())
+	})
+
+	It("distinguishes a null value from a removed entry", func() {
+		Expect(client.PutNull("foo", "A")).To(BeNil())
+		Expect(cluster.keys("foo")).To(HaveLen(1))
+
+		result, err := client.GetOptional("foo", "A")
+		Expect(err).To(BeNil())
+		Expect(result.IsNull()).To(BeTrue())
+
+		Expect(client.Remove("foo", "A")).To(BeNil())
+		result, err = client.GetOptional("foo", "A")
+		Expect(err).To(BeNil())
+		Expect(result.Present).To(BeFalse())
+		Expect(result.IsNull()).To(BeFalse())
+	})
+
+	It("reports whether an entry exists alongside its value", func() {
+		Expect(client.Put("foo", "A", "x")).To(BeNil())
+		Expect(client.PutNull("foo", "B")).To(BeNil())
+
+		value, found, err := client.GetIfExists("foo", "A")
+		Expect(err).To(BeNil())
+		Expect(found).To(BeTrue())
+		Expect(value).To(Equal("x"))
+
+		value, found, err = client.GetIfExists("foo", "B")
+		Expect(err).To(BeNil())
+		Expect(found).To(BeTrue())
+		Expect(value).To(BeNil())
+
+		value, found, err = client.GetIfExists("foo", "C")
+		Expect(err).To(BeNil())
+		Expect(found).To(BeFalse())
+		Expect(value).To(BeNil())
+	})
+
+	It("applies read transforms to present values only", func() {
+		reads := 0
+		client.AddTransform("foo", &geode.Transform{
+			Read: func(region string, key, value interface{}) (interface{}, error) {
+				reads++
+				return value, nil
+			},
+		})
+
+		Expect(client.Put("foo", "A", "x")).To(BeNil())
+		result, err := client.GetOptional("foo", "A")
+		Expect(err).To(BeNil())
+		Expect(result.Value).To(Equal("x"))
+
+		_, err = client.GetOptional("foo", "B")
+		Expect(err).To(BeNil())
+		Expect(reads).To(Equal(1))
+	})
+})
@@ -0,0 +1,78 @@
+package geode_go_client_test
+
+import (
+	geode "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/connector"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/gemfire/geode-go-client/query"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreparedQuery", func() {
+
+	It("requires an example parameter per placeholder", func() {
+		_, err := query.Prepare("select * from /Employees e where e.age > $1 and e.name = $2", int32(0))
+		Expect(err).To(MatchError("query has 2 placeholders but 1 parameters were given"))
+	})
+
+	It("requires every placeholder to be used", func() {
+		_, err := query.Prepare("select * from /Employees e where e.name = $2", "", "")
+		Expect(err).To(MatchError("query uses $2 but not $1"))
+
+		_, err = query.Prepare("select * from /Employees e where e.name = $0")
+		Expect(err).To(MatchError("query has an invalid placeholder at offset 42"))
+	})
+
+	It("ignores placeholders in string literals", func() {
+		prepared, err := query.Prepare("select * from /Employees e where e.name = '$2' and e.age = $1", int32(0))
+		Expect(err).To(BeNil())
+
+		_, err = prepared.Bind(int32(42))
+		Expect(err).To(BeNil())
+
+		_, err = query.Prepare("select * from /Employees e where e.name = 'Joe", int32(0))
+		Expect(err).To(MatchError("query has an unterminated string literal"))
+	})
+
+	It("checks the types of bound parameters", func() {
+		prepared, err := query.Prepare("select * from /Employees e where e.age > $1 and e.name = $2", int32(0), nil)
+		Expect(err).To(BeNil())
+
+		_, err = prepared.Bind(int32(42))
+		Expect(err).To(MatchError("query has 2 placeholders but 1 parameters were given"))
+
+		_, err = prepared.Bind("42", "Joe")
+		Expect(err).To(MatchError("parameter $1 must be int32, not string"))
+
+		q, err := prepared.Bind(int32(42), 7)
+		Expect(err).To(BeNil())
+		Expect(q.BindParameters).To(Equal([]interface{}{int32(42), 7}))
+	})
+
+	It("is executed repeatedly with different parameters", func() {
+		cluster := newFakeCluster()
+		client := geode.NewGeodeClient(cluster.connector())
+		list, err := connector.EncodeValueList([]interface{}{"Joe"})
+		Expect(err).To(BeNil())
+		cluster.queryResult = &v1.OQLQueryResponse{Result: &v1.OQLQueryResponse_ListResult{ListResult: list}}
+
+		prepared, err := query.PrepareTraced("select e.name from /Employees e where e.age > $1", int32(0))
+		Expect(err).To(BeNil())
+
+		for _, age := range []int32{30, 40} {
+			q, err := prepared.Bind(age)
+			Expect(err).To(BeNil())
+			_, err = client.QueryForListResult(q)
+			Expect(err).To(BeNil())
+		}
+
+		Expect(cluster.requests).To(HaveLen(2))
+		for i, age := range []int32{30, 40} {
+			request := cluster.requests[i].GetOqlQueryRequest()
+			Expect(request.Query).To(Equal("<trace> select e.name from /Employees e where e.age > $1"))
+			Expect(request.BindParameter).To(HaveLen(1))
+			Expect(request.BindParameter[0].GetIntResult()).To(Equal(age))
+		}
+	})
+})
@@ -0,0 +1,308 @@
+// Package prometheus publishes the metrics of a connection pool in Prometheus' text
+// exposition format, for scraping by Prometheus. It does not depend on the Prometheus client
+// library, and is kept apart from the connector so that programs which do not use it do not
+// depend on net/http.
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+// Upper bounds of the latency histogram buckets, in seconds
+var latencyBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}
+
+// Counters which may go down, exposed as gauges
+var gauges = map[string]bool{
+	connector.MetricActiveConnections: true,
+	connector.MetricOpenConnections:   true,
+}
+
+// Label names of the keyed counters maintained by the connector
+var defaultLabels = map[string]string{
+	connector.MetricOperations:         "operation",
+	connector.MetricOperationErrors:    "operation",
+	connector.MetricOperationLatency:   "operation",
+	connector.MetricDeduplicatedWrites: "operation",
+	connector.MetricThrottledAttempts:  "server",
+}
+
+var _ connector.LatencyPublisher = (*Publisher)(nil)
+var _ connector.ErrorPublisher = (*Publisher)(nil)
+
+// A Publisher is a connector.MetricsPublisher which keeps counters in memory and serves them
+// to Prometheus. Counter names are converted to snake case and prefixed with the namespace,
+// so that activeConnections is published as geode_active_connections with the namespace
+// "geode". Counters which only go up are suffixed with _total and latencies are published
+// as histograms, in seconds. Errors are labelled with both their operation and error code.
+// For example:
+//
+//	publisher := prometheus.NewPublisher("geode")
+//	pool.SetMetricsPublisher(publisher)
+//	http.Handle("/metrics", publisher)
+type Publisher struct {
+	sync.Mutex
+	namespace string
+	labels    map[string]string
+	counters  map[string]int64
+	keyed     map[string]map[string]int64
+	latencies map[string]map[string]*histogram
+	errors    map[string]map[errorKey]int64
+}
+
+type errorKey struct {
+	operation string
+	code      string
+}
+
+type histogram struct {
+	buckets []int64
+	count   int64
+	sum     float64
+}
+
+func NewPublisher(namespace string) *Publisher {
+	labels := make(map[string]string, len(defaultLabels))
+	for name, label := range defaultLabels {
+		labels[name] = label
+	}
+
+	return &Publisher{
+		namespace: namespace,
+		labels:    labels,
+		counters:  make(map[string]int64),
+		keyed:     make(map[string]map[string]int64),
+		latencies: make(map[string]map[string]*histogram),
+		errors:    make(map[string]map[errorKey]int64),
+	}
+}
+
+// SetLabel sets the name of the label holding the keys of a keyed counter, such as "tenant"
+// for a count per tenant. Keys are labelled "key" unless set here or by the connector.
+func (this *Publisher) SetLabel(name, label string) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.labels[name] = label
+}
+
+func (this *Publisher) Add(name string, delta int64) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.counters[name] += delta
+}
+
+func (this *Publisher) AddKeyed(name, key string, delta int64) {
+	this.Lock()
+	defer this.Unlock()
+
+	if this.keyed[name] == nil {
+		this.keyed[name] = make(map[string]int64)
+	}
+	this.keyed[name][key] += delta
+}
+
+func (this *Publisher) AddError(name, operation, code string, delta int64) {
+	this.Lock()
+	defer this.Unlock()
+
+	if this.errors[name] == nil {
+		this.errors[name] = make(map[errorKey]int64)
+	}
+	this.errors[name][errorKey{operation, code}] += delta
+}
+
+func (this *Publisher) ObserveLatency(name, operation string, latency time.Duration) {
+	this.Lock()
+	defer this.Unlock()
+
+	if this.latencies[name] == nil {
+		this.latencies[name] = make(map[string]*histogram)
+	}
+	h := this.latencies[name][operation]
+	if h == nil {
+		h = &histogram{buckets: make([]int64, len(latencyBuckets))}
+		this.latencies[name][operation] = h
+	}
+
+	seconds := latency.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// ServeHTTP responds with the current value of every counter.
+func (this *Publisher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	this.WriteTo(w)
+}
+
+// WriteTo writes the current value of every counter in the text exposition format, sorted
+// by name.
+func (this *Publisher) WriteTo(w io.Writer) (int64, error) {
+	this.Lock()
+	defer this.Unlock()
+
+	out := &countingWriter{writer: bufio.NewWriter(w)}
+
+	for _, name := range sortedKeys(this.counters) {
+		metric := this.metricName(name)
+		fmt.Fprintf(out, "# TYPE %s %s\n", metric, this.metricType(name))
+		fmt.Fprintf(out, "%s %d\n", metric, this.counters[name])
+	}
+
+	for _, name := range sortedKeys(this.keyed) {
+		metric := this.metricName(name)
+		fmt.Fprintf(out, "# TYPE %s %s\n", metric, this.metricType(name))
+		for _, key := range sortedKeys(this.keyed[name]) {
+			fmt.Fprintf(out, "%s{%s=\"%s\"} %d\n", metric, this.label(name), escape(key), this.keyed[name][key])
+		}
+	}
+
+	for _, name := range sortedKeys(this.errors) {
+		metric := this.metricName(name)
+		fmt.Fprintf(out, "# TYPE %s counter\n", metric)
+		keys := make([]errorKey, 0, len(this.errors[name]))
+		for key := range this.errors[name] {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].operation != keys[j].operation {
+				return keys[i].operation < keys[j].operation
+			}
+			return keys[i].code < keys[j].code
+		})
+		for _, key := range keys {
+			fmt.Fprintf(out, "%s{operation=\"%s\",code=\"%s\"} %d\n", metric, escape(key.operation), escape(key.code), this.errors[name][key])
+		}
+	}
+
+	for _, name := range sortedKeys(this.latencies) {
+		metric := this.qualify(snakeCase(name)) + "_seconds"
+		label := this.label(name)
+		fmt.Fprintf(out, "# TYPE %s histogram\n", metric)
+		for _, operation := range sortedKeys(this.latencies[name]) {
+			h := this.latencies[name][operation]
+			for i, bound := range latencyBuckets {
+				fmt.Fprintf(out, "%s_bucket{%s=\"%s\",le=\"%g\"} %d\n", metric, label, escape(operation), bound, h.buckets[i])
+			}
+			fmt.Fprintf(out, "%s_bucket{%s=\"%s\",le=\"+Inf\"} %d\n", metric, label, escape(operation), h.count)
+			fmt.Fprintf(out, "%s_sum{%s=\"%s\"} %g\n", metric, label, escape(operation), h.sum)
+			fmt.Fprintf(out, "%s_count{%s=\"%s\"} %d\n", metric, label, escape(operation), h.count)
+		}
+	}
+
+	if err := out.writer.Flush(); err != nil {
+		return out.count, err
+	}
+	return out.count, nil
+}
+
+// MUST hold the publisher lock when calling
+func (this *Publisher) metricName(name string) string {
+	metric := this.qualify(snakeCase(name))
+	if !gauges[name] {
+		metric += "_total"
+	}
+	return metric
+}
+
+func (this *Publisher) metricType(name string) string {
+	if gauges[name] {
+		return "gauge"
+	}
+	return "counter"
+}
+
+// MUST hold the publisher lock when calling
+func (this *Publisher) label(name string) string {
+	if label, ok := this.labels[name]; ok {
+		return label
+	}
+	return "key"
+}
+
+func (this *Publisher) qualify(name string) string {
+	if this.namespace == "" {
+		return name
+	}
+	return this.namespace + "_" + name
+}
+
+// Convert a camel case counter name, such as activeConnections, to snake case
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Escape a label value
+func escape(value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `"`, `\"`, -1)
+	return strings.Replace(value, "\n", `\n`, -1)
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch v := m.(type) {
+	case map[string]int64:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]map[string]int64:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]map[errorKey]int64:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]map[string]*histogram:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*histogram:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type countingWriter struct {
+	writer *bufio.Writer
+	count  int64
+}
+
+func (this *countingWriter) Write(p []byte) (int, error) {
+	n, err := this.writer.Write(p)
+	this.count += int64(n)
+	return n, err
+}
@@ -0,0 +1,89 @@
+package prometheus_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/prometheus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Publisher", func() {
+	var publisher *prometheus.Publisher
+
+	BeforeEach(func() {
+		publisher = prometheus.NewPublisher("geode")
+	})
+
+	scrape := func() string {
+		recorder := httptest.NewRecorder()
+		publisher.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(ContainSubstring("text/plain"))
+		return recorder.Body.String()
+	}
+
+	It("publishes connection counts as gauges", func() {
+		publisher.Add(connector.MetricOpenConnections, 2)
+		publisher.Add(connector.MetricOpenConnections, -1)
+
+		body := scrape()
+		Expect(body).To(ContainSubstring("# TYPE geode_open_connections gauge\n"))
+		Expect(body).To(ContainSubstring("geode_open_connections 1\n"))
+	})
+
+	It("publishes other counters with a total suffix", func() {
+		publisher.Add(connector.MetricBytesSent, 100)
+
+		body := scrape()
+		Expect(body).To(ContainSubstring("# TYPE geode_bytes_sent_total counter\n"))
+		Expect(body).To(ContainSubstring("geode_bytes_sent_total 100\n"))
+	})
+
+	It("labels keyed counters", func() {
+		publisher.AddKeyed(connector.MetricOperations, "Put", 3)
+		publisher.AddKeyed(connector.MetricOperations, "Get", 1)
+		publisher.AddKeyed("rejections", `tenant "a"`, 1)
+		publisher.SetLabel("evictions", "region")
+		publisher.AddKeyed("evictions", "orders", 2)
+
+		body := scrape()
+		Expect(body).To(ContainSubstring("geode_operations_total{operation=\"Get\"} 1\ngeode_operations_total{operation=\"Put\"} 3\n"))
+		Expect(body).To(ContainSubstring(`geode_rejections_total{key="tenant \"a\""} 1`))
+		Expect(body).To(ContainSubstring(`geode_evictions_total{region="orders"} 2`))
+	})
+
+	It("labels errors with their operation and code", func() {
+		publisher.AddError(connector.MetricErrorCodes, "Put", "SERVER_ERROR", 1)
+		publisher.AddError(connector.MetricErrorCodes, "Get", "AUTHENTICATION_FAILED", 2)
+
+		body := scrape()
+		Expect(body).To(ContainSubstring("# TYPE geode_error_codes_total counter\n"))
+		Expect(body).To(ContainSubstring("geode_error_codes_total{operation=\"Get\",code=\"AUTHENTICATION_FAILED\"} 2\n" +
+			"geode_error_codes_total{operation=\"Put\",code=\"SERVER_ERROR\"} 1\n"))
+	})
+
+	It("publishes latencies as histograms in seconds", func() {
+		publisher.ObserveLatency(connector.MetricOperationLatency, "Get", 3*time.Millisecond)
+		publisher.ObserveLatency(connector.MetricOperationLatency, "Get", 2*time.Second)
+
+		body := scrape()
+		Expect(body).To(ContainSubstring("# TYPE geode_operation_latency_seconds histogram\n"))
+		Expect(body).To(ContainSubstring(`geode_operation_latency_seconds_bucket{operation="Get",le="0.001"} 0`))
+		Expect(body).To(ContainSubstring(`geode_operation_latency_seconds_bucket{operation="Get",le="0.005"} 1`))
+		Expect(body).To(ContainSubstring(`geode_operation_latency_seconds_bucket{operation="Get",le="5"} 2`))
+		Expect(body).To(ContainSubstring(`geode_operation_latency_seconds_bucket{operation="Get",le="+Inf"} 2`))
+		Expect(body).To(ContainSubstring(`geode_operation_latency_seconds_sum{operation="Get"} 2.003`))
+		Expect(body).To(ContainSubstring(`geode_operation_latency_seconds_count{operation="Get"} 2`))
+	})
+
+	It("omits the namespace when it is empty", func() {
+		publisher = prometheus.NewPublisher("")
+		publisher.Add(connector.MetricActiveConnections, 1)
+
+		Expect(scrape()).To(ContainSubstring("\nactive_connections 1\n"))
+	})
+})
@@ -0,0 +1,128 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// A PreparedQuery is a query whose placeholders, $1, $2 and so on, have been checked once so
+// that it can be bound to different parameters and executed repeatedly. A PreparedQuery may be
+// bound concurrently; each Bind returns a new Query.
+type PreparedQuery struct {
+	// Reference is copied to every bound Query
+	Reference interface{}
+
+	statement string
+	trace     bool
+	types     []reflect.Type
+}
+
+// Prepare a query, passing one example parameter per placeholder. Parameters bound later must
+// have the same types as the examples; an example of nil accepts any type. The number of
+// examples must equal the highest placeholder, and every placeholder up to it must be used.
+func Prepare(queryString string, examples ...interface{}) (*PreparedQuery, error) {
+	return prepare(&Query{QueryString: queryString}, examples)
+}
+
+// PrepareTraced prepares a query with Trace set on every bound Query.
+func PrepareTraced(queryString string, examples ...interface{}) (*PreparedQuery, error) {
+	return prepare(&Query{QueryString: queryString, Trace: true}, examples)
+}
+
+func prepare(query *Query, examples []interface{}) (*PreparedQuery, error) {
+	count, err := placeholders(query.QueryString)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(examples) != count {
+		return nil, errors.New(fmt.Sprintf("query has %d placeholders but %d parameters were given",
+			count, len(examples)))
+	}
+
+	types := make([]reflect.Type, len(examples))
+	for i, example := range examples {
+		if example != nil {
+			types[i] = reflect.TypeOf(example)
+		}
+	}
+
+	return &PreparedQuery{
+		statement: query.Statement(),
+		trace:     query.Trace,
+		types:     types,
+	}, nil
+}
+
+// Bind parameters to the query, returning a Query to be executed.
+func (this *PreparedQuery) Bind(parameters ...interface{}) (*Query, error) {
+	if len(parameters) != len(this.types) {
+		return nil, errors.New(fmt.Sprintf("query has %d placeholders but %d parameters were given",
+			len(this.types), len(parameters)))
+	}
+
+	for i, parameter := range parameters {
+		if parameter == nil || this.types[i] == nil {
+			continue
+		}
+		if t := reflect.TypeOf(parameter); t != this.types[i] {
+			return nil, errors.New(fmt.Sprintf("parameter $%d must be %s, not %s", i+1, this.types[i], t))
+		}
+	}
+
+	// The statement already carries any <trace> prefix, which Statement will not add again
+	return &Query{
+		QueryString:    this.statement,
+		BindParameters: parameters,
+		Reference:      this.Reference,
+		Trace:          this.trace,
+	}, nil
+}
+
+// Statement returns the query sent to the server.
+func (this *PreparedQuery) Statement() string {
+	return this.statement
+}
+
+// Return the highest placeholder used by a query, ignoring any within string
+// literals, or an error if a placeholder below it is unused.
+func placeholders(queryString string) (int, error) {
+	used := make(map[int]bool)
+	highest := 0
+
+	for i := 0; i < len(queryString); i++ {
+		switch queryString[i] {
+		case '\'':
+			// Skip the literal; a quote within it is doubled, which reads as two literals
+			for i++; i < len(queryString) && queryString[i] != '\''; i++ {
+			}
+			if i == len(queryString) {
+				return 0, errors.New("query has an unterminated string literal")
+			}
+		case '$':
+			end := i + 1
+			for end < len(queryString) && queryString[end] >= '0' && queryString[end] <= '9' {
+				end++
+			}
+			n, err := strconv.Atoi(queryString[i+1 : end])
+			if err != nil || n < 1 {
+				return 0, errors.New(fmt.Sprintf("query has an invalid placeholder at offset %d", i))
+			}
+			used[n] = true
+			if n > highest {
+				highest = n
+			}
+			i = end - 1
+		}
+	}
+
+	for n := 1; n < highest; n++ {
+		if !used[n] {
+			return 0, errors.New(fmt.Sprintf("query uses $%d but not $%d", highest, n))
+		}
+	}
+
+	return highest, nil
+}
@@ -1,9 +1,30 @@
 package query
 
+import (
+	"strings"
+	"time"
+)
+
 type Query struct {
 	QueryString    string
 	BindParameters []interface{}
 	Reference      interface{}
+	// Trace prepends <trace> to the query. The server then logs the execution time and the
+	// indexes used, and LastTrace is set when the query is executed. The protocol does not
+	// return the server's trace, so LastTrace holds what the client can observe.
+	Trace     bool
+	LastTrace *Trace
+}
+
+// A Trace describes an execution of a query with Trace set. A Query which is traced should not
+// be executed concurrently.
+type Trace struct {
+	// Server which executed the query, in whose log the full trace can be found
+	Server string
+	// Time from sending the query to receiving its results
+	Elapsed time.Duration
+	// Number of results, or of rows for a table result
+	Results int
 }
 
 // Create a Query object which can be used to perform a query. If the query returns some type of struct then a
@@ -14,4 +35,12 @@ func NewQuery(queryString string, bindParameters ...interface{}) *Query {
 		QueryString: queryString,
 		BindParameters: bindParameters,
 	}
-}
\ No newline at end of file
+}
+
+// Statement returns the query sent to the server, prefixed with <trace> if Trace is set.
+func (this *Query) Statement() string {
+	if this.Trace && !strings.HasPrefix(strings.TrimSpace(this.QueryString), "<trace>") {
+		return "<trace> " + this.QueryString
+	}
+	return this.QueryString
+}
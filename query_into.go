@@ -0,0 +1,157 @@
+package geode_go_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/gemfire/geode-go-client/query"
+)
+
+// QueryInto executes a query, decoding every result into dest, which must point to a slice.
+// The results of a list query are decoded into the slice's elements, JSON documents being
+// unmarshalled into structs, so that no Reference or type assertions are needed:
+//
+//	var people []Person
+//	err := client.QueryInto(client.Query("select * from /Employees"), &people)
+//
+// Each row of a table result is decoded into a struct, whose fields are matched to columns by
+// their JSON name or, failing that, their name ignoring case, or into a map from column name
+// to value. Columns without a matching field are ignored. Primitive values must be
+// assignable to their elements or fields, so integers are decoded into int32 or int64, not
+// int. dest is only set if every result is decoded.
+func (this *Client) QueryInto(query *Query, dest interface{}) error {
+	return this.queryInto(this.connector, query, dest)
+}
+
+func (this *Client) QueryIntoCtx(ctx context.Context, query *Query, dest interface{}) error {
+	return this.queryInto(this.connector.BindContext(ctx), query, dest)
+}
+
+func (this *Client) queryInto(conn connector.Operations, query *Query, dest interface{}) error {
+	target := reflect.ValueOf(dest)
+	if target.Kind() != reflect.Ptr || target.IsNil() || target.Elem().Kind() != reflect.Slice {
+		return errors.New(fmt.Sprintf("query results cannot be decoded into %T; a pointer to a slice is required", dest))
+	}
+	sliceType := target.Elem().Type()
+
+	rows, err := this.queryRows(conn, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	scan, err := rowScanner(rows.Columns(), sliceType.Elem())
+	if err != nil {
+		return err
+	}
+
+	results := reflect.MakeSlice(sliceType, 0, rows.Len())
+	for rows.Next() {
+		elem := reflect.New(sliceType.Elem()).Elem()
+		if err := scan(rows, elem); err != nil {
+			return errors.New(fmt.Sprintf("unable to decode query result %d: %s", results.Len(), err.Error()))
+		}
+		results = reflect.Append(results, elem)
+	}
+
+	target.Elem().Set(results)
+	return nil
+}
+
+// Return a function scanning a row into a settable value of type elemType
+func rowScanner(columns []string, elemType reflect.Type) (func(*connector.Rows, reflect.Value) error, error) {
+	if columns == nil {
+		return func(rows *connector.Rows, elem reflect.Value) error {
+			return rows.Scan(elem.Addr().Interface())
+		}, nil
+	}
+
+	rowType := elemType
+	if rowType.Kind() == reflect.Ptr {
+		rowType = rowType.Elem()
+	}
+
+	switch {
+	case rowType.Kind() == reflect.Map && rowType.Key().Kind() == reflect.String:
+		return func(rows *connector.Rows, elem reflect.Value) error {
+			row := reflect.MakeMapWithSize(rowType, len(columns))
+			values := make([]reflect.Value, len(columns))
+			dest := make([]interface{}, len(columns))
+			for i := range columns {
+				values[i] = reflect.New(rowType.Elem())
+				dest[i] = values[i].Interface()
+			}
+			if err := rows.Scan(dest...); err != nil {
+				return err
+			}
+			for i, column := range columns {
+				row.SetMapIndex(reflect.ValueOf(column).Convert(rowType.Key()), values[i].Elem())
+			}
+			setRow(elem, row)
+			return nil
+		}, nil
+
+	case rowType.Kind() == reflect.Struct:
+		fields := make([][]int, len(columns))
+		for i, column := range columns {
+			fields[i] = columnField(rowType, column)
+		}
+
+		return func(rows *connector.Rows, elem reflect.Value) error {
+			row := reflect.New(rowType).Elem()
+			dest := make([]interface{}, len(columns))
+			for i := range columns {
+				if fields[i] == nil {
+					// Ignored
+					dest[i] = new(interface{})
+				} else {
+					dest[i] = row.FieldByIndex(fields[i]).Addr().Interface()
+				}
+			}
+			if err := rows.Scan(dest...); err != nil {
+				return err
+			}
+			setRow(elem, row)
+			return nil
+		}, nil
+	}
+
+	return nil, errors.New(fmt.Sprintf("table results cannot be decoded into %s; a struct or a map keyed by string is required", elemType))
+}
+
+// Set an element of type T or *T to a row of type T
+func setRow(elem, row reflect.Value) {
+	if elem.Kind() == reflect.Ptr {
+		p := reflect.New(row.Type())
+		p.Elem().Set(row)
+		row = p
+	}
+	elem.Set(row)
+}
+
+// Return the index of the exported field of a struct matching a column, or nil if none does
+func columnField(structType reflect.Type, column string) []int {
+	var byName []int
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == column {
+			return field.Index
+		}
+		if byName == nil && strings.EqualFold(field.Name, column) {
+			byName = field.Index
+		}
+	}
+	return byName
+}
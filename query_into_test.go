@@ -0,0 +1,99 @@
+package geode_go_client_test
+
+import (
+	geode "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/connector"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/gemfire/geode-go-client/query"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type employee struct {
+	Name string `json:"name"`
+	Age  int32  `json:"age"`
+}
+
+var _ = Describe("QueryInto", func() {
+
+	var cluster *fakeCluster
+	var client *geode.Client
+
+	listResult := func(values ...interface{}) *v1.OQLQueryResponse {
+		list, err := connector.EncodeValueList(values)
+		Expect(err).To(BeNil())
+		return &v1.OQLQueryResponse{Result: &v1.OQLQueryResponse_ListResult{ListResult: list}}
+	}
+
+	tableResult := func(names []string, columns ...[]interface{}) *v1.OQLQueryResponse {
+		table := &v1.Table{FieldName: names}
+		for _, column := range columns {
+			list, err := connector.EncodeValueList(column)
+			Expect(err).To(BeNil())
+			table.Row = append(table.Row, list)
+		}
+		return &v1.OQLQueryResponse{Result: &v1.OQLQueryResponse_TableResult{TableResult: table}}
+	}
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		client = geode.NewGeodeClient(cluster.connector())
+	})
+
+	It("decodes JSON results into a slice of structs", func() {
+		cluster.queryResult = listResult(&employee{Name: "Joe", Age: 42}, &employee{Name: "Ann", Age: 37})
+
+		var employees []employee
+		Expect(client.QueryInto(query.NewQuery("select * from /Employees"), &employees)).To(Succeed())
+		Expect(employees).To(Equal([]employee{{Name: "Joe", Age: 42}, {Name: "Ann", Age: 37}}))
+	})
+
+	It("decodes JSON results into a slice of pointers", func() {
+		cluster.queryResult = listResult(&employee{Name: "Joe", Age: 42})
+
+		var employees []*employee
+		Expect(client.QueryInto(query.NewQuery("select * from /Employees"), &employees)).To(Succeed())
+		Expect(employees).To(Equal([]*employee{{Name: "Joe", Age: 42}}))
+	})
+
+	It("decodes primitive results", func() {
+		cluster.queryResult = listResult("Joe", "Ann")
+
+		var names []string
+		Expect(client.QueryInto(query.NewQuery("select e.name from /Employees e"), &names)).To(Succeed())
+		Expect(names).To(Equal([]string{"Joe", "Ann"}))
+	})
+
+	It("decodes table rows into structs by column name", func() {
+		cluster.queryResult = tableResult([]string{"age", "name", "other"},
+			[]interface{}{int32(42), int32(37)}, []interface{}{"Joe", "Ann"}, []interface{}{"x", "y"})
+
+		var employees []employee
+		Expect(client.QueryInto(query.NewQuery("select e.age, e.name, e.other from /Employees e"), &employees)).To(Succeed())
+		Expect(employees).To(Equal([]employee{{Name: "Joe", Age: 42}, {Name: "Ann", Age: 37}}))
+	})
+
+	It("decodes table rows into maps", func() {
+		cluster.queryResult = tableResult([]string{"name", "age"}, []interface{}{"Joe"}, []interface{}{int32(42)})
+
+		var rows []map[string]interface{}
+		Expect(client.QueryInto(query.NewQuery("select e.name, e.age from /Employees e"), &rows)).To(Succeed())
+		Expect(rows).To(Equal([]map[string]interface{}{{"name": "Joe", "age": int32(42)}}))
+	})
+
+	It("leaves the destination unchanged if a result cannot be decoded", func() {
+		cluster.queryResult = listResult("Joe", int32(42))
+
+		names := []string{"unchanged"}
+		err := client.QueryInto(query.NewQuery("select * from /Employees"), &names)
+		Expect(err).To(MatchError("unable to decode query result 1: cannot decode int32 into *string"))
+		Expect(names).To(Equal([]string{"unchanged"}))
+	})
+
+	It("requires a pointer to a slice", func() {
+		var names []string
+		Expect(client.QueryInto(query.NewQuery("select * from /Employees"), names)).
+			To(MatchError("query results cannot be decoded into []string; a pointer to a slice is required"))
+		Expect(cluster.requests).To(BeEmpty())
+	})
+})
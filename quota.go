@@ -0,0 +1,194 @@
+package geode_go_client
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/golang/protobuf/proto"
+)
+
+// Name of the metric counting operations rejected by a Quota, keyed by tenant
+const MetricTenantQuotaRejections = "tenantQuotaRejections"
+
+// A QuotaExceededError is returned by a TenantScopedClient when an operation is rejected by
+// its Quota. Limit is either "ops" or "bytes".
+type QuotaExceededError struct {
+	Tenant string
+	Limit  string
+}
+
+func (this *QuotaExceededError) Error() string {
+	return fmt.Sprintf("tenant %s exceeded %s quota", this.Tenant, this.Limit)
+}
+
+// A Quota decides whether a tenant may perform an operation. Admit is called before every
+// operation is sent to the cluster with the encoded size of the keys and values being sent;
+// returning an error rejects the operation. Values returned by reads are not counted.
+type Quota interface {
+	Admit(tenant string, bytes int) error
+}
+
+type rateLimit struct {
+	opsPerSecond   float64
+	bytesPerSecond float64
+}
+
+type tenantBucket struct {
+	ops     float64
+	bytes   float64
+	updated time.Time
+}
+
+// A RateQuota limits each tenant to a number of operations and bytes per second, allowing
+// bursts of up to one second's worth of either. A limit of 0 is unlimited. An operation
+// larger than one second's worth of bytes is always rejected.
+type RateQuota struct {
+	sync.Mutex
+	limit     rateLimit
+	overrides map[string]rateLimit
+	buckets   map[string]*tenantBucket
+	now       func() time.Time
+}
+
+func NewRateQuota(opsPerSecond, bytesPerSecond int) *RateQuota {
+	return &RateQuota{
+		limit:     rateLimit{float64(opsPerSecond), float64(bytesPerSecond)},
+		overrides: make(map[string]rateLimit),
+		buckets:   make(map[string]*tenantBucket),
+		now:       time.Now,
+	}
+}
+
+// SetTenantLimit overrides the default limits for a single tenant.
+func (this *RateQuota) SetTenantLimit(tenant string, opsPerSecond, bytesPerSecond int) {
+	this.Lock()
+	defer this.Unlock()
+
+	this.overrides[tenant] = rateLimit{float64(opsPerSecond), float64(bytesPerSecond)}
+	delete(this.buckets, tenant)
+}
+
+func (this *RateQuota) Admit(tenant string, bytes int) error {
+	this.Lock()
+	defer this.Unlock()
+
+	limit, ok := this.overrides[tenant]
+	if !ok {
+		limit = this.limit
+	}
+
+	now := this.now()
+	bucket, ok := this.buckets[tenant]
+	if !ok {
+		bucket = &tenantBucket{ops: limit.opsPerSecond, bytes: limit.bytesPerSecond, updated: now}
+		this.buckets[tenant] = bucket
+	}
+
+	elapsed := now.Sub(bucket.updated).Seconds()
+	bucket.updated = now
+	bucket.ops = refill(bucket.ops, limit.opsPerSecond, elapsed)
+	bucket.bytes = refill(bucket.bytes, limit.bytesPerSecond, elapsed)
+
+	if limit.opsPerSecond > 0 && bucket.ops < 1 {
+		return &QuotaExceededError{Tenant: tenant, Limit: "ops"}
+	}
+	if limit.bytesPerSecond > 0 && bucket.bytes < float64(bytes) {
+		return &QuotaExceededError{Tenant: tenant, Limit: "bytes"}
+	}
+
+	if limit.opsPerSecond > 0 {
+		bucket.ops--
+	}
+	if limit.bytesPerSecond > 0 {
+		bucket.bytes -= float64(bytes)
+	}
+
+	return nil
+}
+
+func refill(available, rate, elapsed float64) float64 {
+	available += rate * elapsed
+	if available > rate {
+		return rate
+	}
+	return available
+}
+
+// SetQuota enforces a Quota on every operation performed through this client. Rejected
+// operations return the Quota's error without contacting the cluster and are counted by the
+// pool's MetricsPublisher as MetricTenantQuotaRejections.
+func (this *TenantScopedClient) SetQuota(quota Quota) {
+	this.quotaLock.Lock()
+	defer this.quotaLock.Unlock()
+
+	this.quota = quota
+}
+
+func (this *TenantScopedClient) admit(tenant string, values ...interface{}) error {
+	this.quotaLock.RLock()
+	quota := this.quota
+	this.quotaLock.RUnlock()
+
+	if quota == nil {
+		return nil
+	}
+
+	size := 0
+	for _, v := range values {
+		size += encodedSize(v)
+	}
+
+	if err := admitQuota(quota, tenant, size); err != nil {
+		this.countRejection(tenant)
+		return err
+	}
+
+	return nil
+}
+
+func admitQuota(quota Quota, tenant string, size int) (err error) {
+	defer connector.RecoverCallback("Quota", &err)
+	return quota.Admit(tenant, size)
+}
+
+func (this *TenantScopedClient) countRejection(tenant string) {
+	conn, err := this.client.protobuf("metrics")
+	if err != nil {
+		return
+	}
+
+	defer connector.RecoverCallback("MetricsPublisher", nil)
+	conn.GetPool().GetMetricsPublisher().AddKeyed(MetricTenantQuotaRejections, tenant, 1)
+}
+
+// Estimate the number of bytes a key, value, slice of keys or map of entries will occupy
+// on the wire. Values which cannot be encoded count as 0 and will fail later.
+func encodedSize(value interface{}) int {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		size := 0
+		for _, k := range v.MapKeys() {
+			size += encodedSize(k.Interface()) + encodedSize(v.MapIndex(k).Interface())
+		}
+		return size
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			size := 0
+			for i := 0; i < v.Len(); i++ {
+				size += encodedSize(v.Index(i).Interface())
+			}
+			return size
+		}
+	}
+
+	encoded, err := connector.EncodeValue(value)
+	if err != nil {
+		return 0
+	}
+
+	return proto.Size(encoded)
+}
@@ -0,0 +1,115 @@
+package geode_go_client_test
+
+import (
+	"context"
+	"sync"
+
+	geode "github.com/gemfire/geode-go-client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type recordingPublisher struct {
+	sync.Mutex
+	counters map[string]int64
+}
+
+func (this *recordingPublisher) Add(name string, delta int64) {
+	this.Lock()
+	defer this.Unlock()
+	this.counters[name] += delta
+}
+
+func (this *recordingPublisher) AddKeyed(name, key string, delta int64) {
+	this.Add(name+"/"+key, delta)
+}
+
+var _ = Describe("Tenant quotas", func() {
+
+	var cluster *fakeCluster
+	var tenants *geode.TenantScopedClient
+	var quota *geode.RateQuota
+	var acme, globex context.Context
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		tenants = geode.NewTenantScopedClient(geode.NewGeodeClient(cluster.connector()), geode.PrefixKeys)
+		acme = geode.WithTenant(context.Background(), "acme")
+		globex = geode.WithTenant(context.Background(), "globex")
+	})
+
+	It("rejects operations over the ops limit", func() {
+		quota = geode.NewRateQuota(2, 0)
+		tenants.SetQuota(quota)
+
+		Expect(tenants.Put(acme, "foo", "A", 1)).To(BeNil())
+		Expect(tenants.Put(acme, "foo", "B", 2)).To(BeNil())
+
+		err := tenants.Put(acme, "foo", "C", 3)
+		Expect(err).To(Equal(&geode.QuotaExceededError{Tenant: "acme", Limit: "ops"}))
+		Expect(cluster.requests).To(HaveLen(2))
+
+		// Other tenants are unaffected
+		Expect(tenants.Put(globex, "foo", "A", 1)).To(BeNil())
+	})
+
+	It("rejects operations over the bytes limit", func() {
+		quota = geode.NewRateQuota(0, 64)
+		tenants.SetQuota(quota)
+
+		Expect(tenants.Put(acme, "foo", "A", "small")).To(BeNil())
+
+		err := tenants.Put(acme, "foo", "B", string(make([]byte, 100)))
+		Expect(err).To(Equal(&geode.QuotaExceededError{Tenant: "acme", Limit: "bytes"}))
+	})
+
+	It("applies per-tenant overrides", func() {
+		quota = geode.NewRateQuota(1, 0)
+		quota.SetTenantLimit("globex", 3, 0)
+		tenants.SetQuota(quota)
+
+		Expect(tenants.Put(acme, "foo", "A", 1)).To(BeNil())
+		Expect(tenants.Put(acme, "foo", "B", 1)).ToNot(BeNil())
+
+		for _, key := range []string{"A", "B", "C"} {
+			Expect(tenants.Put(globex, "foo", key, 1)).To(BeNil())
+		}
+		Expect(tenants.Put(globex, "foo", "X", 1)).ToNot(BeNil())
+	})
+
+	It("counts rejections per tenant", func() {
+		publisher := &recordingPublisher{counters: make(map[string]int64)}
+		conn := cluster.connector()
+		conn.GetPool().SetMetricsPublisher(publisher)
+		tenants = geode.NewTenantScopedClient(geode.NewGeodeClient(conn), geode.PrefixKeys)
+
+		tenants.SetQuota(geode.NewRateQuota(1, 0))
+		tenants.Put(acme, "foo", "A", 1)
+		tenants.Put(acme, "foo", "B", 1)
+		tenants.Put(acme, "foo", "C", 1)
+		tenants.Put(globex, "foo", "A", 1)
+
+		Expect(publisher.counters[geode.MetricTenantQuotaRejections+"/acme"]).To(Equal(int64(2)))
+		Expect(publisher.counters).ToNot(HaveKey(geode.MetricTenantQuotaRejections + "/globex"))
+	})
+
+	It("can be changed while operations run", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for j := 0; j < 50; j++ {
+					tenants.Put(acme, "foo", "A", j)
+				}
+			}()
+		}
+		for j := 0; j < 50; j++ {
+			tenants.SetQuota(geode.NewRateQuota(1000, 0))
+		}
+		wg.Wait()
+
+		Expect(tenants.Put(acme, "foo", "A", 1)).To(BeNil())
+	})
+})
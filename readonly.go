@@ -0,0 +1,43 @@
+package geode_go_client
+
+import (
+	"errors"
+)
+
+// ErrReadOnly is returned when an operation which may modify a region is attempted on a
+// read-only Client.
+var ErrReadOnly = errors.New("client is read-only")
+
+// SetReadOnly makes the Client reject writes, and functions not declared with
+// AllowReadOnlyFunction, with ErrReadOnly. Queries are always permitted.
+func (this *Client) SetReadOnly(readOnly bool) {
+	this.accessLock.Lock()
+	defer this.accessLock.Unlock()
+
+	this.readOnly = readOnly
+}
+
+// AllowReadOnlyFunction declares that the given functions do not modify data and may be
+// executed by a read-only Client.
+func (this *Client) AllowReadOnlyFunction(functionIds ...string) {
+	this.accessLock.Lock()
+	defer this.accessLock.Unlock()
+
+	if this.readOnlyFunctions == nil {
+		this.readOnlyFunctions = make(map[string]bool)
+	}
+
+	for _, id := range functionIds {
+		this.readOnlyFunctions[id] = true
+	}
+}
+
+func (this *Client) checkFunction(functionId string) error {
+	this.accessLock.RLock()
+	defer this.accessLock.RUnlock()
+
+	if this.readOnly && !this.readOnlyFunctions[functionId] {
+		return ErrReadOnly
+	}
+	return nil
+}
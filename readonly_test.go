@@ -0,0 +1,85 @@
+package geode_go_client_test
+
+import (
+	geode "github.com/gemfire/geode-go-client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Read-only client", func() {
+
+	var cluster *fakeCluster
+	var client *geode.Client
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		writer := geode.NewGeodeClient(cluster.connector())
+		Expect(writer.Put("foo", "A", 1)).To(BeNil())
+
+		client = geode.NewGeodeClient(cluster.connector())
+		client.SetReadOnly(true)
+		cluster.requests = nil
+	})
+
+	It("rejects writes without contacting the cluster", func() {
+		Expect(client.Put("foo", "B", 2)).To(Equal(geode.ErrReadOnly))
+		Expect(client.PutIfAbsent("foo", "B", 2)).To(Equal(geode.ErrReadOnly))
+		Expect(client.Remove("foo", "A")).To(Equal(geode.ErrReadOnly))
+		Expect(client.Clear("foo")).To(Equal(geode.ErrReadOnly))
+
+		_, err := client.PutAll("foo", map[string]int{"B": 2})
+		Expect(err).To(Equal(geode.ErrReadOnly))
+
+		_, err = client.RemoveAll("foo", []string{"A"})
+		Expect(err).To(Equal(geode.ErrReadOnly))
+
+		Expect(client.PutRaw("foo", nil, nil)).To(Equal(geode.ErrReadOnly))
+
+		Expect(cluster.requests).To(BeEmpty())
+	})
+
+	It("allows reads", func() {
+		v, err := client.Get("foo", "A")
+		Expect(err).To(BeNil())
+		Expect(v).To(Equal(int32(1)))
+
+		size, err := client.Size("foo")
+		Expect(err).To(BeNil())
+		Expect(size).To(Equal(int32(1)))
+	})
+
+	It("only executes functions declared read-only", func() {
+		_, err := client.ExecuteOnRegion("writer", "foo", nil, nil)
+		Expect(err).To(Equal(geode.ErrReadOnly))
+		_, err = client.ExecuteOnMembers("writer", []string{"server1"}, nil)
+		Expect(err).To(Equal(geode.ErrReadOnly))
+		Expect(cluster.requests).To(BeEmpty())
+
+		client.AllowReadOnlyFunction("reader")
+		_, err = client.ExecuteOnGroups("reader", []string{"group1"}, nil)
+		Expect(err).ToNot(Equal(geode.ErrReadOnly))
+		Expect(cluster.requests).To(HaveLen(1))
+	})
+
+	It("can be made writable again", func() {
+		client.SetReadOnly(false)
+		Expect(client.Put("foo", "B", 2)).To(BeNil())
+	})
+
+	It("can be toggled while operations are running", func() {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 100; i++ {
+				client.SetReadOnly(i%2 == 0)
+				client.AllowReadOnlyFunction("reader")
+				client.DenyRegions("bar")
+			}
+		}()
+
+		for i := 0; i < 100; i++ {
+			_ = client.Put("foo", "B", 2)
+		}
+		<-done
+	})
+})
@@ -0,0 +1,296 @@
+package geode_go_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/gemfire/geode-go-client/codec"
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+// A Region is a typed view of a region of a Client, so that keys and values need no type
+// assertions and JSON values need no reference. Values are converted from the types they are
+// decoded as, so an int is read back from the int32 it is stored as. A V which is a struct,
+// a map or a pointer to a struct is stored as JSON and decoded into a new value for each
+// entry.
+//
+// For example:
+//
+//	employees := geode.NewRegion[string, Employee](client, "Employees")
+//	err := employees.Put("Joe", Employee{Name: "Joe"})
+//	joe, err := employees.Get("Joe")
+type Region[K comparable, V any] struct {
+	client *Client
+	name   string
+}
+
+// NewRegion returns a typed view of the named region of client. Operations are performed by
+// the client, so its transforms and access control apply.
+func NewRegion[K comparable, V any](client *Client, name string) *Region[K, V] {
+	return &Region[K, V]{client: client, name: name}
+}
+
+// Name returns the name of the region.
+func (this *Region[K, V]) Name() string {
+	return this.name
+}
+
+// Get returns the value for key, or the zero value of V if there is none. Use Lookup to tell
+// a missing entry from a zero value.
+func (this *Region[K, V]) Get(key K) (V, error) {
+	return this.GetCtx(context.Background(), key)
+}
+
+func (this *Region[K, V]) GetCtx(ctx context.Context, key K) (V, error) {
+	value, _, err := this.LookupCtx(ctx, key)
+	return value, err
+}
+
+// Lookup returns the value for key and whether the region has an entry for it. See
+// Client.GetOptional.
+func (this *Region[K, V]) Lookup(key K) (V, bool, error) {
+	return this.LookupCtx(context.Background(), key)
+}
+
+func (this *Region[K, V]) LookupCtx(ctx context.Context, key K) (V, bool, error) {
+	var zero V
+
+	result, err := this.client.GetOptionalCtx(ctx, this.name, key, newReference[V]())
+	if err != nil {
+		return zero, false, err
+	}
+
+	value, err := convertTo[V](result.Value)
+	if err != nil {
+		return zero, false, err
+	}
+	return value, result.Present, nil
+}
+
+func (this *Region[K, V]) Put(key K, value V) error {
+	return this.client.PutCtx(context.Background(), this.name, key, value)
+}
+
+func (this *Region[K, V]) PutCtx(ctx context.Context, key K, value V) error {
+	return this.client.PutCtx(ctx, this.name, key, value)
+}
+
+func (this *Region[K, V]) PutIfAbsent(key K, value V) error {
+	return this.client.PutIfAbsentCtx(context.Background(), this.name, key, value)
+}
+
+func (this *Region[K, V]) PutIfAbsentCtx(ctx context.Context, key K, value V) error {
+	return this.client.PutIfAbsentCtx(ctx, this.name, key, value)
+}
+
+func (this *Region[K, V]) Remove(key K) error {
+	return this.client.RemoveCtx(context.Background(), this.name, key)
+}
+
+func (this *Region[K, V]) RemoveCtx(ctx context.Context, key K) error {
+	return this.client.RemoveCtx(ctx, this.name, key)
+}
+
+// RemoveAll removes many entries, returning the errors for any keys which could not be
+// removed, as Client.RemoveAll.
+func (this *Region[K, V]) RemoveAll(keys []K) (map[K]error, error) {
+	return this.RemoveAllCtx(context.Background(), keys)
+}
+
+func (this *Region[K, V]) RemoveAllCtx(ctx context.Context, keys []K) (map[K]error, error) {
+	failures, err := this.client.RemoveAllCtx(ctx, this.name, keys)
+	if len(failures) == 0 {
+		return nil, err
+	}
+
+	result := make(map[K]error, len(failures))
+	for k, failure := range failures {
+		key, convertErr := convertTo[K](k)
+		if convertErr != nil {
+			return nil, convertErr
+		}
+		result[key] = failure
+	}
+
+	return result, err
+}
+
+func (this *Region[K, V]) Size() (int32, error) {
+	return this.client.SizeCtx(context.Background(), this.name)
+}
+
+func (this *Region[K, V]) SizeCtx(ctx context.Context) (int32, error) {
+	return this.client.SizeCtx(ctx, this.name)
+}
+
+func (this *Region[K, V]) Clear() error {
+	return this.client.ClearCtx(context.Background(), this.name)
+}
+
+func (this *Region[K, V]) ClearCtx(ctx context.Context) error {
+	return this.client.ClearCtx(ctx, this.name)
+}
+
+// Keys returns the keys of the region.
+func (this *Region[K, V]) Keys() ([]K, error) {
+	return this.KeysCtx(context.Background())
+}
+
+func (this *Region[K, V]) KeysCtx(ctx context.Context) ([]K, error) {
+	keys := make([]K, 0)
+	var convertErr error
+
+	err := this.client.KeysFuncCtx(ctx, this.name, func(k interface{}) bool {
+		key, err := convertTo[K](k)
+		if err != nil {
+			convertErr = err
+			return false
+		}
+		keys = append(keys, key)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if convertErr != nil {
+		return nil, convertErr
+	}
+
+	return keys, nil
+}
+
+// GetAll returns the values of the keys which have entries and the errors for those which
+// could not be read, as Client.GetAll.
+func (this *Region[K, V]) GetAll(keys []K) (map[K]V, map[K]error, error) {
+	return this.GetAllCtx(context.Background(), keys)
+}
+
+func (this *Region[K, V]) GetAllCtx(ctx context.Context, keys []K) (map[K]V, map[K]error, error) {
+	entries := make(map[K]V, len(keys))
+	var failures map[K]error
+	var convertErr error
+
+	err := this.client.GetAllIntoCtx(ctx, this.name, keys, newReference[V], func(k, v interface{}, err error) bool {
+		key, keyErr := convertTo[K](k)
+		if keyErr != nil {
+			convertErr = keyErr
+			return false
+		}
+
+		var value V
+		if err == nil {
+			value, err = convertTo[V](v)
+		}
+		if err != nil {
+			if failures == nil {
+				failures = make(map[K]error)
+			}
+			failures[key] = err
+			return true
+		}
+
+		entries[key] = value
+		return true
+	})
+	if convertErr != nil {
+		return nil, nil, convertErr
+	}
+	if _, partial := err.(*connector.MultiError); err != nil && !partial {
+		return nil, nil, err
+	}
+
+	return entries, failures, err
+}
+
+// PutAll puts many entries, returning the errors for any keys which could not be put, as
+// Client.PutAll.
+func (this *Region[K, V]) PutAll(entries map[K]V) (map[K]error, error) {
+	return this.PutAllCtx(context.Background(), entries)
+}
+
+func (this *Region[K, V]) PutAllCtx(ctx context.Context, entries map[K]V) (map[K]error, error) {
+	failures, err := this.client.PutAllCtx(ctx, this.name, entries)
+	if len(failures) == 0 {
+		return nil, err
+	}
+
+	result := make(map[K]error, len(failures))
+	for k, failure := range failures {
+		key, convertErr := convertTo[K](k)
+		if convertErr != nil {
+			return nil, convertErr
+		}
+		result[key] = failure
+	}
+
+	return result, err
+}
+
+// Return a new value for a JSON document to be decoded into, if V is stored as JSON
+func newReference[V any]() interface{} {
+	t := reflect.TypeOf((*V)(nil)).Elem()
+	switch {
+	case t.Kind() == reflect.Struct, t.Kind() == reflect.Map:
+		return reflect.New(t).Interface()
+	case t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct:
+		return reflect.New(t.Elem()).Interface()
+	}
+	return nil
+}
+
+// Convert a decoded key or value to T. nil converts to the zero value.
+func convertTo[T any](v interface{}) (T, error) {
+	var zero T
+	if v == nil {
+		return zero, nil
+	}
+	if t, ok := v.(T); ok {
+		return t, nil
+	}
+
+	// RoutedKeys are read back as the strings they are stored as
+	if s, ok := v.(string); ok {
+		if _, routed := interface{}(zero).(codec.RoutedKey); routed {
+			if key, ok := codec.ParseRoutedKey(s); ok {
+				return interface{}(key).(T), nil
+			}
+		}
+	}
+
+	target := reflect.TypeOf((*T)(nil)).Elem()
+	value := reflect.ValueOf(v)
+
+	// JSON documents are decoded into a pointer to a new T
+	if value.Kind() == reflect.Ptr && value.Type().Elem() == target {
+		return value.Elem().Interface().(T), nil
+	}
+
+	if sameKindFamily(value.Kind(), target.Kind()) && value.Type().ConvertibleTo(target) {
+		return value.Convert(target).Interface().(T), nil
+	}
+
+	return zero, errors.New(fmt.Sprintf("cannot convert %T to %s", v, target))
+}
+
+// Whether a value of one kind may be converted to another without changing its meaning, for
+// example from int32 to int but not from int32 to string
+func sameKindFamily(from, to reflect.Kind) bool {
+	family := func(kind reflect.Kind) int {
+		switch kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return 1
+		case reflect.Float32, reflect.Float64:
+			return 2
+		case reflect.String:
+			return 3
+		case reflect.Bool:
+			return 4
+		}
+		return 0
+	}
+
+	return family(from) != 0 && family(from) == family(to)
+}
@@ -0,0 +1,140 @@
+package geode_go_client_test
+
+import (
+	geode "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/codec"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type Employee struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+var _ = Describe("Region", func() {
+
+	var cluster *fakeCluster
+	var client *geode.Client
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		client = geode.NewGeodeClient(cluster.connector())
+	})
+
+	It("reads structs without a reference", func() {
+		employees := geode.NewRegion[string, Employee](client, "Employees")
+		Expect(employees.Name()).To(Equal("Employees"))
+
+		Expect(employees.Put("Joe", Employee{Name: "Joe", Age: 42})).To(BeNil())
+		joe, err := employees.Get("Joe")
+		Expect(err).To(BeNil())
+		Expect(joe).To(Equal(Employee{Name: "Joe", Age: 42}))
+
+		Expect(employees.Size()).To(Equal(int32(1)))
+		Expect(employees.Remove("Joe")).To(BeNil())
+		Expect(cluster.keys("Employees")).To(BeEmpty())
+	})
+
+	It("reads pointers to structs", func() {
+		employees := geode.NewRegion[string, *Employee](client, "Employees")
+
+		Expect(employees.Put("Joe", &Employee{Name: "Joe"})).To(BeNil())
+		joe, err := employees.Get("Joe")
+		Expect(err).To(BeNil())
+		Expect(joe).To(Equal(&Employee{Name: "Joe"}))
+
+		missing, err := employees.Get("Ann")
+		Expect(err).To(BeNil())
+		Expect(missing).To(BeNil())
+	})
+
+	It("converts primitive keys and values", func() {
+		counts := geode.NewRegion[int, int](client, "Counts")
+
+		Expect(counts.Put(1, 10)).To(BeNil())
+		count, ok, err := counts.Lookup(1)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+		Expect(count).To(Equal(10))
+
+		count, ok, err = counts.Lookup(2)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeFalse())
+		Expect(count).To(Equal(0))
+	})
+
+	It("puts and gets many entries", func() {
+		employees := geode.NewRegion[string, Employee](client, "Employees")
+
+		failures, err := employees.PutAll(map[string]Employee{
+			"Joe": {Name: "Joe"},
+			"Ann": {Name: "Ann"},
+		})
+		Expect(err).To(BeNil())
+		Expect(failures).To(BeNil())
+
+		entries, failures, err := employees.GetAll([]string{"Joe", "Ann", "Bob"})
+		Expect(err).To(BeNil())
+		Expect(failures).To(BeNil())
+		Expect(entries).To(Equal(map[string]Employee{
+			"Joe": {Name: "Joe"},
+			"Ann": {Name: "Ann"},
+		}))
+	})
+
+	It("lists keys", func() {
+		counts := geode.NewRegion[int, int](client, "Counts")
+		Expect(counts.Put(1, 10)).To(BeNil())
+		Expect(counts.Put(2, 20)).To(BeNil())
+
+		keys, err := counts.Keys()
+		Expect(err).To(BeNil())
+		Expect(keys).To(ConsistOf(1, 2))
+
+		untyped, err := client.Keys("Counts")
+		Expect(err).To(BeNil())
+		Expect(untyped).To(ConsistOf(int32(1), int32(2)))
+
+		Expect(counts.Clear()).To(BeNil())
+		Expect(counts.Keys()).To(BeEmpty())
+	})
+
+	It("removes many entries", func() {
+		counts := geode.NewRegion[int, int](client, "Counts")
+		Expect(counts.Put(1, 10)).To(BeNil())
+		Expect(counts.Put(2, 20)).To(BeNil())
+		Expect(counts.Put(3, 30)).To(BeNil())
+
+		failures, err := counts.RemoveAll([]int{1, 3})
+		Expect(err).To(BeNil())
+		Expect(failures).To(BeNil())
+		Expect(counts.Keys()).To(ConsistOf(2))
+	})
+
+	It("reads routed keys back", func() {
+		orders := geode.NewRegion[codec.RoutedKey, string](client, "Orders")
+		key := codec.RoutedKey{Routing: "customer-7", Key: "order-12"}
+
+		Expect(orders.Put(key, "shipped")).To(BeNil())
+		Expect(cluster.keys("Orders")).To(ConsistOf("customer-7|order-12"))
+
+		entries, failures, err := orders.GetAll([]codec.RoutedKey{key})
+		Expect(err).To(BeNil())
+		Expect(failures).To(BeNil())
+		Expect(entries).To(Equal(map[codec.RoutedKey]string{key: "shipped"}))
+	})
+
+	It("reports values of the wrong type", func() {
+		Expect(client.Put("Mixed", "A", int32(7))).To(BeNil())
+
+		names := geode.NewRegion[string, string](client, "Mixed")
+		_, err := names.Get("A")
+		Expect(err).To(MatchError("cannot convert int32 to string"))
+
+		entries, failures, err := names.GetAll([]string{"A"})
+		Expect(err).To(BeNil())
+		Expect(entries).To(BeEmpty())
+		Expect(failures["A"]).To(MatchError("cannot convert int32 to string"))
+	})
+})
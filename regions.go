@@ -0,0 +1,60 @@
+package geode_go_client
+
+import (
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+// RegionInfo describes a region. The protocol has no message describing a region's
+// attributes, so its data policy and scope cannot be reported.
+type RegionInfo struct {
+	Name string
+	// Number of entries in the region
+	Size int32
+}
+
+// RegionNames returns the names of the regions hosted by the cluster, omitting those the
+// Client has been denied access to.
+func (this *Client) RegionNames() ([]string, error) {
+	return this.regionNames(this.connector)
+}
+
+func (this *Client) regionNames(conn connector.Operations) ([]string, error) {
+	if err := this.authorize(OpRegions, ""); err != nil {
+		return nil, err
+	}
+
+	names, err := conn.RegionNames()
+	if err != nil {
+		return nil, err
+	}
+
+	this.accessLock.RLock()
+	defer this.accessLock.RUnlock()
+
+	permitted := make([]string, 0, len(names))
+	for _, name := range names {
+		if this.regionAccess.permits(name) {
+			permitted = append(permitted, name)
+		}
+	}
+
+	return permitted, nil
+}
+
+// GetRegion describes a region, returning an error if the region does not exist.
+func (this *Client) GetRegion(region string) (*RegionInfo, error) {
+	return this.getRegion(this.connector, region)
+}
+
+func (this *Client) getRegion(conn connector.Operations, region string) (*RegionInfo, error) {
+	if err := this.authorize(OpRegions, region); err != nil {
+		return nil, err
+	}
+
+	size, err := conn.Size(region)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RegionInfo{Name: region, Size: size}, nil
+}
@@ -0,0 +1,54 @@
+package geode_go_client_test
+
+import (
+	geode "github.com/gemfire/geode-go-client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Regions", func() {
+
+	var cluster *fakeCluster
+	var client *geode.Client
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		client = geode.NewGeodeClient(cluster.connector())
+		cluster.createRegion("Employees")
+		cluster.createRegion("Orders")
+	})
+
+	It("lists the regions", func() {
+		names, err := client.RegionNames()
+		Expect(err).To(BeNil())
+		Expect(names).To(ConsistOf("Employees", "Orders"))
+	})
+
+	It("only lists the regions the client may access", func() {
+		client.AllowRegions("Employees")
+
+		names, err := client.RegionNames()
+		Expect(err).To(BeNil())
+		Expect(names).To(ConsistOf("Employees"))
+	})
+
+	It("describes a region", func() {
+		Expect(client.Put("Employees", "Joe", 1)).To(BeNil())
+
+		info, err := client.GetRegion("Employees")
+		Expect(err).To(BeNil())
+		Expect(info).To(Equal(&geode.RegionInfo{Name: "Employees", Size: 1}))
+	})
+
+	It("returns an error for a missing region", func() {
+		_, err := client.GetRegion("Missing")
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("checks access to the region described", func() {
+		client.DenyRegions("Orders")
+
+		_, err := client.GetRegion("Orders")
+		Expect(err).To(Equal(&geode.AccessDeniedError{Operation: geode.OpRegions, Region: "Orders"}))
+	})
+})
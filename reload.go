@@ -0,0 +1,84 @@
+package geode_go_client
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+// SetConfigSource sets the source from which the client configuration is loaded by Reload.
+func (this *Client) SetConfigSource(source connector.ConfigSource) {
+	this.configLock.Lock()
+	defer this.configLock.Unlock()
+
+	this.configSource = source
+}
+
+// Reload loads the configuration from the config source and applies it to the client's
+// pool. Operations in progress are not interrupted. If the configuration cannot be loaded or
+// is invalid the current configuration is retained and an error returned.
+func (this *Client) Reload() error {
+	this.configLock.Lock()
+	defer this.configLock.Unlock()
+
+	if this.configSource == nil {
+		return errors.New("no config source set")
+	}
+
+	config, err := loadConfig(this.configSource)
+	if err != nil {
+		return err
+	}
+
+	conn, err := this.protobuf("reloading configuration")
+	if err != nil {
+		return err
+	}
+
+	return conn.GetPool().Configure(config)
+}
+
+func loadConfig(source connector.ConfigSource) (config *connector.Config, err error) {
+	defer connector.RecoverCallback("ConfigSource", &err)
+	return source.Load()
+}
+
+// ReloadOnSignal calls Reload whenever one of the given signals is received; SIGHUP is used
+// if none are given. Errors are passed to the optional onError function. The returned
+// function stops watching for signals.
+func (this *Client) ReloadOnSignal(onError func(error), signals ...os.Signal) (stop func()) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(c, signals...)
+
+	go func() {
+		for {
+			select {
+			case <-c:
+				if err := this.Reload(); err != nil && onError != nil {
+					reportReloadError(onError, err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(c)
+		close(done)
+	}
+}
+
+// Report an error, recovering a panic so that signals continue to be handled
+func reportReloadError(onError func(error), err error) {
+	defer connector.RecoverCallback("ReloadOnSignal error handler", nil)
+	onError(err)
+}
@@ -0,0 +1,67 @@
+package geode_go_client_test
+
+import (
+	"errors"
+
+	geode "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeConfigSource struct {
+	config *connector.Config
+	err    error
+	loads  int
+}
+
+func (this *fakeConfigSource) Load() (*connector.Config, error) {
+	this.loads++
+	return this.config, this.err
+}
+
+var _ = Describe("Reload", func() {
+
+	var cluster *fakeCluster
+	var conn *connector.Protobuf
+	var client *geode.Client
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		conn = cluster.connector()
+		client = geode.NewGeodeClient(conn)
+	})
+
+	It("requires a config source", func() {
+		Expect(client.Reload()).ToNot(BeNil())
+	})
+
+	It("returns errors from the config source", func() {
+		source := &fakeConfigSource{err: errors.New("bad config")}
+		client.SetConfigSource(source)
+
+		Expect(client.Reload()).To(MatchError("bad config"))
+		Expect(source.loads).To(Equal(1))
+	})
+
+	It("applies the configuration to the pool", func() {
+		max := 2
+		source := &fakeConfigSource{config: &connector.Config{MaxConnections: &max}}
+		client.SetConfigSource(source)
+
+		Expect(client.Reload()).To(BeNil())
+		Expect(conn.GetPool().GetMaxConnections()).To(Equal(2))
+		Expect(client.Put("foo", "A", 1)).To(BeNil())
+	})
+
+	It("retains the current configuration when the new one is invalid", func() {
+		max := 2
+		client.SetConfigSource(&fakeConfigSource{config: &connector.Config{MaxConnections: &max}})
+		Expect(client.Reload()).To(BeNil())
+
+		client.SetConfigSource(&fakeConfigSource{config: &connector.Config{Servers: []string{"localhost"}}})
+		Expect(client.Reload()).ToNot(BeNil())
+		Expect(conn.GetPool().GetMaxConnections()).To(Equal(2))
+		Expect(client.Put("foo", "A", 1)).To(BeNil())
+	})
+})
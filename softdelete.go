@@ -0,0 +1,249 @@
+package geode_go_client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gemfire/geode-go-client/connector"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/golang/protobuf/proto"
+)
+
+// Number of entries read at once when purging tombstones
+const purgeBatchSize = 500
+
+// SoftDeletes performs recoverable deletes on an ordinary region. Delete replaces an entry
+// with a tombstone, a JSON document recording when the entry was deleted and holding its
+// value exactly as it was stored, so that Restore can put it back. The reads of SoftDeletes
+// treat tombstones as missing entries; Purge removes those deleted before a given time.
+//
+// Other clients see tombstones as JSON documents with a "geodeTombstone" field, so every
+// reader of the region should use SoftDeletes, or skip such documents. The protocol has no
+// conditional writes, so an entry written by another client between a Delete or Purge
+// reading it and replacing or removing it is lost.
+//
+// Operations are performed by the client, so its key transforms and access control apply.
+// Read transforms are applied to live values as they are decoded.
+type SoftDeletes struct {
+	client *Client
+	region string
+	now    func() time.Time
+}
+
+// A Tombstone describes an entry deleted by SoftDeletes.
+type Tombstone struct {
+	DeletedAt time.Time
+}
+
+// The JSON document stored in place of a deleted entry
+type tombstoneDocument struct {
+	Tombstone bool      `json:"geodeTombstone"`
+	DeletedAt time.Time `json:"deletedAt"`
+	// The deleted value, as an encoded protobuf EncodedValue
+	Value []byte `json:"value"`
+}
+
+// NewSoftDeletes returns soft delete operations on the named region of client.
+func NewSoftDeletes(client *Client, region string) *SoftDeletes {
+	return &SoftDeletes{client: client, region: region, now: time.Now}
+}
+
+// Delete replaces the entry for key with a tombstone. Deleting a missing or already deleted
+// entry does nothing.
+func (this *SoftDeletes) Delete(key interface{}) error {
+	encodedKey, err := this.encodeKey(key)
+	if err != nil {
+		return err
+	}
+
+	current, err := this.client.GetRaw(this.region, encodedKey)
+	if err != nil || current == nil {
+		return err
+	}
+	if _, deleted := asTombstone(current); deleted {
+		return nil
+	}
+
+	data, err := proto.Marshal(current)
+	if err != nil {
+		return err
+	}
+	document, err := json.Marshal(&tombstoneDocument{Tombstone: true, DeletedAt: this.now().UTC(), Value: data})
+	if err != nil {
+		return err
+	}
+
+	return this.client.PutRaw(this.region, encodedKey, &v1.EncodedValue{
+		Value: &v1.EncodedValue_JsonObjectResult{JsonObjectResult: string(document)},
+	})
+}
+
+// Restore puts back the value of a deleted entry, returning false if the entry for key is
+// not a tombstone.
+func (this *SoftDeletes) Restore(key interface{}) (bool, error) {
+	encodedKey, err := this.encodeKey(key)
+	if err != nil {
+		return false, err
+	}
+
+	current, err := this.client.GetRaw(this.region, encodedKey)
+	if err != nil || current == nil {
+		return false, err
+	}
+	tombstone, deleted := asTombstone(current)
+	if !deleted {
+		return false, nil
+	}
+
+	value := &v1.EncodedValue{}
+	if err := proto.Unmarshal(tombstone.Value, value); err != nil {
+		return false, errors.New(fmt.Sprintf("unable to restore deleted value: %s", err.Error()))
+	}
+
+	if err := this.client.PutRaw(this.region, encodedKey, value); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Get decodes the value for key into into, as connector.LazyValue.Decode, returning false if
+// there is no entry or it has been deleted.
+func (this *SoftDeletes) Get(key interface{}, into interface{}) (bool, error) {
+	entries, failures, err := this.GetAllLazy([]interface{}{key})
+	if err != nil {
+		return false, err
+	}
+
+	// Keys come back as decoded, which may be of another type, such as int32 for an int
+	for _, failure := range failures {
+		return false, failure
+	}
+	for _, value := range entries {
+		return true, value.Decode(into)
+	}
+	return false, nil
+}
+
+// GetAllLazy gets many entries as Client.GetAllLazy, leaving out those which have been
+// deleted.
+func (this *SoftDeletes) GetAllLazy(keys interface{}) (map[interface{}]*connector.LazyValue, map[interface{}]error, error) {
+	entries, failures, err := this.client.GetAllLazy(this.region, keys)
+	for k, v := range entries {
+		if _, deleted := asTombstone(v.Encoded()); deleted {
+			delete(entries, k)
+		}
+	}
+
+	return entries, failures, err
+}
+
+// Deleted returns the tombstone of the entry for key, or nil if the entry is missing or has
+// not been deleted.
+func (this *SoftDeletes) Deleted(key interface{}) (*Tombstone, error) {
+	encodedKey, err := this.encodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := this.client.GetRaw(this.region, encodedKey)
+	if err != nil || current == nil {
+		return nil, err
+	}
+	if tombstone, deleted := asTombstone(current); deleted {
+		return &Tombstone{DeletedAt: tombstone.DeletedAt}, nil
+	}
+	return nil, nil
+}
+
+// Purge removes the tombstones of entries deleted before the given time, returning the number
+// removed. Every entry of the region is read to find them.
+func (this *SoftDeletes) Purge(before time.Time) (int, error) {
+	keys, err := this.client.Keys(this.region)
+	if err != nil {
+		return 0, err
+	}
+	if err := this.client.authorize(OpGetAll, this.region); err != nil {
+		return 0, err
+	}
+	if err := this.client.authorize(OpRemove, this.region); err != nil {
+		return 0, err
+	}
+
+	// The keys are read as stored, so are used without transforming them again
+	purged := 0
+	for start := 0; start < len(keys); start += purgeBatchSize {
+		end := start + purgeBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		entries, _, err := this.client.connector.GetAllLazy(this.region, keys[start:end])
+		if _, partial := err.(*connector.MultiError); err != nil && !partial {
+			return purged, err
+		}
+
+		for k, v := range entries {
+			tombstone, deleted := asTombstone(v.Encoded())
+			if !deleted || !tombstone.DeletedAt.Before(before) {
+				continue
+			}
+			if err := this.client.connector.Remove(this.region, k); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// StartPurging calls Purge at the given interval, removing the tombstones of entries deleted
+// more than retention ago, until the returned function is called. Errors are passed to
+// onError, which may be nil.
+func (this *SoftDeletes) StartPurging(interval, retention time.Duration, onError func(error)) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := this.Purge(this.now().Add(-retention)); err != nil && onError != nil {
+					onError(err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+func (this *SoftDeletes) encodeKey(key interface{}) (*v1.EncodedValue, error) {
+	physicalKey, err := this.client.transformKey(this.region, key)
+	if err != nil {
+		return nil, err
+	}
+	return connector.EncodeValue(physicalKey)
+}
+
+// Return the tombstone an encoded value holds, if it is one
+func asTombstone(value *v1.EncodedValue) (*tombstoneDocument, bool) {
+	document := value.GetJsonObjectResult()
+	if !strings.Contains(document, `"geodeTombstone"`) {
+		return nil, false
+	}
+
+	tombstone := &tombstoneDocument{}
+	if err := json.Unmarshal([]byte(document), tombstone); err != nil || !tombstone.Tombstone {
+		return nil, false
+	}
+	return tombstone, true
+}
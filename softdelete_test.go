@@ -0,0 +1,80 @@
+package geode_go_client_test
+
+import (
+	"time"
+
+	geode "github.com/gemfire/geode-go-client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Soft deletes", func() {
+
+	type person struct {
+		Name string `json:"name"`
+	}
+
+	var cluster *fakeCluster
+	var client *geode.Client
+	var deletes *geode.SoftDeletes
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		client = geode.NewGeodeClient(cluster.connector())
+		deletes = geode.NewSoftDeletes(client, "people")
+	})
+
+	It("hides deleted entries until they are restored", func() {
+		Expect(client.Put("people", "joe", &person{Name: "Joe"})).To(Succeed())
+		Expect(client.Put("people", "ann", &person{Name: "Ann"})).To(Succeed())
+
+		before := time.Now()
+		Expect(deletes.Delete("joe")).To(Succeed())
+		Expect(cluster.keys("people")).To(HaveLen(2))
+
+		var joe person
+		found, err := deletes.Get("joe", &joe)
+		Expect(err).To(BeNil())
+		Expect(found).To(BeFalse())
+
+		entries, _, err := deletes.GetAllLazy([]string{"joe", "ann"})
+		Expect(err).To(BeNil())
+		Expect(entries).To(HaveLen(1))
+		Expect(entries).To(HaveKey("ann"))
+
+		tombstone, err := deletes.Deleted("joe")
+		Expect(err).To(BeNil())
+		Expect(tombstone.DeletedAt).To(BeTemporally(">=", before.Truncate(time.Second)))
+		Expect(deletes.Deleted("ann")).To(BeNil())
+
+		Expect(deletes.Restore("joe")).To(BeTrue())
+		found, err = deletes.Get("joe", &joe)
+		Expect(err).To(BeNil())
+		Expect(found).To(BeTrue())
+		Expect(joe).To(Equal(person{Name: "Joe"}))
+	})
+
+	It("keeps the original value when deleted twice", func() {
+		Expect(client.Put("people", "joe", "Joe")).To(Succeed())
+		Expect(deletes.Delete("joe")).To(Succeed())
+		Expect(deletes.Delete("joe")).To(Succeed())
+		Expect(deletes.Delete("ann")).To(Succeed())
+		Expect(cluster.keys("people")).To(ConsistOf("joe"))
+
+		Expect(deletes.Restore("joe")).To(BeTrue())
+		Expect(client.Get("people", "joe")).To(Equal("Joe"))
+		Expect(deletes.Restore("joe")).To(BeFalse())
+	})
+
+	It("purges the tombstones of entries deleted before a time", func() {
+		Expect(client.Put("people", "joe", "Joe")).To(Succeed())
+		Expect(client.Put("people", "ann", "Ann")).To(Succeed())
+		Expect(deletes.Delete("joe")).To(Succeed())
+
+		Expect(deletes.Purge(time.Now().Add(-time.Hour))).To(Equal(0))
+		Expect(cluster.keys("people")).To(HaveLen(2))
+
+		Expect(deletes.Purge(time.Now().Add(time.Hour))).To(Equal(1))
+		Expect(cluster.keys("people")).To(ConsistOf("ann"))
+	})
+})
@@ -0,0 +1,125 @@
+package geode_go_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"time"
+)
+
+// ClusterRequirements describes the state a cluster must reach before WaitForCluster
+// returns.
+type ClusterRequirements struct {
+	// Locators and servers, as host:port, which must accept connections
+	Locators []string
+	Servers  []string
+	// Regions which must exist
+	Regions []string
+	// Delay between checks, doubling up to MaxBackoff. Default to 100ms and 5s.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// If set, progress is logged here
+	Logger *log.Logger
+}
+
+// A ClusterNotReadyError is returned by WaitForCluster when ctx is done before the cluster
+// meets the requirements. Err is the reason the last check failed. The error wraps both Err
+// and the context's error.
+type ClusterNotReadyError struct {
+	Attempts int
+	Err      error
+	ctxErr   error
+}
+
+func (this *ClusterNotReadyError) Error() string {
+	return fmt.Sprintf("cluster not ready after %d attempts: %s", this.Attempts, this.Err.Error())
+}
+
+func (this *ClusterNotReadyError) Unwrap() []error {
+	return []error{this.ctxErr, this.Err}
+}
+
+// WaitForCluster blocks until the required locators and servers accept connections and all
+// the required regions exist, or until ctx is done. It is intended to order service
+// startup, for example in containerized deployments where the cluster may start at the same
+// time as its clients.
+//
+// Regions are checked through the client's pool, which avoids servers it suspects have
+// failed. Listing the pool's servers in Servers ensures that the pool is not used until they
+// are reachable.
+func (this *Client) WaitForCluster(ctx context.Context, requirements ClusterRequirements) error {
+	backoff := requirements.InitialBackoff
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+	maxBackoff := requirements.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 5 * time.Second
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := this.checkCluster(ctx, requirements)
+		if err == nil {
+			if requirements.Logger != nil {
+				requirements.Logger.Printf("cluster ready after %d attempts", attempt)
+			}
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return &ClusterNotReadyError{Attempts: attempt, Err: err, ctxErr: ctx.Err()}
+		}
+
+		if requirements.Logger != nil {
+			requirements.Logger.Printf("waiting for cluster (attempt %d): %s; retrying in %s", attempt, err.Error(), backoff)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return &ClusterNotReadyError{Attempts: attempt, Err: err, ctxErr: ctx.Err()}
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (this *Client) checkCluster(ctx context.Context, requirements ClusterRequirements) error {
+	for _, locator := range requirements.Locators {
+		if err := probe(ctx, locator); err != nil {
+			return errors.New(fmt.Sprintf("locator %s not reachable: %s", locator, err.Error()))
+		}
+	}
+
+	for _, server := range requirements.Servers {
+		if err := probe(ctx, server); err != nil {
+			return errors.New(fmt.Sprintf("server %s not reachable: %s", server, err.Error()))
+		}
+	}
+
+	conn := this.connector.BindContext(ctx)
+	for _, region := range requirements.Regions {
+		if _, err := conn.Size(region); err != nil {
+			return errors.New(fmt.Sprintf("region %s not available: %s", region, err.Error()))
+		}
+	}
+
+	return nil
+}
+
+func probe(ctx context.Context, address string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
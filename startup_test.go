@@ -0,0 +1,101 @@
+package geode_go_client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net"
+	"time"
+
+	geode "github.com/gemfire/geode-go-client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WaitForCluster", func() {
+
+	var cluster *fakeCluster
+	var client *geode.Client
+	var output *bytes.Buffer
+	var requirements geode.ClusterRequirements
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		client = geode.NewGeodeClient(cluster.connector())
+		output = &bytes.Buffer{}
+		requirements = geode.ClusterRequirements{
+			Regions:        []string{"foo", "bar"},
+			InitialBackoff: 5 * time.Millisecond,
+			MaxBackoff:     10 * time.Millisecond,
+			Logger:         log.New(output, "", 0),
+		}
+	})
+
+	It("returns once the required regions exist", func() {
+		cluster.createRegion("foo")
+		cluster.createRegion("bar")
+
+		Expect(client.WaitForCluster(context.Background(), requirements)).To(BeNil())
+		Expect(output.String()).To(Equal("cluster ready after 1 attempts\n"))
+	})
+
+	It("waits for missing regions", func() {
+		cluster.createRegion("foo")
+		go func() {
+			time.Sleep(30 * time.Millisecond)
+			cluster.createRegion("bar")
+		}()
+
+		Expect(client.WaitForCluster(context.Background(), requirements)).To(BeNil())
+		Expect(output.String()).To(ContainSubstring("waiting for cluster (attempt 1): region bar not available"))
+	})
+
+	It("probes locators and servers", func() {
+		cluster.createRegion("foo")
+		cluster.createRegion("bar")
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		defer listener.Close()
+
+		// Nothing can listen on port 0, so the server is refused however busy the machine is
+		unreachable := "127.0.0.1:0"
+		requirements.Locators = []string{listener.Addr().String()}
+		requirements.Servers = []string{unreachable}
+
+		// Give up once the first check has failed, rather than on a deadline
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		requirements.Logger = log.New(cancelingWriter{output, cancel}, "", 0)
+
+		err = client.WaitForCluster(ctx, requirements)
+		Expect(err).To(MatchError(ContainSubstring("server " + unreachable + " not reachable")))
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+		Expect(output.String()).ToNot(ContainSubstring("locator"))
+	})
+
+	It("gives up when the context is done", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+
+		err := client.WaitForCluster(ctx, requirements)
+		Expect(err).To(MatchError(ContainSubstring("region foo not available")))
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+
+		notReady, ok := err.(*geode.ClusterNotReadyError)
+		Expect(ok).To(BeTrue())
+		Expect(notReady.Attempts).To(BeNumerically(">", 1))
+	})
+})
+
+// A writer which cancels a context once written to
+type cancelingWriter struct {
+	*bytes.Buffer
+	cancel context.CancelFunc
+}
+
+func (this cancelingWriter) Write(p []byte) (int, error) {
+	defer this.cancel()
+	return this.Buffer.Write(p)
+}
@@ -0,0 +1,345 @@
+package geode_go_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+// ErrNoTenant is returned by a TenantScopedClient when the supplied context does not
+// identify a tenant.
+var ErrNoTenant = errors.New("no tenant associated with context")
+
+// TenantIsolation determines how a TenantScopedClient keeps the data of each tenant apart.
+type TenantIsolation int
+
+const (
+	// Keys are prefixed with the tenant name and all tenants share the same regions. Keys
+	// are stored as strings of the form <tenant>:<key>, so only string keys are accepted
+	// and tenant names may not contain ':'.
+	PrefixKeys TenantIsolation = iota
+	// Each tenant has its own set of regions, named <tenant>-<region>. The regions must
+	// already exist on the cluster. Tenant names may not contain '-'.
+	NamespaceRegions
+)
+
+type tenantContextKey struct{}
+
+// WithTenant returns a copy of ctx which identifies the given tenant.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant identified by ctx, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// A TenantScopedClient wraps a Client for use on a cluster which is shared by many tenants.
+// Every operation takes a context from which the tenant is determined (see WithTenant), and
+// which bounds the operation as for the Ctx variants of the Client's operations. Keys or
+// regions are rewritten so that one tenant can neither see nor modify the data of
+// another. Callers always deal in logical keys and regions.
+type TenantScopedClient struct {
+	client    *Client
+	isolation TenantIsolation
+
+	quotaLock sync.RWMutex
+	quota     Quota
+}
+
+func NewTenantScopedClient(client *Client, isolation TenantIsolation) *TenantScopedClient {
+	return &TenantScopedClient{
+		client:    client,
+		isolation: isolation,
+	}
+}
+
+// Put data into a region for the tenant identified by ctx.
+func (this *TenantScopedClient) Put(ctx context.Context, region string, key, value interface{}) error {
+	tenant, err := this.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	if err := this.admit(tenant, key, value); err != nil {
+		return err
+	}
+
+	scopedKey, err := this.key(tenant, key)
+	if err != nil {
+		return err
+	}
+
+	return this.client.PutCtx(ctx, this.region(tenant, region), scopedKey, value)
+}
+
+// Put data into a region for the tenant identified by ctx if the key is not present.
+func (this *TenantScopedClient) PutIfAbsent(ctx context.Context, region string, key, value interface{}) error {
+	tenant, err := this.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	if err := this.admit(tenant, key, value); err != nil {
+		return err
+	}
+
+	scopedKey, err := this.key(tenant, key)
+	if err != nil {
+		return err
+	}
+
+	return this.client.PutIfAbsentCtx(ctx, this.region(tenant, region), scopedKey, value)
+}
+
+// Get an entry from a region for the tenant identified by ctx. See Client.Get for the use
+// of the optional value.
+func (this *TenantScopedClient) Get(ctx context.Context, region string, key interface{}, value ...interface{}) (interface{}, error) {
+	tenant, err := this.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := this.admit(tenant, key); err != nil {
+		return nil, err
+	}
+
+	scopedKey, err := this.key(tenant, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return this.client.GetCtx(ctx, this.region(tenant, region), scopedKey, value...)
+}
+
+// PutAll adds multiple key/value pairs to a region for the tenant identified by ctx. Keys in
+// the returned failures are the logical keys passed in.
+func (this *TenantScopedClient) PutAll(ctx context.Context, region string, entries interface{}) (map[interface{}]error, error) {
+	tenant, err := this.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := this.admit(tenant, entries); err != nil {
+		return nil, err
+	}
+
+	entriesMap := reflect.ValueOf(entries)
+	if this.isolation != PrefixKeys || entriesMap.Kind() != reflect.Map {
+		return this.client.PutAllCtx(ctx, this.region(tenant, region), entries)
+	}
+
+	scoped := make(map[interface{}]interface{}, entriesMap.Len())
+	originals := make(map[interface{}]interface{}, entriesMap.Len())
+	for _, k := range entriesMap.MapKeys() {
+		key, err := this.key(tenant, k.Interface())
+		if err != nil {
+			return nil, err
+		}
+		scoped[key] = entriesMap.MapIndex(k).Interface()
+		originals[key] = k.Interface()
+	}
+
+	failures, err := this.client.PutAllCtx(ctx, region, scoped)
+	if _, partial := err.(*connector.MultiError); failures == nil || (err != nil && !partial) {
+		return failures, err
+	}
+
+	result := make(map[interface{}]error, len(failures))
+	for k, failure := range failures {
+		result[originalKey(originals, k)] = failure
+	}
+
+	return result, err
+}
+
+// GetAll returns the values of multiple keys for the tenant identified by ctx. Keys in the
+// returned maps are the logical keys passed in.
+func (this *TenantScopedClient) GetAll(ctx context.Context, region string, keys interface{}) (map[interface{}]interface{}, map[interface{}]error, error) {
+	tenant, err := this.tenant(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := this.admit(tenant, keys); err != nil {
+		return nil, nil, err
+	}
+
+	keySlice := reflect.ValueOf(keys)
+	if this.isolation != PrefixKeys || (keySlice.Kind() != reflect.Slice && keySlice.Kind() != reflect.Array) {
+		return this.client.GetAllCtx(ctx, this.region(tenant, region), keys)
+	}
+
+	scoped := make([]interface{}, keySlice.Len())
+	originals := make(map[interface{}]interface{}, keySlice.Len())
+	for i := 0; i < keySlice.Len(); i++ {
+		key, err := this.key(tenant, keySlice.Index(i).Interface())
+		if err != nil {
+			return nil, nil, err
+		}
+		scoped[i] = key
+		originals[key] = keySlice.Index(i).Interface()
+	}
+
+	entries, failures, err := this.client.GetAllCtx(ctx, region, scoped)
+	if _, partial := err.(*connector.MultiError); err != nil && !partial {
+		return nil, nil, err
+	}
+
+	result := make(map[interface{}]interface{}, len(entries))
+	for k, v := range entries {
+		result[originalKey(originals, k)] = v
+	}
+
+	var resultFailures map[interface{}]error
+	if failures != nil {
+		resultFailures = make(map[interface{}]error, len(failures))
+		for k, failure := range failures {
+			resultFailures[originalKey(originals, k)] = failure
+		}
+	}
+
+	return result, resultFailures, err
+}
+
+// Remove an entry from a region for the tenant identified by ctx.
+func (this *TenantScopedClient) Remove(ctx context.Context, region string, key interface{}) error {
+	tenant, err := this.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	if err := this.admit(tenant, key); err != nil {
+		return err
+	}
+
+	scopedKey, err := this.key(tenant, key)
+	if err != nil {
+		return err
+	}
+
+	return this.client.RemoveCtx(ctx, this.region(tenant, region), scopedKey)
+}
+
+// RemoveAll removes many entries from a region for the tenant identified by ctx. Keys in the
+// returned failures are the logical keys passed in.
+func (this *TenantScopedClient) RemoveAll(ctx context.Context, region string, keys interface{}) (map[interface{}]error, error) {
+	tenant, err := this.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := this.admit(tenant, keys); err != nil {
+		return nil, err
+	}
+
+	keySlice := reflect.ValueOf(keys)
+	if this.isolation != PrefixKeys || (keySlice.Kind() != reflect.Slice && keySlice.Kind() != reflect.Array) {
+		return this.client.RemoveAllCtx(ctx, this.region(tenant, region), keys)
+	}
+
+	scoped := make([]interface{}, keySlice.Len())
+	originals := make(map[interface{}]interface{}, keySlice.Len())
+	for i := 0; i < keySlice.Len(); i++ {
+		key, err := this.key(tenant, keySlice.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		scoped[i] = key
+		originals[key] = keySlice.Index(i).Interface()
+	}
+
+	failures, err := this.client.RemoveAllCtx(ctx, region, scoped)
+	if failures == nil || err != nil {
+		return failures, err
+	}
+
+	result := make(map[interface{}]error, len(failures))
+	for k, failure := range failures {
+		result[originalKey(originals, k)] = failure
+	}
+
+	return result, nil
+}
+
+// Size returns the number of entries in a region for the tenant identified by ctx. This is
+// only supported when regions are namespaced, as with prefixed keys the region is shared.
+func (this *TenantScopedClient) Size(ctx context.Context, region string) (int32, error) {
+	tenant, err := this.tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := this.admit(tenant); err != nil {
+		return 0, err
+	}
+
+	if this.isolation != NamespaceRegions {
+		return 0, errors.New("Size is not supported for tenants sharing regions")
+	}
+
+	return this.client.SizeCtx(ctx, this.region(tenant, region))
+}
+
+// Clear removes every entry of a region for the tenant identified by ctx. Like Size, this is
+// only supported when regions are namespaced.
+func (this *TenantScopedClient) Clear(ctx context.Context, region string) error {
+	tenant, err := this.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	if err := this.admit(tenant); err != nil {
+		return err
+	}
+
+	if this.isolation != NamespaceRegions {
+		return errors.New("Clear is not supported for tenants sharing regions")
+	}
+
+	return this.client.ClearCtx(ctx, this.region(tenant, region))
+}
+
+func (this *TenantScopedClient) tenant(ctx context.Context) (string, error) {
+	if ctx == nil {
+		return "", ErrNoTenant
+	}
+
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return "", ErrNoTenant
+	}
+
+	// The separator would make one tenant's keys or regions indistinguishable from another's
+	if strings.Contains(tenant, this.separator()) {
+		return "", errors.New(fmt.Sprintf("tenant %s may not contain '%s'", tenant, this.separator()))
+	}
+
+	return tenant, nil
+}
+
+func (this *TenantScopedClient) separator() string {
+	if this.isolation == NamespaceRegions {
+		return "-"
+	}
+	return ":"
+}
+
+func (this *TenantScopedClient) region(tenant, region string) string {
+	if this.isolation == NamespaceRegions {
+		return fmt.Sprintf("%s-%s", tenant, region)
+	}
+	return region
+}
+
+func (this *TenantScopedClient) key(tenant string, key interface{}) (interface{}, error) {
+	if this.isolation != PrefixKeys {
+		return key, nil
+	}
+
+	// Formatting other types could map distinct keys, such as 7 and "7", to the same string
+	s, ok := key.(string)
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("keys must be strings when tenants share regions, not %T", key))
+	}
+
+	return tenant + ":" + s, nil
+}
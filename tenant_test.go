@@ -0,0 +1,161 @@
+package geode_go_client_test
+
+import (
+	"context"
+
+	geode "github.com/gemfire/geode-go-client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TenantScopedClient", func() {
+
+	var cluster *fakeCluster
+	var client *geode.Client
+	var acme, globex context.Context
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		client = geode.NewGeodeClient(cluster.connector())
+		acme = geode.WithTenant(context.Background(), "acme")
+		globex = geode.WithTenant(context.Background(), "globex")
+	})
+
+	It("requires a tenant", func() {
+		tenants := geode.NewTenantScopedClient(client, geode.PrefixKeys)
+
+		Expect(tenants.Put(context.Background(), "foo", "A", 1)).To(Equal(geode.ErrNoTenant))
+		_, err := tenants.Get(context.Background(), "foo", "A")
+		Expect(err).To(Equal(geode.ErrNoTenant))
+		Expect(cluster.requests).To(BeEmpty())
+	})
+
+	It("is bound by the context", func() {
+		tenants := geode.NewTenantScopedClient(client, geode.PrefixKeys)
+		ctx, cancel := context.WithCancel(acme)
+		cancel()
+
+		Expect(tenants.Put(ctx, "foo", "A", 1)).To(Equal(context.Canceled))
+		Expect(cluster.requests).To(BeEmpty())
+	})
+
+	Context("with prefixed keys", func() {
+		var tenants *geode.TenantScopedClient
+
+		BeforeEach(func() {
+			tenants = geode.NewTenantScopedClient(client, geode.PrefixKeys)
+		})
+
+		It("prefixes keys with the tenant", func() {
+			Expect(tenants.Put(acme, "foo", "A", 1)).To(BeNil())
+			Expect(tenants.Put(globex, "foo", "7", 2)).To(BeNil())
+
+			Expect(cluster.keys("foo")).To(ConsistOf("acme:A", "globex:7"))
+		})
+
+		It("isolates tenants from each other", func() {
+			Expect(tenants.Put(acme, "foo", "A", 1)).To(BeNil())
+
+			v, err := tenants.Get(acme, "foo", "A")
+			Expect(err).To(BeNil())
+			Expect(v).To(Equal(int32(1)))
+
+			v, err = tenants.Get(globex, "foo", "A")
+			Expect(err).To(BeNil())
+			Expect(v).To(BeNil())
+
+			Expect(tenants.Remove(globex, "foo", "A")).To(BeNil())
+			v, err = tenants.Get(acme, "foo", "A")
+			Expect(err).To(BeNil())
+			Expect(v).To(Equal(int32(1)))
+		})
+
+		It("returns logical keys from GetAll", func() {
+			failures, err := tenants.PutAll(acme, "foo", map[string]int{"A": 1, "B": 2})
+			Expect(err).To(BeNil())
+			Expect(failures).To(BeNil())
+			Expect(tenants.Put(globex, "foo", "C", 3)).To(BeNil())
+
+			entries, failures, err := tenants.GetAll(acme, "foo", []string{"A", "B", "C"})
+			Expect(err).To(BeNil())
+			Expect(failures).To(BeEmpty())
+			Expect(entries).To(Equal(map[interface{}]interface{}{"A": int32(1), "B": int32(2)}))
+		})
+
+		It("removes only the tenant's entries with RemoveAll", func() {
+			Expect(tenants.Put(acme, "foo", "A", 1)).To(BeNil())
+			Expect(tenants.Put(globex, "foo", "A", 2)).To(BeNil())
+
+			failures, err := tenants.RemoveAll(acme, "foo", []string{"A"})
+			Expect(err).To(BeNil())
+			Expect(failures).To(BeNil())
+			Expect(cluster.keys("foo")).To(ConsistOf("globex:A"))
+		})
+
+		It("rejects keys which are not strings", func() {
+			Expect(tenants.Put(acme, "foo", 7, 1)).To(MatchError(ContainSubstring("keys must be strings")))
+			_, _, err := tenants.GetAll(acme, "foo", []interface{}{"A", 7})
+			Expect(err).To(MatchError(ContainSubstring("keys must be strings")))
+			Expect(cluster.requests).To(BeEmpty())
+		})
+
+		It("rejects tenants which could collide with another", func() {
+			a := geode.WithTenant(context.Background(), "a")
+			ab := geode.WithTenant(context.Background(), "a:b")
+
+			Expect(tenants.Put(a, "foo", "b:c", 1)).To(BeNil())
+			Expect(tenants.Put(ab, "foo", "c", 2)).To(MatchError(ContainSubstring("may not contain ':'")))
+
+			v, err := tenants.Get(ab, "foo", "c")
+			Expect(err).ToNot(BeNil())
+			Expect(v).To(BeNil())
+			Expect(cluster.keys("foo")).To(ConsistOf("a:b:c"))
+		})
+
+		It("does not support Size or Clear", func() {
+			_, err := tenants.Size(acme, "foo")
+			Expect(err).ToNot(BeNil())
+			Expect(tenants.Clear(acme, "foo")).ToNot(BeNil())
+			Expect(cluster.requests).To(BeEmpty())
+		})
+	})
+
+	Context("with namespaced regions", func() {
+		var tenants *geode.TenantScopedClient
+
+		BeforeEach(func() {
+			tenants = geode.NewTenantScopedClient(client, geode.NamespaceRegions)
+		})
+
+		It("maps regions per tenant and leaves keys alone", func() {
+			Expect(tenants.Put(acme, "foo", "A", 1)).To(BeNil())
+			Expect(tenants.Put(globex, "foo", "A", 2)).To(BeNil())
+
+			Expect(cluster.keys("acme-foo")).To(ConsistOf("A"))
+			Expect(cluster.keys("globex-foo")).To(ConsistOf("A"))
+			Expect(cluster.keys("foo")).To(BeEmpty())
+
+			v, err := tenants.Get(globex, "foo", "A")
+			Expect(err).To(BeNil())
+			Expect(v).To(Equal(int32(2)))
+
+			size, err := tenants.Size(acme, "foo")
+			Expect(err).To(BeNil())
+			Expect(size).To(Equal(int32(1)))
+
+			Expect(tenants.Clear(acme, "foo")).To(BeNil())
+			Expect(cluster.keys("acme-foo")).To(BeEmpty())
+			Expect(cluster.keys("globex-foo")).To(ConsistOf("A"))
+		})
+
+		It("rejects tenants which could collide with another", func() {
+			a := geode.WithTenant(context.Background(), "a")
+			ab := geode.WithTenant(context.Background(), "a-b")
+
+			Expect(tenants.Put(a, "b-c", "A", 1)).To(BeNil())
+			_, err := tenants.Get(ab, "c", "A")
+			Expect(err).To(MatchError(ContainSubstring("may not contain '-'")))
+			Expect(cluster.keys("a-b-c")).To(ConsistOf("A"))
+		})
+	})
+})
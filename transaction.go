@@ -0,0 +1,130 @@
+package geode_go_client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+// ErrTransactionsUnsupported is returned by Begin when the Client has no TransactionFunctions.
+var ErrTransactionsUnsupported = errors.New("transactions are not supported by the protocol without transaction functions")
+
+// ErrTransactionDone is returned by the operations of a Transaction which has been committed
+// or rolled back.
+var ErrTransactionDone = errors.New("transaction has already been committed or rolled back")
+
+// TransactionFunctions names functions deployed on the servers which begin, commit and roll
+// back a transaction for the connection executing them. Geode's protobuf protocol has no
+// transaction messages, so transactions are only available through such functions. They are
+// executed on Region with no arguments.
+type TransactionFunctions struct {
+	Region   string
+	Begin    string
+	Commit   string
+	Rollback string
+}
+
+// A Transaction groups operations which are committed or rolled back together. Every
+// operation of a transaction uses the same connection, pinned from the pool until the
+// transaction ends (see connector.Protobuf.Pin), and is bound by the context given to Begin.
+// Should the connection fail, the transaction's operations return
+// connector.ErrPinnedConnectionLost and the servers are left to abandon the transaction.
+// A Transaction must not be used concurrently.
+type Transaction struct {
+	client    *Client
+	conn      *connector.Protobuf
+	release   func()
+	functions TransactionFunctions
+	done      bool
+}
+
+// SetTransactionFunctions sets the functions used to begin, commit and roll back
+// transactions. A nil value, the default, disables transactions.
+func (this *Client) SetTransactionFunctions(functions *TransactionFunctions) {
+	this.transactionLock.Lock()
+	defer this.transactionLock.Unlock()
+
+	if functions == nil {
+		this.transactions = nil
+	} else {
+		copied := *functions
+		this.transactions = &copied
+	}
+}
+
+// Begin starts a transaction. The transaction's connection is held until it is committed or
+// rolled back, so every transaction must end with one or the other.
+func (this *Client) Begin(ctx context.Context) (*Transaction, error) {
+	this.transactionLock.RLock()
+	functions := this.transactions
+	this.transactionLock.RUnlock()
+
+	if functions == nil {
+		return nil, ErrTransactionsUnsupported
+	}
+
+	conn, err := this.protobuf("transactions")
+	if err != nil {
+		return nil, err
+	}
+
+	pinned, release, err := conn.WithContext(ctx).Pin()
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &Transaction{client: this, conn: pinned, release: release, functions: *functions}
+	if err := tx.execute(functions.Begin); err != nil {
+		release()
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (this *Transaction) Put(region string, key, value interface{}) error {
+	if this.done {
+		return ErrTransactionDone
+	}
+	return this.client.put(this.conn, region, key, value)
+}
+
+func (this *Transaction) Get(region string, key interface{}, value ...interface{}) (interface{}, error) {
+	if this.done {
+		return nil, ErrTransactionDone
+	}
+	return this.client.get(this.conn, region, key, value...)
+}
+
+func (this *Transaction) Remove(region string, key interface{}) error {
+	if this.done {
+		return ErrTransactionDone
+	}
+	return this.client.remove(this.conn, region, key)
+}
+
+// Commit commits the transaction and releases its connection.
+func (this *Transaction) Commit() error {
+	return this.end(this.functions.Commit)
+}
+
+// Rollback rolls back the transaction and releases its connection.
+func (this *Transaction) Rollback() error {
+	return this.end(this.functions.Rollback)
+}
+
+func (this *Transaction) end(functionId string) error {
+	if this.done {
+		return ErrTransactionDone
+	}
+	this.done = true
+	defer this.release()
+
+	return this.execute(functionId)
+}
+
+func (this *Transaction) execute(functionId string) error {
+	_, err := this.client.executeOnRegion(this.conn, functionId, this.functions.Region, nil, nil)
+	return err
+}
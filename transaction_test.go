@@ -0,0 +1,138 @@
+package geode_go_client_test
+
+import (
+	"context"
+	"time"
+
+	geode "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Transactions", func() {
+
+	var cluster *fakeCluster
+	var client *geode.Client
+
+	// Return the functions executed on the cluster, in order
+	executed := func() []string {
+		functions := make([]string, 0)
+		for _, request := range cluster.requests {
+			if r := request.GetExecuteFunctionOnRegionRequest(); r != nil {
+				functions = append(functions, r.FunctionID)
+			}
+		}
+		return functions
+	}
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		client = geode.NewGeodeClient(cluster.connector())
+		client.SetTransactionFunctions(&geode.TransactionFunctions{
+			Region:   "tx",
+			Begin:    "begin",
+			Commit:   "commit",
+			Rollback: "rollback",
+		})
+	})
+
+	It("is not supported without transaction functions", func() {
+		client.SetTransactionFunctions(nil)
+
+		_, err := client.Begin(context.Background())
+		Expect(err).To(Equal(geode.ErrTransactionsUnsupported))
+		Expect(cluster.requests).To(BeEmpty())
+	})
+
+	It("begins and commits through the functions", func() {
+		tx, err := client.Begin(context.Background())
+		Expect(err).To(BeNil())
+
+		Expect(tx.Put("foo", "A", 1)).To(BeNil())
+		Expect(tx.Get("foo", "A")).To(Equal(int32(1)))
+		Expect(tx.Remove("foo", "A")).To(BeNil())
+		Expect(tx.Commit()).To(BeNil())
+
+		Expect(executed()).To(Equal([]string{"begin", "commit"}))
+		Expect(cluster.requests).To(HaveLen(5))
+	})
+
+	It("rolls back through the function", func() {
+		tx, err := client.Begin(context.Background())
+		Expect(err).To(BeNil())
+		Expect(tx.Rollback()).To(BeNil())
+
+		Expect(executed()).To(Equal([]string{"begin", "rollback"}))
+	})
+
+	It("cannot be used once ended", func() {
+		tx, err := client.Begin(context.Background())
+		Expect(err).To(BeNil())
+		Expect(tx.Commit()).To(BeNil())
+
+		Expect(tx.Put("foo", "A", 1)).To(Equal(geode.ErrTransactionDone))
+		Expect(tx.Rollback()).To(Equal(geode.ErrTransactionDone))
+	})
+
+	It("returns the error of a failed begin", func() {
+		client.SetTransactionFunctions(&geode.TransactionFunctions{Region: "tx", Begin: "fail"})
+
+		_, err := client.Begin(context.Background())
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("ends even when the commit fails", func() {
+		client.SetTransactionFunctions(&geode.TransactionFunctions{Region: "tx", Begin: "begin", Commit: "fail"})
+		tx, err := client.Begin(context.Background())
+		Expect(err).To(BeNil())
+
+		Expect(tx.Commit()).ToNot(BeNil())
+		Expect(tx.Put("foo", "A", 1)).To(Equal(geode.ErrTransactionDone))
+	})
+
+	It("does not share the values it reads and writes through a near cache", func() {
+		conn := cluster.connector()
+		conn.SetNearCache("foo", &connector.NearCache{TTL: time.Hour})
+		client = geode.NewGeodeClient(conn)
+		client.SetTransactionFunctions(&geode.TransactionFunctions{Region: "tx", Begin: "begin", Rollback: "rollback"})
+		Expect(client.Put("foo", "A", 1)).To(BeNil())
+
+		tx, err := client.Begin(context.Background())
+		Expect(err).To(BeNil())
+		Expect(tx.Put("foo", "A", 2)).To(BeNil())
+		Expect(tx.Get("foo", "A")).To(Equal(int32(2)))
+		Expect(tx.Rollback()).To(BeNil())
+
+		// The fake has no transactions, so undo the write as the server's rollback would
+		other := geode.NewGeodeClient(cluster.connector())
+		Expect(other.Put("foo", "A", 1)).To(BeNil())
+
+		Expect(client.Get("foo", "A")).To(Equal(int32(1)))
+	})
+
+	It("removes the entries it wrote from a near cache once committed", func() {
+		conn := cluster.connector()
+		conn.SetNearCache("foo", &connector.NearCache{TTL: time.Hour})
+		client = geode.NewGeodeClient(conn)
+		client.SetTransactionFunctions(&geode.TransactionFunctions{Region: "tx", Begin: "begin", Commit: "commit"})
+		other := geode.NewGeodeClient(cluster.connector())
+
+		Expect(client.Put("foo", "A", 1)).To(BeNil())
+		Expect(client.Get("foo", "A")).To(Equal(int32(1)))
+
+		tx, err := client.Begin(context.Background())
+		Expect(err).To(BeNil())
+		Expect(tx.Put("foo", "A", 2)).To(BeNil())
+
+		// The fake has no transactions, so show other connections the value from before the
+		// transaction until it commits, which is then cached again
+		Expect(other.Put("foo", "A", 1)).To(BeNil())
+		Expect(client.Get("foo", "A")).To(Equal(int32(1)))
+
+		Expect(tx.Commit()).To(BeNil())
+		Expect(other.Put("foo", "A", 2)).To(BeNil())
+
+		Expect(client.Get("foo", "A")).To(Equal(int32(2)))
+	})
+})
@@ -0,0 +1,147 @@
+package geode_go_client
+
+import (
+	"reflect"
+
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+// A Transform rewrites keys and values as they pass between a Client and a region. It can be
+// used for concerns such as key prefixing, field redaction or normalization which would
+// otherwise have to be handled at every call site. Any of the functions may be nil. A panic
+// in a function is returned as a connector.CallbackPanicError.
+//
+// Transforms apply to the region data operations: Get, GetAll, Put, PutAll, PutIfAbsent and
+// Remove, and Key functions to the key filters of functions executed on a region. They are
+// not otherwise applied to queries, function executions or raw operations.
+type Transform struct {
+	// Key is applied to every key before it is sent to the region.
+	Key func(region string, key interface{}) (interface{}, error)
+	// Write is applied to every value before it is written to the region. The key is the
+	// untransformed key.
+	Write func(region string, key, value interface{}) (interface{}, error)
+	// Read is applied to every value read from the region. The key is the untransformed key.
+	Read func(region string, key, value interface{}) (interface{}, error)
+}
+
+// AddTransform registers a Transform for a region. If region is empty the transform applies
+// to all regions. Key and Write functions are applied in the order in which transforms are
+// added, with those registered for all regions applied first; Read functions are applied in
+// the reverse order.
+func (this *Client) AddTransform(region string, transform *Transform) {
+	this.transformLock.Lock()
+	defer this.transformLock.Unlock()
+
+	if this.transforms == nil {
+		this.transforms = make(map[string][]*Transform)
+	}
+	this.transforms[region] = append(this.transforms[region], transform)
+}
+
+func (this *Client) transformsFor(region string) []*Transform {
+	this.transformLock.RLock()
+	defer this.transformLock.RUnlock()
+
+	if len(this.transforms) == 0 {
+		return nil
+	}
+
+	result := make([]*Transform, 0)
+	result = append(result, this.transforms[""]...)
+	if region != "" {
+		result = append(result, this.transforms[region]...)
+	}
+
+	return result
+}
+
+func (this *Client) transformKey(region string, key interface{}) (interface{}, error) {
+	var err error
+	for _, t := range this.transformsFor(region) {
+		if t.Key == nil {
+			continue
+		}
+		if key, err = applyKeyTransform(t.Key, region, key); err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+func (this *Client) transformWrite(region string, key, value interface{}) (interface{}, error) {
+	var err error
+	for _, t := range this.transformsFor(region) {
+		if t.Write == nil {
+			continue
+		}
+		if value, err = applyValueTransform("Transform.Write", t.Write, region, key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+func (this *Client) transformRead(region string, key, value interface{}) (interface{}, error) {
+	var err error
+	transforms := this.transformsFor(region)
+	for i := len(transforms) - 1; i >= 0; i-- {
+		if transforms[i].Read == nil {
+			continue
+		}
+		if value, err = applyValueTransform("Transform.Read", transforms[i].Read, region, key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+func applyKeyTransform(fn func(string, interface{}) (interface{}, error), region string, key interface{}) (result interface{}, err error) {
+	defer connector.RecoverCallback("Transform.Key", &err)
+	return fn(region, key)
+}
+
+func applyValueTransform(name string, fn func(string, interface{}, interface{}) (interface{}, error), region string, key, value interface{}) (result interface{}, err error) {
+	defer connector.RecoverCallback(name, &err)
+	return fn(region, key, value)
+}
+
+// Transform a slice or array of keys, returning the transformed keys along with a mapping
+// from each transformed key back to the original.
+func (this *Client) transformKeys(region string, keys interface{}) (interface{}, map[interface{}]interface{}, error) {
+	if len(this.transformsFor(region)) == 0 {
+		return keys, nil, nil
+	}
+
+	keySlice := reflect.ValueOf(keys)
+	if keySlice.Kind() != reflect.Slice && keySlice.Kind() != reflect.Array {
+		// Let the connector report the error
+		return keys, nil, nil
+	}
+
+	transformed := make([]interface{}, keySlice.Len())
+	originals := make(map[interface{}]interface{}, keySlice.Len())
+	for i := 0; i < keySlice.Len(); i++ {
+		key := keySlice.Index(i).Interface()
+		physical, err := this.transformKey(region, key)
+		if err != nil {
+			return nil, nil, err
+		}
+		transformed[i] = physical
+		if physical != nil && reflect.TypeOf(physical).Comparable() {
+			originals[physical] = key
+		}
+	}
+
+	return transformed, originals, nil
+}
+
+// Map a key returned by the server back to the key supplied by the caller.
+func originalKey(originals map[interface{}]interface{}, key interface{}) interface{} {
+	if original, ok := originals[key]; ok {
+		return original
+	}
+	return key
+}
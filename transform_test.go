@@ -0,0 +1,129 @@
+package geode_go_client_test
+
+import (
+	"fmt"
+	"strings"
+
+	geode "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/connector"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Transforms", func() {
+
+	var cluster *fakeCluster
+	var client *geode.Client
+	var calls []string
+
+	// A transform which prefixes keys and wraps values with its name, recording each call
+	named := func(name string) *geode.Transform {
+		return &geode.Transform{
+			Key: func(region string, key interface{}) (interface{}, error) {
+				calls = append(calls, name+".Key")
+				return fmt.Sprintf("%s/%v", name, key), nil
+			},
+			Write: func(region string, key, value interface{}) (interface{}, error) {
+				calls = append(calls, name+".Write")
+				return fmt.Sprintf("%s(%v)", name, value), nil
+			},
+			Read: func(region string, key, value interface{}) (interface{}, error) {
+				calls = append(calls, name+".Read")
+				s := value.(string)
+				return strings.TrimSuffix(strings.TrimPrefix(s, name+"("), ")"), nil
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = newFakeCluster()
+		client = geode.NewGeodeClient(cluster.connector())
+		calls = nil
+	})
+
+	It("applies global transforms before region transforms and reads in reverse", func() {
+		client.AddTransform("foo", named("region"))
+		client.AddTransform("", named("global"))
+
+		Expect(client.Put("foo", "A", "x")).To(BeNil())
+		Expect(cluster.keys("foo")).To(ConsistOf("region/global/A"))
+		Expect(calls).To(Equal([]string{"global.Key", "region.Key", "global.Write", "region.Write"}))
+
+		calls = nil
+		Expect(client.Get("foo", "A")).To(Equal("x"))
+		Expect(calls).To(Equal([]string{"global.Key", "region.Key", "region.Read", "global.Read"}))
+	})
+
+	It("applies region transforms only to their region", func() {
+		client.AddTransform("foo", named("region"))
+
+		Expect(client.Put("bar", "A", "x")).To(BeNil())
+		Expect(cluster.keys("bar")).To(ConsistOf("A"))
+		Expect(calls).To(BeEmpty())
+		Expect(client.Get("bar", "A")).To(Equal("x"))
+	})
+
+	It("maps PutAll and GetAll keys back to the keys passed in", func() {
+		client.AddTransform("foo", named("region"))
+
+		failures, err := client.PutAll("foo", map[string]string{"A": "x", "B": "y"})
+		Expect(err).To(BeNil())
+		Expect(failures).To(BeEmpty())
+		Expect(cluster.keys("foo")).To(ConsistOf("region/A", "region/B"))
+
+		entries, failures, err := client.GetAll("foo", []string{"A", "B"})
+		Expect(err).To(BeNil())
+		Expect(failures).To(BeEmpty())
+		Expect(entries).To(Equal(map[interface{}]interface{}{"A": "x", "B": "y"}))
+	})
+
+	It("transforms lazily read values as they are decoded", func() {
+		client.AddTransform("foo", named("region"))
+		Expect(client.Put("foo", "A", "x")).To(BeNil())
+		calls = nil
+
+		entries, failures, err := client.GetAllLazy("foo", []string{"A"})
+		Expect(err).To(BeNil())
+		Expect(failures).To(BeNil())
+		Expect(calls).To(Equal([]string{"region.Key"}))
+
+		var value string
+		Expect(entries["A"].Decode(&value)).To(BeNil())
+		Expect(value).To(Equal("x"))
+		Expect(calls).To(Equal([]string{"region.Key", "region.Read"}))
+	})
+
+	It("transforms the keys of function filters", func() {
+		client.AddTransform("foo", named("region"))
+
+		client.ExecuteOnRegion("fn", "foo", nil, []interface{}{"A"})
+		filter := cluster.requests[0].GetExecuteFunctionOnRegionRequest().GetKeyFilter()
+		Expect(filter).To(HaveLen(1))
+		Expect(filter[0].GetStringResult()).To(Equal("region/A"))
+	})
+
+	It("returns an error when PutAll keys cannot be held in a map", func() {
+		client.AddTransform("foo", &geode.Transform{
+			Key: func(region string, key interface{}) (interface{}, error) {
+				return []byte(key.(string)), nil
+			},
+		})
+
+		_, err := client.PutAll("foo", map[string]string{"A": "x"})
+		Expect(err).To(MatchError(ContainSubstring("cannot be used with PutAll")))
+		Expect(cluster.requests).To(BeEmpty())
+	})
+
+	It("returns an error when a transform panics", func() {
+		client.AddTransform("foo", &geode.Transform{
+			Write: func(region string, key, value interface{}) (interface{}, error) {
+				panic("boom")
+			},
+		})
+
+		err := client.Put("foo", "A", "x")
+		Expect(err).To(BeAssignableToTypeOf(&connector.CallbackPanicError{}))
+		Expect(err).To(MatchError("Transform.Write panicked: boom"))
+		Expect(cluster.requests).To(BeEmpty())
+	})
+})
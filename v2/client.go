@@ -0,0 +1,184 @@
+// Package geode is the second version of the client API. Every operation takes a context
+// first, options are passed in structs so that they can grow without breaking callers, and
+// failures are reported as typed errors, such as ErrNotFound for a missing entry.
+//
+// It is built on the first version, which remains supported. Configuration, such as access
+// lists, transforms and transaction functions, is set on the first version's Client, which
+// V1 returns.
+package geode
+
+import (
+	"context"
+	"reflect"
+
+	v1 "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/query"
+)
+
+// A Client performs operations on a cluster. It is safe for concurrent use.
+type Client struct {
+	client *v1.Client
+}
+
+// New returns a Client using the given connector, usually a *connector.Protobuf.
+func New(conn connector.Operations) *Client {
+	return &Client{client: v1.NewGeodeClient(conn)}
+}
+
+// Wrap returns a Client sharing the configuration of a Client of the first version.
+func Wrap(client *v1.Client) *Client {
+	return &Client{client: client}
+}
+
+// V1 returns the first version's Client, on which the Client is configured.
+func (this *Client) V1() *v1.Client {
+	return this.client
+}
+
+// GetOptions are the options of Get and GetAll.
+type GetOptions struct {
+	// Value which JSON documents are decoded into, such as a pointer to a struct. For GetAll,
+	// a new value of the same type is used for each entry.
+	Into interface{}
+}
+
+// PutOptions are the options of Put.
+type PutOptions struct {
+	// Only put the value if the region has no entry for the key.
+	IfAbsent bool
+}
+
+// QueryOptions are the options of Query.
+type QueryOptions struct {
+	// Values of the query's bind parameters, $1, $2 and so on
+	Parameters []interface{}
+	// Value which JSON results are decoded into, such as a pointer to a struct
+	Into interface{}
+	// Whether to have the server log the query's execution
+	Trace bool
+}
+
+// Get returns the value of a key, or ErrNotFound if the region has no entry for it.
+func (this *Client) Get(ctx context.Context, region string, key interface{}, options *GetOptions) (interface{}, error) {
+	var refs []interface{}
+	if options != nil && options.Into != nil {
+		refs = append(refs, options.Into)
+	}
+
+	result, err := this.client.GetOptionalCtx(ctx, region, key, refs...)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Present {
+		return nil, ErrNotFound
+	}
+
+	return result.Value, nil
+}
+
+// Put stores a value for a key.
+func (this *Client) Put(ctx context.Context, region string, key, value interface{}, options *PutOptions) error {
+	if options != nil && options.IfAbsent {
+		return this.client.PutIfAbsentCtx(ctx, region, key, value)
+	}
+	return this.client.PutCtx(ctx, region, key, value)
+}
+
+// Remove removes the entry for a key, if any.
+func (this *Client) Remove(ctx context.Context, region string, key interface{}) error {
+	return this.client.RemoveCtx(ctx, region, key)
+}
+
+// GetAll returns the values of keys, which must be a slice or array, along with the errors of
+// keys which could not be read. Missing keys are not included in either.
+func (this *Client) GetAll(ctx context.Context, region string, keys interface{}, options *GetOptions) (map[interface{}]interface{}, map[interface{}]error, error) {
+	if options == nil || options.Into == nil {
+		return this.client.GetAllCtx(ctx, region, keys)
+	}
+
+	entries := make(map[interface{}]interface{})
+	failures := make(map[interface{}]error)
+	newRef := newReference(options.Into)
+	err := this.client.GetAllIntoCtx(ctx, region, keys, newRef, func(key, value interface{}, err error) bool {
+		if err != nil {
+			failures[key] = err
+		} else {
+			entries[key] = value
+		}
+		return true
+	})
+	if _, partial := err.(*MultiError); err != nil && !partial {
+		return nil, nil, err
+	}
+	if len(failures) == 0 {
+		failures = nil
+	}
+
+	return entries, failures, err
+}
+
+// Return a function creating new values of the type into points to
+func newReference(into interface{}) func() interface{} {
+	t := reflect.Indirect(reflect.ValueOf(into)).Type()
+	return func() interface{} {
+		return reflect.New(t).Interface()
+	}
+}
+
+// PutAll stores many entries, which must be a map, returning the errors of entries which
+// could not be stored.
+func (this *Client) PutAll(ctx context.Context, region string, entries interface{}) (map[interface{}]error, error) {
+	return this.client.PutAllCtx(ctx, region, entries)
+}
+
+// RemoveAll removes the entries for many keys, which must be a slice or array, returning the
+// errors of keys which could not be removed.
+func (this *Client) RemoveAll(ctx context.Context, region string, keys interface{}) (map[interface{}]error, error) {
+	return this.client.RemoveAllCtx(ctx, region, keys)
+}
+
+// Keys returns the keys of a region.
+func (this *Client) Keys(ctx context.Context, region string) ([]interface{}, error) {
+	return this.client.KeysCtx(ctx, region)
+}
+
+// Size returns the number of entries in a region.
+func (this *Client) Size(ctx context.Context, region string) (int32, error) {
+	return this.client.SizeCtx(ctx, region)
+}
+
+// Clear removes every entry of a region.
+func (this *Client) Clear(ctx context.Context, region string) error {
+	return this.client.ClearCtx(ctx, region)
+}
+
+// Query runs an OQL query, returning its results as a list.
+func (this *Client) Query(ctx context.Context, oql string, options *QueryOptions) ([]interface{}, error) {
+	q := query.NewQuery(oql)
+	if options != nil {
+		q.BindParameters = options.Parameters
+		q.Reference = options.Into
+		q.Trace = options.Trace
+	}
+
+	return this.client.QueryForListResultCtx(ctx, q)
+}
+
+// An Execution holds the options of a function execution.
+type Execution = v1.Execution
+
+// ExecuteOnRegion executes a function on the members hosting a region.
+func (this *Client) ExecuteOnRegion(ctx context.Context, functionId, region string, execution *Execution) ([]interface{}, error) {
+	return this.client.ExecuteOnRegionWith(ctx, functionId, region, execution)
+}
+
+// ExecuteOnMembers executes a function on the named members.
+func (this *Client) ExecuteOnMembers(ctx context.Context, functionId string, members []string, execution *Execution) ([]interface{}, error) {
+	return this.client.ExecuteOnMembersWith(ctx, functionId, members, execution)
+}
+
+// ExecuteOnGroups executes a function on the members of the named groups.
+func (this *Client) ExecuteOnGroups(ctx context.Context, functionId string, groups []string, execution *Execution) ([]interface{}, error) {
+	return this.client.ExecuteOnGroupsWith(ctx, functionId, groups, execution)
+}
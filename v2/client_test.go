@@ -0,0 +1,142 @@
+package geode_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gemfire/geode-go-client/connector"
+	"github.com/gemfire/geode-go-client/connector/connectorfakes"
+	v1 "github.com/gemfire/geode-go-client/protobuf/v1"
+	"github.com/gemfire/geode-go-client/v2"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// A fakeRegion serves puts, gets and removes for a single region over a fake connection.
+type fakeRegion struct {
+	sync.Mutex
+	entries  map[string]*v1.Entry
+	response *v1.Message
+}
+
+func (this *fakeRegion) connector() *connector.Protobuf {
+	fakeConn := new(connectorfakes.FakeConn)
+	fakeConn.WriteStub = this.write
+	fakeConn.ReadStub = this.read
+
+	pool := connector.NewPool()
+	pool.AddConnection(fakeConn, true)
+
+	return connector.NewConnector(pool)
+}
+
+func (this *fakeRegion) write(b []byte) (int, error) {
+	request := &v1.Message{}
+	if err := proto.NewBuffer(b).DecodeMessage(request); err != nil {
+		return 0, err
+	}
+
+	this.Lock()
+	defer this.Unlock()
+
+	switch r := request.MessageType.(type) {
+	case *v1.Message_PutRequest:
+		this.entries[key(r.PutRequest.Entry.Key)] = r.PutRequest.Entry
+		this.response = &v1.Message{MessageType: &v1.Message_PutResponse{PutResponse: &v1.PutResponse{}}}
+	case *v1.Message_PutIfAbsentRequest:
+		response := &v1.PutIfAbsentResponse{}
+		if existing, ok := this.entries[key(r.PutIfAbsentRequest.Entry.Key)]; ok {
+			response.OldValue = existing.Value
+		} else {
+			this.entries[key(r.PutIfAbsentRequest.Entry.Key)] = r.PutIfAbsentRequest.Entry
+		}
+		this.response = &v1.Message{MessageType: &v1.Message_PutIfAbsentResponse{PutIfAbsentResponse: response}}
+	case *v1.Message_GetRequest:
+		response := &v1.GetResponse{}
+		if entry, ok := this.entries[key(r.GetRequest.Key)]; ok {
+			response.Result = entry.Value
+		}
+		this.response = &v1.Message{MessageType: &v1.Message_GetResponse{GetResponse: response}}
+	case *v1.Message_RemoveRequest:
+		delete(this.entries, key(r.RemoveRequest.Key))
+		this.response = &v1.Message{MessageType: &v1.Message_RemoveResponse{RemoveResponse: &v1.RemoveResponse{}}}
+	default:
+		return 0, fmt.Errorf("unexpected request %T", r)
+	}
+
+	return len(b), nil
+}
+
+func (this *fakeRegion) read(b []byte) (int, error) {
+	this.Lock()
+	defer this.Unlock()
+
+	p := proto.NewBuffer(nil)
+	p.EncodeMessage(this.response)
+
+	return copy(b, p.Bytes()), nil
+}
+
+func key(k *v1.EncodedValue) string {
+	decoded, _ := connector.DecodeValue(k, nil)
+	return fmt.Sprintf("%T:%v", decoded, decoded)
+}
+
+var _ = Describe("Client", func() {
+	var client *geode.Client
+	var ctx context.Context
+
+	BeforeEach(func() {
+		region := &fakeRegion{entries: make(map[string]*v1.Entry)}
+		client = geode.New(region.connector())
+		ctx = context.Background()
+	})
+
+	It("puts and gets values", func() {
+		Expect(client.Put(ctx, "foo", "A", "apple", nil)).To(Succeed())
+
+		Expect(client.Get(ctx, "foo", "A", nil)).To(Equal("apple"))
+	})
+
+	It("returns ErrNotFound for missing entries", func() {
+		_, err := client.Get(ctx, "foo", "A", nil)
+
+		Expect(err).To(Equal(geode.ErrNotFound))
+	})
+
+	It("removes values", func() {
+		Expect(client.Put(ctx, "foo", "A", "apple", nil)).To(Succeed())
+		Expect(client.Remove(ctx, "foo", "A")).To(Succeed())
+
+		_, err := client.Get(ctx, "foo", "A", nil)
+		Expect(err).To(Equal(geode.ErrNotFound))
+	})
+
+	It("only puts absent values when asked to", func() {
+		Expect(client.Put(ctx, "foo", "A", "apple", nil)).To(Succeed())
+		Expect(client.Put(ctx, "foo", "A", "avocado", &geode.PutOptions{IfAbsent: true})).To(Succeed())
+
+		Expect(client.Get(ctx, "foo", "A", nil)).To(Equal("apple"))
+	})
+
+	It("decodes documents into the given value", func() {
+		type fruit struct {
+			Name string `json:"name"`
+		}
+		Expect(client.Put(ctx, "foo", "A", &fruit{Name: "apple"}, nil)).To(Succeed())
+
+		value := &fruit{}
+		_, err := client.Get(ctx, "foo", "A", &geode.GetOptions{Into: value})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value.Name).To(Equal("apple"))
+	})
+
+	It("stops at a cancelled context", func() {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		Expect(client.Put(cancelled, "foo", "A", "apple", nil)).ToNot(Succeed())
+	})
+})
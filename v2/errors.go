@@ -0,0 +1,36 @@
+package geode
+
+import (
+	"errors"
+
+	v1 "github.com/gemfire/geode-go-client"
+	"github.com/gemfire/geode-go-client/connector"
+)
+
+// ErrNotFound is returned by Get when a region has no entry for a key. An entry whose value
+// is null is returned as a nil value instead.
+var ErrNotFound = errors.New("entry not found")
+
+// Errors shared with the first version of the API, so that either can be checked with
+// errors.Is and errors.As.
+var (
+	ErrReadOnly                = v1.ErrReadOnly
+	ErrTransactionsUnsupported = v1.ErrTransactionsUnsupported
+	ErrTransactionDone         = v1.ErrTransactionDone
+)
+
+type (
+	// An error response from a server
+	ServerError = connector.ServerError
+	GeodeError  = connector.GeodeError
+	// An operation rejected by the Client's allow and deny lists
+	AccessDeniedError = v1.AccessDeniedError
+	// An operation abandoned once its retries were exhausted
+	RetryBudgetError = connector.RetryBudgetError
+	// An operation on an optional region which could not reach a server
+	UnavailableError = connector.UnavailableError
+	// A bulk operation of which some chunks failed
+	MultiError = connector.MultiError
+	// A panic recovered from a callback
+	CallbackPanicError = connector.CallbackPanicError
+)